@@ -0,0 +1,182 @@
+// Package ad provides forward-mode automatic differentiation based on dual
+// numbers, which yields exact Jacobian matrices for the implicit integrators.
+//
+// A dual number a + bε, where ε² = 0, carries a value a together with a
+// derivative b, and arithmetic on dual numbers propagates derivatives by the
+// chain rule. If the right-hand side of a system is written in terms of the
+// operations of this package, the jth column of its Jacobian matrix is
+// obtained exactly, up to rounding, by evaluating the right-hand side once
+// with the jth component of y seeded with a unit derivative.
+//
+// https://en.wikipedia.org/wiki/Automatic_differentiation
+package ad
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode"
+)
+
+// Dual is a dual number.
+type Dual struct {
+	Value float64 // The value.
+	Deriv float64 // The derivative.
+}
+
+// Constant returns a dual number with value c and zero derivative.
+func Constant(c float64) Dual {
+	return Dual{Value: c}
+}
+
+// Variable returns a dual number with value c and unit derivative.
+func Variable(c float64) Dual {
+	return Dual{Value: c, Deriv: 1}
+}
+
+// Add returns a + b.
+func (a Dual) Add(b Dual) Dual {
+	return Dual{a.Value + b.Value, a.Deriv + b.Deriv}
+}
+
+// Sub returns a - b.
+func (a Dual) Sub(b Dual) Dual {
+	return Dual{a.Value - b.Value, a.Deriv - b.Deriv}
+}
+
+// Mul returns a b.
+func (a Dual) Mul(b Dual) Dual {
+	return Dual{a.Value * b.Value, a.Deriv*b.Value + a.Value*b.Deriv}
+}
+
+// Div returns a / b.
+func (a Dual) Div(b Dual) Dual {
+	return Dual{a.Value / b.Value, (a.Deriv*b.Value - a.Value*b.Deriv) / (b.Value * b.Value)}
+}
+
+// Neg returns -a.
+func (a Dual) Neg() Dual {
+	return Dual{-a.Value, -a.Deriv}
+}
+
+// Scale returns c a.
+func (a Dual) Scale(c float64) Dual {
+	return Dual{c * a.Value, c * a.Deriv}
+}
+
+// Shift returns a + c.
+func (a Dual) Shift(c float64) Dual {
+	return Dual{a.Value + c, a.Deriv}
+}
+
+// Sin returns sin(a).
+func Sin(a Dual) Dual {
+	return Dual{math.Sin(a.Value), math.Cos(a.Value) * a.Deriv}
+}
+
+// Cos returns cos(a).
+func Cos(a Dual) Dual {
+	return Dual{math.Cos(a.Value), -math.Sin(a.Value) * a.Deriv}
+}
+
+// Tan returns tan(a).
+func Tan(a Dual) Dual {
+	t := math.Tan(a.Value)
+	return Dual{t, (1 + t*t) * a.Deriv}
+}
+
+// Atan returns arctan(a).
+func Atan(a Dual) Dual {
+	return Dual{math.Atan(a.Value), a.Deriv / (1 + a.Value*a.Value)}
+}
+
+// Exp returns e^a.
+func Exp(a Dual) Dual {
+	e := math.Exp(a.Value)
+	return Dual{e, e * a.Deriv}
+}
+
+// Log returns the natural logarithm of a.
+func Log(a Dual) Dual {
+	return Dual{math.Log(a.Value), a.Deriv / a.Value}
+}
+
+// Sqrt returns the square root of a.
+func Sqrt(a Dual) Dual {
+	s := math.Sqrt(a.Value)
+	return Dual{s, a.Deriv / (2 * s)}
+}
+
+// Tanh returns tanh(a).
+func Tanh(a Dual) Dual {
+	t := math.Tanh(a.Value)
+	return Dual{t, (1 - t*t) * a.Deriv}
+}
+
+// Pow returns a^p for a real exponent p.
+func Pow(a Dual, p float64) Dual {
+	return Dual{math.Pow(a.Value, p), p * math.Pow(a.Value, p-1) * a.Deriv}
+}
+
+// Abs returns |a|. The derivative at zero is taken to be zero.
+func Abs(a Dual) Dual {
+	switch {
+	case a.Value > 0:
+		return a
+	case a.Value < 0:
+		return a.Neg()
+	default:
+		return Dual{0, 0}
+	}
+}
+
+// Function converts a right-hand side written in terms of dual numbers into
+// one that operates on real numbers, which can be passed to Compute.
+//
+// The returned function reuses internal buffers and is therefore not safe for
+// concurrent use.
+func Function(dydx func(float64, []Dual, []Dual)) func(float64, []float64, []float64) {
+	var z, fz []Dual
+
+	return func(x float64, y, f []float64) {
+		nd := len(y)
+		if len(z) != nd {
+			z, fz = make([]Dual, nd), make([]Dual, nd)
+		}
+		for i := range z {
+			z[i] = Constant(y[i])
+		}
+		dydx(x, z, fz)
+		for i := range f {
+			f[i] = fz[i].Value
+		}
+	}
+}
+
+// Jacobian converts a right-hand side written in terms of dual numbers into
+// the exact Jacobian matrix of the right-hand side, which can be assigned to
+// the Jacobian field of the configuration of an implicit integrator. Each
+// matrix takes nd evaluations of dydx.
+//
+// The returned function reuses internal buffers and is therefore not safe for
+// concurrent use.
+func Jacobian(dydx func(float64, []Dual, []Dual)) ode.Jacobian {
+	var z, fz []Dual
+
+	return func(x float64, y, J []float64) {
+		nd := len(y)
+		if len(z) != nd {
+			z, fz = make([]Dual, nd), make([]Dual, nd)
+		}
+		for i := range z {
+			z[i] = Constant(y[i])
+		}
+		for j := 0; j < nd; j++ {
+			z[j].Deriv = 1
+			dydx(x, z, fz)
+			for i := 0; i < nd; i++ {
+				J[i*nd+j] = fz[i].Deriv
+			}
+			z[j].Deriv = 0
+		}
+	}
+}
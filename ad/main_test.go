@@ -0,0 +1,54 @@
+package ad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/radau"
+)
+
+func TestDual(t *testing.T) {
+	a := Variable(0.5)
+
+	b := Sin(a).Mul(Exp(a)).Div(a.Shift(1))
+	assert.Close(b.Value, math.Sin(0.5)*math.Exp(0.5)/1.5, 1e-15, t)
+	assert.Close(b.Deriv, math.Exp(0.5)*((math.Cos(0.5)+math.Sin(0.5))*1.5-math.Sin(0.5))/2.25, 1e-15, t)
+
+	b = Pow(a, 3).Sub(Sqrt(a).Scale(2))
+	assert.Close(b.Deriv, 3*0.25-1/math.Sqrt(0.5), 1e-15, t)
+}
+
+func TestJacobian(t *testing.T) {
+	dydx := func(_ float64, y, f []Dual) {
+		f[0] = y[0].Mul(y[1])
+		f[1] = y[0].Add(y[1].Scale(3))
+	}
+
+	J := make([]float64, 4)
+	Jacobian(dydx)(0, []float64{2, -1}, J)
+
+	assert.Equal(J, []float64{-1, 2, 1, 3}, t)
+}
+
+func TestComputeRobertson(t *testing.T) {
+	dydx := func(_ float64, y, f []Dual) {
+		f[0] = y[0].Scale(-0.04).Add(y[1].Mul(y[2]).Scale(1e4))
+		f[1] = y[0].Scale(0.04).Sub(y[1].Mul(y[2]).Scale(1e4)).Sub(y[1].Mul(y[1]).Scale(3e7))
+		f[2] = y[1].Mul(y[1]).Scale(3e7)
+	}
+
+	config := radau.DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-8
+	config.Jacobian = Jacobian(dydx)
+
+	integrator, _ := radau.New(config)
+
+	ys, _, err := integrator.Compute(Function(dydx), []float64{1, 0, 0}, []float64{0, 40})
+	assert.Equal(err, nil, t)
+
+	n := len(ys)
+	expected := []float64{7.158270687193e-01, 9.185534764529e-06, 2.841637457460e-01}
+	assert.Close(ys[n-3:], expected, 1e-8, t)
+}
@@ -0,0 +1,56 @@
+package adams
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The initial step of integration.
+	TryStep float64 `json:"tryStep"`
+	// The maximal step of integration.
+	MaxStep float64 `json:"maxStep"`
+	// The absolute error tolerance.
+	AbsError float64 `json:"absError"`
+	// The relative error tolerance.
+	RelError float64 `json:"relError"`
+	// The maximal order of the method, which is at most 12.
+	MaxOrder uint `json:"maxOrder"`
+}
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return &Config{
+		TryStep:  0,
+		MaxStep:  0,
+		AbsError: 1e-6,
+		RelError: 1e-3,
+		MaxOrder: 12,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.TryStep < 0 {
+		return errors.New("the initial step should be nonnegative")
+	}
+	if c.MaxStep < 0 {
+		return errors.New("the maximal step should be nonnegative")
+	}
+	if c.AbsError <= 0 {
+		return errors.New("the absolute error tolerance should be positive")
+	}
+	if c.RelError <= 0 {
+		return errors.New("the relative error tolerance should be positive")
+	}
+	if c.MaxOrder < 1 || c.MaxOrder > maxOrder {
+		return errors.New("the maximal order should be between 1 and 12")
+	}
+
+	return nil
+}
@@ -0,0 +1,375 @@
+// Package adams provides an integrator of nonstiff systems of ordinary
+// differential equations based on the variable-order variable-step
+// Adams–Bashforth–Moulton method applied in the predict-evaluate-correct-
+// evaluate mode, which is the approach behind ode113 in MATLAB.
+//
+// Each step requires two evaluations of the derivative regardless of the
+// order, which makes the method efficient for expensive right-hand sides and
+// stringent tolerances. The coefficients are computed for the actual history
+// of steps by integrating the polynomials interpolating the derivative.
+//
+// https://en.wikipedia.org/wiki/Linear_multistep_method
+package adams
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+const (
+	maxOrder = 12
+)
+
+// The Gauss–Legendre quadrature rule with seven nodes on [0, 1], which is exact
+// for the polynomials arising for the orders up to twelve.
+var (
+	gaussNodes = [...]float64{
+		(1 - 0.9491079123427585) / 2,
+		(1 - 0.7415311855993945) / 2,
+		(1 - 0.4058451513773972) / 2,
+		0.5,
+		(1 + 0.4058451513773972) / 2,
+		(1 + 0.7415311855993945) / 2,
+		(1 + 0.9491079123427585) / 2,
+	}
+	gaussWeights = [...]float64{
+		0.1294849661688697 / 2,
+		0.2797053914892766 / 2,
+		0.3818300505051189 / 2,
+		0.4179591836734694 / 2,
+		0.3818300505051189 / 2,
+		0.2797053914892766 / 2,
+		0.1294849661688697 / 2,
+	}
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	config := &self.config
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	y := make([]float64, nd)
+	ynew := make([]float64, nd)
+	yp := make([]float64, nd)
+	fp := make([]float64, nd)
+
+	// The history of the derivative with the most recent value first.
+	fh := make([][]float64, maxOrder+1)
+	for i := range fh {
+		fh[i] = make([]float64, nd)
+	}
+	xh := make([]float64, maxOrder+1)
+	nh := uint(0)
+
+	// The nodes of the predictor are stored as the tail of the ones of the
+	// corrector, which starts with the new point.
+	cnodes := make([]float64, maxOrder+2)
+	pnodes := cnodes[1:]
+	w := make([]float64, maxOrder+2)
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	dydx(x, y, fh[0])
+	xh[0], nh = x, 1
+	stats.Evaluations++
+
+	relerr := config.RelError
+	threshold := config.AbsError / relerr
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = 0.1 * (xend - x)
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = xs[1] - x
+		if h > hmax {
+			h = hmax
+		}
+
+		scale := 0.0
+		for i := 0; i < nd; i++ {
+			s := math.Abs(fh[0][i]) / math.Max(math.Abs(y[i]), threshold)
+			if s > scale {
+				scale = s
+			}
+		}
+		scale = scale / (0.8 * math.Sqrt(relerr))
+
+		if h*scale > 1 {
+			h = 1 / scale
+		}
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	predict := func(q uint, h float64, yp []float64) {
+		for j := uint(0); j < q; j++ {
+			pnodes[j] = (xh[j] - x) / h
+		}
+		integrate(pnodes[:q], 1, w)
+		for i := 0; i < nd; i++ {
+			yp[i] = y[i]
+			for j := uint(0); j < q; j++ {
+				yp[i] += h * w[j] * fh[j][i]
+			}
+		}
+	}
+
+	measure := func(yp []float64) float64 {
+		ε := 0.0
+		for i := 0; i < nd; i++ {
+			scale := math.Max(math.Max(math.Abs(y[i]), math.Abs(ynew[i])), threshold)
+			if e := math.Abs(ynew[i]-yp[i]) / scale; e > ε {
+				ε = e
+			}
+		}
+		return ε
+	}
+
+	k := uint(1)
+
+	for done := false; ; {
+		var xnew, ε float64
+
+		stats.Steps++
+
+		hmin := 16 * epsilon(x)
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		// Close to the end?
+		if 1.1*h >= xend-x {
+			h = xend - x
+			done = true
+		}
+
+		rejected := false
+
+		for {
+			xnew = x + h
+
+			// Predict
+			predict(k, h, yp)
+
+			// Evaluate
+			dydx(xnew, yp, fp)
+			stats.Evaluations++
+
+			// Correct
+			cnodes[0] = 1
+			integrate(cnodes[:k+1], 1, w)
+			for i := 0; i < nd; i++ {
+				ynew[i] = y[i] + h*w[0]*fp[i]
+				for j := uint(0); j < k; j++ {
+					ynew[i] += h * w[j+1] * fh[j][i]
+				}
+			}
+
+			// Compute the relative error.
+			if ε = measure(yp); ε <= relerr {
+				break
+			}
+
+			stats.Rejections++
+
+			if h <= hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			// Shrink the step size and possibly the order as the current step
+			// has been rejected.
+			if rejected {
+				h = 0.5 * h
+				if k > 1 {
+					k--
+				}
+			} else {
+				h = h * math.Max(0.1, 0.9*math.Pow(relerr/ε, 1/float64(k+1)))
+			}
+
+			if h < hmin {
+				h = hmin
+			}
+
+			done = false
+			rejected = true
+		}
+
+		if fixed {
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					copy(ys[nc*nd:(nc+1)*nd], ynew)
+				} else {
+					interpolate(y, h, cnodes[:k+1], fp, fh, (xs[nc]-x)/h, w,
+						ys[nc*nd:(nc+1)*nd])
+				}
+
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		if done {
+			break
+		}
+
+		// Choose the order of the next step by comparing the predictors of the
+		// neighboring orders with the corrector.
+		order, best := k, math.Pow(relerr/ε, 1/float64(k+1))
+		if k > 1 {
+			predict(k-1, h, yp)
+			if factor := math.Pow(relerr/measure(yp), 1/float64(k)); factor > best {
+				order, best = k-1, factor
+			}
+		}
+		if !rejected && k < config.MaxOrder && k < nh {
+			predict(k+1, h, yp)
+			if factor := math.Pow(relerr/measure(yp), 1/float64(k+2)); factor > best {
+				order, best = k+1, factor
+			}
+		}
+		k = order
+
+		// Shift the history.
+		last := fh[maxOrder]
+		copy(fh[1:], fh[:maxOrder])
+		copy(xh[1:], xh[:maxOrder])
+		fh[0] = last
+		if nh <= maxOrder {
+			nh++
+		}
+
+		// Evaluate
+		x = xnew
+		copy(y, ynew)
+		dydx(x, y, fh[0])
+		xh[0] = x
+		stats.Evaluations++
+
+		// Compute a new step size.
+		scale := math.Min(2, 0.9*best)
+		if rejected && scale > 1 {
+			scale = 1
+		}
+		h = h * scale
+	}
+
+	return ys, xs, stats, nil
+}
+
+// integrate computes the integrals of the Lagrange basis polynomials of the
+// given nodes over [0, upper].
+func integrate(nodes []float64, upper float64, w []float64) {
+	for j := range nodes {
+		w[j] = 0
+	}
+	for g := range gaussNodes {
+		u := upper * gaussNodes[g]
+		for j := range nodes {
+			l := 1.0
+			for m := range nodes {
+				if m != j {
+					l *= (u - nodes[m]) / (nodes[j] - nodes[m])
+				}
+			}
+			w[j] += upper * gaussWeights[g] * l
+		}
+	}
+}
+
+func interpolate(y []float64, h float64, nodes, fp []float64, fh [][]float64,
+	s float64, w, ynext []float64) {
+
+	k := len(nodes) - 1
+
+	integrate(nodes, s, w)
+	for i := range ynext {
+		ynext[i] = y[i] + h*w[0]*fp[i]
+		for j := 0; j < k; j++ {
+			ynext[i] += h * w[j+1] * fh[j][i]
+		}
+	}
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
@@ -0,0 +1,20 @@
+package adams
+
+import (
+	"github.com/ready-steady/ode"
+)
+
+func init() {
+	ode.Register("adams", func(options *ode.Options) (ode.Integrator, error) {
+		config := DefaultConfig()
+		options.Adapt(&config.TryStep, &config.MaxStep, &config.AbsError, &config.RelError)
+		integrator, err := New(config)
+		if err != nil {
+			return nil, err
+		}
+		return integrator, nil
+	})
+	ode.RegisterDecoder("adams", func(config []byte) (ode.Integrator, error) {
+		return ode.Decode(config, DefaultConfig(), New)
+	})
+}
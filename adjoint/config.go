@@ -0,0 +1,47 @@
+package adjoint
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The integrator used for both the forward and the adjoint systems.
+	Integrator ode.Integrator `json:"-"`
+	// The Jacobian matrix of the right-hand side with respect to the solution,
+	// which is stored in row-major order; see ode.Jacobian. If it is not
+	// given, it is approximated using finite differences.
+	JacobianY func(x float64, y, p, J []float64) `json:"-"`
+	// The Jacobian matrix of the right-hand side with respect to the
+	// parameters, which is an nd-by-np matrix stored in row-major order. If it
+	// is not given, it is approximated using finite differences.
+	JacobianP func(x float64, y, p, J []float64) `json:"-"`
+	// The number of segments into which the interval of integration is split
+	// by checkpoints.
+	Checkpoints uint `json:"checkpoints"`
+	// The number of intervals per segment at which the forward solution is
+	// stored when the segment is recomputed for the adjoint system.
+	Points uint `json:"points"`
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Integrator == nil {
+		return errors.New("the integrator should be given")
+	}
+	if c.Checkpoints == 0 {
+		return errors.New("the number of checkpoints should be positive")
+	}
+	if c.Points == 0 {
+		return errors.New("the number of points should be positive")
+	}
+
+	return nil
+}
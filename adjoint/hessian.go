@@ -0,0 +1,320 @@
+package adjoint
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode"
+)
+
+// ComputeHessian augments Compute by computing, in addition to the gradient,
+// the product of the Hessian matrix of the functional with respect to the
+// initial condition and the parameters with a direction (vy, vp).
+//
+// The product is obtained using second-order adjoints. The forward system is
+// augmented with the tangent s = ∂y/∂y₀ vy + ∂y/∂p vp, which starts from vy,
+// and the adjoint system is augmented with the second-order adjoint variables
+//
+//	σ′ = -(∂f/∂y)ᵀ σ - (∂²f/∂y² s + ∂²f/∂y∂p vp)ᵀ λ and
+//	ν′ = -(∂f/∂p)ᵀ σ - (∂²f/∂p∂y s + ∂²f/∂p² vp)ᵀ λ,
+//
+// which are integrated backward from σ(xend) = ∂²g/∂y² s + ∂²g/∂y∂p vp and
+// ν(xend) = 0. The right-hand side of the tangent system and the products with
+// the second derivatives of f and g are approximated using central differences
+// of f, the Jacobian matrices, and the gradient of g, respectively, along the
+// direction (s, vp); the Jacobian matrices themselves are approximated using
+// central differences if they are not given. Either vy or vp can be nil, which
+// stands for zero.
+//
+// The function returns the final state, the gradients with respect to y0 and
+// p, and the corresponding blocks of the Hessian-vector product.
+func (self *Integrator) ComputeHessian(dydx ode.Parametric,
+	g func([]float64, []float64, []float64, []float64), y0, p, vy, vp,
+	xs []float64) ([]float64, []float64, []float64, []float64, []float64, error) {
+
+	yend, gy, gp, hy, hp, _, err := self.ComputeHessianWithStats(dydx, g, y0, p, vy, vp, xs)
+
+	return yend, gy, gp, hy, hp, err
+}
+
+// ComputeHessianWithStats augments ComputeHessian by providing additional
+// information about the solution process.
+func (self *Integrator) ComputeHessianWithStats(dydx ode.Parametric,
+	g func([]float64, []float64, []float64, []float64), y0, p, vy, vp,
+	xs []float64) ([]float64, []float64, []float64, []float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	config := &self.config
+
+	nx := len(xs)
+	if nx < 2 {
+		return nil, nil, nil, nil, nil, stats, errors.New("the interval should have two endpoints")
+	}
+
+	nd, np := len(y0), len(p)
+	nk, nm := int(config.Checkpoints), int(config.Points)
+
+	if vy == nil {
+		vy = make([]float64, nd)
+	} else if len(vy) != nd {
+		return nil, nil, nil, nil, nil, stats, errors.New("the direction should match the dimensions of the system")
+	}
+	if vp == nil {
+		vp = make([]float64, np)
+	} else if len(vp) != np {
+		return nil, nil, nil, nil, nil, stats, errors.New("the direction should match the dimensions of the system")
+	}
+
+	x0, xend := xs[0], xs[nx-1]
+
+	p = append([]float64(nil), p...)
+
+	third := math.Cbrt(epsilon)
+	fourth := math.Sqrt(math.Sqrt(epsilon))
+
+	// step returns the perturbation along the direction (s, vp) of the point
+	// (y, p), which is zero if the direction is zero.
+	step := func(η float64, y, s []float64) float64 {
+		scale, size := 0.0, 0.0
+		for i := range y {
+			scale = math.Max(scale, math.Abs(y[i]))
+			size = math.Max(size, math.Abs(s[i]))
+		}
+		for j := range p {
+			scale = math.Max(scale, math.Abs(p[j]))
+			size = math.Max(size, math.Abs(vp[j]))
+		}
+		if size == 0 {
+			return 0
+		}
+		return η * (1 + scale) / size
+	}
+
+	yplus, yminus := make([]float64, nd), make([]float64, nd)
+	qplus, qminus := make([]float64, np), make([]float64, np)
+
+	// perturb stores the points (y ± ε s, p ± ε vp) in yplus, qplus, yminus,
+	// and qminus.
+	perturb := func(ε float64, y, s []float64) {
+		for i := 0; i < nd; i++ {
+			yplus[i], yminus[i] = y[i]+ε*s[i], y[i]-ε*s[i]
+		}
+		for j := 0; j < np; j++ {
+			qplus[j], qminus[j] = p[j]+ε*vp[j], p[j]-ε*vp[j]
+		}
+	}
+
+	fplus, fminus := make([]float64, nd), make([]float64, nd)
+
+	// tangent evaluates the right-hand side of the forward system augmented
+	// with the tangent.
+	tangent := func(x float64, z, dz []float64) {
+		y, s := z[:nd], z[nd:]
+		f, ds := dz[:nd], dz[nd:]
+
+		dydx(x, y, p, f)
+		stats.Evaluations++
+
+		ε := step(third, y, s)
+		if ε == 0 {
+			for i := range ds {
+				ds[i] = 0
+			}
+			return
+		}
+		perturb(ε, y, s)
+		dydx(x, yplus, qplus, fplus)
+		dydx(x, yminus, qminus, fminus)
+		stats.Evaluations += 2
+		for i := 0; i < nd; i++ {
+			ds[i] = (fplus[i] - fminus[i]) / (2 * ε)
+		}
+	}
+
+	// Compute the checkpoints.
+	bounds := make([]float64, nk+1)
+	for k := range bounds {
+		bounds[k] = x0 + (xend-x0)*float64(k)/float64(nk)
+	}
+	bounds[nk] = xend
+
+	checkpoints := make([]float64, (nk+1)*2*nd)
+	copy(checkpoints, y0)
+	copy(checkpoints[nd:], vy)
+	for k := 0; k < nk; k++ {
+		zs, _, err := config.Integrator.Compute(tangent, checkpoints[k*2*nd:(k+1)*2*nd],
+			[]float64{bounds[k], bounds[k+1]})
+		if err != nil {
+			return nil, nil, nil, nil, nil, stats, err
+		}
+		copy(checkpoints[(k+1)*2*nd:(k+2)*2*nd], zs[len(zs)-2*nd:])
+	}
+
+	yend := append([]float64(nil), checkpoints[nk*2*nd:nk*2*nd+nd]...)
+	send := checkpoints[nk*2*nd+nd:]
+
+	// Initialize the adjoint variables.
+	gy := make([]float64, nd)
+	gp := make([]float64, np)
+	g(yend, p, gy, gp)
+
+	u := make([]float64, 2*(nd+np))
+	copy(u, gy)
+
+	hp := make([]float64, np)
+	if ε := step(third, yend, send); ε != 0 {
+		gyplus, gpplus := make([]float64, nd), make([]float64, np)
+		gyminus, gpminus := make([]float64, nd), make([]float64, np)
+		perturb(ε, yend, send)
+		g(yplus, qplus, gyplus, gpplus)
+		g(yminus, qminus, gyminus, gpminus)
+		for i := 0; i < nd; i++ {
+			u[nd+i] = (gyplus[i] - gyminus[i]) / (2 * ε)
+		}
+		for j := 0; j < np; j++ {
+			hp[j] = (gpplus[j] - gpminus[j]) / (2 * ε)
+		}
+	}
+
+	grid := make([]float64, nm+1)
+	Z := make([]float64, (nm+1)*2*nd)
+	F := make([]float64, (nm+1)*2*nd)
+
+	z := make([]float64, 2*nd)
+	w := make([]float64, nd)
+	q := make([]float64, np)
+	Jy := make([]float64, nd*nd)
+	Jp := make([]float64, nd*np)
+
+	// gradient computes the products of the transposed Jacobian matrices at
+	// (y, r) with λ and stores them in the first nd and the last np entries of
+	// out, respectively.
+	gradient := func(x float64, y, r, λ, out []float64) {
+		if config.JacobianY != nil {
+			config.JacobianY(x, y, r, Jy)
+			for j := 0; j < nd; j++ {
+				sum := 0.0
+				for i := 0; i < nd; i++ {
+					sum += Jy[i*nd+j] * λ[i]
+				}
+				out[j] = sum
+			}
+		} else {
+			copy(w, y)
+			for j := 0; j < nd; j++ {
+				δ := fourth * math.Max(math.Abs(y[j]), 1)
+				w[j] = y[j] + δ
+				dydx(x, w, r, fplus)
+				w[j] = y[j] - δ
+				dydx(x, w, r, fminus)
+				δ = (y[j] + δ) - (y[j] - δ)
+				w[j] = y[j]
+				sum := 0.0
+				for i := 0; i < nd; i++ {
+					sum += (fplus[i] - fminus[i]) * λ[i]
+				}
+				out[j] = sum / δ
+			}
+			stats.Evaluations += uint(2 * nd)
+		}
+		if config.JacobianP != nil {
+			config.JacobianP(x, y, r, Jp)
+			for j := 0; j < np; j++ {
+				sum := 0.0
+				for i := 0; i < nd; i++ {
+					sum += Jp[i*np+j] * λ[i]
+				}
+				out[nd+j] = sum
+			}
+		} else if np > 0 {
+			copy(q, r)
+			for j := 0; j < np; j++ {
+				δ := fourth * math.Max(math.Abs(r[j]), 1)
+				q[j] = r[j] + δ
+				dydx(x, y, q, fplus)
+				q[j] = r[j] - δ
+				dydx(x, y, q, fminus)
+				δ = (r[j] + δ) - (r[j] - δ)
+				q[j] = r[j]
+				sum := 0.0
+				for i := 0; i < nd; i++ {
+					sum += (fplus[i] - fminus[i]) * λ[i]
+				}
+				out[nd+j] = sum / δ
+			}
+			stats.Evaluations += uint(2 * np)
+		}
+		stats.Jacobians++
+	}
+
+	first, other := make([]float64, nd+np), make([]float64, nd+np)
+	plus, minus := make([]float64, nd+np), make([]float64, nd+np)
+
+	var a, h float64
+
+	// adjoint evaluates the right-hand side of the adjoint system augmented
+	// with the second-order adjoint variables with respect to s = -x.
+	adjoint := func(s float64, u, du []float64) {
+		x := -s
+
+		interpolate(grid, Z, F, a, h, x, z)
+		y, t := z[:nd], z[nd:]
+		λ, σ := u[:nd], u[nd:2*nd]
+
+		gradient(x, y, p, λ, first)
+		gradient(x, y, p, σ, other)
+
+		if ε := step(fourth, y, t); ε != 0 {
+			perturb(ε, y, t)
+			gradient(x, yplus, qplus, λ, plus)
+			gradient(x, yminus, qminus, λ, minus)
+			for j := range other {
+				other[j] += (plus[j] - minus[j]) / (2 * ε)
+			}
+		}
+
+		copy(du[:nd], first[:nd])
+		copy(du[nd:2*nd], other[:nd])
+		copy(du[2*nd:2*nd+np], first[nd:])
+		copy(du[2*nd+np:], other[nd:])
+	}
+
+	for k := nk - 1; k >= 0; k-- {
+		a, h = bounds[k], (bounds[k+1]-bounds[k])/float64(nm)
+		for i := range grid {
+			grid[i] = a + h*float64(i)
+		}
+		grid[nm] = bounds[k+1]
+
+		// Recompute the forward solution and the tangent over the segment
+		// unless they are already known at the points of the grid.
+		if nm == 1 {
+			copy(Z, checkpoints[k*2*nd:(k+2)*2*nd])
+		} else {
+			zs, _, err := config.Integrator.Compute(tangent, checkpoints[k*2*nd:(k+1)*2*nd], grid)
+			if err != nil {
+				return nil, nil, nil, nil, nil, stats, err
+			}
+			copy(Z, zs)
+		}
+		for i := range grid {
+			tangent(grid[i], Z[i*2*nd:(i+1)*2*nd], F[i*2*nd:(i+1)*2*nd])
+		}
+
+		us, _, err := config.Integrator.Compute(adjoint, u, []float64{-bounds[k+1], -bounds[k]})
+		if err != nil {
+			return nil, nil, nil, nil, nil, stats, err
+		}
+		copy(u, us[len(us)-2*(nd+np):])
+	}
+
+	for j := 0; j < np; j++ {
+		gp[j] += u[2*nd+j]
+		hp[j] += u[2*nd+np+j]
+	}
+	copy(gy, u[:nd])
+	hy := append([]float64(nil), u[nd:2*nd]...)
+
+	return yend, gy, gp, hy, hp, stats, nil
+}
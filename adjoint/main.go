@@ -0,0 +1,262 @@
+// Package adjoint provides an integrator of systems of ordinary differential
+// equations dy/dx = f(x, y, p) that computes the gradient of a functional
+// G = g(y(xend), p) of the solution with respect to the initial condition and
+// the parameters p.
+//
+// The gradient is obtained by integrating the adjoint system
+//
+//	λ′ = -(∂f/∂y)ᵀ λ and μ′ = -(∂f/∂p)ᵀ λ
+//
+// backward from λ(xend) = ∂g/∂y and μ(xend) = 0, after which ∂G/∂y₀ = λ(x₀) and
+// ∂G/∂p = μ(x₀) + ∂g/∂p. The cost is independent of the number of parameters,
+// which makes the approach preferable to forward sensitivity analysis when
+// there are many parameters and few functionals.
+//
+// The adjoint system depends on the forward solution. In order to bound the
+// memory, the forward pass stores the solution only at the boundaries of a
+// number of segments, which are the checkpoints. During the backward pass,
+// the forward solution is recomputed segment by segment starting from the
+// checkpoints, stored at a number of equidistant points, and interpolated
+// between them using cubic Hermite polynomials. The adjoint system is
+// integrated in the reversed variable s = -x so that any integrator can be
+// used.
+//
+// Products of the Hessian matrix of G with a direction, which are needed by
+// Newton-type optimization methods, are obtained using second-order adjoints;
+// see Integrator.ComputeHessian.
+//
+// https://en.wikipedia.org/wiki/Adjoint_state_method
+package adjoint
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/internal/jacobian"
+)
+
+const (
+	epsilon = 2.220446049250313e-16
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y, p)
+// and computes the gradient of a functional of the final state.
+//
+// The input function dydx(x, y, p, f) evaluates f(x, y, p) and stores the
+// result in its last argument; see ode.Parametric. The function g(y, p, gy, gp) evaluates the
+// partial derivatives of the functional with respect to the final state y and
+// the parameters p and stores them in gy and gp, respectively. The initial
+// condition is y0, and the interval of integration is [x0, xend] where x0 and
+// xend are the first and last entries of xs, respectively.
+//
+// The function returns the final state, the gradient with respect to y0, and
+// the gradient with respect to p.
+func (self *Integrator) Compute(dydx ode.Parametric,
+	g func([]float64, []float64, []float64, []float64), y0, p, xs []float64) ([]float64,
+	[]float64, []float64, error) {
+
+	yend, gy, gp, _, err := self.ComputeWithStats(dydx, g, y0, p, xs)
+
+	return yend, gy, gp, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process. The evaluations performed by the inner integrator are
+// counted.
+func (self *Integrator) ComputeWithStats(dydx ode.Parametric,
+	g func([]float64, []float64, []float64, []float64), y0, p, xs []float64) ([]float64,
+	[]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	config := &self.config
+
+	nx := len(xs)
+	if nx < 2 {
+		return nil, nil, nil, stats, errors.New("the interval should have two endpoints")
+	}
+
+	nd, np := len(y0), len(p)
+	nk, nm := int(config.Checkpoints), int(config.Points)
+
+	x0, xend := xs[0], xs[nx-1]
+
+	p = append([]float64(nil), p...)
+
+	forward := func(x float64, y, f []float64) {
+		dydx(x, y, p, f)
+		stats.Evaluations++
+	}
+
+	// Compute the checkpoints.
+	bounds := make([]float64, nk+1)
+	for k := range bounds {
+		bounds[k] = x0 + (xend-x0)*float64(k)/float64(nk)
+	}
+	bounds[nk] = xend
+
+	checkpoints := make([]float64, (nk+1)*nd)
+	copy(checkpoints, y0)
+	for k := 0; k < nk; k++ {
+		ys, _, err := config.Integrator.Compute(forward, checkpoints[k*nd:(k+1)*nd],
+			[]float64{bounds[k], bounds[k+1]})
+		if err != nil {
+			return nil, nil, nil, stats, err
+		}
+		copy(checkpoints[(k+1)*nd:(k+2)*nd], ys[len(ys)-nd:])
+	}
+
+	yend := append([]float64(nil), checkpoints[nk*nd:]...)
+
+	// Initialize the adjoint variables.
+	gy := make([]float64, nd)
+	gp := make([]float64, np)
+	g(yend, p, gy, gp)
+
+	u := make([]float64, nd+np)
+	copy(u, gy)
+
+	grid := make([]float64, nm+1)
+	Y := make([]float64, (nm+1)*nd)
+	F := make([]float64, (nm+1)*nd)
+
+	y := make([]float64, nd)
+	f := make([]float64, nd)
+	q := make([]float64, np)
+	fq := make([]float64, nd)
+	Jy := make([]float64, nd*nd)
+	Jp := make([]float64, nd*np)
+
+	approximator := jacobian.New(uint(nd), nil)
+
+	var a, h float64
+
+	// adjoint evaluates the right-hand side of the adjoint system with respect
+	// to s = -x, in which the system reads λ′ = (∂f/∂y)ᵀ λ and μ′ = (∂f/∂p)ᵀ λ.
+	adjoint := func(s float64, u, du []float64) {
+		x := -s
+
+		interpolate(grid, Y, F, a, h, x, y)
+
+		if config.JacobianY != nil {
+			config.JacobianY(x, y, p, Jy)
+		} else {
+			forward(x, y, f)
+			approximator.Compute(forward, x, y, f, Jy)
+		}
+		if config.JacobianP != nil {
+			config.JacobianP(x, y, p, Jp)
+		} else if np > 0 {
+			if config.JacobianY != nil {
+				forward(x, y, f)
+			}
+			copy(q, p)
+			for j := 0; j < np; j++ {
+				δ := math.Sqrt(epsilon) * math.Max(math.Abs(p[j]), 1)
+				q[j] = p[j] + δ
+				δ = q[j] - p[j]
+				dydx(x, y, q, fq)
+				stats.Evaluations++
+				for i := 0; i < nd; i++ {
+					Jp[i*np+j] = (fq[i] - f[i]) / δ
+				}
+				q[j] = p[j]
+			}
+		}
+		stats.Jacobians++
+
+		λ := u[:nd]
+		for j := 0; j < nd; j++ {
+			sum := 0.0
+			for i := 0; i < nd; i++ {
+				sum += Jy[i*nd+j] * λ[i]
+			}
+			du[j] = sum
+		}
+		for j := 0; j < np; j++ {
+			sum := 0.0
+			for i := 0; i < nd; i++ {
+				sum += Jp[i*np+j] * λ[i]
+			}
+			du[nd+j] = sum
+		}
+	}
+
+	for k := nk - 1; k >= 0; k-- {
+		a, h = bounds[k], (bounds[k+1]-bounds[k])/float64(nm)
+		for i := range grid {
+			grid[i] = a + h*float64(i)
+		}
+		grid[nm] = bounds[k+1]
+
+		// Recompute the forward solution over the segment unless it is
+		// already known at the points of the grid.
+		if nm == 1 {
+			copy(Y, checkpoints[k*nd:(k+2)*nd])
+		} else {
+			ys, _, err := config.Integrator.Compute(forward, checkpoints[k*nd:(k+1)*nd], grid)
+			if err != nil {
+				return nil, nil, nil, stats, err
+			}
+			copy(Y, ys)
+		}
+		for i := range grid {
+			forward(grid[i], Y[i*nd:(i+1)*nd], F[i*nd:(i+1)*nd])
+		}
+
+		us, _, err := config.Integrator.Compute(adjoint, u, []float64{-bounds[k+1], -bounds[k]})
+		if err != nil {
+			return nil, nil, nil, stats, err
+		}
+		copy(u, us[len(us)-(nd+np):])
+	}
+
+	for j := 0; j < np; j++ {
+		gp[j] += u[nd+j]
+	}
+	copy(gy, u[:nd])
+
+	return yend, gy, gp, stats, nil
+}
+
+// interpolate evaluates the cubic Hermite interpolant of the solution at x
+// given the values Y and the derivatives F at the equidistant points of grid,
+// which starts at a and has step h.
+func interpolate(grid, Y, F []float64, a, h, x float64, y []float64) {
+	nd, nm := len(y), len(grid)-1
+
+	i := int((x - a) / h)
+	if i < 0 {
+		i = 0
+	} else if i >= nm {
+		i = nm - 1
+	}
+
+	h = grid[i+1] - grid[i]
+	θ := (x - grid[i]) / h
+
+	h00 := (1 + 2*θ) * (1 - θ) * (1 - θ)
+	h10 := θ * (1 - θ) * (1 - θ)
+	h01 := θ * θ * (3 - 2*θ)
+	h11 := θ * θ * (θ - 1)
+
+	y0, y1 := Y[i*nd:(i+1)*nd], Y[(i+1)*nd:(i+2)*nd]
+	f0, f1 := F[i*nd:(i+1)*nd], F[(i+1)*nd:(i+2)*nd]
+	for j := 0; j < nd; j++ {
+		y[j] = h00*y0[j] + h10*h*f0[j] + h01*y1[j] + h11*h*f1[j]
+	}
+}
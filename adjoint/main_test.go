@@ -0,0 +1,190 @@
+package adjoint
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/dopri"
+)
+
+func TestComputeDecay(t *testing.T) {
+	// y′ = -a y and G = y(xend).
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = -p[0] * y[0]
+	}
+	g := func(_, _, gy, gp []float64) {
+		gy[0], gp[0] = 1, 0
+	}
+
+	a, b, xend := 2.0, 3.0, 1.5
+
+	inner, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-10})
+	integrator, _ := New(&Config{Integrator: inner, Checkpoints: 4, Points: 20})
+
+	yend, gy, gp, err := integrator.Compute(dydx, g, []float64{b}, []float64{a},
+		[]float64{0, xend})
+	assert.Equal(err, nil, t)
+
+	e := math.Exp(-a * xend)
+	assert.Close(yend[0], b*e, 1e-9, t)
+	assert.Close(gy[0], e, 1e-7, t)
+	assert.Close(gp[0], -xend*b*e, 1e-6, t)
+}
+
+func TestComputeLotkaVolterra(t *testing.T) {
+	// G = y₁(xend)² / 2 + p₀, compared with finite differences of the
+	// solution.
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = p[0]*y[0] - p[1]*y[0]*y[1]
+		f[1] = p[1]*y[0]*y[1] - p[2]*y[1]
+	}
+	jacobianY := func(_ float64, y, p, J []float64) {
+		J[0], J[1] = p[0]-p[1]*y[1], -p[1]*y[0]
+		J[2], J[3] = p[1]*y[1], p[1]*y[0]-p[2]
+	}
+	g := func(y, _, gy, gp []float64) {
+		gy[0], gy[1] = 0, y[1]
+		gp[0], gp[1], gp[2] = 1, 0, 0
+	}
+
+	y0, p := []float64{1, 0.5}, []float64{1.1, 0.4, 0.8}
+	xs := []float64{0, 5}
+
+	inner, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-12})
+
+	functional := func(y0, p []float64) float64 {
+		ys, _, _ := inner.Compute(func(x float64, y, f []float64) {
+			dydx(x, y, p, f)
+		}, y0, xs)
+		y := ys[len(ys)-2:]
+		return y[1]*y[1]/2 + p[0]
+	}
+
+	difference := func(v []float64, j int, G func() float64) float64 {
+		const δ = 1e-6
+		old := v[j]
+		v[j] = old + δ
+		gplus := G()
+		v[j] = old - δ
+		gminus := G()
+		v[j] = old
+		return (gplus - gminus) / (2 * δ)
+	}
+
+	G := func() float64 { return functional(y0, p) }
+
+	for _, J := range []func(float64, []float64, []float64, []float64){nil, jacobianY} {
+		integrator, _ := New(&Config{Integrator: inner, JacobianY: J, Checkpoints: 5,
+			Points: 50})
+
+		_, gy, gp, stats, err := integrator.ComputeWithStats(dydx, g, y0, p, xs)
+		assert.Equal(err, nil, t)
+		assert.Equal(stats.Jacobians > 0, true, t)
+
+		for j := range y0 {
+			assert.Close(gy[j], difference(y0, j, G), 1e-5, t)
+		}
+		for j := range p {
+			assert.Close(gp[j], difference(p, j, G), 1e-5, t)
+		}
+	}
+}
+
+func TestComputeHessianDecay(t *testing.T) {
+	// y′ = -a y and G = y(xend) = b exp(-a xend).
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = -p[0] * y[0]
+	}
+	jacobianY := func(_ float64, _, p, J []float64) {
+		J[0] = -p[0]
+	}
+	jacobianP := func(_ float64, y, _, J []float64) {
+		J[0] = -y[0]
+	}
+	g := func(_, _, gy, gp []float64) {
+		gy[0], gp[0] = 1, 0
+	}
+
+	a, b, xend := 2.0, 3.0, 1.5
+	e := math.Exp(-a * xend)
+
+	inner, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-10})
+
+	for _, J := range [][2]func(float64, []float64, []float64, []float64){
+		{nil, nil},
+		{jacobianY, jacobianP},
+	} {
+		integrator, _ := New(&Config{Integrator: inner, JacobianY: J[0], JacobianP: J[1],
+			Checkpoints: 4, Points: 20})
+
+		yend, gy, gp, hy, hp, err := integrator.ComputeHessian(dydx, g, []float64{b},
+			[]float64{a}, []float64{1}, nil, []float64{0, xend})
+		assert.Equal(err, nil, t)
+		assert.Close(yend[0], b*e, 1e-9, t)
+		assert.Close(gy[0], e, 1e-7, t)
+		assert.Close(gp[0], -xend*b*e, 1e-6, t)
+		assert.Close(hy[0], 0.0, 1e-7, t)
+		assert.Close(hp[0], -xend*e, 1e-6, t)
+
+		_, _, _, hy, hp, err = integrator.ComputeHessian(dydx, g, []float64{b},
+			[]float64{a}, nil, []float64{1}, []float64{0, xend})
+		assert.Equal(err, nil, t)
+		assert.Close(hy[0], -xend*e, 1e-6, t)
+		assert.Close(hp[0], xend*xend*b*e, 1e-6, t)
+	}
+}
+
+func TestComputeHessianLotkaVolterra(t *testing.T) {
+	// G = y₁(xend)² / 2 + p₀², compared with finite differences of the
+	// gradient.
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = p[0]*y[0] - p[1]*y[0]*y[1]
+		f[1] = p[1]*y[0]*y[1] - p[2]*y[1]
+	}
+	g := func(y, p, gy, gp []float64) {
+		gy[0], gy[1] = 0, y[1]
+		gp[0], gp[1], gp[2] = 2*p[0], 0, 0
+	}
+
+	y0, p := []float64{1, 0.5}, []float64{1.1, 0.4, 0.8}
+	vy, vp := []float64{0.3, -0.2}, []float64{0.5, 1, -0.4}
+	xs := []float64{0, 5}
+
+	inner, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-12})
+	integrator, _ := New(&Config{Integrator: inner, Checkpoints: 5, Points: 50})
+
+	_, gy, gp, hy, hp, stats, err := integrator.ComputeHessianWithStats(dydx, g, y0, p,
+		vy, vp, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Jacobians > 0, true, t)
+
+	_, gy1, gp1, err := integrator.Compute(dydx, g, y0, p, xs)
+	assert.Equal(err, nil, t)
+	assert.Close(gy, gy1, 1e-6, t)
+	assert.Close(gp, gp1, 1e-6, t)
+
+	const δ = 1e-4
+	shift := func(σ float64) ([]float64, []float64) {
+		y, q := make([]float64, len(y0)), make([]float64, len(p))
+		for i := range y0 {
+			y[i] = y0[i] + σ*vy[i]
+		}
+		for j := range p {
+			q[j] = p[j] + σ*vp[j]
+		}
+		_, gy, gp, _ := integrator.Compute(dydx, g, y, q, xs)
+		return gy, gp
+	}
+	gyplus, gpplus := shift(δ)
+	gyminus, gpminus := shift(-δ)
+	for i := range y0 {
+		assert.Close(hy[i], (gyplus[i]-gyminus[i])/(2*δ), 1e-3, t)
+	}
+	for j := range p {
+		assert.Close(hp[j], (gpplus[j]-gpminus[j])/(2*δ), 1e-3, t)
+	}
+
+	_, _, _, _, _, err = integrator.ComputeHessian(dydx, g, y0, p, vy[:1], vp, xs)
+	assert.Equal(err != nil, true, t)
+}
@@ -0,0 +1,7 @@
+package adjoint
+
+// Stats contains information about the work done by an integrator.
+type Stats struct {
+	Evaluations uint // The number of invocations of the derivative function.
+	Jacobians   uint // The number of evaluations of the Jacobian matrices.
+}
@@ -0,0 +1,306 @@
+// Package auto provides an integrator of systems of ordinary differential
+// equations that detects stiffness automatically and switches between the
+// Dormand–Prince method and the backward differentiation formulas
+// accordingly, in the spirit of LSODA.
+//
+// The interval of integration is traversed in segments, each of which is
+// expected to take a fixed number of steps of the current method. At the end
+// of each segment, the spectral radius ρ of the Jacobian matrix is estimated
+// and compared with the average step size h taken within the segment. The
+// integrator switches to the stiff method when hρ is close to the boundary of
+// the stability region of the nonstiff method, which means that the step size
+// is limited by stability rather than accuracy, and it switches back when hρ
+// drops well inside that region.
+//
+// https://en.wikipedia.org/wiki/Stiff_equation
+package auto
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/bdf"
+	"github.com/ready-steady/ode/dopri"
+	"github.com/ready-steady/ode/internal/interval"
+	"github.com/ready-steady/ode/internal/jacobian"
+)
+
+const (
+	segmentSteps = 25
+
+	stiffLimit    = 2.0
+	nonstiffLimit = 1.0
+
+	powerIterations = 20
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses. The integration is carried out only forward, since the
+// stiff method does not integrate backward, so the points of xs should be
+// strictly increasing; otherwise, an error is returned.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	config := &self.config
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	y := make([]float64, nd)
+	f := make([]float64, nd)
+	v := make([]float64, 2*nd)
+
+	J := make([]float64, nd*nd)
+	approximator := jacobian.New(uint(nd), nil)
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	dydx(x, y, f)
+	stats.Evaluations++
+
+	relerr := config.RelError
+	threshold := config.AbsError / relerr
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = xend - x
+
+		scale := 0.0
+		for i := 0; i < nd; i++ {
+			s := math.Abs(f[i]) / math.Max(math.Abs(y[i]), threshold)
+			if s > scale {
+				scale = s
+			}
+		}
+		scale = scale / (0.8 * math.Pow(relerr, 1.0/5))
+
+		if h*scale > 1 {
+			h = 1 / scale
+		}
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	points := make([]float64, 0, nx+1)
+
+	stiff := false
+
+	for x < xend {
+		// Choose the end of the segment.
+		xnext := x + segmentSteps*h
+		if config.MaxStep > 0 {
+			xnext = math.Min(xnext, x+segmentSteps*config.MaxStep)
+		}
+		if 1.1*(xnext-x) >= xend-x {
+			xnext = xend
+		}
+		if xnext <= x {
+			return nil, nil, stats, errors.New("encountered a step-size underflow")
+		}
+
+		points = append(points[:0], x)
+		if fixed {
+			for i := nc; i < nx && xs[i] < xnext; i++ {
+				points = append(points, xs[i])
+			}
+		}
+		points = append(points, xnext)
+
+		var segment struct {
+			ys, xs []float64
+			steps  uint
+		}
+
+		if stiff {
+			integrator, err := bdf.New(&bdf.Config{
+				TryStep:  h,
+				MaxStep:  config.MaxStep,
+				AbsError: config.AbsError,
+				RelError: config.RelError,
+				Jacobian: config.Jacobian,
+			})
+			if err != nil {
+				return nil, nil, stats, err
+			}
+			zs, zxs, substats, err := integrator.ComputeWithStats(dydx, y, points)
+			if substats != nil {
+				stats.Evaluations += substats.Evaluations
+				stats.Jacobians += substats.Jacobians
+				stats.Decompositions += substats.Decompositions
+				stats.Rejections += substats.Rejections
+				stats.Steps += substats.Steps
+			}
+			if err != nil {
+				return nil, nil, stats, err
+			}
+			segment.ys, segment.xs, segment.steps = zs, zxs, substats.Steps
+		} else {
+			integrator, err := dopri.New(&dopri.Config{
+				TryStep:  h,
+				MaxStep:  config.MaxStep,
+				AbsError: config.AbsError,
+				RelError: config.RelError,
+			})
+			if err != nil {
+				return nil, nil, stats, err
+			}
+			zs, zxs, substats, err := integrator.ComputeWithStats(dydx, y, points)
+			if substats != nil {
+				stats.Evaluations += substats.Evaluations
+				stats.Rejections += substats.Rejections
+				stats.Steps += substats.Steps
+			}
+			if err != nil {
+				return nil, nil, stats, err
+			}
+			segment.ys, segment.xs, segment.steps = zs, zxs, substats.Steps
+		}
+
+		n := len(segment.xs)
+
+		if fixed {
+			// The segment is traversed freely unless it contains intermediate
+			// points, in which case the solution is returned at all points.
+			for i := n - len(points) + 1; i < n; i++ {
+				if nc < nx && segment.xs[i] == xs[nc] {
+					copy(ys[nc*nd:(nc+1)*nd], segment.ys[i*nd:(i+1)*nd])
+					nc++
+				}
+			}
+		} else {
+			ys = append(ys, segment.ys[nd:]...)
+			xs = append(xs, segment.xs[1:]...)
+			nc += len(segment.xs) - 1
+		}
+
+		copy(y, segment.ys[(n-1)*nd:])
+
+		h = (xnext - x) / float64(segment.steps)
+		x = xnext
+
+		if x >= xend {
+			break
+		}
+
+		// Estimate the spectral radius of the Jacobian matrix and decide which
+		// method to use for the next segment.
+		if config.Jacobian != nil {
+			config.Jacobian(x, y, J)
+		} else {
+			dydx(x, y, f)
+			stats.Evaluations++
+			stats.Evaluations += approximator.Compute(dydx, x, y, f, J)
+		}
+		stats.Jacobians++
+
+		ρ := radius(J, v)
+		if !stiff && h*ρ > stiffLimit {
+			stiff = true
+			stats.Switches++
+		} else if stiff && h*ρ < nonstiffLimit {
+			stiff = false
+			stats.Switches++
+		}
+	}
+
+	return ys, xs, stats, nil
+}
+
+// radius estimates the spectral radius of a matrix using the power iteration.
+// The matrix is stored in row-major order, and v is an auxiliary buffer whose
+// length is twice the order of the matrix.
+func radius(J []float64, v []float64) float64 {
+	nd := len(v) / 2
+
+	u, w := v[:nd], v[nd:]
+	for i := range u {
+		u[i] = 1 / float64(i+1)
+	}
+	normalize(u)
+
+	sum := 0.0
+	for k := 0; k < powerIterations; k++ {
+		for i := 0; i < nd; i++ {
+			w[i] = 0
+			for j := 0; j < nd; j++ {
+				w[i] += J[i*nd+j] * u[j]
+			}
+		}
+		norm := normalize(w)
+		if norm == 0 {
+			return 0
+		}
+		sum += math.Log(norm)
+		u, w = w, u
+	}
+
+	return math.Exp(sum / powerIterations)
+}
+
+func normalize(v []float64) float64 {
+	norm := 0.0
+	for i := range v {
+		norm += v[i] * v[i]
+	}
+	norm = math.Sqrt(norm)
+	if norm > 0 {
+		for i := range v {
+			v[i] /= norm
+		}
+	}
+	return norm
+}
@@ -0,0 +1,106 @@
+package auto
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeDecay(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{0, 0.25, 0.5, 0.75, 1, 1.25, 1.5, 1.75, 2}
+
+	integrator, _ := New(&Config{AbsError: 1e-8, RelError: 1e-6})
+
+	ys, _, _ := integrator.Compute(dydx, []float64{0.5}, xs)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 1e-5, t)
+	}
+}
+
+func TestComputeBackward(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	_, _, err := integrator.Compute(dydx, []float64{1}, []float64{1, 0})
+	assert.Equal(err != nil, true, t)
+
+	_, _, err = integrator.Compute(dydx, []float64{1}, []float64{1, 0.5, 0})
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeKepler(t *testing.T) {
+	const (
+		e = 0.5
+	)
+
+	dydx := func(_ float64, y, f []float64) {
+		r := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+		f[0], f[1] = y[2], y[3]
+		f[2], f[3] = -y[0]/r, -y[1]/r
+	}
+
+	y0 := []float64{1 - e, 0, 0, math.Sqrt((1 + e) / (1 - e))}
+
+	integrator, _ := New(&Config{AbsError: 1e-8, RelError: 1e-8})
+
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, y0, []float64{0, 2 * math.Pi})
+	assert.Equal(err, nil, t)
+
+	n := len(xs)
+	assert.Equal(xs[n-1], 2*math.Pi, t)
+	assert.Close(ys[4*(n-1):], y0, 1e-5, t)
+	assert.Equal(stats.Switches, uint(0), t)
+}
+
+func TestComputeRobertson(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -0.04*y[0] + 1e4*y[1]*y[2]
+		f[1] = 0.04*y[0] - 1e4*y[1]*y[2] - 3e7*y[1]*y[1]
+		f[2] = 3e7 * y[1] * y[1]
+	}
+
+	expected := []float64{7.158270687193e-01, 9.185534764529e-06, 2.841637457460e-01}
+
+	integrator, _ := New(&Config{AbsError: 1e-10, RelError: 1e-6})
+
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0, 0}, []float64{0, 40})
+	assert.Equal(err, nil, t)
+
+	n := len(xs)
+	assert.Equal(xs[n-1], 40.0, t)
+	y := ys[3*(n-1):]
+	for i := range y {
+		assert.Close(y[i]/expected[i], 1.0, 1e-3, t)
+	}
+	assert.Equal(stats.Switches > 0, true, t)
+	assert.Equal(stats.Steps < 1000, true, t)
+}
+
+func TestComputeVanDerPol(t *testing.T) {
+	const (
+		μ = 1000
+	)
+
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = μ*(1-y[0]*y[0])*y[1] - y[0]
+	}
+
+	xs := []float64{0, 500, 1000, 1500, 2000, 2500, 3000}
+
+	integrator, _ := New(&Config{AbsError: 1e-6, RelError: 1e-3})
+
+	ys, _, stats, err := integrator.ComputeWithStats(dydx, []float64{2, 0}, xs)
+	assert.Equal(err, nil, t)
+	assert.Close(ys[len(ys)-2], -1.5, 0.1, t)
+	assert.Equal(stats.Switches > 0, true, t)
+	assert.Equal(stats.Steps < 5000, true, t)
+}
@@ -0,0 +1,23 @@
+package ode
+
+// Batch is the right-hand side of a system of differential equations
+// dy/dx = f(x, y) that is evaluated at a number of states at once.
+//
+// The function evaluates f(xs[k], y_k) for each k and stores the result in
+// f_k. If nd is the dimension of the system, the states are stored one after
+// another in ys, so that y_k is ys[k*nd:(k+1)*nd], and the results are stored
+// in fs in the same way. Equivalently, ys and fs are matrices in row-major
+// order whose rows are the states, which lets a right-hand side that is a
+// matrix–vector product be evaluated as a single matrix–matrix product.
+type Batch func(xs []float64, ys, fs []float64)
+
+// Batched turns a right-hand side evaluated at one state at a time into a
+// Batch, which evaluates it at each state in turn.
+func Batched(dydx func(float64, []float64, []float64)) Batch {
+	return func(xs []float64, ys, fs []float64) {
+		nd := len(ys) / len(xs)
+		for k, x := range xs {
+			dydx(x, ys[k*nd:(k+1)*nd], fs[k*nd:(k+1)*nd])
+		}
+	}
+}
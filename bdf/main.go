@@ -0,0 +1,514 @@
+// Package bdf provides an integrator of stiff systems of ordinary differential
+// equations based on the variable-order backward differentiation formulas.
+//
+// The formulas of orders one through five are implemented in the
+// quasi-constant step size form using backward differences. The resulting
+// nonlinear systems are solved using a simplified Newton method, in which the
+// Jacobian matrix is reevaluated only when the iterations fail to converge.
+//
+// https://en.wikipedia.org/wiki/Backward_differentiation_formula
+package bdf
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/internal/dense"
+	"github.com/ready-steady/ode/internal/interval"
+	"github.com/ready-steady/ode/internal/jacobian"
+)
+
+const (
+	maxOrder      = 5
+	newtonMaxIter = 4
+	minFactor     = 0.2
+	maxFactor     = 10
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	var γ, α, ε [maxOrder + 2]float64
+
+	κ := [maxOrder + 2]float64{0, -0.1850, -1.0 / 9, -0.0823, -0.0415, 0}
+	for k := 1; k <= maxOrder; k++ {
+		γ[k] = γ[k-1] + 1/float64(k)
+	}
+	for k := 0; k <= maxOrder; k++ {
+		α[k] = (1 - κ[k]) * γ[k]
+		ε[k] = κ[k]*γ[k] + 1/float64(k+1)
+	}
+
+	stats := &Stats{}
+
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	config := &self.config
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	f := make([]float64, nd)
+	y := make([]float64, nd)
+	ynew := make([]float64, nd)
+	ypredict := make([]float64, nd)
+	scale := make([]float64, nd)
+	ψ := make([]float64, nd)
+	d := make([]float64, nd)
+	δ := make([]float64, nd)
+	z := make([]float64, nd)
+	fz := make([]float64, nd)
+
+	J := make([]float64, nd*nd)
+	approximator := jacobian.New(uint(nd), nil)
+	A := make([]float64, nd*nd)
+	var lu ode.LinearSolver = dense.NewLU(uint(nd))
+	if config.LinearSolver != nil {
+		lu = config.LinearSolver(uint(nd), nil)
+	}
+
+	D := make([]float64, (maxOrder+3)*nd)
+	row := func(i int) []float64 {
+		return D[i*nd : (i+1)*nd]
+	}
+
+	evaluate := func(x float64, y, f []float64) {
+		dydx(x, y, f)
+		stats.Evaluations++
+	}
+
+	jacobian := func(x float64, y []float64) {
+		if config.Jacobian != nil {
+			config.Jacobian(x, y, J)
+		} else {
+			evaluate(x, y, fz)
+			stats.Evaluations += approximator.Compute(dydx, x, y, fz, J)
+		}
+		stats.Jacobians++
+	}
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	abserr, relerr := config.AbsError, config.RelError
+
+	copy(y, y0)
+	evaluate(x, y, f)
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = xend - x
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = initialize(evaluate, x, y, f, z, fz, abserr, relerr)
+	}
+	if h > hmax {
+		h = hmax
+	}
+
+	tolerance := math.Max(10*epsilon(1)/relerr, math.Min(0.03, math.Sqrt(relerr)))
+
+	copy(row(0), y)
+	for i := 0; i < nd; i++ {
+		row(1)[i] = h * f[i]
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	order, equal := 1, 0
+
+	jacobian(x, y)
+	current, factorized := true, false
+
+	for x < xend {
+		var xnew, norm, safety float64
+
+		stats.Steps++
+
+		hmin := 10 * epsilon(x)
+
+		if h > hmax {
+			change(D, nd, order, hmax/h)
+			h, equal = hmax, 0
+			factorized = false
+		} else if h < hmin {
+			change(D, nd, order, hmin/h)
+			h, equal = hmin, 0
+			factorized = false
+		}
+
+		for {
+			if h < hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			xnew = x + h
+			if xnew >= xend || xend-xnew < hmin {
+				xnew = xend
+				change(D, nd, order, (xnew-x)/h)
+				equal = 0
+				factorized = false
+			}
+			h = xnew - x
+
+			for i := 0; i < nd; i++ {
+				s := 0.0
+				for j := 0; j <= order; j++ {
+					s += D[j*nd+i]
+				}
+				ypredict[i] = s
+				scale[i] = abserr + relerr*math.Abs(s)
+
+				s = 0.0
+				for j := 1; j <= order; j++ {
+					s += γ[j] * D[j*nd+i]
+				}
+				ψ[i] = s / α[order]
+			}
+
+			c := h / α[order]
+
+			converged, iterations := false, 0
+			for {
+				if !factorized {
+					for i := range A {
+						A[i] = -c * J[i]
+					}
+					for i := 0; i < nd; i++ {
+						A[i*nd+i] += 1
+					}
+					stats.Decompositions++
+					if err := lu.Factorize(A); err != nil {
+						return nil, nil, stats, err
+					}
+					factorized = true
+				}
+
+				converged, iterations = solve(evaluate, xnew, ypredict, c, ψ, lu, scale,
+					tolerance, ynew, d, δ)
+				if converged || current {
+					break
+				}
+
+				jacobian(xnew, ypredict)
+				current, factorized = true, false
+			}
+
+			if !converged {
+				stats.Rejections++
+				change(D, nd, order, 0.5)
+				h, equal = 0.5*h, 0
+				factorized = false
+				continue
+			}
+
+			safety = 0.9 * (2*newtonMaxIter + 1) / float64(2*newtonMaxIter+iterations)
+
+			for i := 0; i < nd; i++ {
+				scale[i] = abserr + relerr*math.Abs(ynew[i])
+				δ[i] = ε[order] * d[i]
+			}
+			norm = rms(δ, scale)
+
+			if norm <= 1 {
+				break
+			}
+
+			stats.Rejections++
+
+			factor := math.Max(minFactor, safety*math.Pow(norm, -1/float64(order+1)))
+			change(D, nd, order, factor)
+			h, equal = factor*h, 0
+			factorized = false
+		}
+
+		equal++
+		current = false
+
+		// Update the differences.
+		for i := 0; i < nd; i++ {
+			D[(order+2)*nd+i] = d[i] - D[(order+1)*nd+i]
+			D[(order+1)*nd+i] = d[i]
+		}
+		for j := order; j >= 0; j-- {
+			for i := 0; i < nd; i++ {
+				D[j*nd+i] += D[(j+1)*nd+i]
+			}
+		}
+
+		if fixed {
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					copy(ys[nc*nd:(nc+1)*nd], ynew)
+				} else {
+					interpolate(D, nd, order, xnew, h, xs[nc], ys[nc*nd:(nc+1)*nd])
+				}
+
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		x = xnew
+		copy(y, ynew)
+
+		if equal < order+1 {
+			continue
+		}
+
+		// Choose the order and the step size of the next step.
+		lower, upper := math.Inf(1), math.Inf(1)
+		if order > 1 {
+			for i := 0; i < nd; i++ {
+				δ[i] = ε[order-1] * D[order*nd+i]
+			}
+			lower = rms(δ, scale)
+		}
+		if order < maxOrder {
+			for i := 0; i < nd; i++ {
+				δ[i] = ε[order+1] * D[(order+2)*nd+i]
+			}
+			upper = rms(δ, scale)
+		}
+
+		best, delta := 0.0, 0
+		for k, e := range [3]float64{lower, norm, upper} {
+			if factor := math.Pow(e, -1/float64(order+k)); factor > best {
+				best, delta = factor, k-1
+			}
+		}
+		order += delta
+
+		factor := math.Min(maxFactor, safety*best)
+		change(D, nd, order, factor)
+		h, equal = factor*h, 0
+		factorized = false
+	}
+
+	return ys, xs, stats, nil
+}
+
+func solve(evaluate func(float64, []float64, []float64), x float64, ypredict []float64,
+	c float64, ψ []float64, lu ode.LinearSolver, scale []float64, tolerance float64,
+	y, d, δ []float64) (bool, int) {
+
+	nd := len(y)
+
+	copy(y, ypredict)
+	for i := range d {
+		d[i] = 0
+	}
+
+	var norm, rate float64
+	old := -1.0
+
+	k := 0
+	for ; k < newtonMaxIter; k++ {
+		evaluate(x, y, δ)
+		for i := 0; i < nd; i++ {
+			if math.IsNaN(δ[i]) || math.IsInf(δ[i], 0) {
+				return false, k + 1
+			}
+			δ[i] = c*δ[i] - ψ[i] - d[i]
+		}
+		lu.Solve(δ)
+
+		norm = rms(δ, scale)
+		if old >= 0 {
+			rate = norm / old
+			if rate >= 1 || math.Pow(rate, float64(newtonMaxIter-k))/(1-rate)*norm > tolerance {
+				return false, k + 1
+			}
+		}
+
+		for i := 0; i < nd; i++ {
+			y[i] += δ[i]
+			d[i] += δ[i]
+		}
+
+		if norm == 0 || (old >= 0 && rate/(1-rate)*norm < tolerance) {
+			return true, k + 1
+		}
+
+		old = norm
+	}
+
+	return false, k
+}
+
+// change rescales the differences for a new step size equal to the current
+// one multiplied by factor.
+func change(D []float64, nd, order int, factor float64) {
+	R := matrix(order, factor)
+	U := matrix(order, 1)
+
+	n := order + 1
+
+	RU := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			for k := 0; k < n; k++ {
+				RU[i*n+j] += R[i*n+k] * U[k*n+j]
+			}
+		}
+	}
+
+	old := make([]float64, n*nd)
+	copy(old, D[:n*nd])
+	for j := 0; j < n; j++ {
+		for l := 0; l < nd; l++ {
+			s := 0.0
+			for i := 0; i < n; i++ {
+				s += RU[i*n+j] * old[i*nd+l]
+			}
+			D[j*nd+l] = s
+		}
+	}
+}
+
+func matrix(order int, factor float64) []float64 {
+	n := order + 1
+
+	M := make([]float64, n*n)
+	for j := 0; j < n; j++ {
+		M[j] = 1
+	}
+	for i := 1; i < n; i++ {
+		for j := 1; j < n; j++ {
+			M[i*n+j] = M[(i-1)*n+j] * (float64(i) - 1 - factor*float64(j)) / float64(i)
+		}
+	}
+
+	return M
+}
+
+func interpolate(D []float64, nd, order int, x, h, xnext float64, ynext []float64) {
+	copy(ynext, D[:nd])
+
+	p := 1.0
+	for j := 1; j <= order; j++ {
+		p *= (xnext - x + h*float64(j-1)) / (h * float64(j))
+		for i := 0; i < nd; i++ {
+			ynext[i] += p * D[j*nd+i]
+		}
+	}
+}
+
+func initialize(evaluate func(float64, []float64, []float64), x float64, y, f, ynew,
+	fnew []float64, abserr, relerr float64) float64 {
+
+	const (
+		order = 1
+	)
+
+	nd := len(y)
+
+	scale := make([]float64, nd)
+	for i := 0; i < nd; i++ {
+		scale[i] = abserr + relerr*math.Abs(y[i])
+	}
+
+	d0, d1 := rms(y, scale), rms(f, scale)
+
+	var h float64
+	if d0 < 1e-5 || d1 < 1e-5 {
+		h = 1e-6
+	} else {
+		h = 0.01 * d0 / d1
+	}
+
+	for i := 0; i < nd; i++ {
+		ynew[i] = y[i] + h*f[i]
+	}
+	evaluate(x+h, ynew, fnew)
+
+	for i := 0; i < nd; i++ {
+		ynew[i] = fnew[i] - f[i]
+	}
+	d2 := rms(ynew, scale) / h
+
+	var h1 float64
+	if d1 <= 1e-15 && d2 <= 1e-15 {
+		h1 = math.Max(1e-6, h*1e-3)
+	} else {
+		h1 = math.Pow(0.01/math.Max(d1, d2), 1.0/(order+1))
+	}
+
+	return math.Min(100*h, h1)
+}
+
+func rms(v, scale []float64) float64 {
+	s := 0.0
+	for i := range v {
+		e := v[i] / scale[i]
+		s += e * e
+	}
+	return math.Sqrt(s / float64(len(v)))
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
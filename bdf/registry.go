@@ -0,0 +1,23 @@
+package bdf
+
+import (
+	"github.com/ready-steady/ode"
+)
+
+func init() {
+	ode.Register("bdf", func(options *ode.Options) (ode.Integrator, error) {
+		config := DefaultConfig()
+		options.Adapt(&config.TryStep, &config.MaxStep, &config.AbsError, &config.RelError)
+		if options.Jacobian != nil {
+			config.Jacobian = options.Jacobian
+		}
+		integrator, err := New(config)
+		if err != nil {
+			return nil, err
+		}
+		return integrator, nil
+	})
+	ode.RegisterDecoder("bdf", func(config []byte) (ode.Integrator, error) {
+		return ode.Decode(config, DefaultConfig(), New)
+	})
+}
@@ -0,0 +1,47 @@
+package benchmark
+
+import (
+	"errors"
+)
+
+// Config is the configuration of a benchmark.
+type Config struct {
+	// The tolerances at which each method is run. For the methods with
+	// adaptive steps, a tolerance is used as both the absolute and relative
+	// error tolerances; for the methods with fixed steps, it is used as the
+	// step; see Registered.
+	Tolerances []float64 `json:"tolerances"`
+	// The number of times each run is repeated in order to measure its
+	// duration, which is the shortest of the repetitions.
+	Repeats uint `json:"repeats"`
+}
+
+// DefaultConfig returns the default configuration of a benchmark.
+func DefaultConfig() *Config {
+	return &Config{
+		Tolerances: []float64{1e-3, 1e-4, 1e-5, 1e-6, 1e-7, 1e-8, 1e-9, 1e-10},
+		Repeats:    1,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when a
+// benchmark is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if len(c.Tolerances) == 0 {
+		return errors.New("the tolerances should be given")
+	}
+	for _, tolerance := range c.Tolerances {
+		if !(tolerance > 0) {
+			return errors.New("the tolerances should be positive")
+		}
+	}
+	if c.Repeats == 0 {
+		return errors.New("the number of repetitions should be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// Package benchmark provides a utility for comparing integrators by means of
+// work–precision diagrams.
+//
+// Each method is run on a problem at a number of tolerances, and each run
+// yields the error of the solution at the end of the interval along with the
+// number of evaluations of the right-hand side and the time taken. Plotting
+// the error against the work for each method shows which one reaches a given
+// accuracy at the lowest cost.
+package benchmark
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/problems"
+)
+
+// Benchmark is a benchmark.
+type Benchmark struct {
+	config Config
+}
+
+// Method is an integrator taking part in a benchmark.
+type Method struct {
+	// The name of the method.
+	Name string
+	// The function creating the integrator for a tolerance.
+	New func(tolerance float64) (ode.Integrator, error)
+}
+
+// Point is the outcome of a run of a method at a tolerance.
+type Point struct {
+	Method      string        // The name of the method.
+	Tolerance   float64       // The tolerance.
+	Error       float64       // The error of the solution at the end.
+	Evaluations uint          // The number of evaluations of the derivative.
+	Duration    time.Duration // The time taken by the integration.
+}
+
+// New creates a new benchmark.
+func New(config *Config) (*Benchmark, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Benchmark{config: *config}, nil
+}
+
+// Registered returns a method registered by name; see ode.New. A tolerance is
+// passed to the method as both the absolute and relative error tolerances and
+// as the step, so that the methods with adaptive steps use the former and the
+// ones with fixed steps the latter.
+func Registered(name string) Method {
+	return Method{
+		Name: name,
+		New: func(tolerance float64) (ode.Integrator, error) {
+			return ode.New(name, &ode.Options{
+				AbsError: tolerance,
+				RelError: tolerance,
+				Step:     tolerance,
+			})
+		},
+	}
+}
+
+// Run runs a number of methods on a problem at each of the tolerances of the
+// configuration and returns the outcomes grouped by method in the order of
+// the methods and then of the tolerances. The problem should have a reference
+// solution. The error of a run is the maximum over the components of the
+// absolute error at the end of the interval divided by the maximum of one and
+// the magnitude of the reference solution.
+func (self *Benchmark) Run(problem *problems.Problem, methods []Method) ([]Point, error) {
+	if len(problem.Reference) != len(problem.Y0) {
+		return nil, errors.New("the problem should have a reference solution")
+	}
+
+	config := &self.config
+
+	nd := len(problem.Y0)
+	points := make([]Point, 0, len(methods)*len(config.Tolerances))
+
+	for _, method := range methods {
+		for _, tolerance := range config.Tolerances {
+			integrator, err := method.New(tolerance)
+			if err != nil {
+				return nil, err
+			}
+
+			point := Point{Method: method.Name, Tolerance: tolerance}
+
+			var evaluations uint
+			dydx := func(x float64, y, f []float64) {
+				evaluations++
+				problem.Dydx(x, y, f)
+			}
+
+			for i := uint(0); i < config.Repeats; i++ {
+				evaluations = 0
+				start := time.Now()
+				ys, _, err := integrator.Compute(dydx, problem.Y0, problem.Xs)
+				duration := time.Since(start)
+				if err != nil {
+					return nil, err
+				}
+				if i == 0 || duration < point.Duration {
+					point.Duration = duration
+				}
+				point.Error = measure(ys[len(ys)-nd:], problem.Reference)
+				point.Evaluations = evaluations
+			}
+
+			points = append(points, point)
+		}
+	}
+
+	return points, nil
+}
+
+// Write writes a table of outcomes to w as comma-separated values with a
+// header, which contains one line per point with the name of the method, the
+// tolerance, the error, the number of evaluations, and the duration in
+// seconds. The table is ready to be plotted, for instance, as the error
+// against the number of evaluations on logarithmic scales for each method.
+func Write(w io.Writer, points []Point) error {
+	writer := bufio.NewWriter(w)
+
+	writer.WriteString("method,tolerance,error,evaluations,seconds\n")
+
+	var record []byte
+	for _, point := range points {
+		record = append(record[:0], point.Method...)
+		record = append(record, ',')
+		record = strconv.AppendFloat(record, point.Tolerance, 'g', -1, 64)
+		record = append(record, ',')
+		record = strconv.AppendFloat(record, point.Error, 'g', -1, 64)
+		record = append(record, ',')
+		record = strconv.AppendUint(record, uint64(point.Evaluations), 10)
+		record = append(record, ',')
+		record = strconv.AppendFloat(record, point.Duration.Seconds(), 'g', -1, 64)
+		record = append(record, '\n')
+		writer.Write(record)
+	}
+
+	return writer.Flush()
+}
+
+func measure(y, reference []float64) float64 {
+	ε := 0.0
+	for i := range y {
+		ε = math.Max(ε, math.Abs(y[i]-reference[i])/math.Max(1, math.Abs(reference[i])))
+	}
+	return ε
+}
@@ -0,0 +1,55 @@
+package benchmark
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/problems"
+
+	_ "github.com/ready-steady/ode/dopri"
+	_ "github.com/ready-steady/ode/rk4"
+)
+
+func TestRun(t *testing.T) {
+	problem := problems.DETEST()[2]
+	assert.Equal(problem.Name, "A3", t)
+
+	benchmark, err := New(&Config{Tolerances: []float64{1e-1, 1e-2, 1e-3}, Repeats: 2})
+	assert.Equal(err, nil, t)
+
+	points, err := benchmark.Run(problem, []Method{Registered("dopri"), Registered("rk4")})
+	assert.Equal(err, nil, t)
+	assert.Equal(len(points), 6, t)
+
+	for i, point := range points {
+		assert.Equal(point.Method, []string{"dopri", "rk4"}[i/3], t)
+		assert.Equal(point.Evaluations > 0, true, t)
+		if i%3 > 0 {
+			assert.Equal(point.Error < points[i-1].Error, true, t)
+			assert.Equal(point.Evaluations > points[i-1].Evaluations, true, t)
+		}
+	}
+
+	// The classical method takes four evaluations per step of 0.1 over 20.
+	assert.Equal(points[3].Evaluations, uint(4*200), t)
+
+	var buffer bytes.Buffer
+	assert.Equal(Write(&buffer, points), nil, t)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(len(lines), 7, t)
+	assert.Equal(lines[0], "method,tolerance,error,evaluations,seconds", t)
+	assert.Equal(strings.HasPrefix(lines[4], "rk4,0.1,"), true, t)
+}
+
+func TestRunWithoutReference(t *testing.T) {
+	benchmark, _ := New(DefaultConfig())
+
+	problem := problems.VanDerPol(42)
+	_, err := benchmark.Run(problem, []Method{Registered("dopri")})
+	assert.Equal(err != nil, true, t)
+
+	_, err = New(&Config{Tolerances: []float64{0}, Repeats: 1})
+	assert.Equal(err != nil, true, t)
+}
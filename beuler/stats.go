@@ -0,0 +1,10 @@
+package beuler
+
+// Stats contains information about the work done by an integrator.
+type Stats struct {
+	Evaluations    uint // The number of invocations of the derivative function.
+	Jacobians      uint // The number of evaluations of the Jacobian matrix.
+	Decompositions uint // The number of LU decompositions.
+	Rejections     uint // The number of rejected iterations of the algorithm.
+	Steps          uint // The number of steps the algorithm has taken.
+}
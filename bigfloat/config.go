@@ -0,0 +1,85 @@
+package bigfloat
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The method of integration.
+	Method Method `json:"method"`
+	// The precision of the mantissa in bits, which applies to all the
+	// arithmetic of the integration.
+	Precision uint `json:"precision"`
+	// The initial step of integration.
+	TryStep float64 `json:"tryStep"`
+	// The maximal step of integration.
+	MaxStep float64 `json:"maxStep"`
+	// The absolute error tolerance.
+	AbsError float64 `json:"absError"`
+	// The relative error tolerance.
+	RelError float64 `json:"relError"`
+	// The number of columns of the extrapolation table, which determines the
+	// order of Extrapolation; the order is twice the number of columns.
+	Columns uint `json:"columns"`
+	// The maximal number of steps, which is unlimited if zero.
+	MaxSteps uint `json:"maxSteps"`
+}
+
+// Method is a choice of a method of integration.
+type Method uint
+
+const (
+	// The Extrapolation method, which is the Gragg–Bulirsch–Stoer algorithm
+	// with a fixed number of columns. Its order can be made high enough for
+	// the tolerances that the precision permits.
+	Extrapolation Method = iota
+	// The Dormand–Prince method, which is of order five and is suitable only
+	// for moderate tolerances.
+	Dopri
+)
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return &Config{
+		Method:    Extrapolation,
+		Precision: 256,
+		TryStep:   0,
+		MaxStep:   0,
+		AbsError:  1e-40,
+		RelError:  1e-40,
+		Columns:   12,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Method > Dopri {
+		return errors.New("the method is unknown")
+	}
+	if c.Precision < 53 {
+		return errors.New("the precision should not be lower than the one of float64")
+	}
+	if c.TryStep < 0 {
+		return errors.New("the initial step should be nonnegative")
+	}
+	if c.MaxStep < 0 {
+		return errors.New("the maximal step should be nonnegative")
+	}
+	if c.AbsError <= 0 {
+		return errors.New("the absolute error tolerance should be positive")
+	}
+	if c.RelError <= 0 {
+		return errors.New("the relative error tolerance should be positive")
+	}
+	if c.Method == Extrapolation && c.Columns < 2 {
+		return errors.New("the number of columns should be at least two")
+	}
+
+	return nil
+}
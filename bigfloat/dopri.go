@@ -0,0 +1,89 @@
+package bigfloat
+
+import (
+	"math/big"
+)
+
+// dopri is the Dormand–Prince method.
+type dopri struct {
+	a       [][]*big.Float
+	b, c, e []*big.Float
+	k       [][]*big.Float
+	z       []*big.Float
+	t, u    *big.Float
+}
+
+func newDopri(nd int, prec uint) *dopri {
+	k := make([][]*big.Float, 7)
+	for i := range k {
+		k[i] = newVector(prec, nd)
+	}
+
+	return &dopri{
+		a: [][]*big.Float{
+			{},
+			parse(prec, "1/5"),
+			parse(prec, "3/40", "9/40"),
+			parse(prec, "44/45", "-56/15", "32/9"),
+			parse(prec, "19372/6561", "-25360/2187", "64448/6561", "-212/729"),
+			parse(prec, "9017/3168", "-355/33", "46732/5247", "49/176", "-5103/18656"),
+		},
+		b: parse(prec, "35/384", "0", "500/1113", "125/192", "-2187/6784", "11/84"),
+		c: parse(prec, "0", "1/5", "3/10", "4/5", "8/9", "1"),
+		e: parse(prec, "71/57600", "0", "-71/16695", "71/1920", "-17253/339200",
+			"22/525", "-1/40"),
+		k: k,
+		z: newVector(prec, nd),
+		t: newFloat(prec),
+		u: newFloat(prec),
+	}
+}
+
+func (self *dopri) step(dydx func(*big.Float, []*big.Float, []*big.Float),
+	x, h *big.Float, y, ynew, e []*big.Float) uint {
+
+	a, b, c, k, z, t := self.a, self.b, self.c, self.k, self.z, self.t
+
+	dydx(x, y, k[0])
+	for l := 1; l < 6; l++ {
+		combine(y, h, a[l], k, z, self.u)
+		t.Mul(c[l], h)
+		t.Add(t, x)
+		dydx(t, z, k[l])
+	}
+	combine(y, h, b, k, ynew, self.u)
+
+	t.Add(x, h)
+	dydx(t, ynew, k[6])
+
+	for i := range e {
+		e[i].SetInt64(0)
+	}
+	combine(e, h, self.e, k, e, self.u)
+
+	return 7
+}
+
+func (self *dopri) power() float64 {
+	return 1.0 / 5
+}
+
+// combine computes ynew = y + h Σ w[j] k[j] using a temporary t. The vectors
+// y and ynew can be the same.
+func combine(y []*big.Float, h *big.Float, w []*big.Float, k [][]*big.Float,
+	ynew []*big.Float, t *big.Float) {
+
+	sum := newFloat(t.Prec())
+	for i := range y {
+		sum.SetInt64(0)
+		for j := range w {
+			if w[j].Sign() == 0 {
+				continue
+			}
+			t.Mul(w[j], k[j][i])
+			sum.Add(sum, t)
+		}
+		sum.Mul(sum, h)
+		ynew[i].Add(y[i], sum)
+	}
+}
@@ -0,0 +1,114 @@
+package bigfloat
+
+import (
+	"math/big"
+)
+
+// extrapolation is the Gragg–Bulirsch–Stoer algorithm with a fixed number of
+// columns, in which the modified midpoint rule with 2, 4, 6, … substeps is
+// extrapolated to the zero substep size by the Aitken–Neville scheme.
+type extrapolation struct {
+	columns uint
+	rows    [2][][]*big.Float
+	f0, f   []*big.Float
+	z0, z1  []*big.Float
+	s, t, u *big.Float
+}
+
+func newExtrapolation(nd int, prec uint, columns uint) *extrapolation {
+	// The current and previous rows of the extrapolation table.
+	var rows [2][][]*big.Float
+	for r := range rows {
+		rows[r] = make([][]*big.Float, columns)
+		for k := range rows[r] {
+			rows[r][k] = newVector(prec, nd)
+		}
+	}
+
+	return &extrapolation{
+		columns: columns,
+		rows:    rows,
+		f0:      newVector(prec, nd),
+		f:       newVector(prec, nd),
+		z0:      newVector(prec, nd),
+		z1:      newVector(prec, nd),
+		s:       newFloat(prec),
+		t:       newFloat(prec),
+		u:       newFloat(prec),
+	}
+}
+
+func (self *extrapolation) step(dydx func(*big.Float, []*big.Float, []*big.Float),
+	x, h *big.Float, y, ynew, e []*big.Float) uint {
+
+	nk := int(self.columns)
+	f0, f, z0, z1 := self.f0, self.f, self.z0, self.z1
+	s, t, u := self.s, self.t, self.u
+
+	dydx(x, y, f0)
+	evaluations := uint(1)
+
+	previous, current := self.rows[0], self.rows[1]
+	one, two := big.NewFloat(1), big.NewFloat(2)
+
+	for j := 0; j < nk; j++ {
+		n := 2 * (j + 1)
+
+		// The modified midpoint rule with n substeps of size s.
+		s.SetInt64(int64(n))
+		s.Quo(h, s)
+		for i := range y {
+			z0[i].Set(y[i])
+			z1[i].Mul(s, f0[i])
+			z1[i].Add(z1[i], y[i])
+		}
+		for m := 1; m < n; m++ {
+			t.SetInt64(int64(m))
+			t.Mul(t, s)
+			t.Add(t, x)
+			dydx(t, z1, f)
+			for i := range y {
+				u.Mul(s, f[i])
+				u.Add(u, u)
+				z0[i].Add(z0[i], u)
+			}
+			z0, z1 = z1, z0
+		}
+		t.Add(x, h)
+		dydx(t, z1, f)
+		evaluations += uint(n)
+		for i := range y {
+			u.Mul(s, f[i])
+			u.Add(u, z1[i])
+			u.Add(u, z0[i])
+			current[0][i].Quo(u, two)
+		}
+
+		// The Aitken–Neville scheme.
+		for k := 1; k <= j; k++ {
+			// The ratio of the squared substep sizes minus one, that is,
+			// ((j+1)/(j+1-k))² - 1.
+			t.SetInt64(int64((j + 1) * (j + 1)))
+			u.SetInt64(int64((j + 1 - k) * (j + 1 - k)))
+			t.Quo(t, u)
+			t.Sub(t, one)
+			for i := range y {
+				u.Sub(current[k-1][i], previous[k-1][i])
+				u.Quo(u, t)
+				current[k][i].Add(current[k-1][i], u)
+			}
+		}
+		previous, current = current, previous
+	}
+
+	for i := range y {
+		ynew[i].Set(previous[nk-1][i])
+		e[i].Sub(previous[nk-1][i], previous[nk-2][i])
+	}
+
+	return evaluations
+}
+
+func (self *extrapolation) power() float64 {
+	return 1 / float64(2*self.columns-1)
+}
@@ -0,0 +1,256 @@
+// Package bigfloat provides an integrator of systems of ordinary differential
+// equations in arbitrary-precision floating-point arithmetic based on
+// math/big.Float.
+//
+// The integrator is intended for computing reference solutions with which the
+// integrators working in double precision can be verified. All the arithmetic
+// of the integration, including the coefficients of the methods, which are
+// given as exact fractions, is carried out with the precision of the
+// configuration, and the tolerances can be far below the machine epsilon of
+// float64. The step-size selection uses float64, which is sufficient for
+// ratios of errors. The methods have no continuous extension; therefore, when
+// the solution is requested at fixed points, the steps are shortened in order
+// to hit these points exactly.
+//
+// https://en.wikipedia.org/wiki/Arbitrary-precision_arithmetic
+package bigfloat
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+const (
+	safety  = 0.9
+	minimal = 0.2
+	maximal = 5.0
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// stepper is a method of integration.
+type stepper interface {
+	// step takes a step of size h from x and y, stores the solution at x + h
+	// in ynew and the estimate of its error in e, and returns the number of
+	// evaluations of the derivative.
+	step(dydx func(*big.Float, []*big.Float, []*big.Float), x, h *big.Float,
+		y, ynew, e []*big.Float) uint
+	// power returns the exponent of the step-size controller.
+	power() float64
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// The input function dydx(x, y, f) evaluates f(x, y) and stores the result in
+// its last argument, whose entries are allocated with the precision of the
+// integrator and should be set in place. The arguments of the function should
+// not be retained. If xs does not specify any intermediate points, the
+// solution is returned at the points that the integrator internally
+// traverses. Otherwise, the solution is returned at the points of xs, which
+// should be strictly monotonic. If xend < x0, the integration is carried out
+// backward.
+func (self *Integrator) Compute(dydx func(*big.Float, []*big.Float, []*big.Float),
+	y0 []*big.Float, xs []*big.Float) ([]*big.Float, []*big.Float, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(*big.Float, []*big.Float, []*big.Float),
+	y0 []*big.Float, xs []*big.Float) ([]*big.Float, []*big.Float, *Stats, error) {
+
+	if err := validate(y0, xs); err != nil {
+		return nil, nil, nil, err
+	}
+
+	config := &self.config
+	prec := config.Precision
+
+	nd, nx := len(y0), len(xs)
+	fixed := nx > 2
+
+	var stepper stepper
+	switch config.Method {
+	case Dopri:
+		stepper = newDopri(nd, prec)
+	case Extrapolation:
+		stepper = newExtrapolation(nd, prec, config.Columns)
+	}
+
+	dir := 1.0
+	if xs[nx-1].Cmp(xs[0]) < 0 {
+		dir = -1
+	}
+
+	span, _ := sub(prec, xs[nx-1], xs[0]).Float64()
+	span = math.Abs(span)
+
+	hmax := span
+	if config.MaxStep > 0 && config.MaxStep < hmax {
+		hmax = config.MaxStep
+	}
+
+	h := config.TryStep
+	if h == 0 || h > hmax {
+		h = 0.01 * hmax
+	}
+
+	threshold := config.AbsError / config.RelError
+	power := stepper.power()
+
+	stats := &Stats{}
+
+	x, y := copyFloat(prec, xs[0]), copyVector(prec, y0)
+	ynew, e := newVector(prec, nd), newVector(prec, nd)
+	hb, xnew := newFloat(prec), newFloat(prec)
+
+	ys, zs := copyVector(prec, y0), []*big.Float{copyFloat(prec, x)}
+
+	for nc := 1; nc < nx; {
+		if config.MaxSteps > 0 && stats.Steps == config.MaxSteps {
+			return ys, zs, stats, errors.New("the maximal number of steps has been reached")
+		}
+
+		// Land exactly on the next requested point.
+		target := xs[nc]
+		hb.SetFloat64(dir * h)
+		xnew.Add(x, hb)
+		landing := false
+		if dir*float64(xnew.Cmp(target)) >= 0 {
+			hb.Sub(target, x)
+			xnew.Set(target)
+			landing = true
+		}
+
+		stats.Evaluations += stepper.step(dydx, x, hb, y, ynew, e)
+
+		// The maximum norm of the error estimate relative to the solution.
+		ε := 0.0
+		for i := 0; i < nd; i++ {
+			scale := threshold
+			if s := magnitude(y[i]); s > scale {
+				scale = s
+			}
+			if s := magnitude(ynew[i]); s > scale {
+				scale = s
+			}
+			ε = math.Max(ε, magnitude(e[i])/scale)
+		}
+
+		factor := maximal
+		if ε > 0 {
+			factor = math.Min(maximal, math.Max(minimal,
+				safety*math.Pow(config.RelError/ε, power)))
+		}
+
+		if ε > config.RelError || math.IsNaN(ε) {
+			stats.Rejections++
+			if math.IsNaN(ε) {
+				factor = minimal
+			}
+			h *= factor
+			if h < 1e-300 || h < span*math.Pow(2, -float64(prec)) {
+				return ys, zs, stats, errors.New("the step size has become too small")
+			}
+			continue
+		}
+
+		stats.Steps++
+
+		x.Set(xnew)
+		y, ynew = ynew, y
+
+		if !fixed || landing {
+			ys, zs = append(ys, copyVector(prec, y)...), append(zs, copyFloat(prec, x))
+		}
+		if landing {
+			nc++
+		}
+
+		h = math.Min(h*factor, hmax)
+	}
+
+	return ys, zs, stats, nil
+}
+
+// validate checks that the initial condition is not empty and that the points
+// of the interval are strictly monotonic.
+func validate(y0, xs []*big.Float) error {
+	if len(y0) == 0 {
+		return errors.New("the initial condition should not be empty")
+	}
+	nx := len(xs)
+	if nx < 2 {
+		return errors.New("the interval should have two endpoints")
+	}
+	dir := xs[nx-1].Cmp(xs[0])
+	for i := 1; i < nx; i++ {
+		if xs[i].Cmp(xs[i-1]) != dir || dir == 0 {
+			return errors.New("the points of the interval should be strictly monotonic")
+		}
+	}
+	return nil
+}
+
+// magnitude returns the absolute value of a number as a float64.
+func magnitude(x *big.Float) float64 {
+	v, _ := x.Float64()
+	return math.Abs(v)
+}
+
+func newFloat(prec uint) *big.Float {
+	return new(big.Float).SetPrec(prec)
+}
+
+func newVector(prec uint, n int) []*big.Float {
+	x := make([]*big.Float, n)
+	for i := range x {
+		x[i] = newFloat(prec)
+	}
+	return x
+}
+
+func copyFloat(prec uint, x *big.Float) *big.Float {
+	return newFloat(prec).Set(x)
+}
+
+func copyVector(prec uint, x []*big.Float) []*big.Float {
+	y := make([]*big.Float, len(x))
+	for i := range x {
+		y[i] = copyFloat(prec, x[i])
+	}
+	return y
+}
+
+func sub(prec uint, x, y *big.Float) *big.Float {
+	return newFloat(prec).Sub(x, y)
+}
+
+// parse converts fractions given as strings to numbers of a precision.
+func parse(prec uint, fractions ...string) []*big.Float {
+	x := make([]*big.Float, len(fractions))
+	for i, s := range fractions {
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			panic("the fraction is invalid")
+		}
+		x[i] = newFloat(prec).SetRat(r)
+	}
+	return x
+}
@@ -0,0 +1,76 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeExtrapolation(t *testing.T) {
+	const prec = 256
+
+	// y′ = y with y(0) = 1, whose solution is y(x) = exp(x).
+	dydx := func(_ *big.Float, y, f []*big.Float) {
+		f[0].Set(y[0])
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	xs := []*big.Float{big.NewFloat(0), big.NewFloat(0.5), big.NewFloat(1)}
+	ys, zs, stats, err := integrator.ComputeWithStats(dydx, []*big.Float{big.NewFloat(1)}, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(len(zs), 3, t)
+	assert.Equal(stats.Steps > 0, true, t)
+
+	e := exp(prec, 1)
+	assert.Equal(relative(prec, ys[2], e) < 1e-38, true, t)
+	assert.Equal(ys[1].Prec(), uint(prec), t)
+
+	value, _ := ys[1].Float64()
+	assert.Close(value, math.Exp(0.5), 1e-15, t)
+}
+
+func TestComputeDopri(t *testing.T) {
+	const prec = 128
+
+	// y′ = -2 x y with y(0) = 1, whose solution is y(x) = exp(-x²).
+	dydx := func(x *big.Float, y, f []*big.Float) {
+		f[0].Mul(x, y[0])
+		f[0].Mul(f[0], big.NewFloat(-2))
+	}
+
+	config := DefaultConfig()
+	config.Method = Dopri
+	config.Precision = prec
+	config.AbsError = 1e-20
+	config.RelError = 1e-20
+
+	integrator, _ := New(config)
+
+	ys, zs, err := integrator.Compute(dydx, []*big.Float{big.NewFloat(1)},
+		[]*big.Float{big.NewFloat(0), big.NewFloat(1)})
+	assert.Equal(err, nil, t)
+	assert.Equal(zs[len(zs)-1].Cmp(big.NewFloat(1)), 0, t)
+	assert.Equal(relative(prec, ys[len(ys)-1], exp(prec, -1)) < 1e-18, true, t)
+}
+
+// exp computes exp(x) for a small integer x using its Taylor series.
+func exp(prec uint, x int64) *big.Float {
+	sum, term := newFloat(prec).SetInt64(1), newFloat(prec).SetInt64(1)
+	X := newFloat(prec).SetInt64(x)
+	for k := int64(1); k < 200; k++ {
+		term.Mul(term, X)
+		term.Quo(term, newFloat(prec).SetInt64(k))
+		sum.Add(sum, term)
+	}
+	return sum
+}
+
+func relative(prec uint, x, y *big.Float) float64 {
+	d := newFloat(prec).Sub(x, y)
+	d.Quo(d, y)
+	v, _ := d.Float64()
+	return math.Abs(v)
+}
@@ -0,0 +1,35 @@
+// Package bs23 provides an integrator of systems of ordinary differential
+// equations based on the Bogacki–Shampine method of orders three and two.
+//
+// https://en.wikipedia.org/wiki/Bogacki%E2%80%93Shampine_method
+package bs23
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Integrator is an integrator.
+type Integrator = erk.Integrator
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return erk.New(tableau, config)
+}
+
+var tableau = &erk.Tableau{
+	A: [][]float64{
+		{},
+		{1.0 / 2},
+		{0, 3.0 / 4},
+	},
+	B: []float64{2.0 / 9, 1.0 / 3, 4.0 / 9},
+	C: []float64{0, 1.0 / 2, 3.0 / 4},
+	E: []float64{-5.0 / 72, 1.0 / 12, 1.0 / 9, -1.0 / 8},
+	D: [][]float64{
+		{1.0, -4.0 / 3, 5.0 / 9},
+		{0, 1.0, -2.0 / 3},
+		{0, 4.0 / 3, -8.0 / 9},
+		{0, -1.0, 1.0},
+	},
+	Order: 2,
+}
@@ -0,0 +1,20 @@
+package bs23
+
+import (
+	"github.com/ready-steady/ode"
+)
+
+func init() {
+	ode.Register("bs23", func(options *ode.Options) (ode.Integrator, error) {
+		config := DefaultConfig()
+		options.Adapt(&config.TryStep, &config.MaxStep, &config.AbsError, &config.RelError)
+		integrator, err := New(config)
+		if err != nil {
+			return nil, err
+		}
+		return integrator, nil
+	})
+	ode.RegisterDecoder("bs23", func(config []byte) (ode.Integrator, error) {
+		return ode.Decode(config, DefaultConfig(), New)
+	})
+}
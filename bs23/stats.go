@@ -0,0 +1,8 @@
+package bs23
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Stats contains information about the work done by an integrator.
+type Stats = erk.Stats
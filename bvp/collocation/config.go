@@ -0,0 +1,51 @@
+package collocation
+
+import (
+	"errors"
+)
+
+// Config is the configuration of a solver.
+type Config struct {
+	// The number of intervals of the initial mesh, which are of equal length.
+	Intervals uint `json:"intervals"`
+	// The maximal number of points of the mesh.
+	MaxPoints uint `json:"maxPoints"`
+	// The maximal number of Newton iterations per mesh.
+	MaxIterations uint `json:"maxIterations"`
+	// The tolerance on the residual of the continuous solution relative to
+	// max(|f|, 1) where f is the right-hand side of the system.
+	Tolerance float64 `json:"tolerance"`
+}
+
+// DefaultConfig returns the default configuration of a solver.
+func DefaultConfig() *Config {
+	return &Config{
+		Intervals:     10,
+		MaxPoints:     10000,
+		MaxIterations: 50,
+		Tolerance:     1e-6,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when a
+// solver is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Intervals == 0 {
+		return errors.New("the number of intervals should be positive")
+	}
+	if c.MaxPoints <= c.Intervals {
+		return errors.New("the maximal number of points should exceed the number of intervals")
+	}
+	if c.MaxIterations == 0 {
+		return errors.New("the maximal number of iterations should be positive")
+	}
+	if !(c.Tolerance > 0) {
+		return errors.New("the tolerance should be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,446 @@
+// Package collocation provides a solver of two-point boundary value problems
+//
+//	y′(x) = f(x, y(x)) for a < x < b and g(y(a), y(b)) = 0
+//
+// based on the three-stage Lobatto IIIA collocation, which is also used by
+// bvp4c of MATLAB.
+//
+// The solution is approximated by a continuous piecewise cubic polynomial on a
+// mesh, which satisfies the system at the points of the mesh and the midpoints
+// of its intervals. The resulting nonlinear equations for the values at the
+// points of the mesh are solved by the damped Newton method with a Jacobian
+// matrix approximated using finite differences. Unlike shooting, the method
+// does not integrate the system, and, therefore, it is applicable to problems
+// whose solutions are highly sensitive to the initial condition.
+//
+// The accuracy is controlled by the residual of the polynomial in the system,
+// which is sampled at the interior nodes of the five-point Lobatto quadrature
+// rule in each interval. The intervals where the residual exceeds the
+// tolerance are subdivided, and the equations are solved anew on the refined
+// mesh until the residual is acceptable everywhere.
+//
+// In order to keep the linear systems banded regardless of the form of the
+// boundary conditions, the system is augmented by the constant y(a), which
+// turns the boundary conditions into separated ones.
+//
+// https://en.wikipedia.org/wiki/Collocation_method
+package collocation
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/ready-steady/ode/internal/band"
+)
+
+const (
+	epsilon    = 2.220446049250313e-16
+	maxDamping = 10
+)
+
+// The interior nodes of the five-point Lobatto quadrature rule on [0, 1] other
+// than the midpoint, where the residual is sampled.
+var samples = []float64{0.5 - math.Sqrt(21)/14, 0.5 + math.Sqrt(21)/14}
+
+// Solver is a solver.
+type Solver struct {
+	config Config
+}
+
+// New creates a new solver.
+func New(config *Config) (*Solver, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Solver{config: *config}, nil
+}
+
+// Compute solves a boundary value problem.
+//
+// The input function dydx(x, y, f) evaluates f(x, y) and stores the result in
+// its last argument. The function residual(ya, yb, r) evaluates g(ya, yb) and
+// stores the result, which should have nd entries, in its last argument. The
+// function guess(x, y) stores an initial guess of the solution at x in y,
+// which has nd entries; it is evaluated at the points of the initial mesh. The
+// boundary points are a = xs[0] and b = xs[len(xs)-1], and b should be greater
+// than a. If xs does not specify any intermediate points, the solution is
+// returned at the points of the final mesh; otherwise, it is returned at the
+// points of xs.
+func (self *Solver) Compute(dydx func(float64, []float64, []float64),
+	residual func([]float64, []float64, []float64), guess func(float64, []float64),
+	nd uint, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, residual, guess, nd, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Solver) ComputeWithStats(dydx func(float64, []float64, []float64),
+	residual func([]float64, []float64, []float64), guess func(float64, []float64),
+	nd uint, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	config := &self.config
+
+	nx := len(xs)
+	if nd == 0 {
+		return nil, nil, nil, errors.New("the dimension of the system should be positive")
+	}
+	if nx < 2 {
+		return nil, nil, nil, errors.New("the interval should have two endpoints")
+	}
+
+	a, b := xs[0], xs[nx-1]
+	if !(b > a) {
+		return nil, nil, nil, errors.New("the interval should be traversed forward")
+	}
+
+	stats := &Stats{}
+
+	counted := func(x float64, y, f []float64) {
+		dydx(x, y, f)
+		stats.Evaluations++
+	}
+
+	md := int(nd)
+
+	ni := int(config.Intervals)
+	mesh := make([]float64, ni+1)
+	for i := range mesh {
+		mesh[i] = a + (b-a)*float64(i)/float64(ni)
+	}
+	mesh[ni] = b
+
+	Y := make([]float64, len(mesh)*md)
+	for i, x := range mesh {
+		guess(x, Y[i*md:(i+1)*md])
+	}
+
+	var F []float64
+	for {
+		var err error
+		if F, err = self.solve(counted, residual, mesh, Y, md, stats); err != nil {
+			return nil, nil, stats, err
+		}
+
+		refined := refine(counted, mesh, Y, F, md, config.Tolerance)
+		if len(refined) == len(mesh) {
+			break
+		}
+		if uint(len(refined)) > config.MaxPoints {
+			return nil, nil, stats, errors.New("the maximal number of points has been reached")
+		}
+
+		stats.Refinements++
+
+		Y = resample(mesh, Y, F, md, refined)
+		mesh = refined
+	}
+
+	stats.Points = uint(len(mesh))
+
+	if nx == 2 {
+		return Y, mesh, stats, nil
+	}
+
+	ys := make([]float64, nx*md)
+	for k, x := range xs {
+		i := sort.SearchFloat64s(mesh, x) - 1
+		if i < 0 {
+			i = 0
+		} else if i > len(mesh)-2 {
+			i = len(mesh) - 2
+		}
+		hermite(mesh[i], mesh[i+1], Y[i*md:(i+1)*md], F[i*md:(i+1)*md],
+			Y[(i+1)*md:(i+2)*md], F[(i+1)*md:(i+2)*md], x, ys[k*md:(k+1)*md], nil)
+	}
+
+	return ys, xs, stats, nil
+}
+
+// solve solves the collocation equations on a mesh by the damped Newton method
+// starting from the values Y at the points of the mesh, which are overwritten
+// by the solution. The function returns the derivatives at the points of the
+// mesh.
+//
+// The unknowns are the pairs (yᵢ, zᵢ) at the points of the mesh where the
+// auxiliary variable z is the constant y(a). The equations are ordered as
+// z₀ - y₀ = 0, the collocation equations and zᵢ₊₁ - zᵢ = 0 for each interval,
+// and g(zₘ, yₘ) = 0, which makes the Jacobian matrix banded.
+func (self *Solver) solve(dydx func(float64, []float64, []float64),
+	residual func([]float64, []float64, []float64), mesh, Y []float64, md int,
+	stats *Stats) ([]float64, error) {
+
+	config := &self.config
+
+	m := len(mesh) - 1
+	nu := 2 * md
+	n := (m + 1) * nu
+	ml, mu := 3*md-1, 3*md-1
+	w := ml + mu + 1
+
+	u := make([]float64, n)
+	for i := 0; i <= m; i++ {
+		copy(u[i*nu:i*nu+md], Y[i*md:(i+1)*md])
+		copy(u[i*nu+md:(i+1)*nu], Y[:md])
+	}
+
+	y := func(u []float64, i int) []float64 {
+		return u[i*nu : i*nu+md]
+	}
+	z := func(u []float64, i int) []float64 {
+		return u[i*nu+md : (i+1)*nu]
+	}
+
+	ym, fm := make([]float64, md), make([]float64, md)
+
+	// collocate evaluates the collocation equation of the ith interval.
+	collocate := func(i int, y0, f0, y1, f1, r []float64) {
+		h := mesh[i+1] - mesh[i]
+		for j := 0; j < md; j++ {
+			ym[j] = (y0[j]+y1[j])/2 - h*(f1[j]-f0[j])/8
+		}
+		dydx(mesh[i]+h/2, ym, fm)
+		for j := 0; j < md; j++ {
+			r[j] = y1[j] - y0[j] - h*(f0[j]+4*fm[j]+f1[j])/6
+		}
+	}
+
+	// conditions evaluates the derivatives at the points of the mesh and the
+	// equations and returns the maximum norm of the latter.
+	conditions := func(u, F, r []float64) float64 {
+		for i := 0; i <= m; i++ {
+			dydx(mesh[i], y(u, i), F[i*md:(i+1)*md])
+		}
+		for j := 0; j < md; j++ {
+			r[j] = z(u, 0)[j] - y(u, 0)[j]
+		}
+		for i := 0; i < m; i++ {
+			base := md + i*nu
+			collocate(i, y(u, i), F[i*md:(i+1)*md], y(u, i+1), F[(i+1)*md:(i+2)*md],
+				r[base:base+md])
+			for j := 0; j < md; j++ {
+				r[base+md+j] = z(u, i+1)[j] - z(u, i)[j]
+			}
+		}
+		residual(z(u, m), y(u, m), r[n-md:])
+
+		norm := 0.0
+		for _, v := range r {
+			norm = math.Max(norm, math.Abs(v))
+		}
+		return norm
+	}
+
+	F, r := make([]float64, (m+1)*md), make([]float64, n)
+	unew, Fnew, rnew := make([]float64, n), make([]float64, (m+1)*md), make([]float64, n)
+
+	A := make([]float64, n*w)
+	set := func(i, j int, v float64) {
+		A[i*w+j-i+ml] = v
+	}
+
+	lu := band.NewLU(uint(n), uint(ml), uint(mu))
+
+	δ := make([]float64, n)
+	yp, fp, rp := make([]float64, md), make([]float64, md), make([]float64, md)
+
+	σ := math.Sqrt(epsilon)
+
+	norm := conditions(u, F, r)
+
+	for k := uint(0); ; k++ {
+		if k == config.MaxIterations {
+			return nil, errors.New("the Newton iterations have not converged")
+		}
+
+		stats.Iterations++
+
+		for i := range A {
+			A[i] = 0
+		}
+
+		for j := 0; j < md; j++ {
+			set(j, j, -1)
+			set(j, md+j, 1)
+		}
+
+		// The collocation equations depend on the values at both ends of the
+		// interval.
+		for i := 0; i < m; i++ {
+			base := md + i*nu
+			for side := 0; side < 2; side++ {
+				l := i + side
+				copy(yp, y(u, l))
+				for j := 0; j < md; j++ {
+					h := σ * math.Max(math.Abs(yp[j]), 1)
+					yp[j] += h
+					h = yp[j] - y(u, l)[j]
+					dydx(mesh[l], yp, fp)
+					if side == 0 {
+						collocate(i, yp, fp, y(u, i+1), F[(i+1)*md:(i+2)*md], rp)
+					} else {
+						collocate(i, y(u, i), F[i*md:(i+1)*md], yp, fp, rp)
+					}
+					for t := 0; t < md; t++ {
+						set(base+t, l*nu+j, (rp[t]-r[base+t])/h)
+					}
+					yp[j] = y(u, l)[j]
+				}
+			}
+			for j := 0; j < md; j++ {
+				set(base+md+j, i*nu+md+j, -1)
+				set(base+md+j, (i+1)*nu+md+j, 1)
+			}
+		}
+
+		// The boundary conditions depend on zₘ = y(a) and yₘ = y(b).
+		for side := 0; side < 2; side++ {
+			offset := m*nu + md
+			if side == 1 {
+				offset = m * nu
+			}
+			for j := 0; j < md; j++ {
+				copy(unew[offset:offset+md], u[offset:offset+md])
+				v := u[offset+j]
+				h := σ * math.Max(math.Abs(v), 1)
+				unew[offset+j] = v + h
+				h = unew[offset+j] - v
+				if side == 0 {
+					residual(unew[offset:offset+md], y(u, m), rp)
+				} else {
+					residual(z(u, m), unew[offset:offset+md], rp)
+				}
+				for t := 0; t < md; t++ {
+					set(n-md+t, offset+j, (rp[t]-r[n-md+t])/h)
+				}
+			}
+		}
+
+		if err := lu.Factorize(A); err != nil {
+			return nil, errors.New("the Jacobian matrix of the collocation equations is singular")
+		}
+		for i := range δ {
+			δ[i] = -r[i]
+		}
+		lu.Solve(δ)
+
+		// Damp the step until the equations improve.
+		var λ float64
+		var normnew float64
+		for l := 0; ; l++ {
+			λ = math.Pow(0.5, float64(l))
+			for i := range u {
+				unew[i] = u[i] + λ*δ[i]
+			}
+			normnew = conditions(unew, Fnew, rnew)
+			if normnew < norm || l == maxDamping {
+				break
+			}
+		}
+
+		step := 0.0
+		for i := range u {
+			step = math.Max(step, math.Abs(λ*δ[i])/(1+math.Abs(u[i])))
+		}
+
+		u, unew = unew, u
+		F, Fnew = Fnew, F
+		r, rnew = rnew, r
+		norm = normnew
+
+		if step <= 1e-3*config.Tolerance || norm == 0 {
+			break
+		}
+	}
+
+	for i := 0; i <= m; i++ {
+		copy(Y[i*md:(i+1)*md], y(u, i))
+	}
+
+	return F, nil
+}
+
+// refine estimates the residual in each interval of a mesh and returns the
+// mesh with the intervals where the residual exceeds the tolerance
+// subdivided. An interval is split into two if the residual exceeds the
+// tolerance and into three if it exceeds the tolerance by two orders of
+// magnitude.
+func refine(dydx func(float64, []float64, []float64), mesh, Y, F []float64, md int,
+	tolerance float64) []float64 {
+
+	m := len(mesh) - 1
+
+	y, dy, f := make([]float64, md), make([]float64, md), make([]float64, md)
+
+	refined := []float64{mesh[0]}
+	for i := 0; i < m; i++ {
+		x0, x1 := mesh[i], mesh[i+1]
+
+		worst := 0.0
+		for _, s := range samples {
+			x := x0 + s*(x1-x0)
+			hermite(x0, x1, Y[i*md:(i+1)*md], F[i*md:(i+1)*md], Y[(i+1)*md:(i+2)*md],
+				F[(i+1)*md:(i+2)*md], x, y, dy)
+			dydx(x, y, f)
+			for j := 0; j < md; j++ {
+				worst = math.Max(worst, math.Abs(dy[j]-f[j])/math.Max(math.Abs(f[j]), 1))
+			}
+		}
+
+		switch {
+		case worst > 100*tolerance:
+			refined = append(refined, x0+(x1-x0)/3, x0+2*(x1-x0)/3)
+		case worst > tolerance:
+			refined = append(refined, x0+(x1-x0)/2)
+		}
+		refined = append(refined, x1)
+	}
+
+	return refined
+}
+
+// resample evaluates the piecewise cubic polynomial given by the values Y and
+// derivatives F at the points of a mesh at the points of another mesh.
+func resample(mesh, Y, F []float64, md int, points []float64) []float64 {
+	Z := make([]float64, len(points)*md)
+	for k, i := 0, 0; k < len(points); k++ {
+		for i < len(mesh)-2 && points[k] > mesh[i+1] {
+			i++
+		}
+		hermite(mesh[i], mesh[i+1], Y[i*md:(i+1)*md], F[i*md:(i+1)*md],
+			Y[(i+1)*md:(i+2)*md], F[(i+1)*md:(i+2)*md], points[k], Z[k*md:(k+1)*md], nil)
+	}
+	return Z
+}
+
+// hermite evaluates the cubic Hermite polynomial given by the values and
+// derivatives at x0 and x1 and, if dy is not nil, its derivative at x.
+func hermite(x0, x1 float64, y0, f0, y1, f1 []float64, x float64, y, dy []float64) {
+	h := x1 - x0
+	s := (x - x0) / h
+
+	h00 := (1 + 2*s) * (1 - s) * (1 - s)
+	h10 := s * (1 - s) * (1 - s)
+	h01 := s * s * (3 - 2*s)
+	h11 := s * s * (s - 1)
+
+	for j := range y {
+		y[j] = h00*y0[j] + h*h10*f0[j] + h01*y1[j] + h*h11*f1[j]
+	}
+
+	if dy == nil {
+		return
+	}
+
+	d00 := 6 * s * (s - 1)
+	d10 := (1 - s) * (1 - 3*s)
+	d01 := -d00
+	d11 := s * (3*s - 2)
+
+	for j := range dy {
+		dy[j] = (d00*y0[j]+d01*y1[j])/h + d10*f0[j] + d11*f1[j]
+	}
+}
@@ -0,0 +1,9 @@
+package collocation
+
+// Stats contains information about the work done by a solver.
+type Stats struct {
+	Evaluations uint // The number of invocations of the derivative function.
+	Iterations  uint // The number of Newton iterations over all meshes.
+	Refinements uint // The number of refinements of the mesh.
+	Points      uint // The number of points of the final mesh.
+}
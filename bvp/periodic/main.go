@@ -0,0 +1,302 @@
+// Package periodic provides a solver of periodic orbits of autonomous systems
+// of ordinary differential equations y′(x) = f(y(x)), that is, of solutions
+// satisfying y(0) = y(T) for some unknown period T > 0.
+//
+// Given an approximate point on the orbit and an approximate period, the
+// boundary value problem
+//
+//	y(T; s) - s = 0 and f(s₀)ᵀ (s - s₀) = 0
+//
+// is solved by single shooting for the starting point s and the period T
+// using the damped Newton method, where y(x; s) is the solution starting from
+// s, and s₀ is the initial guess. The second condition, which is known as the
+// phase condition, confines s to the hyperplane passing through s₀ orthogonal
+// to the flow, which removes the freedom of shifting the solution along the
+// orbit. The system is integrated by the Dormand–Prince method together with
+// its variational equations, which yields the monodromy matrix ∂y(T; s)/∂s
+// needed by Newton's method. At convergence, the eigenvalues of the monodromy
+// matrix are the Floquet multipliers of the orbit, one of which is equal to
+// one, and the rest determine its stability.
+//
+// https://en.wikipedia.org/wiki/Limit_cycle
+package periodic
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/dopri"
+	"github.com/ready-steady/ode/internal/dense"
+)
+
+const (
+	epsilon    = 2.220446049250313e-16
+	maxDamping = 10
+)
+
+// Solver is a solver.
+type Solver struct {
+	config Config
+}
+
+// Orbit is a periodic orbit.
+type Orbit struct {
+	Y         []float64 // The solution over one period with nd entries per point.
+	X         []float64 // The points of the solution from zero to the period.
+	Period    float64   // The period.
+	Monodromy []float64 // The monodromy matrix stored in row-major order.
+
+	nd uint
+}
+
+// New creates a new solver.
+func New(config *Config) (*Solver, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Solver{config: *config}, nil
+}
+
+// Multipliers computes the Floquet multipliers of the orbit, which are the
+// eigenvalues of the monodromy matrix.
+func (self *Orbit) Multipliers() ([]complex128, error) {
+	return dense.Eigenvalues(self.Monodromy, self.nd)
+}
+
+// Compute finds a periodic orbit.
+//
+// The input function dydx(x, y, f) evaluates f(y) and stores the result in its
+// last argument; the system should be autonomous, and x is the time elapsed
+// since the starting point. The initial guesses of a point on the orbit and
+// the period are y0 and period, respectively. The solution is returned at the
+// points that the integrator internally traverses over one period, and the
+// first point is the one satisfying the phase condition.
+func (self *Solver) Compute(dydx func(float64, []float64, []float64), y0 []float64,
+	period float64) (*Orbit, error) {
+
+	orbit, _, err := self.ComputeWithStats(dydx, y0, period)
+
+	return orbit, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Solver) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, period float64) (*Orbit, *Stats, error) {
+
+	config := &self.config
+
+	nd := len(y0)
+	if nd == 0 {
+		return nil, nil, errors.New("the dimension of the system should be positive")
+	}
+	if !(period > 0) {
+		return nil, nil, errors.New("the period should be positive")
+	}
+
+	integrator, err := dopri.New(&config.Integrator)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats := &Stats{}
+
+	counted := func(x float64, y, f []float64) {
+		dydx(x, y, f)
+		stats.Evaluations++
+	}
+
+	reference := append([]float64(nil), y0...)
+	normal := make([]float64, nd)
+	counted(0, reference, normal)
+	if maxNorm(normal) == 0 {
+		return nil, nil, errors.New("the initial guess should not be an equilibrium")
+	}
+
+	augmented := self.augment(counted, nd)
+
+	n, nz := nd+1, nd*(1+nd)
+
+	// evaluate integrates the system and its variational equations from s over
+	// the period T and evaluates the conditions.
+	evaluate := func(s []float64, T float64, F []float64) ([]float64, []float64, error) {
+		z0 := make([]float64, nz)
+		copy(z0, s)
+		for i := 0; i < nd; i++ {
+			z0[nd+i*nd+i] = 1
+		}
+
+		zs, xs, err := integrator.Compute(augmented, z0, []float64{0, T})
+		stats.Integrations++
+		if err != nil {
+			return nil, nil, err
+		}
+
+		end := zs[len(zs)-nz:]
+		F[nd] = 0
+		for i := 0; i < nd; i++ {
+			F[i] = end[i] - s[i]
+			F[nd] += normal[i] * (s[i] - reference[i])
+		}
+
+		return zs, xs, nil
+	}
+
+	u, unew := make([]float64, n), make([]float64, n)
+	copy(u, y0)
+	u[nd] = period
+
+	F, Fnew := make([]float64, n), make([]float64, n)
+	J, δ := make([]float64, n*n), make([]float64, n)
+	f := make([]float64, nd)
+
+	lu := dense.NewLU(uint(n))
+
+	zs, xs, err := evaluate(u[:nd], u[nd], F)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	for {
+		norm := maxNorm(F)
+		if norm <= config.Tolerance {
+			break
+		}
+		if stats.Iterations == config.MaxIterations {
+			return nil, stats, errors.New("the Newton iterations have not converged")
+		}
+
+		stats.Iterations++
+
+		// The Jacobian matrix of the conditions consists of the monodromy
+		// matrix minus the identity, the derivative at the end, and the
+		// normal of the phase condition.
+		end := zs[len(zs)-nz:]
+		counted(u[nd], end[:nd], f)
+		for i := 0; i < nd; i++ {
+			for j := 0; j < nd; j++ {
+				J[i*n+j] = end[nd+i*nd+j]
+			}
+			J[i*n+i] -= 1
+			J[i*n+nd] = f[i]
+			J[nd*n+i] = normal[i]
+		}
+		J[nd*n+nd] = 0
+
+		if err := lu.Factorize(J); err != nil {
+			return nil, stats, errors.New("the Jacobian matrix of the conditions is singular")
+		}
+		for i := range δ {
+			δ[i] = -F[i]
+		}
+		lu.Solve(δ)
+
+		// Damp the step until the conditions improve.
+		var znew, xnew []float64
+		for λ, l := 1.0, 0; ; λ, l = λ/2, l+1 {
+			for i := range u {
+				unew[i] = u[i] + λ*δ[i]
+			}
+			err = errors.New("the period should stay positive")
+			if unew[nd] > 0 {
+				znew, xnew, err = evaluate(unew[:nd], unew[nd], Fnew)
+			}
+			if err == nil && maxNorm(Fnew) < norm {
+				break
+			}
+			if l == maxDamping {
+				if err != nil {
+					return nil, stats, err
+				}
+				break
+			}
+		}
+		u, unew = unew, u
+		F, Fnew = Fnew, F
+		zs, xs = znew, xnew
+	}
+
+	np := len(xs)
+	orbit := &Orbit{
+		Y:         make([]float64, np*nd),
+		X:         xs,
+		Period:    u[nd],
+		Monodromy: append([]float64(nil), zs[len(zs)-nz+nd:]...),
+		nd:        uint(nd),
+	}
+	for k := 0; k < np; k++ {
+		copy(orbit.Y[k*nd:(k+1)*nd], zs[k*nz:k*nz+nd])
+	}
+
+	return orbit, stats, nil
+}
+
+// augment returns the right-hand side of the system augmented by its
+// variational equations Φ′ = ∂f/∂y Φ where Φ is an nd-by-nd matrix stored in
+// row-major order.
+func (self *Solver) augment(dydx func(float64, []float64, []float64),
+	nd int) func(float64, []float64, []float64) {
+
+	jacobian := self.config.Jacobian
+
+	var J []float64
+	if jacobian != nil {
+		J = make([]float64, nd*nd)
+	}
+
+	z, fz := make([]float64, nd), make([]float64, nd)
+
+	return func(x float64, u, du []float64) {
+		y, Φ := u[:nd], u[nd:]
+		f, dΦ := du[:nd], du[nd:]
+
+		dydx(x, y, f)
+
+		if jacobian != nil {
+			jacobian(x, y, J)
+			for i := 0; i < nd; i++ {
+				for j := 0; j < nd; j++ {
+					sum := 0.0
+					for k := 0; k < nd; k++ {
+						sum += J[i*nd+k] * Φ[k*nd+j]
+					}
+					dΦ[i*nd+j] = sum
+				}
+			}
+			return
+		}
+
+		for j := 0; j < nd; j++ {
+			// The directional derivative along the jth column of Φ.
+			scale, size := 0.0, 0.0
+			for i := 0; i < nd; i++ {
+				scale = math.Max(scale, math.Abs(y[i]))
+				size = math.Max(size, math.Abs(Φ[i*nd+j]))
+			}
+
+			if size == 0 {
+				for i := 0; i < nd; i++ {
+					dΦ[i*nd+j] = 0
+				}
+				continue
+			}
+
+			σ := math.Sqrt(epsilon) * (1 + scale) / size
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + σ*Φ[i*nd+j]
+			}
+			dydx(x, z, fz)
+			for i := 0; i < nd; i++ {
+				dΦ[i*nd+j] = (fz[i] - f[i]) / σ
+			}
+		}
+	}
+}
+
+func maxNorm(x []float64) float64 {
+	norm := 0.0
+	for _, v := range x {
+		norm = math.Max(norm, math.Abs(v))
+	}
+	return norm
+}
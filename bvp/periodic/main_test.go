@@ -0,0 +1,85 @@
+package periodic
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeHopf(t *testing.T) {
+	// The normal form of the Hopf bifurcation, whose limit cycle is the unit
+	// circle with period 2π and the nontrivial multiplier exp(-4π).
+	dydx := func(_ float64, y, f []float64) {
+		r := y[0]*y[0] + y[1]*y[1]
+		f[0] = y[0] - y[1] - y[0]*r
+		f[1] = y[0] + y[1] - y[1]*r
+	}
+
+	solver, _ := New(DefaultConfig())
+
+	orbit, stats, err := solver.ComputeWithStats(dydx, []float64{1.2, 0.1}, 6)
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Iterations > 0, true, t)
+	assert.Close(orbit.Period, 2*math.Pi, 1e-8, t)
+
+	np := len(orbit.X)
+	assert.Equal(orbit.X[0], 0.0, t)
+	assert.Equal(orbit.X[np-1], orbit.Period, t)
+	for k := 0; k < np; k++ {
+		y := orbit.Y[2*k : 2*k+2]
+		assert.Close(math.Hypot(y[0], y[1]), 1.0, 1e-8, t)
+	}
+
+	μ, err := orbit.Multipliers()
+	assert.Equal(err, nil, t)
+	if math.Abs(real(μ[0])) < math.Abs(real(μ[1])) {
+		μ[0], μ[1] = μ[1], μ[0]
+	}
+	assert.Close([]float64{real(μ[0]), imag(μ[0]), real(μ[1]), imag(μ[1])},
+		[]float64{1, 0, math.Exp(-4 * math.Pi), 0}, 1e-7, t)
+}
+
+func TestComputeVanDerPol(t *testing.T) {
+	// The Van der Pol oscillator with μ = 1, whose period is approximately
+	// 6.6632868593231.
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = (1-y[0]*y[0])*y[1] - y[0]
+	}
+
+	config := DefaultConfig()
+	config.Jacobian = func(_ float64, y, J []float64) {
+		J[0], J[1] = 0, 1
+		J[2], J[3] = -2*y[0]*y[1]-1, 1-y[0]*y[0]
+	}
+
+	solver, _ := New(config)
+
+	orbit, err := solver.Compute(dydx, []float64{2, 0}, 6.5)
+	assert.Equal(err, nil, t)
+	assert.Close(orbit.Period, 6.6632868593231, 1e-8, t)
+
+	np := len(orbit.X)
+	assert.Close(orbit.Y[2*(np-1):], orbit.Y[:2], 1e-8, t)
+
+	// The determinant of the monodromy matrix is the product of the
+	// multipliers, which is exp(∫ tr(∂f/∂y)) by Liouville's formula.
+	μ, err := orbit.Multipliers()
+	assert.Equal(err, nil, t)
+	product := real(μ[0] * μ[1])
+	M := orbit.Monodromy
+	assert.Close(product, M[0]*M[3]-M[1]*M[2], 1e-10, t)
+	assert.Close(math.Min(math.Abs(real(μ[0])-1), math.Abs(real(μ[1])-1)), 0.0, 1e-7, t)
+}
+
+func TestComputeEquilibrium(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	solver, _ := New(DefaultConfig())
+
+	_, err := solver.Compute(dydx, []float64{0}, 1)
+	assert.Equal(err != nil, true, t)
+}
@@ -0,0 +1,8 @@
+package periodic
+
+// Stats contains information about the work done by a solver.
+type Stats struct {
+	Evaluations  uint // The number of invocations of the derivative function.
+	Integrations uint // The number of integrations over a period.
+	Iterations   uint // The number of Newton iterations.
+}
@@ -0,0 +1,55 @@
+package shooting
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode/dopri"
+)
+
+// Config is the configuration of a solver.
+type Config struct {
+	// The configuration of the underlying integrator.
+	Integrator dopri.Config `json:"integrator"`
+	// The number of shooting intervals, which are of equal length. If it is
+	// one, the method reduces to single shooting.
+	Intervals uint `json:"intervals"`
+	// The maximal number of Newton iterations.
+	MaxIterations uint `json:"maxIterations"`
+	// The tolerance on the maximum norm of the residuals of the boundary and
+	// continuity conditions.
+	Tolerance float64 `json:"tolerance"`
+}
+
+// DefaultConfig returns the default configuration of a solver.
+func DefaultConfig() *Config {
+	integrator := dopri.DefaultConfig()
+	integrator.AbsError = 1e-10
+	integrator.RelError = 1e-10
+
+	return &Config{
+		Integrator:    *integrator,
+		Intervals:     1,
+		MaxIterations: 50,
+		Tolerance:     1e-8,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when a
+// solver is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Intervals == 0 {
+		return errors.New("the number of shooting intervals should be positive")
+	}
+	if c.MaxIterations == 0 {
+		return errors.New("the maximal number of iterations should be positive")
+	}
+	if !(c.Tolerance > 0) {
+		return errors.New("the tolerance should be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,272 @@
+// Package shooting provides a solver of two-point boundary value problems
+//
+//	y′(x) = f(x, y(x)) for a < x < b and g(y(a), y(b)) = 0
+//
+// based on single and multiple shooting.
+//
+// The interval [a, b] is divided into a number of shooting intervals of equal
+// length, and the solution at the beginning of each of them is treated as
+// unknown. Given the unknowns, the system is integrated over each interval by
+// the Dormand–Prince method, and the unknowns are adjusted by the damped
+// Newton method until the boundary conditions are satisfied and the pieces of
+// the solution join continuously. The Jacobian matrix of the conditions is
+// approximated using finite differences, which costs nd integrations per
+// interval and iteration where nd is the dimension of the system. Multiple
+// intervals make the method applicable to problems whose solutions are too
+// sensitive to the initial condition for single shooting.
+//
+// https://en.wikipedia.org/wiki/Shooting_method
+package shooting
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/dopri"
+	"github.com/ready-steady/ode/internal/dense"
+)
+
+const (
+	epsilon    = 2.220446049250313e-16
+	maxDamping = 10
+)
+
+// Solver is a solver.
+type Solver struct {
+	config Config
+}
+
+// New creates a new solver.
+func New(config *Config) (*Solver, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Solver{config: *config}, nil
+}
+
+// Compute solves a boundary value problem.
+//
+// The input function dydx(x, y, f) evaluates f(x, y) and stores the result in
+// its last argument. The function residual(ya, yb, r) evaluates g(ya, yb) and
+// stores the result, which should have nd entries, in its last argument. The
+// function guess(x, y) stores an initial guess of the solution at x in y,
+// which has nd entries; it is evaluated at the beginning of each shooting
+// interval. The boundary points are a = xs[0] and b = xs[len(xs)-1], and b
+// should be greater than a. If xs does not specify any intermediate points,
+// the solution is returned at the points that the integrator internally
+// traverses, which include the boundaries of the shooting intervals;
+// otherwise, it is returned at the points of xs.
+func (self *Solver) Compute(dydx func(float64, []float64, []float64),
+	residual func([]float64, []float64, []float64), guess func(float64, []float64),
+	nd uint, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, residual, guess, nd, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Solver) ComputeWithStats(dydx func(float64, []float64, []float64),
+	residual func([]float64, []float64, []float64), guess func(float64, []float64),
+	nd uint, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	config := &self.config
+
+	nx := len(xs)
+	if nd == 0 {
+		return nil, nil, nil, errors.New("the dimension of the system should be positive")
+	}
+	if nx < 2 {
+		return nil, nil, nil, errors.New("the interval should have two endpoints")
+	}
+
+	a, b := xs[0], xs[nx-1]
+	if !(b > a) {
+		return nil, nil, nil, errors.New("the interval should be traversed forward")
+	}
+
+	integrator, err := dopri.New(&config.Integrator)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	stats := &Stats{}
+
+	ni, md := int(config.Intervals), int(nd)
+	n := ni * md
+
+	nodes := make([]float64, ni+1)
+	for k := range nodes {
+		nodes[k] = a + (b-a)*float64(k)/float64(ni)
+	}
+	nodes[ni] = b
+
+	// shoot integrates over the kth interval and stores the solution at its
+	// end in end.
+	shoot := func(k int, start, end []float64) error {
+		ys, _, inner, err := integrator.ComputeWithStats(dydx, start,
+			[]float64{nodes[k], nodes[k+1]})
+		stats.Integrations++
+		if inner != nil {
+			stats.Evaluations += inner.Evaluations
+		}
+		if err != nil {
+			return err
+		}
+		copy(end, ys[len(ys)-md:])
+		return nil
+	}
+
+	// assemble evaluates the continuity and boundary conditions given the
+	// starts and ends of the intervals.
+	assemble := func(s, e, F []float64) {
+		for k := 0; k < ni-1; k++ {
+			for i := 0; i < md; i++ {
+				F[k*md+i] = e[k*md+i] - s[(k+1)*md+i]
+			}
+		}
+		residual(s[:md], e[(ni-1)*md:], F[(ni-1)*md:])
+	}
+
+	// evaluate integrates over all the intervals and evaluates the conditions.
+	evaluate := func(s, e, F []float64) error {
+		for k := 0; k < ni; k++ {
+			if err := shoot(k, s[k*md:(k+1)*md], e[k*md:(k+1)*md]); err != nil {
+				return err
+			}
+		}
+		assemble(s, e, F)
+		return nil
+	}
+
+	s := make([]float64, n)
+	for k := 0; k < ni; k++ {
+		guess(nodes[k], s[k*md:(k+1)*md])
+	}
+
+	e, F := make([]float64, n), make([]float64, n)
+	snew, enew, Fnew := make([]float64, n), make([]float64, n), make([]float64, n)
+	J, δ := make([]float64, n*n), make([]float64, n)
+
+	lu := dense.NewLU(uint(n))
+
+	if err := evaluate(s, e, F); err != nil {
+		return nil, nil, stats, err
+	}
+
+	σ := math.Sqrt(math.Max(config.Integrator.RelError, epsilon))
+
+	for {
+		norm := maxNorm(F)
+		if norm <= config.Tolerance {
+			break
+		}
+		if stats.Iterations == config.MaxIterations {
+			return nil, nil, stats, errors.New("the Newton iterations have not converged")
+		}
+
+		stats.Iterations++
+
+		// Approximate the Jacobian matrix column by column. A perturbation
+		// of the start of an interval affects only its own end.
+		copy(snew, s)
+		copy(enew, e)
+		for k := 0; k < ni; k++ {
+			start, end := snew[k*md:(k+1)*md], enew[k*md:(k+1)*md]
+			for i := 0; i < md; i++ {
+				j := k*md + i
+				h := σ * math.Max(math.Abs(s[j]), 1)
+				start[i] = s[j] + h
+				h = start[i] - s[j]
+				if err := shoot(k, start, end); err != nil {
+					return nil, nil, stats, err
+				}
+				assemble(snew, enew, Fnew)
+				for l := 0; l < n; l++ {
+					J[l*n+j] = (Fnew[l] - F[l]) / h
+				}
+				start[i] = s[j]
+			}
+			copy(end, e[k*md:(k+1)*md])
+		}
+
+		if err := lu.Factorize(J); err != nil {
+			return nil, nil, stats, errors.New("the Jacobian matrix of the conditions is singular")
+		}
+		for l := range δ {
+			δ[l] = -F[l]
+		}
+		lu.Solve(δ)
+
+		// Damp the step until the conditions improve.
+		for λ, l := 1.0, 0; ; λ, l = λ/2, l+1 {
+			for j := range s {
+				snew[j] = s[j] + λ*δ[j]
+			}
+			err := evaluate(snew, enew, Fnew)
+			if err == nil && maxNorm(Fnew) < norm {
+				break
+			}
+			if l == maxDamping {
+				if err != nil {
+					return nil, nil, stats, err
+				}
+				break
+			}
+		}
+		s, snew = snew, s
+		e, enew = enew, e
+		F, Fnew = Fnew, F
+	}
+
+	ys, zs := append([]float64(nil), s[:md]...), []float64{a}
+
+	fixed := nx > 2
+	for k, nc := 0, 1; k < ni; k++ {
+		grid := []float64{nodes[k]}
+		for ; fixed && nc < nx && xs[nc] < nodes[k+1]; nc++ {
+			grid = append(grid, xs[nc])
+		}
+		grid = append(grid, nodes[k+1])
+
+		requested := !fixed || nc < nx && xs[nc] == nodes[k+1]
+		if fixed && requested {
+			nc++
+		}
+
+		local, points, inner, err := integrator.ComputeWithStats(dydx,
+			s[k*md:(k+1)*md], grid)
+		stats.Integrations++
+		if inner != nil {
+			stats.Evaluations += inner.Evaluations
+		}
+		if err != nil {
+			return nil, nil, stats, err
+		}
+
+		if fixed && len(grid) == 2 {
+			// Only the endpoints are of interest.
+			local = append(local[:md:md], local[len(local)-md:]...)
+			points = []float64{grid[0], points[len(points)-1]}
+		}
+
+		// Drop the start, which has already been reported, and the end unless
+		// it is requested.
+		local, points = local[md:], points[1:]
+		if !requested {
+			local, points = local[:len(local)-md], points[:len(points)-1]
+		}
+		ys, zs = append(ys, local...), append(zs, points...)
+	}
+
+	return ys, zs, stats, nil
+}
+
+func maxNorm(x []float64) float64 {
+	norm := 0.0
+	for _, v := range x {
+		norm = math.Max(norm, math.Abs(v))
+	}
+	return norm
+}
@@ -0,0 +1,78 @@
+package shooting
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeBratu(t *testing.T) {
+	// y″ = -exp(y) with y(0) = y(1) = 0, whose lower solution is
+	// y(x) = -2 ln(cosh((x - 1/2) θ/2) / cosh(θ/4)) where θ = √2 cosh(θ/4).
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -math.Exp(y[0])
+	}
+	residual := func(ya, yb, r []float64) {
+		r[0] = ya[0]
+		r[1] = yb[0]
+	}
+	guess := func(_ float64, y []float64) {
+		y[0], y[1] = 0, 0
+	}
+
+	θ := 1.0
+	for k := 0; k < 100; k++ {
+		θ = math.Sqrt2 * math.Cosh(θ/4)
+	}
+	exact := func(x float64) float64 {
+		return -2 * math.Log(math.Cosh((x-0.5)*θ/2)/math.Cosh(θ/4))
+	}
+
+	for _, intervals := range []uint{1, 4} {
+		config := DefaultConfig()
+		config.Intervals = intervals
+
+		solver, _ := New(config)
+
+		xs := []float64{0, 0.2, 0.25, 0.5, 0.9, 1}
+		ys, zs, stats, err := solver.ComputeWithStats(dydx, residual, guess, 2, xs)
+		assert.Equal(err, nil, t)
+		assert.Equal(zs, xs, t)
+		assert.Equal(stats.Iterations > 0, true, t)
+		for i, x := range xs {
+			assert.Close(ys[2*i], exact(x), 1e-8, t)
+		}
+	}
+}
+
+func TestComputeUnstable(t *testing.T) {
+	// y″ = 100 y with y(0) = y(10) = 1, whose solution is
+	// y(x) = cosh(10 (x - 5)) / cosh(50). Single shooting is hopeless as the
+	// solution is amplified by about e¹⁰⁰ over the interval.
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = 100 * y[0]
+	}
+	residual := func(ya, yb, r []float64) {
+		r[0] = ya[0] - 1
+		r[1] = yb[0] - 1
+	}
+	guess := func(_ float64, y []float64) {
+		y[0], y[1] = 0, 0
+	}
+
+	config := DefaultConfig()
+	config.Intervals = 50
+
+	solver, _ := New(config)
+
+	ys, zs, err := solver.Compute(dydx, residual, guess, 2, []float64{0, 10})
+	assert.Equal(err, nil, t)
+	assert.Equal(zs[0], 0.0, t)
+	assert.Equal(zs[len(zs)-1], 10.0, t)
+	for i, x := range zs {
+		assert.Close(ys[2*i], math.Cosh(10*(x-5))/math.Cosh(50), 1e-6, t)
+	}
+}
@@ -0,0 +1,32 @@
+// Package cashkarp provides an integrator of systems of ordinary differential
+// equations based on the Cash–Karp method of orders five and four.
+//
+// https://en.wikipedia.org/wiki/Cash%E2%80%93Karp_method
+package cashkarp
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Integrator is an integrator.
+type Integrator = erk.Integrator
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return erk.New(tableau, config)
+}
+
+var tableau = &erk.Tableau{
+	A: [][]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{3.0 / 10, -9.0 / 10, 6.0 / 5},
+		{-11.0 / 54, 5.0 / 2, -70.0 / 27, 35.0 / 27},
+		{1631.0 / 55296, 175.0 / 512, 575.0 / 13824, 44275.0 / 110592, 253.0 / 4096},
+	},
+	B:     []float64{37.0 / 378, 0, 250.0 / 621, 125.0 / 594, 0, 512.0 / 1771},
+	C:     []float64{0, 1.0 / 5, 3.0 / 10, 3.0 / 5, 1, 7.0 / 8},
+	E:     []float64{37.0/378 - 2825.0/27648, 0, 250.0/621 - 18575.0/48384, 125.0/594 - 13525.0/55296, -277.0 / 14336, 512.0/1771 - 1.0/4, 0},
+	Order: 4,
+}
@@ -0,0 +1,48 @@
+package cashkarp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeDecay(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{0, 0.25, 0.5, 0.75, 1, 1.25, 1.5, 1.75, 2}
+
+	integrator, _ := New(&Config{AbsError: 1e-8, RelError: 1e-6})
+
+	ys, _, _ := integrator.Compute(dydx, []float64{0.5}, xs)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 1e-5, t)
+	}
+}
+
+func TestComputeKepler(t *testing.T) {
+	const (
+		e = 0.5
+	)
+
+	dydx := func(_ float64, y, f []float64) {
+		r := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+		f[0], f[1] = y[2], y[3]
+		f[2], f[3] = -y[0]/r, -y[1]/r
+	}
+
+	y0 := []float64{1 - e, 0, 0, math.Sqrt((1 + e) / (1 - e))}
+
+	for _, relerr := range []float64{1e-3, 1e-6, 1e-9} {
+		integrator, _ := New(&Config{AbsError: relerr, RelError: relerr})
+
+		ys, xs, stats, err := integrator.ComputeWithStats(dydx, y0, []float64{0, 2 * math.Pi})
+		assert.Equal(err, nil, t)
+
+		n := len(xs)
+		assert.Close(ys[4*(n-1):], y0, 100*relerr, t)
+		assert.Equal(stats.Evaluations < 1000, true, t)
+	}
+}
@@ -0,0 +1,52 @@
+package ode
+
+// ComputeComplex integrates the system of differential equations
+// dy/dx = f(x, y) where y is complex using an integrator. See
+// Integrator.Compute.
+//
+// The input function dydx(x, y, f) evaluates f(x, y) for a given x and y and
+// stores the result in its last argument. The initial condition is y0. The
+// system is passed to the integrator as a real one of twice the dimension in
+// which the real and imaginary parts of each component are adjacent.
+// Consequently, the error control of the integrator applies to the real and
+// imaginary parts separately; that is, the absolute and relative tolerances
+// bound the error of each part rather than the modulus of the error of each
+// component.
+func ComputeComplex(integrator Integrator, dydx func(float64, []complex128, []complex128),
+	y0 []complex128, xs []float64) ([]complex128, []float64, error) {
+
+	nd := len(y0)
+
+	z, g := make([]complex128, nd), make([]complex128, nd)
+
+	ys, xs, err := integrator.Compute(func(x float64, y, f []float64) {
+		combine(y, z)
+		dydx(x, z, g)
+		split(g, f)
+	}, split(y0, nil), xs)
+
+	return combine(ys, nil), xs, err
+}
+
+// split stores the real and imaginary parts of a complex vector in a real one
+// of twice the length, which is allocated if nil.
+func split(z []complex128, y []float64) []float64 {
+	if y == nil {
+		y = make([]float64, 2*len(z))
+	}
+	for i, v := range z {
+		y[2*i], y[2*i+1] = real(v), imag(v)
+	}
+	return y
+}
+
+// combine is the inverse of split.
+func combine(y []float64, z []complex128) []complex128 {
+	if z == nil {
+		z = make([]complex128, len(y)/2)
+	}
+	for i := range z {
+		z[i] = complex(y[2*i], y[2*i+1])
+	}
+	return z
+}
@@ -0,0 +1,54 @@
+package dde
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode/dopri"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The configuration of the underlying integrator. Its maximal step is
+	// limited by the smallest delay, and the discontinuities propagated from
+	// the initial point are added to its stops.
+	Integrator dopri.Config `json:"integrator"`
+	// The delays, which should be positive.
+	Delays []float64 `json:"delays"`
+	// The number of delays that are added up when propagating the
+	// discontinuity of the derivative at the initial point. The integrator
+	// lands exactly on x₀ + n₁τ₁ + … + nₖτₖ for n₁ + … + nₖ ≤ Levels, beyond
+	// which the solution is assumed to be smooth enough.
+	Levels uint `json:"levels"`
+}
+
+// DefaultConfig returns the default configuration of an integrator with the
+// given delays.
+func DefaultConfig(delays ...float64) *Config {
+	return &Config{
+		Integrator: *dopri.DefaultConfig(),
+		Delays:     delays,
+		Levels:     3,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if len(c.Delays) == 0 {
+		return errors.New("the delays should be given")
+	}
+	for _, τ := range c.Delays {
+		if !(τ > 0) {
+			return errors.New("the delays should be positive")
+		}
+	}
+	if len(c.Integrator.Stops) > 0 || len(c.Integrator.Events) > 0 {
+		return errors.New("the stops and events of the underlying integrator are not supported")
+	}
+
+	return nil
+}
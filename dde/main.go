@@ -0,0 +1,168 @@
+// Package dde provides an integrator of systems of delay differential
+// equations with constant delays of the form
+//
+//	y′(x) = f(x, y(x), y(x - τ₁), …, y(x - τₖ))
+//
+// for x ≥ x₀ and y(x) = φ(x) for x < x₀ where φ is a history function.
+//
+// The system is integrated by the Dormand–Prince method. The delayed values
+// are obtained from the dense output of the steps taken so far or from the
+// history function. The step size is bounded by the smallest delay so that
+// the delayed values are always available, and the integrator lands on the
+// points where the discontinuity of the derivative at x₀ propagates.
+//
+// https://en.wikipedia.org/wiki/Delay_differential_equation
+package dde
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/ready-steady/ode/dopri"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of delay differential equations.
+//
+// The input function dydx(x, y, z, f) evaluates f for a given x, y(x), and
+// delayed values z and stores the result in its last argument. The delayed
+// values are stored one after another in the order of Config.Delays; that is,
+// z[j*nd:(j+1)*nd] is y(x - τⱼ). The function history(x, y) evaluates φ(x) for
+// x < x₀ and stores the result in y. The initial condition is y0, and xs is
+// treated as by Integrator.Compute in the parent package except that xend
+// should be greater than x0.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64, []float64),
+	history func(float64, []float64), y0, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, history, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64, []float64),
+	history func(float64, []float64), y0, xs []float64) ([]float64, []float64, *dopri.Stats,
+	error) {
+
+	config := &self.config
+
+	nx := len(xs)
+	if nx < 2 {
+		return nil, nil, nil, errors.New("the interval should have two endpoints")
+	}
+
+	x0, xend := xs[0], xs[nx-1]
+	if !(xend > x0) {
+		return nil, nil, nil, errors.New("the interval should be traversed forward")
+	}
+
+	nd, nτ := len(y0), len(config.Delays)
+
+	var steps []*dopri.Interpolant
+
+	inner := config.Integrator
+
+	τmin := config.Delays[0]
+	for _, τ := range config.Delays {
+		τmin = math.Min(τmin, τ)
+	}
+	if inner.MaxStep == 0 || inner.MaxStep > τmin {
+		inner.MaxStep = τmin
+	}
+	inner.Stops = discontinuities(config.Delays, config.Levels, x0, xend)
+
+	callback := inner.Callback
+	inner.Callback = func(interpolant *dopri.Interpolant) {
+		steps = append(steps, interpolant.Clone())
+		if callback != nil {
+			callback(interpolant)
+		}
+	}
+
+	integrator, err := dopri.New(&inner)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	initial := append([]float64(nil), y0...)
+
+	// delay evaluates the solution at a point before the current one.
+	delay := func(x float64, y []float64) {
+		if x < x0 {
+			history(x, y)
+			return
+		}
+		k := sort.Search(len(steps), func(k int) bool {
+			return steps[k].X+steps[k].H >= x
+		})
+		if k == len(steps) {
+			if k == 0 {
+				copy(y, initial)
+				return
+			}
+			k--
+		}
+		steps[k].Evaluate(x, y)
+	}
+
+	z := make([]float64, nτ*nd)
+
+	return integrator.ComputeWithStats(func(x float64, y, f []float64) {
+		for j, τ := range config.Delays {
+			delay(x-τ, z[j*nd:(j+1)*nd])
+		}
+		dydx(x, y, z, f)
+	}, y0, xs)
+}
+
+// discontinuities returns the sorted points within (x0, xend) where the
+// discontinuity of the derivative at x0 propagates up to a given level.
+func discontinuities(delays []float64, levels uint, x0, xend float64) []float64 {
+	var points []float64
+
+	level := []float64{x0}
+	for l := uint(0); l < levels; l++ {
+		var next []float64
+		for _, x := range level {
+			for _, τ := range delays {
+				if x+τ < xend {
+					next = append(next, x+τ)
+				}
+			}
+		}
+		level = unique(next)
+		points = append(points, level...)
+	}
+
+	return unique(points)
+}
+
+// unique sorts points and removes those that are indistinguishable from their
+// predecessors.
+func unique(points []float64) []float64 {
+	sort.Float64s(points)
+
+	var result []float64
+	for _, x := range points {
+		n := len(result)
+		if n == 0 || x-result[n-1] > 16*(math.Nextafter(x, math.Inf(1))-x) {
+			result = append(result, x)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,53 @@
+package dde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestCompute(t *testing.T) {
+	// y′(x) = -y(x - 1) with y(x) = 1 for x ≤ 0, whose solution is piecewise
+	// polynomial and can be found using the method of steps.
+	dydx := func(_ float64, _, z, f []float64) {
+		f[0] = -z[0]
+	}
+	history := func(_ float64, y []float64) {
+		y[0] = 1
+	}
+
+	config := DefaultConfig(1)
+	config.Integrator.AbsError = 1e-10
+	config.Integrator.RelError = 1e-10
+
+	integrator, _ := New(config)
+
+	xs := []float64{0, 0.5, 1, 1.5, 2, 3}
+	ys, _, stats, err := integrator.ComputeWithStats(dydx, history, []float64{1}, xs)
+	assert.Equal(err, nil, t)
+	assert.Close(ys, []float64{1, 0.5, 0, -0.375, -0.5, -1.0 / 6}, 1e-9, t)
+	assert.Equal(stats.Steps > 0, true, t)
+}
+
+func TestComputeLogistic(t *testing.T) {
+	// Hutchinson’s equation y′(x) = y(x) (1 - y(x - τ)), whose solution
+	// converges to the equilibrium at one for τ < π/2.
+	dydx := func(_ float64, y, z, f []float64) {
+		f[0] = y[0] * (1 - z[0])
+	}
+	history := func(x float64, y []float64) {
+		y[0] = 0.5 + 0.1*math.Sin(x)
+	}
+
+	integrator, _ := New(DefaultConfig(1))
+
+	ys, _, err := integrator.Compute(dydx, history, []float64{0.5}, []float64{0, 100})
+	assert.Equal(err, nil, t)
+	assert.Close(ys[len(ys)-1], 1.0, 1e-3, t)
+}
+
+func TestDiscontinuities(t *testing.T) {
+	points := discontinuities([]float64{1, 1.5}, 3, 0, 4)
+	assert.Equal(points, []float64{1, 1.5, 2, 2.5, 3, 3.5}, t)
+}
@@ -0,0 +1,181 @@
+package dop853
+
+// The coefficients of the method given by Hairer et al. in the DOP853 code.
+const (
+	c2  = 0.05260015195876773
+	c3  = 0.0789002279381516
+	c4  = 0.1183503419072274
+	c5  = 0.2816496580927726
+	c6  = 0.3333333333333333
+	c7  = 0.25
+	c8  = 0.3076923076923077
+	c9  = 0.6512820512820513
+	c10 = 0.6
+	c11 = 0.8571428571428571
+	c12 = 1.0
+	c14 = 0.1
+	c15 = 0.2
+	c16 = 0.7777777777777778
+
+	a2_1 = 0.05260015195876773
+
+	a3_1 = 0.0197250569845379
+	a3_2 = 0.0591751709536137
+
+	a4_1 = 0.02958758547680685
+	a4_3 = 0.08876275643042054
+
+	a5_1 = 0.2413651341592667
+	a5_3 = -0.8845494793282861
+	a5_4 = 0.924834003261792
+
+	a6_1 = 0.037037037037037035
+	a6_4 = 0.17082860872947386
+	a6_5 = 0.12546768756682242
+
+	a7_1 = 0.037109375
+	a7_4 = 0.17025221101954405
+	a7_5 = 0.06021653898045596
+	a7_6 = -0.017578125
+
+	a8_1 = 0.03709200011850479
+	a8_4 = 0.17038392571223998
+	a8_5 = 0.10726203044637328
+	a8_6 = -0.015319437748624402
+	a8_7 = 0.008273789163814023
+
+	a9_1 = 0.6241109587160757
+	a9_4 = -3.3608926294469414
+	a9_5 = -0.868219346841726
+	a9_6 = 27.59209969944671
+	a9_7 = 20.154067550477894
+	a9_8 = -43.48988418106996
+
+	a10_1 = 0.47766253643826434
+	a10_4 = -2.4881146199716677
+	a10_5 = -0.590290826836843
+	a10_6 = 21.230051448181193
+	a10_7 = 15.279233632882423
+	a10_8 = -33.28821096898486
+	a10_9 = -0.020331201708508627
+
+	a11_1  = -0.9371424300859873
+	a11_4  = 5.186372428844064
+	a11_5  = 1.0914373489967295
+	a11_6  = -8.149787010746927
+	a11_7  = -18.52006565999696
+	a11_8  = 22.739487099350505
+	a11_9  = 2.4936055526796523
+	a11_10 = -3.0467644718982196
+
+	a12_1  = 2.273310147516538
+	a12_4  = -10.53449546673725
+	a12_5  = -2.0008720582248625
+	a12_6  = -17.9589318631188
+	a12_7  = 27.94888452941996
+	a12_8  = -2.8589982771350235
+	a12_9  = -8.87285693353063
+	a12_10 = 12.360567175794303
+	a12_11 = 0.6433927460157636
+
+	a14_1  = 0.056167502283047954
+	a14_7  = 0.25350021021662483
+	a14_8  = -0.2462390374708025
+	a14_9  = -0.12419142326381637
+	a14_10 = 0.15329179827876568
+	a14_11 = 0.00820105229563469
+	a14_12 = 0.007567897660545699
+	a14_13 = -0.008298
+
+	a15_1  = 0.03183464816350214
+	a15_6  = 0.028300909672366776
+	a15_7  = 0.053541988307438566
+	a15_8  = -0.05492374857139099
+	a15_11 = -0.00010834732869724932
+	a15_12 = 0.0003825710908356584
+	a15_13 = -0.00034046500868740456
+	a15_14 = 0.1413124436746325
+
+	a16_1  = -0.42889630158379194
+	a16_6  = -4.697621415361164
+	a16_7  = 7.683421196062599
+	a16_8  = 4.06898981839711
+	a16_9  = 0.3567271874552811
+	a16_13 = -0.0013990241651590145
+	a16_14 = 2.9475147891527724
+	a16_15 = -9.15095847217987
+
+	b1  = 0.054293734116568765
+	b6  = 4.450312892752409
+	b7  = 1.8915178993145003
+	b8  = -5.801203960010585
+	b9  = 0.3111643669578199
+	b10 = -0.1521609496625161
+	b11 = 0.20136540080403034
+	b12 = 0.04471061572777259
+
+	bhh1  = 0.2440944881889764
+	bhh9  = 0.7338466882816118
+	bhh12 = 0.022058823529411766
+
+	er1  = 0.01312004499419488
+	er6  = -1.2251564463762044
+	er7  = -0.4957589496572502
+	er8  = 1.6643771824549864
+	er9  = -0.35032884874997366
+	er10 = 0.3341791187130175
+	er11 = 0.08192320648511571
+	er12 = -0.022355307863886294
+
+	d4_1  = -8.428938276109013
+	d4_6  = 0.5667149535193777
+	d4_7  = -3.0689499459498917
+	d4_8  = 2.38466765651207
+	d4_9  = 2.117034582445028
+	d4_10 = -0.871391583777973
+	d4_11 = 2.2404374302607883
+	d4_12 = 0.6315787787694688
+	d4_13 = -0.08899033645133331
+	d4_14 = 18.148505520854727
+	d4_15 = -9.194632392478356
+	d4_16 = -4.436036387594894
+
+	d5_1  = 10.427508642579134
+	d5_6  = 242.28349177525817
+	d5_7  = 165.20045171727028
+	d5_8  = -374.5467547226902
+	d5_9  = -22.113666853125306
+	d5_10 = 7.733432668472264
+	d5_11 = -30.674084731089398
+	d5_12 = -9.332130526430229
+	d5_13 = 15.697238121770845
+	d5_14 = -31.139403219565178
+	d5_15 = -9.35292435884448
+	d5_16 = 35.81684148639408
+
+	d6_1  = 19.985053242002433
+	d6_6  = -387.0373087493518
+	d6_7  = -189.17813819516758
+	d6_8  = 527.8081592054236
+	d6_9  = -11.57390253995963
+	d6_10 = 6.8812326946963
+	d6_11 = -1.0006050966910838
+	d6_12 = 0.7777137798053443
+	d6_13 = -2.778205752353508
+	d6_14 = -60.19669523126412
+	d6_15 = 84.32040550667716
+	d6_16 = 11.99229113618279
+
+	d7_1  = -25.69393346270375
+	d7_6  = -154.18974869023643
+	d7_7  = -231.5293791760455
+	d7_8  = 357.6391179106141
+	d7_9  = 93.40532418362432
+	d7_10 = -37.45832313645163
+	d7_11 = 104.0996495089623
+	d7_12 = 29.8402934266605
+	d7_13 = -43.53345659001114
+	d7_14 = 96.32455395918828
+	d7_15 = -39.17726167561544
+	d7_16 = -149.72683625798564
+)
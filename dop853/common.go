@@ -0,0 +1,22 @@
+package dop853
+
+import (
+	"github.com/ready-steady/ode"
+)
+
+// ComputeWithSummary augments Compute by returning the work done in the form
+// common to all integrators; see ode.StatsIntegrator.
+func (self *Integrator) ComputeWithSummary(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *ode.Stats, error) {
+
+	ys, xs, stats, err := self.ComputeWithStats(dydx, y0, xs)
+	if stats == nil {
+		return ys, xs, nil, err
+	}
+
+	return ys, xs, &ode.Stats{
+		Evaluations: stats.Evaluations,
+		Rejections:  stats.Rejections,
+		Steps:       stats.Steps,
+	}, err
+}
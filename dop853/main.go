@@ -0,0 +1,486 @@
+// Package dop853 provides an integrator of systems of ordinary differential
+// equations based on the eighth-order Dormand–Prince method with the error
+// estimator of orders five and three and the dense output of order seven.
+//
+// https://en.wikipedia.org/wiki/Dormand%E2%80%93Prince_method
+package dop853
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	const (
+		power   = 1.0 / 8
+		safety  = 0.9
+		minimal = 1.0 / 3
+		maximal = 6.0
+	)
+
+	stats := &Stats{}
+
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	z := make([]float64, nd)
+	y := make([]float64, nd)
+	ynew := make([]float64, nd)
+
+	f := make([]float64, 16*nd)
+	f1 := f[0*nd : 1*nd]
+	f2 := f[1*nd : 2*nd]
+	f3 := f[2*nd : 3*nd]
+	f4 := f[3*nd : 4*nd]
+	f5 := f[4*nd : 5*nd]
+	f6 := f[5*nd : 6*nd]
+	f7 := f[6*nd : 7*nd]
+	f8 := f[7*nd : 8*nd]
+	f9 := f[8*nd : 9*nd]
+	f10 := f[9*nd : 10*nd]
+	f11 := f[10*nd : 11*nd]
+	f12 := f[11*nd : 12*nd]
+	f13 := f[12*nd : 13*nd]
+
+	r := make([]float64, 8*nd)
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	dydx(x, y, f1)
+	stats.Evaluations++
+
+	config := &self.config
+
+	abserr, relerr := config.AbsError, config.RelError
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = xend - x
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = initialize(dydx, x, y, f1, f2, z, hmax, abserr, relerr)
+		stats.Evaluations++
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	for done := false; ; {
+		var xnew, ε float64
+
+		stats.Steps++
+
+		hmin := 16 * epsilon(x)
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		// Close to the end?
+		if 1.01*h >= xend-x {
+			h = xend - x
+			done = true
+		}
+
+		rejected := false
+
+		for {
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*a2_1*f1[i]
+			}
+			dydx(x+c2*h, z, f2)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a3_1*f1[i]+a3_2*f2[i])
+			}
+			dydx(x+c3*h, z, f3)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a4_1*f1[i]+a4_3*f3[i])
+			}
+			dydx(x+c4*h, z, f4)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a5_1*f1[i]+a5_3*f3[i]+a5_4*f4[i])
+			}
+			dydx(x+c5*h, z, f5)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a6_1*f1[i]+a6_4*f4[i]+a6_5*f5[i])
+			}
+			dydx(x+c6*h, z, f6)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a7_1*f1[i]+a7_4*f4[i]+a7_5*f5[i]+a7_6*f6[i])
+			}
+			dydx(x+c7*h, z, f7)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a8_1*f1[i]+a8_4*f4[i]+a8_5*f5[i]+a8_6*f6[i]+
+					a8_7*f7[i])
+			}
+			dydx(x+c8*h, z, f8)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a9_1*f1[i]+a9_4*f4[i]+a9_5*f5[i]+a9_6*f6[i]+
+					a9_7*f7[i]+a9_8*f8[i])
+			}
+			dydx(x+c9*h, z, f9)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a10_1*f1[i]+a10_4*f4[i]+a10_5*f5[i]+a10_6*f6[i]+
+					a10_7*f7[i]+a10_8*f8[i]+a10_9*f9[i])
+			}
+			dydx(x+c10*h, z, f10)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a11_1*f1[i]+a11_4*f4[i]+a11_5*f5[i]+a11_6*f6[i]+
+					a11_7*f7[i]+a11_8*f8[i]+a11_9*f9[i]+a11_10*f10[i])
+			}
+			dydx(x+c11*h, z, f11)
+
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + h*(a12_1*f1[i]+a12_4*f4[i]+a12_5*f5[i]+a12_6*f6[i]+
+					a12_7*f7[i]+a12_8*f8[i]+a12_9*f9[i]+a12_10*f10[i]+a12_11*f11[i])
+			}
+			dydx(x+h, z, f12)
+
+			for i := 0; i < nd; i++ {
+				ynew[i] = y[i] + h*(b1*f1[i]+b6*f6[i]+b7*f7[i]+b8*f8[i]+b9*f9[i]+
+					b10*f10[i]+b11*f11[i]+b12*f12[i])
+			}
+
+			xnew = x + h
+
+			stats.Evaluations += 11
+
+			// Compute the error by combining the estimators of orders five
+			// and three.
+			ε5, ε3 := 0.0, 0.0
+			for i := 0; i < nd; i++ {
+				scale := y[i]
+				if scale < 0 {
+					scale = -scale
+				}
+				if ynew[i] > 0 {
+					if ynew[i] > scale {
+						scale = ynew[i]
+					}
+				} else {
+					if -ynew[i] > scale {
+						scale = -ynew[i]
+					}
+				}
+				scale = abserr + relerr*scale
+
+				e := (b1-bhh1)*f1[i] + b6*f6[i] + b7*f7[i] + b8*f8[i] +
+					(b9-bhh9)*f9[i] + b10*f10[i] + b11*f11[i] + (b12-bhh12)*f12[i]
+				e /= scale
+				ε3 += e * e
+
+				e = er1*f1[i] + er6*f6[i] + er7*f7[i] + er8*f8[i] + er9*f9[i] +
+					er10*f10[i] + er11*f11[i] + er12*f12[i]
+				e /= scale
+				ε5 += e * e
+			}
+
+			denominator := ε5 + 0.01*ε3
+			if denominator <= 0 {
+				denominator = 1
+			}
+			ε = h * ε5 * math.Sqrt(1/(float64(nd)*denominator))
+
+			if ε <= 1 {
+				break
+			}
+
+			stats.Rejections++
+
+			if h <= hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			// Shrink the step size as the current one has been rejected.
+			scale := math.Pow(ε, power) / safety
+			if scale > 1/minimal {
+				scale = 1 / minimal
+			}
+			h = h / scale
+
+			if h < hmin {
+				h = hmin
+			}
+
+			done = false
+			rejected = true
+		}
+
+		dydx(xnew, ynew, f13)
+		stats.Evaluations++
+
+		if fixed {
+			prepared := false
+
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					copy(ys[nc*nd:(nc+1)*nd], ynew)
+				} else {
+					if !prepared {
+						prepare(dydx, x, y, ynew, f, h, z, r)
+						stats.Evaluations += 3
+						prepared = true
+					}
+					interpolate(r, (xs[nc]-x)/h, ys[nc*nd:(nc+1)*nd])
+				}
+
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		if done {
+			break
+		}
+
+		x = xnew
+		copy(f1, f13)
+		copy(y, ynew)
+
+		// Compute a new step size.
+		scale := math.Pow(ε, power) / safety
+		if scale < 1/maximal {
+			scale = 1 / maximal
+		} else if scale > 1/minimal {
+			scale = 1 / minimal
+		}
+		if rejected && scale < 1 {
+			scale = 1
+		}
+		h = h / scale
+	}
+
+	return ys, xs, stats, nil
+}
+
+func initialize(dydx func(float64, []float64, []float64), x float64, y, f, fnew,
+	ynew []float64, hmax, abserr, relerr float64) float64 {
+
+	const (
+		order = 8
+	)
+
+	nd := len(y)
+
+	dnf, dny := 0.0, 0.0
+	for i := 0; i < nd; i++ {
+		scale := abserr + relerr*math.Abs(y[i])
+		dnf += (f[i] / scale) * (f[i] / scale)
+		dny += (y[i] / scale) * (y[i] / scale)
+	}
+
+	var h float64
+	if dnf <= 1e-10 || dny <= 1e-10 {
+		h = 1e-6
+	} else {
+		h = 0.01 * math.Sqrt(dny/dnf)
+	}
+	if h > hmax {
+		h = hmax
+	}
+
+	// Perform an explicit Euler step.
+	for i := 0; i < nd; i++ {
+		ynew[i] = y[i] + h*f[i]
+	}
+	dydx(x+h, ynew, fnew)
+
+	// Estimate the second derivative of the solution.
+	der2 := 0.0
+	for i := 0; i < nd; i++ {
+		scale := abserr + relerr*math.Abs(y[i])
+		d := (fnew[i] - f[i]) / scale
+		der2 += d * d
+	}
+	der2 = math.Sqrt(der2) / h
+
+	der12 := math.Max(math.Abs(der2), math.Sqrt(dnf))
+
+	var h1 float64
+	if der12 <= 1e-15 {
+		h1 = math.Max(1e-6, h*1e-3)
+	} else {
+		h1 = math.Pow(0.01/der12, 1.0/order)
+	}
+
+	return math.Min(math.Min(100*h, h1), hmax)
+}
+
+func prepare(dydx func(float64, []float64, []float64), x float64, y, ynew, f []float64,
+	h float64, z, r []float64) {
+
+	nd := len(y)
+
+	f1 := f[0*nd : 1*nd]
+	f6 := f[5*nd : 6*nd]
+	f7 := f[6*nd : 7*nd]
+	f8 := f[7*nd : 8*nd]
+	f9 := f[8*nd : 9*nd]
+	f10 := f[9*nd : 10*nd]
+	f11 := f[10*nd : 11*nd]
+	f12 := f[11*nd : 12*nd]
+	f13 := f[12*nd : 13*nd]
+	f14 := f[13*nd : 14*nd]
+	f15 := f[14*nd : 15*nd]
+	f16 := f[15*nd : 16*nd]
+
+	r1 := r[0*nd : 1*nd]
+	r2 := r[1*nd : 2*nd]
+	r3 := r[2*nd : 3*nd]
+	r4 := r[3*nd : 4*nd]
+	r5 := r[4*nd : 5*nd]
+	r6 := r[5*nd : 6*nd]
+	r7 := r[6*nd : 7*nd]
+	r8 := r[7*nd : 8*nd]
+
+	for i := 0; i < nd; i++ {
+		δ := ynew[i] - y[i]
+		β := h*f1[i] - δ
+
+		r1[i] = y[i]
+		r2[i] = δ
+		r3[i] = β
+		r4[i] = δ - h*f13[i] - β
+
+		r5[i] = d4_1*f1[i] + d4_6*f6[i] + d4_7*f7[i] + d4_8*f8[i] + d4_9*f9[i] +
+			d4_10*f10[i] + d4_11*f11[i] + d4_12*f12[i] + d4_13*f13[i]
+		r6[i] = d5_1*f1[i] + d5_6*f6[i] + d5_7*f7[i] + d5_8*f8[i] + d5_9*f9[i] +
+			d5_10*f10[i] + d5_11*f11[i] + d5_12*f12[i] + d5_13*f13[i]
+		r7[i] = d6_1*f1[i] + d6_6*f6[i] + d6_7*f7[i] + d6_8*f8[i] + d6_9*f9[i] +
+			d6_10*f10[i] + d6_11*f11[i] + d6_12*f12[i] + d6_13*f13[i]
+		r8[i] = d7_1*f1[i] + d7_6*f6[i] + d7_7*f7[i] + d7_8*f8[i] + d7_9*f9[i] +
+			d7_10*f10[i] + d7_11*f11[i] + d7_12*f12[i] + d7_13*f13[i]
+	}
+
+	// Compute the three additional stages of the dense output.
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h*(a14_1*f1[i]+a14_7*f7[i]+a14_8*f8[i]+a14_9*f9[i]+
+			a14_10*f10[i]+a14_11*f11[i]+a14_12*f12[i]+a14_13*f13[i])
+	}
+	dydx(x+c14*h, z, f14)
+
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h*(a15_1*f1[i]+a15_6*f6[i]+a15_7*f7[i]+a15_8*f8[i]+
+			a15_11*f11[i]+a15_12*f12[i]+a15_13*f13[i]+a15_14*f14[i])
+	}
+	dydx(x+c15*h, z, f15)
+
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h*(a16_1*f1[i]+a16_6*f6[i]+a16_7*f7[i]+a16_8*f8[i]+
+			a16_9*f9[i]+a16_13*f13[i]+a16_14*f14[i]+a16_15*f15[i])
+	}
+	dydx(x+c16*h, z, f16)
+
+	for i := 0; i < nd; i++ {
+		r5[i] = h * (r5[i] + d4_14*f14[i] + d4_15*f15[i] + d4_16*f16[i])
+		r6[i] = h * (r6[i] + d5_14*f14[i] + d5_15*f15[i] + d5_16*f16[i])
+		r7[i] = h * (r7[i] + d6_14*f14[i] + d6_15*f15[i] + d6_16*f16[i])
+		r8[i] = h * (r8[i] + d7_14*f14[i] + d7_15*f15[i] + d7_16*f16[i])
+	}
+}
+
+func interpolate(r []float64, θ float64, ynext []float64) {
+	nd := len(ynext)
+
+	r1 := r[0*nd : 1*nd]
+	r2 := r[1*nd : 2*nd]
+	r3 := r[2*nd : 3*nd]
+	r4 := r[3*nd : 4*nd]
+	r5 := r[4*nd : 5*nd]
+	r6 := r[5*nd : 6*nd]
+	r7 := r[6*nd : 7*nd]
+	r8 := r[7*nd : 8*nd]
+
+	θ1 := 1 - θ
+
+	for i := 0; i < nd; i++ {
+		c := r5[i] + θ*(r6[i]+θ1*(r7[i]+θ*r8[i]))
+		ynext[i] = r1[i] + θ*(r2[i]+θ1*(r3[i]+θ*(r4[i]+θ1*c)))
+	}
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
@@ -0,0 +1,77 @@
+package dop853
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeExponential(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = y[0] * math.Cos(x)
+	}
+
+	xs := make([]float64, 21)
+	for i := range xs {
+		xs[i] = 0.5 * float64(i)
+	}
+
+	integrator, _ := New(&Config{AbsError: 1e-12, RelError: 1e-12})
+
+	ys, _, stats, err := integrator.ComputeWithStats(dydx, []float64{1}, xs)
+	assert.Equal(err, nil, t)
+
+	for i, x := range xs {
+		assert.Close(ys[i], math.Exp(math.Sin(x)), 1e-10, t)
+	}
+	assert.Equal(stats.Steps < 100, true, t)
+}
+
+func TestComputeOscillator(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{AbsError: 1e-10, RelError: 1e-10})
+
+	ys, xs, stats, _ := integrator.ComputeWithStats(dydx, []float64{0, 1}, []float64{0, 20})
+
+	nx := len(xs)
+	assert.Equal(xs[nx-1], 20.0, t)
+	for i, x := range xs {
+		assert.Close(ys[2*i:2*i+2], []float64{math.Sin(x), math.Cos(x)}, 1e-8, t)
+	}
+	assert.Equal(stats.Evaluations, 2+12*stats.Steps+11*stats.Rejections, t)
+}
+
+func TestComputeDense(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -2 * y[0]
+	}
+
+	xs := []float64{0, 0.1, 0.15, 0.7, 1}
+
+	integrator, _ := New(&Config{MaxStep: 1, AbsError: 1e-8, RelError: 1e-8})
+
+	ys, _, _, _ := integrator.ComputeWithStats(dydx, []float64{1}, xs)
+	for i, x := range xs {
+		assert.Close(ys[i], math.Exp(-2*x), 1e-8, t)
+	}
+}
+
+func BenchmarkComputeOscillator(b *testing.B) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{AbsError: 1e-10, RelError: 1e-10})
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		integrator.ComputeWithStats(dydx, []float64{0, 1}, []float64{0, 20})
+	}
+}
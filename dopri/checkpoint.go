@@ -0,0 +1,8 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Checkpoint is a snapshot of a stepper.
+type Checkpoint = erk.Checkpoint
@@ -1,44 +1,45 @@
 package dopri
 
 import (
-	"errors"
+	"github.com/ready-steady/ode/erk"
 )
 
 // Config is the configuration of an integrator.
-type Config struct {
-	// The initial step of integration.
-	TryStep float64
-	// The maximal step of integration.
-	MaxStep float64
-	// The absolute error tolerance.
-	AbsError float64
-	// The relative error tolerance.
-	RelError float64
-}
+type Config = erk.Config
 
 // DefaultConfig returns the default configuration of an integrator.
 func DefaultConfig() *Config {
-	return &Config{
-		TryStep:  0,
-		MaxStep:  0,
-		AbsError: 1e-6,
-		RelError: 1e-3,
-	}
+	return erk.DefaultConfig()
 }
 
-func (c *Config) verify() error {
-	if c.TryStep < 0 {
-		return errors.New("the initial step should be nonnegative")
-	}
-	if c.MaxStep < 0 {
-		return errors.New("the maximal step should be nonnegative")
-	}
-	if c.AbsError <= 0 {
-		return errors.New("the absolute error tolerance should be positive")
-	}
-	if c.RelError <= 0 {
-		return errors.New("the relative error tolerance should be positive")
-	}
-
-	return nil
+// Controller is a choice of a controller of the step size.
+type Controller = erk.Controller
+
+// The controllers of the step size; see the corresponding constants of the
+// erk package.
+const (
+	Integral = erk.Integral
+	PI       = erk.PI
+	PID      = erk.PID
+)
+
+// Norm is a choice of a norm of the error estimate.
+type Norm = erk.Norm
+
+// The norms of the error estimate; see the corresponding constants of the erk
+// package.
+const (
+	MaxNorm = erk.MaxNorm
+	RMSNorm = erk.RMSNorm
+)
+
+// Bound is a constraint on a component of the solution.
+type Bound = erk.Bound
+
+// NonNegative returns the bound that keeps a component nonnegative.
+func NonNegative(index uint) Bound {
+	return erk.NonNegative(index)
 }
+
+// Invariant is a quantity conserved by the system.
+type Invariant = erk.Invariant
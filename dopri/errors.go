@@ -0,0 +1,24 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// The kinds of the errors returned by the package, which can be checked using
+// errors.Is.
+var (
+	ErrInvalidConfig   = erk.ErrInvalidConfig
+	ErrInvalidArgument = erk.ErrInvalidArgument
+	ErrStepUnderflow   = erk.ErrStepUnderflow
+	ErrMaxSteps        = erk.ErrMaxSteps
+	ErrMaxEvaluations  = erk.ErrMaxEvaluations
+	ErrMaxDuration     = erk.ErrMaxDuration
+	ErrNotFinite       = erk.ErrNotFinite
+)
+
+// Error is an error that occurs during an integration.
+type Error = erk.Error
+
+// FiniteError is the error that occurs when a derivative or a solution is not
+// finite.
+type FiniteError = erk.FiniteError
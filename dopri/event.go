@@ -0,0 +1,11 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Event is an event whose occurrences are located during the integration.
+type Event = erk.Event
+
+// Crossing is an occurrence of an event located during the integration.
+type Crossing = erk.Crossing
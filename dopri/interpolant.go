@@ -0,0 +1,8 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Interpolant is the interpolant of the method over a step.
+type Interpolant = erk.Interpolant
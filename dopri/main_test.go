@@ -1,7 +1,18 @@
 package dopri
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ready-steady/assert"
 )
@@ -44,7 +55,9 @@ func TestComputeToy(t *testing.T) {
 
 	ys, _, stats, _ := integrator.ComputeWithStats(dydx, input.y0, input.xs)
 	assert.Close(ys, output.ys, 1e-15, t)
-	assert.Equal(*stats, Stats{Evaluations: 61, Rejections: 0, Steps: 10}, t)
+	assert.Equal([]uint{stats.Evaluations, stats.Rejections, stats.Steps}, []uint{61, 0, 10}, t)
+	assert.Equal(stats.Interpolations, uint(3), t)
+	assert.Close([]float64{stats.MinStep, stats.MaxStep, stats.LastStep}, []float64{0.1, 0.1, 0.1}, 1e-15, t)
 }
 
 func TestComputeNonstiff(t *testing.T) {
@@ -55,7 +68,7 @@ func TestComputeNonstiff(t *testing.T) {
 
 	ys, _, stats, _ := integrator.ComputeWithStats(input.dydx, input.y0, input.xs)
 	assert.Close(ys, output.ys, 1e-14, t)
-	assert.Equal(*stats, Stats{Evaluations: 151, Rejections: 3, Steps: 22}, t)
+	assert.Equal([]uint{stats.Evaluations, stats.Rejections, stats.Steps}, []uint{151, 3, 22}, t)
 }
 
 func TestComputeStiff(t *testing.T) {
@@ -67,7 +80,7 @@ func TestComputeStiff(t *testing.T) {
 	ys, xs, stats, _ := integrator.ComputeWithStats(input.dydx, input.y0, input.xs)
 	assert.Close(ys, output.ys, 3e-13, t)
 	assert.Close(xs, output.xs, 4e-9, t)
-	assert.Equal(*stats, Stats{Evaluations: 20179, Rejections: 323, Steps: 3040}, t)
+	assert.Equal([]uint{stats.Evaluations, stats.Rejections, stats.Steps}, []uint{20179, 323, 3040}, t)
 }
 
 func BenchmarkComputeNonstiff(b *testing.B) {
@@ -95,3 +108,1643 @@ func BenchmarkComputeStiff(b *testing.B) {
 		integrator.ComputeWithStats(input.dydx, input.y0, input.xs)
 	}
 }
+
+func BenchmarkComputeKepler(b *testing.B) {
+	const (
+		e = 0.5
+	)
+
+	dydx := func(_ float64, y, f []float64) {
+		r := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+		f[0], f[1] = y[2], y[3]
+		f[2], f[3] = -y[0]/r, -y[1]/r
+	}
+
+	y0 := []float64{1 - e, 0, 0, math.Sqrt((1 + e) / (1 - e))}
+
+	integrator, _ := New(&Config{AbsError: 1e-9, RelError: 1e-9})
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		integrator.Compute(dydx, y0, []float64{0, 2 * math.Pi})
+	}
+}
+
+func BenchmarkComputeLarge(b *testing.B) {
+	benchmarkLarge(b, 0)
+}
+
+func BenchmarkComputeLargeParallel(b *testing.B) {
+	benchmarkLarge(b, 4)
+}
+
+func benchmarkLarge(b *testing.B, parallelism uint) {
+	const (
+		nd = 200000
+	)
+
+	// A system of decoupled oscillators, whose derivative is cheap, so that
+	// the time is dominated by the combination of the stages.
+	dydx := func(_ float64, y, f []float64) {
+		for i := 0; i < nd; i += 2 {
+			f[i], f[i+1] = y[i+1], -y[i]
+		}
+	}
+
+	y0 := make([]float64, nd)
+	for i := 0; i < nd; i += 2 {
+		y0[i] = 1
+	}
+
+	config := DefaultConfig()
+	config.Parallelism = parallelism
+
+	integrator, _ := New(config)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		integrator.Compute(dydx, y0, []float64{0, 1})
+	}
+}
+
+func TestComputeWithEventsFall(t *testing.T) {
+	const g = 9.81
+
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -g
+	}
+
+	config := DefaultConfig()
+	config.Events = []Event{
+		{Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		}},
+	}
+
+	integrator, _ := New(config)
+
+	_, _, events, _, err := integrator.ComputeWithEvents(dydx, []float64{10, 0}, []float64{0, 2})
+	assert.Equal(err, nil, t)
+	assert.Equal(len(events), 1, t)
+	assert.Equal(events[0].Index, uint(0), t)
+	assert.Close(events[0].X, math.Sqrt(2*10/g), 1e-12, t)
+	assert.Close(events[0].Y, []float64{0, -math.Sqrt(2 * 10 * g)}, 1e-10, t)
+}
+
+func TestComputeWithEventsOrder(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-10
+	config.Events = []Event{
+		{Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		}},
+		{Function: func(_ float64, y []float64) float64 {
+			return y[1]
+		}},
+	}
+
+	integrator, _ := New(config)
+
+	_, _, events, _, err := integrator.ComputeWithEvents(dydx, []float64{1, 0}, []float64{0, 10})
+	assert.Equal(err, nil, t)
+
+	// The position vanishes at odd multiples of π/2 and the velocity at
+	// multiples of π, excluding the initial point.
+	assert.Equal(len(events), 6, t)
+	for i, event := range events {
+		assert.Equal(event.Index, uint(i%2), t)
+		assert.Close(event.X, float64(i+1)*math.Pi/2, 1e-8, t)
+	}
+}
+
+func TestComputeWithEventsDirection(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-10
+	config.Events = []Event{
+		{Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		}, Direction: 1},
+	}
+
+	integrator, _ := New(config)
+
+	_, _, crossings, _, _ := integrator.ComputeWithEvents(dydx, []float64{1, 0}, []float64{0, 12})
+
+	// The position increases through zero at 3π/2 + 2πk.
+	assert.Equal(len(crossings), 2, t)
+	assert.Close(crossings[0].X, 3*math.Pi/2, 1e-8, t)
+	assert.Close(crossings[1].X, 7*math.Pi/2, 1e-8, t)
+}
+
+func TestComputeWithEventsBounce(t *testing.T) {
+	const g = 9.81
+
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -g
+	}
+
+	config := DefaultConfig()
+	config.Events = []Event{
+		{Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		}, Direction: -1, Terminal: true},
+	}
+
+	integrator, _ := New(config)
+
+	xs := []float64{0, 0.5, 1, 1.5, 2, 2.5, 3}
+
+	ys, xs, crossings, _, err := integrator.ComputeWithEvents(dydx, []float64{10, 0}, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(len(crossings), 1, t)
+
+	// The ball hits the ground between 1 and 1.5.
+	x := math.Sqrt(2 * 10 / g)
+	assert.Equal(len(xs), 4, t)
+	assert.Equal(xs[:3], []float64{0, 0.5, 1}, t)
+	assert.Close(xs[3], x, 1e-12, t)
+	assert.Close(ys[6], 0.0, 1e-10, t)
+
+	// Restart with the velocity reversed and damped.
+	x0 := crossings[0].X
+	v := -0.5 * crossings[0].Y[1]
+	ys, xs, crossings, _, err = integrator.ComputeWithEvents(dydx, []float64{0, v}, []float64{x0, x0 + 10})
+	assert.Equal(err, nil, t)
+	assert.Equal(len(crossings), 1, t)
+	assert.Close(crossings[0].X-x0, 2*v/g, 1e-12, t)
+	assert.Equal(xs[len(xs)-1], crossings[0].X, t)
+	assert.Equal(ys[len(ys)-2:], crossings[0].Y, t)
+}
+
+func TestComputeWithEventsReset(t *testing.T) {
+	const g = 9.81
+
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -g
+	}
+
+	config := DefaultConfig()
+	config.Events = []Event{
+		{Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		}, Direction: -1, Terminal: true, Reset: func(_ float64, y []float64) {
+			y[0], y[1] = 0, -0.5*y[1]
+		}},
+	}
+
+	integrator, _ := New(config)
+
+	ys, xs, crossings, stats, err := integrator.ComputeWithEvents(dydx, []float64{10, 0},
+		[]float64{0, 3})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs[len(xs)-1], 3.0, t)
+
+	// The ball hits the ground twice, each time losing half of its speed.
+	x1 := math.Sqrt(2 * 10 / g)
+	v1 := 0.5 * g * x1
+	x2 := x1 + 2*v1/g
+	assert.Equal(len(crossings), 2, t)
+	assert.Equal(stats.Resets, uint(2), t)
+	assert.Close(crossings[0].X, x1, 1e-12, t)
+	assert.Close(crossings[1].X, x2, 1e-10, t)
+	assert.Close(crossings[0].Y[1], -g*x1, 1e-10, t)
+
+	// The crossings appear twice, before and after the resets.
+	for _, crossing := range crossings {
+		found := false
+		for i := 1; i < len(xs); i++ {
+			if xs[i-1] == crossing.X && xs[i] == crossing.X {
+				assert.Equal(ys[2*(i-1):2*i], crossing.Y, t)
+				assert.Close(ys[2*i+1], -0.5*crossing.Y[1], 1e-12, t)
+				found = true
+			}
+		}
+		assert.Equal(found, true, t)
+	}
+
+	// The solution at fixed points follows the bounces.
+	x := 2.9
+	ys, xs, _, _, err = integrator.ComputeWithEvents(dydx, []float64{10, 0}, []float64{0, 1, 2, x})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 1, 2, x}, t)
+	v2 := 0.5 * v1
+	assert.Close(ys[6], v2*(x-x2)-g*(x-x2)*(x-x2)/2, 1e-8, t)
+
+	config.Events[0].Terminal = false
+	_, err = New(config)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeWithStops(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		if x < 1 {
+			f[0] = math.Cos(y[0])
+		} else {
+			f[0] = -math.Cos(y[0])
+		}
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-8
+	config.RelError = 1e-8
+
+	integrator, _ := New(config)
+	zs, _, reference, _ := integrator.ComputeWithStats(dydx, []float64{0}, []float64{0, 2})
+
+	config.Stops = []float64{1}
+
+	integrator, _ = New(config)
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{0}, []float64{0, 2})
+	assert.Equal(err, nil, t)
+
+	found := false
+	for _, x := range xs {
+		found = found || x == 1
+	}
+	assert.Equal(found, true, t)
+	assert.Equal(stats.Rejections < reference.Rejections, true, t)
+
+	// The solution is symmetric with respect to the stop.
+	assert.Close(ys[len(ys)-1], 0.0, 1e-6, t)
+	assert.Equal(math.Abs(ys[len(ys)-1]) < math.Abs(zs[len(zs)-1]), true, t)
+}
+
+func TestComputeSteadyState(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = 2 - y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-8
+	config.SteadyState = 1e-6
+
+	integrator, _ := New(config)
+
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{0}, []float64{0, 1000})
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Steady, true, t)
+
+	// The derivative is 2 exp(-x), which falls below 2e-6 at x = ln(1e6).
+	n := len(xs)
+	assert.Equal(xs[n-1] > math.Log(1e6) && xs[n-1] < 20, true, t)
+	assert.Close(ys[n-1], 2.0, 2e-6, t)
+
+	config.SteadyState = 0
+
+	integrator, _ = New(config)
+
+	_, xs, stats, _ = integrator.ComputeWithStats(dydx, []float64{0}, []float64{0, 1000})
+	assert.Equal(stats.Steady, false, t)
+	assert.Equal(xs[len(xs)-1], 1000.0, t)
+}
+
+func TestComputeSection(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-10
+
+	integrator, _ := New(config)
+
+	section := Event{Function: func(_ float64, y []float64) float64 {
+		return y[0]
+	}, Direction: 1}
+
+	ys, xs, _, err := integrator.ComputeSection(dydx, section, []float64{1, 0}, []float64{0, 100})
+	assert.Equal(err, nil, t)
+
+	// The position increases through zero at 3π/2 + 2πk with unit velocity.
+	assert.Equal(len(xs), 16, t)
+	assert.Equal(len(ys), 2*16, t)
+	for i, x := range xs {
+		assert.Close(x, 3*math.Pi/2+2*math.Pi*float64(i), 1e-7, t)
+		assert.Close(ys[2*i:2*i+2], []float64{0, 1}, 1e-7, t)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-10
+
+	integrator, _ := New(config)
+
+	solution, _, err := integrator.Solve(dydx, []float64{1, 0}, []float64{0, 10})
+	assert.Equal(err, nil, t)
+
+	x0, xend := solution.Span()
+	assert.Equal(x0, 0.0, t)
+	assert.Equal(xend, 10.0, t)
+
+	y := make([]float64, 2)
+	for _, x := range []float64{0, 0.1, 1, math.Pi, 5.5, 9.99, 10} {
+		assert.Equal(solution.At(x, y), nil, t)
+		assert.Close(y, []float64{math.Cos(x), -math.Sin(x)}, 1e-8, t)
+	}
+
+	assert.Equal(solution.At(-1, y) != nil, true, t)
+	assert.Equal(solution.At(11, y) != nil, true, t)
+}
+
+func TestSolveTerminal(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.Events = []Event{
+		{Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		}, Terminal: true},
+	}
+
+	integrator, _ := New(config)
+
+	solution, _, err := integrator.Solve(dydx, []float64{1, 0}, []float64{0, 10})
+	assert.Equal(err, nil, t)
+
+	_, xend := solution.Span()
+	assert.Close(xend, math.Pi/2, 1e-3, t)
+}
+
+func TestComputeCallback(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	var interpolants []*Interpolant
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-10
+	config.Callback = func(interpolant *Interpolant) {
+		interpolants = append(interpolants, interpolant.Clone())
+	}
+
+	integrator, _ := New(config)
+
+	_, _, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 10})
+	assert.Equal(err, nil, t)
+	assert.Equal(uint(len(interpolants)), stats.Steps, t)
+
+	y := make([]float64, 2)
+	for k, interpolant := range interpolants {
+		if k > 0 {
+			previous := interpolants[k-1]
+			assert.Equal(previous.X+previous.H, interpolant.X, t)
+		}
+
+		x := interpolant.X + interpolant.H/3
+		interpolant.Evaluate(x, y)
+		assert.Close(y, []float64{math.Cos(x), -math.Sin(x)}, 1e-8, t)
+	}
+}
+
+func TestStepper(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-10
+
+	integrator, _ := New(config)
+
+	stepper := integrator.NewStepper(dydx)
+	stepper.Init(0, []float64{1, 0})
+
+	z := make([]float64, 2)
+	for xold := 0.0; xold < 10; {
+		x, y, h, err := stepper.Step()
+		assert.Equal(err, nil, t)
+		assert.Close(x, xold+h, 1e-14, t)
+		assert.Close(y, []float64{math.Cos(x), -math.Sin(x)}, 1e-8, t)
+
+		stepper.Interpolate(x-h/2, z)
+		assert.Close(z, []float64{math.Cos(x - h/2), -math.Sin(x - h/2)}, 1e-8, t)
+
+		xold = x
+	}
+
+	assert.Equal(stepper.Stats().Steps > 0, true, t)
+}
+
+func TestContinue(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-10
+
+	integrator, _ := New(config)
+
+	ys, xs, state, stats, err := integrator.ComputeWithState(dydx, []float64{1, 0}, []float64{0, 5})
+	assert.Equal(err, nil, t)
+	assert.Equal(state.X, 5.0, t)
+	assert.Equal(state.Y, ys[len(ys)-2:], t)
+	assert.Equal(xs[len(xs)-1], 5.0, t)
+
+	evaluations := stats.Evaluations
+
+	ys, xs, state, stats, err = integrator.Continue(dydx, state, []float64{5, 7, 10})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{5, 7, 10}, t)
+	assert.Close(ys, []float64{
+		math.Cos(5), -math.Sin(5),
+		math.Cos(7), -math.Sin(7),
+		math.Cos(10), -math.Sin(10),
+	}, 1e-8, t)
+	assert.Equal(state.X, 10.0, t)
+
+	// The initial derivative is not evaluated again.
+	assert.Equal(stats.Evaluations%6, uint(0), t)
+	assert.Equal(evaluations%6, uint(1), t)
+
+	_, _, _, _, err = integrator.Continue(dydx, state, []float64{9, 11})
+	assert.Equal(err != nil, true, t)
+}
+
+func TestCheckpoint(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	stepper := integrator.NewStepper(dydx)
+	stepper.Init(0, []float64{1, 0})
+	for k := 0; k < 5; k++ {
+		stepper.Step()
+	}
+
+	data, err := json.Marshal(stepper.Checkpoint())
+	assert.Equal(err, nil, t)
+
+	checkpoint := &Checkpoint{}
+	assert.Equal(json.Unmarshal(data, checkpoint), nil, t)
+
+	restored, err := integrator.Restore(dydx, checkpoint)
+	assert.Equal(err, nil, t)
+
+	for k := 0; k < 5; k++ {
+		x1, y1, h1, _ := stepper.Step()
+		x2, y2, h2, _ := restored.Step()
+		assert.Equal(x2, x1, t)
+		assert.Equal(y2, y1, t)
+		assert.Equal(h2, h1, t)
+	}
+	assert.Equal(restored.Stats(), stepper.Stats(), t)
+
+	checkpoint.F = checkpoint.F[1:]
+	_, err = integrator.Restore(dydx, checkpoint)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeFunc(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	for _, xs := range [][]float64{{0, 10}, {0, 1, 2.5, 7, 10}} {
+		ys1, xs1, _ := integrator.Compute(dydx, []float64{1, 0}, xs)
+
+		var ys2, xs2 []float64
+		_, _, err := integrator.ComputeFunc(dydx, []float64{1, 0}, xs, func(x float64, y []float64) error {
+			ys2, xs2 = append(ys2, y...), append(xs2, x)
+			return nil
+		})
+		assert.Equal(err, nil, t)
+		assert.Equal(ys2, ys1, t)
+		assert.Equal(xs2, xs1, t)
+	}
+
+	count := 0
+	_, _, err := integrator.ComputeFunc(dydx, []float64{1, 0}, []float64{0, 10}, func(float64, []float64) error {
+		if count++; count == 3 {
+			return errors.New("enough")
+		}
+		return nil
+	})
+	assert.Equal(err.Error(), "enough", t)
+	assert.Equal(count, 3, t)
+}
+
+func TestComputeInto(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	workspace := integrator.NewWorkspace(2)
+
+	for _, xs := range [][]float64{{0, 10}, {0, 1, 2.5, 7, 10}} {
+		ys1, xs1, _ := integrator.Compute(dydx, []float64{1, 0}, xs)
+
+		ys2 := make([]float64, 2*len(xs))
+		n, err := integrator.ComputeInto(dydx, []float64{1, 0}, xs, ys2, workspace)
+		assert.Equal(err, nil, t)
+		assert.Equal(n, uint(len(xs)), t)
+		assert.Equal(ys2[:2], ys1[:2], t)
+		assert.Equal(ys2[len(ys2)-2:], ys1[len(ys1)-2:], t)
+
+		ys3, xs3, err := integrator.AppendTo(dydx, []float64{1, 0}, xs, nil, nil, workspace)
+		assert.Equal(err, nil, t)
+		assert.Equal(ys3, ys1, t)
+		assert.Equal(xs3, xs1, t)
+	}
+
+	xs, y0 := []float64{0, 1, 2.5, 7, 10}, []float64{1, 0}
+	ys := make([]float64, 2*len(xs))
+	allocations := testing.AllocsPerRun(10, func() {
+		integrator.ComputeInto(dydx, y0, xs, ys, workspace)
+	})
+	assert.Equal(allocations, 0.0, t)
+
+	ys, zs := make([]float64, 0, 1000), make([]float64, 0, 500)
+	allocations = testing.AllocsPerRun(10, func() {
+		integrator.AppendTo(dydx, y0, []float64{0, 10}, ys[:0], zs[:0], workspace)
+	})
+	assert.Equal(allocations, 0.0, t)
+
+	_, err := integrator.ComputeInto(dydx, []float64{1}, xs, ys, workspace)
+	assert.Equal(errors.Is(err, ErrInvalidArgument), true, t)
+}
+
+func TestSteps(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	ys, xs, _ := integrator.Compute(dydx, []float64{1, 0}, []float64{0, 10})
+
+	k := 0
+	for x, y := range integrator.Steps(dydx, []float64{1, 0}, 0, 10) {
+		assert.Equal(x, xs[k], t)
+		assert.Equal(y, ys[2*k:2*k+2], t)
+		k++
+	}
+	assert.Equal(k, len(xs), t)
+
+	k = 0
+	for x := range integrator.Steps(dydx, []float64{1, 0}, 0, 10) {
+		if x > 5 {
+			break
+		}
+		k++
+	}
+	assert.Equal(k > 0 && k < len(xs), true, t)
+}
+
+func TestComputeAsync(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	ys, xs, _ := integrator.Compute(dydx, []float64{1, 0}, []float64{0, 10})
+
+	points, outcome, cancel := integrator.ComputeAsync(dydx, []float64{1, 0}, []float64{0, 10})
+	defer cancel()
+
+	k := 0
+	for point := range points {
+		assert.Equal(point.X, xs[k], t)
+		assert.Equal(point.Y, ys[2*k:2*k+2], t)
+		k++
+	}
+	assert.Equal(k, len(xs), t)
+	assert.Equal(<-outcome, nil, t)
+
+	points, outcome, cancel = integrator.ComputeAsync(dydx, []float64{1, 0}, []float64{0, 10})
+	<-points
+	cancel()
+	for range points {
+	}
+	assert.Equal(<-outcome != nil, true, t)
+}
+
+func TestComputeHooks(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	var accepted, rejected uint
+
+	config := DefaultConfig()
+	config.TryStep = 5
+	config.MaxStep = 5
+	config.OnAccept = func(x, h float64, y []float64) {
+		accepted++
+		assert.Equal(h > 0, true, t)
+		assert.Close(y, []float64{math.Cos(x), -math.Sin(x)}, 1e-2, t)
+	}
+	config.OnReject = func(x, h, ε float64) {
+		rejected++
+		assert.Equal(ε > config.RelError, true, t)
+	}
+
+	integrator, _ := New(config)
+
+	_, _, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 10})
+	assert.Equal(err, nil, t)
+	assert.Equal(accepted, stats.Steps, t)
+	assert.Equal(rejected, stats.Rejections, t)
+	assert.Equal(rejected > 0, true, t)
+}
+
+func TestComputeProgress(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	var fractions []float64
+
+	config := DefaultConfig()
+	config.ProgressSteps = 3
+	config.Progress = func(fraction, _ float64, stats *Stats) {
+		fractions = append(fractions, fraction)
+		assert.Equal(stats.Steps%3, uint(0), t)
+	}
+
+	integrator, _ := New(config)
+
+	_, _, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{2, 12})
+	assert.Equal(err, nil, t)
+	assert.Equal(uint(len(fractions)), stats.Steps/3, t)
+	for k := range fractions {
+		assert.Equal(fractions[k] > 0 && fractions[k] <= 1, true, t)
+		if k > 0 {
+			assert.Equal(fractions[k] > fractions[k-1], true, t)
+		}
+	}
+}
+
+func TestComputeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	evaluations := 0
+	dydx := func(_ float64, y, f []float64) {
+		if evaluations++; evaluations == 100 {
+			cancel()
+		}
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	ys, xs, err := integrator.ComputeContext(ctx, dydx, []float64{1, 0}, []float64{0, 100})
+	assert.Equal(err, context.Canceled, t)
+	assert.Equal(len(ys), 2*len(xs), t)
+	assert.Equal(len(xs) > 1 && xs[len(xs)-1] < 100, true, t)
+	for i, x := range xs {
+		assert.Close(ys[2*i:2*i+2], []float64{math.Cos(x), -math.Sin(x)}, 1e-2, t)
+	}
+
+	ys, xs, err = integrator.ComputeContext(context.Background(), dydx, []float64{1, 0}, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs[len(xs)-1], 1.0, t)
+}
+
+func TestComputeLimits(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.MaxSteps = 5
+
+	integrator, _ := New(config)
+
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 10})
+	assert.Equal(errors.Is(err, ErrMaxSteps), true, t)
+	assert.Equal(stats.Steps, uint(5), t)
+
+	var failure *Error
+	assert.Equal(errors.As(err, &failure), true, t)
+	assert.Equal(failure.Stats, *stats, t)
+	assert.Equal(failure.X, xs[len(xs)-1], t)
+	assert.Equal(len(xs), 6, t)
+	assert.Equal(len(ys), 2*6, t)
+
+	config = DefaultConfig()
+	config.MaxEvaluations = 20
+
+	integrator, _ = New(config)
+
+	_, _, stats, err = integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 10})
+	assert.Equal(errors.Is(err, ErrMaxEvaluations), true, t)
+	assert.Equal(stats.Evaluations <= 20, true, t)
+}
+
+func TestComputeMaxDuration(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		time.Sleep(time.Millisecond)
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.MaxDuration = 20 * time.Millisecond
+
+	integrator, _ := New(config)
+
+	ys, xs, err := integrator.Compute(dydx, []float64{1, 0}, []float64{0, 1000})
+	assert.Equal(errors.Is(err, ErrMaxDuration), true, t)
+	assert.Equal(len(xs) > 0 && xs[len(xs)-1] < 1000, true, t)
+	assert.Equal(len(ys), 2*len(xs), t)
+}
+
+func TestComputeMinStep(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -1000 * (y[0] - math.Cos(x))
+	}
+
+	config := DefaultConfig()
+	config.MinStep = 0.1
+
+	integrator, _ := New(config)
+
+	_, _, err := integrator.Compute(dydx, []float64{0}, []float64{0, 10})
+	assert.Equal(err.Error(), "encountered a step-size underflow at x = 0 with h = 0.1", t)
+	assert.Equal(errors.Is(err, ErrStepUnderflow), true, t)
+
+	config.MinStep = 1e-6
+
+	integrator, _ = New(config)
+
+	_, _, err = integrator.Compute(dydx, []float64{0}, []float64{0, 10})
+	assert.Equal(err, nil, t)
+
+	config.MaxStep = 1e-7
+	_, err = New(config)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeController(t *testing.T) {
+	// The Van der Pol oscillator with a moderate stiffness, which makes the
+	// integral controller oscillate.
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = 50*(1-y[0]*y[0])*y[1] - y[0]
+	}
+
+	rejections := make(map[Controller]uint)
+	solutions := make(map[Controller][]float64)
+	for _, controller := range []Controller{Integral, PI, PID} {
+		config := DefaultConfig()
+		config.Controller = controller
+
+		integrator, _ := New(config)
+
+		ys, _, stats, err := integrator.ComputeWithStats(dydx, []float64{2, 0}, []float64{0, 100})
+		assert.Equal(err, nil, t)
+
+		rejections[controller] = stats.Rejections
+		solutions[controller] = ys[len(ys)-2:]
+	}
+
+	assert.Equal(rejections[PI] < rejections[Integral], true, t)
+	assert.Equal(rejections[PID] < rejections[Integral], true, t)
+	assert.Close(solutions[PI], solutions[Integral], 1e-1, t)
+	assert.Close(solutions[PID], solutions[Integral], 1e-1, t)
+
+	config := DefaultConfig()
+	config.Controller = PID + 1
+	_, err := New(config)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeNorm(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+		f[2] = -y[2]
+	}
+
+	y0, xs := []float64{1, 0, 1}, []float64{0, 10}
+
+	config := DefaultConfig()
+	integrator, _ := New(config)
+	_, _, max, _ := integrator.ComputeWithStats(dydx, y0, xs)
+
+	config.Norm = RMSNorm
+	integrator, _ = New(config)
+	_, _, rms, _ := integrator.ComputeWithStats(dydx, y0, xs)
+
+	assert.Equal(rms.Steps < max.Steps, true, t)
+
+	threshold := config.AbsError / config.RelError
+	config.ErrorNorm = func(e, y, ynew []float64) float64 {
+		ε := 0.0
+		for i := range e {
+			scale := math.Max(math.Max(math.Abs(y[i]), math.Abs(ynew[i])), threshold)
+			ε = math.Max(ε, math.Abs(e[i])/scale)
+		}
+		return ε
+	}
+	integrator, _ = New(config)
+	_, _, custom, _ := integrator.ComputeWithStats(dydx, y0, xs)
+
+	assert.Equal(custom, max, t)
+}
+
+func TestComputeBackward(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-10
+
+	integrator, _ := New(config)
+
+	y0 := []float64{math.Cos(10), -math.Sin(10)}
+
+	ys, xs, err := integrator.Compute(dydx, y0, []float64{10, 0})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs[len(xs)-1], 0.0, t)
+	for i, x := range xs {
+		if i > 0 {
+			assert.Equal(x < xs[i-1], true, t)
+		}
+		assert.Close(ys[2*i:2*i+2], []float64{math.Cos(x), -math.Sin(x)}, 1e-7, t)
+	}
+
+	xs = []float64{10, 7.5, 3, 1, 0}
+	ys, zs, err := integrator.Compute(dydx, y0, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(zs, xs, t)
+	for i, x := range xs {
+		assert.Close(ys[2*i:2*i+2], []float64{math.Cos(x), -math.Sin(x)}, 1e-7, t)
+	}
+
+	config.Events = []Event{
+		{Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		}, Direction: -1},
+	}
+
+	integrator, _ = New(config)
+
+	_, _, crossings, _, err := integrator.ComputeWithEvents(dydx, y0, []float64{10, 0})
+	assert.Equal(err, nil, t)
+	assert.Equal(len(crossings), 2, t)
+	assert.Close(crossings[0].X, 5*math.Pi/2, 1e-8, t)
+	assert.Close(crossings[1].X, math.Pi/2, 1e-8, t)
+
+	solution, _, err := integrator.Solve(dydx, y0, []float64{10, 0})
+	assert.Equal(err, nil, t)
+
+	y := make([]float64, 2)
+	for _, x := range []float64{10, 9.5, 4, 0.1, 0} {
+		assert.Equal(solution.At(x, y), nil, t)
+		assert.Close(y, []float64{math.Cos(x), -math.Sin(x)}, 1e-7, t)
+	}
+	assert.Equal(solution.At(11, y) != nil, true, t)
+}
+
+func TestComputeBackwardStops(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = math.Abs(x - 5)
+	}
+
+	config := DefaultConfig()
+	config.Stops = []float64{2, 5, 8}
+
+	integrator, _ := New(config)
+
+	ys, xs, err := integrator.Compute(dydx, []float64{0}, []float64{10, 0})
+	assert.Equal(err, nil, t)
+	for _, stop := range []float64{8, 5, 2} {
+		found := false
+		for _, x := range xs {
+			found = found || x == stop
+		}
+		assert.Equal(found, true, t)
+	}
+	assert.Close(ys[len(ys)-1], -25.0, 1e-12, t)
+}
+
+func TestComputeUnderflow(t *testing.T) {
+	// The solution is 1/(1 - x), which blows up at x = 1.
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[0] * y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1}, []float64{0, 2})
+	assert.Equal(err != nil, true, t)
+	assert.Equal(stats.Rejections > 0, true, t)
+
+	n := len(xs)
+	assert.Equal(len(ys), n, t)
+	assert.Equal(xs[n-1] > 0.99 && xs[n-1] < 1, true, t)
+	for k, x := range xs {
+		if x < 0.9 {
+			assert.Close(ys[k]*(1-x), 1.0, 1e-2, t)
+		}
+	}
+}
+
+func TestComputeNotFinite(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+		if x > 0.5 {
+			f[1] = math.NaN()
+		}
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	_, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 1})
+	assert.Equal(errors.Is(err, ErrNotFinite), true, t)
+	assert.Equal(stats.Rejections, uint(0), t)
+	assert.Equal(xs[len(xs)-1] <= 0.5, true, t)
+
+	var failure *FiniteError
+	assert.Equal(errors.As(err, &failure), true, t)
+	assert.Equal(failure.Index, 1, t)
+	assert.Equal(failure.Derivative, true, t)
+	assert.Equal(failure.X > 0.5, true, t)
+
+	config := DefaultConfig()
+	config.SkipFiniteCheck = true
+
+	integrator, _ = New(config)
+
+	_, _, err = integrator.Compute(dydx, []float64{1, 0}, []float64{0, 1})
+	assert.Equal(errors.Is(err, ErrNotFinite), false, t)
+}
+
+func TestNewInvalid(t *testing.T) {
+	config := DefaultConfig()
+	config.RelError = -1
+
+	_, err := New(config)
+	assert.Equal(errors.Is(err, ErrInvalidConfig), true, t)
+
+	integrator, _ := New(DefaultConfig())
+
+	_, _, _, _, err = integrator.Continue(nil, &State{X: 1, Y: []float64{1}}, []float64{0, 1})
+	assert.Equal(errors.Is(err, ErrInvalidArgument), true, t)
+}
+
+func TestComputeInvalid(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	cases := []struct {
+		y0 []float64
+		xs []float64
+	}{
+		{nil, []float64{0, 1}},
+		{[]float64{1}, nil},
+		{[]float64{1}, []float64{0}},
+		{[]float64{1}, []float64{0, 0}},
+		{[]float64{1}, []float64{0, 0.5, 0.5, 1}},
+		{[]float64{1}, []float64{0, 2, 1}},
+		{[]float64{1}, []float64{1, 0.5, 0.7, 0}},
+		{[]float64{1}, []float64{0, math.NaN(), 1}},
+	}
+
+	for _, c := range cases {
+		_, _, err := integrator.Compute(dydx, c.y0, c.xs)
+		assert.Equal(errors.Is(err, ErrInvalidArgument), true, t)
+	}
+}
+
+func TestComputeTiming(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		time.Sleep(10 * time.Microsecond)
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.Timing = true
+
+	integrator, _ := New(config)
+
+	_, _, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Derivative >= time.Duration(stats.Evaluations)*10*time.Microsecond, true, t)
+	assert.Equal(stats.Duration >= stats.Derivative, true, t)
+	assert.Equal(stats.MinStep <= stats.LastStep && stats.LastStep <= stats.MaxStep, true, t)
+}
+
+func TestComputeCompensated(t *testing.T) {
+	dydx := func(_ float64, _, f []float64) {
+		f[0] = 0.1
+	}
+
+	config := DefaultConfig()
+	config.MaxStep = 1e-3
+
+	integrator, _ := New(config)
+
+	ys, _, err := integrator.Compute(dydx, []float64{0}, []float64{0, 100})
+	assert.Equal(err, nil, t)
+	plain := math.Abs(ys[len(ys)-1] - 10)
+
+	config.Compensated = true
+
+	integrator, _ = New(config)
+
+	ys, xs, err := integrator.Compute(dydx, []float64{0}, []float64{0, 100})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs[len(xs)-1], 100.0, t)
+	assert.Equal(plain > 1e-12, true, t)
+	assert.Close(ys[len(ys)-1], 10.0, 1e-14, t)
+}
+
+func TestComputeBounds(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -1
+		f[1] = 1
+	}
+
+	config := DefaultConfig()
+
+	integrator, _ := New(config)
+
+	ys, _, err := integrator.Compute(dydx, []float64{1, 0}, []float64{0, 2})
+	assert.Equal(err, nil, t)
+	assert.Close(ys[len(ys)-2:], []float64{-1, 2}, 1e-12, t)
+
+	config.Bounds = []Bound{NonNegative(0), {Index: 1, Lower: -1, Upper: 1.5}}
+
+	integrator, _ = New(config)
+
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 2})
+	assert.Equal(err, nil, t)
+	for k := range xs {
+		assert.Equal(ys[2*k] >= 0 && ys[2*k+1] <= 1.5, true, t)
+	}
+	assert.Close(ys[len(ys)-2:], []float64{0, 1.5}, 1e-12, t)
+	assert.Equal(stats.Steps < 100, true, t)
+
+	config.Bounds = []Bound{{Index: 0, Lower: 1, Upper: 0}}
+	_, err = New(config)
+	assert.Equal(errors.Is(err, ErrInvalidConfig), true, t)
+}
+
+func TestComputeInvariants(t *testing.T) {
+	// The Kepler problem with the eccentricity of 0.6.
+	dydx := func(_ float64, y, f []float64) {
+		r := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+		f[0], f[1] = y[2], y[3]
+		f[2], f[3] = -y[0]/r, -y[1]/r
+	}
+	energy := func(y []float64) float64 {
+		return (y[2]*y[2]+y[3]*y[3])/2 - 1/math.Hypot(y[0], y[1])
+	}
+	momentum := func(y []float64) float64 {
+		return y[0]*y[3] - y[1]*y[2]
+	}
+
+	y0 := []float64{0.4, 0, 0, 2}
+	drift := func(ys []float64) float64 {
+		n := len(ys) / 4
+		ε := 0.0
+		for k := 0; k < n; k++ {
+			y := ys[4*k : 4*(k+1)]
+			ε = math.Max(ε, math.Abs(energy(y)-energy(y0)))
+			ε = math.Max(ε, math.Abs(momentum(y)-momentum(y0)))
+		}
+		return ε
+	}
+
+	config := DefaultConfig()
+
+	integrator, _ := New(config)
+
+	ys, _, err := integrator.Compute(dydx, y0, []float64{0, 100})
+	assert.Equal(err, nil, t)
+	assert.Equal(drift(ys) > 1e-2, true, t)
+
+	config.Monitors = []func([]float64) float64{energy, momentum}
+
+	integrator, _ = New(config)
+
+	ys, _, stats, err := integrator.ComputeWithStats(dydx, y0, []float64{0, 100})
+	assert.Equal(err, nil, t)
+	assert.Equal(len(stats.Drifts), 2, t)
+	assert.Close(math.Max(stats.Drifts[0].Max, stats.Drifts[1].Max), drift(ys), 1e-15, t)
+	n := len(ys) / 4
+	assert.Close(stats.Drifts[0].Final, energy(ys[4*(n-1):])-energy(y0), 1e-15, t)
+
+	config.Invariants = []Invariant{
+		{
+			Function: energy,
+			Gradient: func(y, g []float64) {
+				r := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+				g[0], g[1], g[2], g[3] = y[0]/r, y[1]/r, y[2], y[3]
+			},
+		},
+		{
+			Function: momentum,
+			Gradient: func(y, g []float64) {
+				g[0], g[1], g[2], g[3] = y[3], -y[2], -y[1], y[0]
+			},
+		},
+	}
+
+	integrator, _ = New(config)
+
+	ys, _, stats, err = integrator.ComputeWithStats(dydx, y0, []float64{0, 100})
+	assert.Equal(err, nil, t)
+	assert.Equal(drift(ys) < 1e-12, true, t)
+	assert.Equal(stats.Drifts[0].Max < 1e-12, true, t)
+}
+
+func TestComputeMass(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -2 * y[0]
+		f[1] = -y[1] + y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-10
+	config.RelError = 1e-8
+	config.Mass = []float64{2, 0, 1, 1}
+
+	integrator, _ := New(config)
+
+	// The system is y₁′ = -y₁ and y₂′ = -y₂ + 2y₁.
+	ys, _, err := integrator.Compute(dydx, []float64{1, 0}, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Close(ys[len(ys)-2:], []float64{math.Exp(-1), 2 * math.Exp(-1)}, 1e-7, t)
+
+	stepper := integrator.NewStepper(dydx)
+	stepper.Init(0, []float64{1, 0})
+	x, y, _, err := stepper.Step()
+	assert.Equal(err, nil, t)
+	assert.Close(y, []float64{math.Exp(-x), 2 * x * math.Exp(-x)}, 1e-8, t)
+
+	config.Mass = []float64{1, 0, 1, 0}
+
+	integrator, _ = New(config)
+
+	_, _, err = integrator.Compute(dydx, []float64{1, 0}, []float64{0, 1})
+	assert.Equal(errors.Is(err, ErrInvalidArgument), true, t)
+}
+
+func TestComputeConcurrent(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	xs, y0 := []float64{0, 1, 2.5, 7, 10}, []float64{1, 0}
+	ys, _, stats, _ := integrator.ComputeWithStats(dydx, y0, xs)
+
+	// Given a workspace, the integration does not allocate, which is measured
+	// without the pool since the runtime can empty it at any time.
+	workspace := integrator.NewWorkspace(2)
+	zs := make([]float64, len(ys))
+	allocations := testing.AllocsPerRun(10, func() {
+		integrator.ComputeInto(dydx, y0, xs, zs, workspace)
+	})
+	assert.Equal(allocations, 0.0, t)
+	assert.Equal(zs, ys, t)
+
+	// The failures are reported by the test goroutine.
+	done := make(chan error)
+	for k := 0; k < 8; k++ {
+		go func() {
+			for i := 0; i < 10; i++ {
+				ys1, _, stats1, err := integrator.ComputeWithStats(dydx, y0, xs)
+				if err != nil {
+					done <- err
+					return
+				}
+				if !reflect.DeepEqual(ys1, ys) || !reflect.DeepEqual(stats1, stats) {
+					done <- errors.New("the concurrent integrations should agree")
+					return
+				}
+			}
+			done <- nil
+		}()
+	}
+	for k := 0; k < 8; k++ {
+		assert.Equal(<-done, nil, t)
+	}
+
+	_, _, state, _, _ := integrator.ComputeWithState(dydx, y0, []float64{0, 1})
+	Y := append([]float64(nil), state.Y...)
+	integrator.Compute(dydx, []float64{2, 0}, []float64{0, 1})
+	assert.Equal(state.Y, Y, t)
+}
+
+func TestComputeToWriter(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	y0, xs := []float64{1, 0}, []float64{0, 1, 2.5, 10}
+	ys, _, _ := integrator.Compute(dydx, y0, xs)
+
+	var buffer bytes.Buffer
+	_, stats, err := integrator.ComputeToWriter(dydx, y0, xs, &buffer, CSV)
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Steps > 0, true, t)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(len(lines), len(xs), t)
+	for i, line := range lines {
+		fields := strings.Split(line, ",")
+		assert.Equal(len(fields), 3, t)
+		for j, field := range fields {
+			value, _ := strconv.ParseFloat(field, 64)
+			if j == 0 {
+				assert.Equal(value, xs[i], t)
+			} else {
+				assert.Equal(value, ys[2*i+j-1], t)
+			}
+		}
+	}
+
+	buffer.Reset()
+	_, _, err = integrator.ComputeToWriter(dydx, y0, xs, &buffer, Binary)
+	assert.Equal(err, nil, t)
+	assert.Equal(buffer.Len(), 8*3*len(xs), t)
+	for i := 0; i < len(xs); i++ {
+		for j := 0; j < 3; j++ {
+			bits := binary.LittleEndian.Uint64(buffer.Next(8))
+			if j == 0 {
+				assert.Equal(math.Float64frombits(bits), xs[i], t)
+			} else {
+				assert.Equal(math.Float64frombits(bits), ys[2*i+j-1], t)
+			}
+		}
+	}
+
+	failure := errors.New("the disk is full")
+	_, _, err = integrator.ComputeToWriter(dydx, y0, []float64{0, 1000}, failing{failure}, CSV)
+	assert.Equal(err, failure, t)
+}
+
+type failing struct {
+	err error
+}
+
+func (self failing) Write([]byte) (int, error) {
+	return 0, self.err
+}
+
+func TestComputeRefineDecimate(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	y0, xs := []float64{1, 0}, []float64{0, 10}
+
+	integrator, _ := New(DefaultConfig())
+	ys, xs1, stats, _ := integrator.ComputeWithStats(dydx, y0, xs)
+
+	config := DefaultConfig()
+	config.Refine = 4
+
+	integrator, _ = New(config)
+	ys2, xs2, stats2, err := integrator.ComputeWithStats(dydx, y0, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(len(xs2), 4*len(xs1)-3, t)
+	assert.Equal(stats2.Steps, stats.Steps, t)
+	assert.Equal(stats2.Interpolations, 3*stats.Steps, t)
+	for i := range xs1 {
+		assert.Equal(xs2[4*i], xs1[i], t)
+		assert.Equal(ys2[8*i:8*i+2], ys[2*i:2*i+2], t)
+	}
+	for i := range xs2 {
+		assert.Close(ys2[2*i], math.Cos(xs2[i]), 1e-2, t)
+	}
+
+	config = DefaultConfig()
+	config.Decimate = 3
+
+	integrator, _ = New(config)
+	ys3, xs3, _, err := integrator.ComputeWithStats(dydx, y0, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(len(xs3), 1+(len(xs1)-1+2)/3, t)
+	for i := 1; i < len(xs3)-1; i++ {
+		assert.Equal(xs3[i], xs1[3*i], t)
+	}
+	assert.Equal(xs3[len(xs3)-1], 10.0, t)
+	assert.Equal(ys3[len(ys3)-2:], ys[len(ys)-2:], t)
+}
+
+func TestComputeMATLAB(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	y0, xs := []float64{1, 0}, []float64{0, 10}
+
+	integrator, _ := New(DefaultConfig())
+	ys1, xs1, stats1, _ := integrator.ComputeWithStats(dydx, y0, xs)
+
+	config := DefaultConfig()
+	config.MATLAB = true
+
+	integrator, _ = New(config)
+	ys2, xs2, stats2, err := integrator.ComputeWithStats(dydx, y0, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(stats2.Steps, stats1.Steps, t)
+	assert.Equal(len(xs2), 4*len(xs1)-3, t)
+	assert.Equal(xs2[len(xs2)-1], 10.0, t)
+	for i := range xs1 {
+		assert.Close(xs2[4*i], xs1[i], 1e-10, t)
+		assert.Close(ys2[8*i:8*i+2], ys1[2*i:2*i+2], 1e-10, t)
+	}
+
+	integrator, _ = New(config)
+	ys3, _, err := integrator.Compute(dydx, y0, []float64{0, 5, 10})
+	assert.Equal(err, nil, t)
+	assert.Equal(len(ys3), 6, t)
+
+	// The fixtures are the output of ode45 on the examples of MATLAB.
+	for _, reference := range []struct {
+		fixture   *fixture
+		tolerance float64
+		stats     []uint
+	}{
+		{&fixtureNonstiff, 1e-14, []uint{151, 3, 22}},
+		{&fixtureStiff, 3e-13, []uint{20179, 323, 3040}},
+	} {
+		input, output := &reference.fixture.input, &reference.fixture.output
+
+		config := reference.fixture.configure()
+		config.MATLAB = true
+		config.Refine = 1
+
+		integrator, _ = New(config)
+		ys, xs, stats, err := integrator.ComputeWithStats(input.dydx, input.y0, input.xs)
+		assert.Equal(err, nil, t)
+		assert.Close(ys, output.ys, reference.tolerance, t)
+		if output.xs != nil {
+			assert.Close(xs, output.xs, 4e-9, t)
+		}
+		assert.Equal([]uint{stats.Evaluations, stats.Rejections, stats.Steps}, reference.stats, t)
+	}
+
+	config.Compensated = true
+	_, err = New(config)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeSegments(t *testing.T) {
+	// A tank filled at a constant rate until x = 1 and drained afterwards.
+	segments := []Segment{
+		{End: 1, Dydx: func(_ float64, _, f []float64) {
+			f[0] = 1
+		}},
+		{End: 3, Dydx: func(_ float64, y, f []float64) {
+			f[0] = -y[0]
+		}},
+	}
+
+	integrator, _ := New(&Config{AbsError: 1e-10, RelError: 1e-10})
+
+	ys, xs, stats, err := integrator.ComputeSegments(segments, []float64{0}, []float64{0, 3})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs[0], 0.0, t)
+	assert.Equal(xs[len(xs)-1], 3.0, t)
+	assert.Equal(stats.Steps > 0, true, t)
+	count := 0
+	for i, x := range xs {
+		if x == 1 {
+			count++
+		}
+		if i > 0 {
+			assert.Equal(x > xs[i-1], true, t)
+		}
+		if x <= 1 {
+			assert.Close(ys[i], x, 1e-10, t)
+		} else {
+			assert.Close(ys[i], math.Exp(1-x), 1e-8, t)
+		}
+	}
+	assert.Equal(count, 1, t)
+
+	ys, xs, _, err = integrator.ComputeSegments(segments, []float64{0}, []float64{0, 0.5, 2, 3})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.5, 2, 3}, t)
+	assert.Close(ys, []float64{0, 0.5, math.Exp(-1), math.Exp(-2)}, 1e-8, t)
+
+	ys, xs, _, err = integrator.ComputeSegments(segments, []float64{0}, []float64{0, 1, 3})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 1, 3}, t)
+	assert.Close(ys, []float64{0, 1, math.Exp(-2)}, 1e-8, t)
+
+	_, _, _, err = integrator.ComputeSegments(segments, []float64{0}, []float64{0, 4})
+	assert.Equal(err != nil, true, t)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	integrator1, err := NewWithOptions(WithAbsError(1e-10), WithRelError(1e-8), WithMaxStep(0.1),
+		WithController(PI))
+	assert.Equal(err, nil, t)
+
+	integrator2, _ := New(&Config{AbsError: 1e-10, RelError: 1e-8, MaxStep: 0.1, Controller: PI})
+
+	ys1, xs1, _ := integrator1.Compute(dydx, []float64{1}, []float64{0, 1})
+	ys2, xs2, _ := integrator2.Compute(dydx, []float64{1}, []float64{0, 1})
+	assert.Equal(ys1, ys2, t)
+	assert.Equal(xs1, xs2, t)
+
+	for _, option := range []Option{WithMaxStep(0), WithRelError(-1), WithMaxSteps(0),
+		WithNorm(Norm(42)), WithEvents(Event{})} {
+
+		_, err = NewWithOptions(option)
+		assert.Equal(errors.Is(err, ErrInvalidConfig), true, t)
+	}
+
+	_, err = NewWithOptions(WithMaxStep(0.1), WithMinStep(1))
+	assert.Equal(errors.Is(err, ErrInvalidConfig), true, t)
+}
+
+func TestComputeDeterministic(t *testing.T) {
+	// The Lorenz system, whose trajectories diverge exponentially, with the
+	// products rounded, so that they are not fused.
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = 10 * (y[1] - y[0])
+		f[1] = float64(y[0]*(28-y[2])) - y[1]
+		f[2] = float64(y[0]*y[1]) - float64(8.0/3*y[2])
+	}
+
+	digest := func(xs, ys []float64) uint64 {
+		hash := fnv.New64a()
+		for _, x := range xs {
+			binary.Write(hash, binary.LittleEndian, math.Float64bits(x))
+		}
+		for _, y := range ys {
+			binary.Write(hash, binary.LittleEndian, math.Float64bits(y))
+		}
+		return hash.Sum64()
+	}
+
+	// The bits of the solution at the end of the interval.
+	y := []uint64{0x402b9907de17090b, 0x4029eb99f5592eb3, 0x40417419f92fb749}
+
+	cases := []struct {
+		xs     []float64
+		digest uint64
+	}{
+		{[]float64{0, 20}, 0x3991d7fb236cf73e},
+		{[]float64{0, 5, 10, 15, 20}, 0x0e08f75d6c334c9a},
+	}
+
+	for _, parallelism := range []uint{0, 4} {
+		config := DefaultConfig()
+		config.AbsError = 1e-10
+		config.RelError = 1e-8
+		config.Deterministic = true
+		config.Parallelism = parallelism
+
+		integrator, _ := New(config)
+
+		for _, c := range cases {
+			ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 1, 1}, c.xs)
+			assert.Equal(err, nil, t)
+			assert.Equal(stats.Steps, uint(2275), t)
+			n := len(ys)
+			for i := range y {
+				assert.Equal(math.Float64bits(ys[n-3+i]), y[i], t)
+			}
+			assert.Equal(digest(xs, ys), c.digest, t)
+		}
+	}
+}
+
+func TestComputeWithDerivatives(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError, config.RelError = 1e-10, 1e-10
+	config.Refine = 4
+
+	integrator, _ := New(config)
+
+	check := func(xs []float64) {
+		ys, zs, fs, stats, err := integrator.ComputeWithDerivatives(dydx, []float64{1, 0}, xs)
+		assert.Equal(err, nil, t)
+		assert.Equal(len(fs), len(ys), t)
+
+		_, _, reference, _ := integrator.ComputeWithStats(dydx, []float64{1, 0}, xs)
+		assert.Equal(stats.Evaluations, reference.Evaluations, t)
+
+		f := make([]float64, 2)
+		for i, x := range zs {
+			dydx(x, ys[2*i:2*i+2], f)
+			assert.Close(fs[2*i:2*i+2], f, 1e-8, t)
+			assert.Close(fs[2*i:2*i+2], []float64{-math.Sin(x - xs[0]), -math.Cos(x - xs[0])}, 1e-8, t)
+		}
+		assert.Equal(fs[len(fs)-2:], []float64{ys[len(ys)-1], -ys[len(ys)-2]}, t)
+	}
+
+	check([]float64{0, 10})
+	check([]float64{0, 0.7, 1.9, 3.1, 5.5, 8.2, 10})
+	check([]float64{10, 6.3, 0})
+}
+
+func TestComputeWithDerivativesReset(t *testing.T) {
+	const g = 9.81
+
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -g
+	}
+
+	config := DefaultConfig()
+	config.Events = []Event{
+		{Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		}, Direction: -1, Terminal: true, Reset: func(_ float64, y []float64) {
+			y[0], y[1] = 0, -0.5*y[1]
+		}},
+	}
+
+	integrator, _ := New(config)
+
+	ys, xs, fs, stats, err := integrator.ComputeWithDerivatives(dydx, []float64{10, 0}, []float64{0, 3})
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Resets, uint(2), t)
+
+	// The velocity is the derivative of the height, and the acceleration is
+	// constant, also right before and after the resets.
+	for i := range xs {
+		assert.Close(fs[2*i], ys[2*i+1], 1e-10, t)
+		assert.Close(fs[2*i+1], -g, 1e-10, t)
+	}
+}
@@ -0,0 +1,83 @@
+package dopri
+
+import (
+	"time"
+
+	"github.com/ready-steady/ode/erk"
+)
+
+// Option is a setting of an integrator; see NewWithOptions.
+type Option = erk.Option
+
+// NewWithOptions creates a new integrator with the default configuration
+// modified by a number of options; see erk.NewWithOptions.
+func NewWithOptions(options ...Option) (*Integrator, error) {
+	return erk.NewWithOptions(tableau, options...)
+}
+
+// WithTryStep sets the initial step of integration, which should be positive.
+func WithTryStep(h float64) Option {
+	return erk.WithTryStep(h)
+}
+
+// WithMaxStep sets the maximal step of integration, which should be positive.
+func WithMaxStep(h float64) Option {
+	return erk.WithMaxStep(h)
+}
+
+// WithMinStep sets the minimal step of integration, which should be positive.
+func WithMinStep(h float64) Option {
+	return erk.WithMinStep(h)
+}
+
+// WithAbsError sets the absolute error tolerance, which should be positive.
+func WithAbsError(ε float64) Option {
+	return erk.WithAbsError(ε)
+}
+
+// WithRelError sets the relative error tolerance, which should be positive.
+func WithRelError(ε float64) Option {
+	return erk.WithRelError(ε)
+}
+
+// WithMaxSteps sets the maximal number of steps, which should be positive.
+func WithMaxSteps(n uint) Option {
+	return erk.WithMaxSteps(n)
+}
+
+// WithMaxEvaluations sets the maximal number of evaluations of the derivative,
+// which should be positive.
+func WithMaxEvaluations(n uint) Option {
+	return erk.WithMaxEvaluations(n)
+}
+
+// WithMaxDuration sets the maximal duration of an integration, which should
+// be positive.
+func WithMaxDuration(d time.Duration) Option {
+	return erk.WithMaxDuration(d)
+}
+
+// WithController sets the controller of the step size.
+func WithController(controller Controller) Option {
+	return erk.WithController(controller)
+}
+
+// WithNorm sets the norm of the error estimate.
+func WithNorm(norm Norm) Option {
+	return erk.WithNorm(norm)
+}
+
+// WithCompensation enables the compensated summation; see Config.Compensated.
+func WithCompensation() Option {
+	return erk.WithCompensation()
+}
+
+// WithDeterminism enables the deterministic mode; see Config.Deterministic.
+func WithDeterminism() Option {
+	return erk.WithDeterminism()
+}
+
+// WithEvents appends events to those of the configuration; see Config.Events.
+func WithEvents(events ...Event) Option {
+	return erk.WithEvents(events...)
+}
@@ -0,0 +1,8 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Point is a point of a solution.
+type Point = erk.Point
@@ -0,0 +1,8 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Segment is a piece of a piecewise-defined system of differential equations.
+type Segment = erk.Segment
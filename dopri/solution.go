@@ -0,0 +1,8 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Solution is a continuous solution of a system of differential equations.
+type Solution = erk.Solution
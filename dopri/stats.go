@@ -1,8 +1,11 @@
 package dopri
 
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
 // Stats contains information about the work done by an integrator.
-type Stats struct {
-	Evaluations uint // The number of invocations of the derivative function.
-	Rejections  uint // The number of rejected iterations of the algorithm.
-	Steps       uint // The number of steps the algorithm has taken.
-}
+type Stats = erk.Stats
+
+// Drift is the deviation of a monitored quantity from its initial value.
+type Drift = erk.Drift
@@ -0,0 +1,8 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Stepper integrates a system of differential equations one step at a time.
+type Stepper = erk.Stepper
@@ -0,0 +1,8 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Workspace is the storage of an integration, which can be reused.
+type Workspace = erk.Workspace
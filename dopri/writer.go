@@ -0,0 +1,15 @@
+package dopri
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Format is a format of the records written by Integrator.ComputeToWriter.
+type Format = erk.Format
+
+// The formats of the records; see the corresponding constants of the erk
+// package.
+const (
+	CSV    = erk.CSV
+	Binary = erk.Binary
+)
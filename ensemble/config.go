@@ -0,0 +1,30 @@
+package ensemble
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The function creating the integrator of a worker. Each worker has its
+	// own instance, which is reused for all the members it integrates.
+	Integrator func() (ode.Integrator, error) `json:"-"`
+	// The number of workers. If it is zero, runtime.GOMAXPROCS(0) is used.
+	Workers uint `json:"workers"`
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Integrator == nil {
+		return errors.New("the function creating integrators should be given")
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+// Package ensemble provides an integrator of ensembles of systems of ordinary
+// differential equations, which differ in their initial conditions and
+// parameters.
+//
+// The members of an ensemble are independent, and they are distributed among
+// a pool of goroutines, each of which has its own instance of the underlying
+// integrator. The results are gathered in the order of the members.
+// Alternatively, the members can be integrated in lockstep with a right-hand
+// side that is evaluated at all of them at once; see Integrator.ComputeBatch.
+package ensemble
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ready-steady/ode"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// Result is the solution of a member of an ensemble.
+type Result struct {
+	Ys  []float64 // The solution.
+	Xs  []float64 // The points of the solution.
+	Err error     // The error of the integration if any.
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y)
+// for each of the initial conditions in y0s. See Integrator.Compute in the
+// parent package. The input function should be safe to be called by several
+// goroutines at the same time.
+//
+// The integrations are independent, and a failure of one does not affect the
+// others. The function returns an error only if the integration could not be
+// started.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0s [][]float64, xs []float64) ([]Result, error) {
+
+	results, _, err := self.ComputeWithStats(func(x float64, y, _, f []float64) {
+		dydx(x, y, f)
+	}, y0s, nil, xs)
+
+	return results, err
+}
+
+// ComputeWithStats integrates the system of differential equations
+// dy/dx = f(x, y, p) for each member of an ensemble and provides additional
+// information about the solution process; see ode.Parametric.
+//
+// The kth member has the initial condition y0s[k] and the parameters ps[k].
+// Either of the two can have a single entry, which is then shared by all the
+// members, and ps can be nil if the system has no parameters.
+func (self *Integrator) ComputeWithStats(dydx ode.Parametric, y0s, ps [][]float64,
+	xs []float64) ([]Result, *Stats, error) {
+
+	stats := &Stats{}
+
+	config := &self.config
+
+	nm := len(y0s)
+	if len(ps) > nm {
+		nm = len(ps)
+	}
+	if len(y0s) == 0 || (len(y0s) != 1 && len(y0s) != nm) ||
+		(len(ps) > 1 && len(ps) != nm) {
+
+		return nil, stats, errors.New("the initial conditions and parameters should match")
+	}
+
+	nw := int(config.Workers)
+	if nw == 0 {
+		nw = runtime.GOMAXPROCS(0)
+	}
+	if nw > nm {
+		nw = nm
+	}
+
+	integrators := make([]ode.Integrator, nw)
+	for w := range integrators {
+		integrator, err := config.Integrator()
+		if err != nil {
+			return nil, stats, err
+		}
+		integrators[w] = integrator
+	}
+
+	results := make([]Result, nm)
+
+	var evaluations, failures uint64
+
+	jobs := make(chan int)
+
+	var group sync.WaitGroup
+	group.Add(nw)
+	for w := 0; w < nw; w++ {
+		go func(integrator ode.Integrator) {
+			defer group.Done()
+
+			count := uint64(0)
+			for k := range jobs {
+				y0 := y0s[0]
+				if len(y0s) > 1 {
+					y0 = y0s[k]
+				}
+				var p []float64
+				if len(ps) == 1 {
+					p = ps[0]
+				} else if len(ps) > 1 {
+					p = ps[k]
+				}
+
+				ys, xs, err := integrator.Compute(func(x float64, y, f []float64) {
+					dydx(x, y, p, f)
+					count++
+				}, y0, xs)
+				results[k] = Result{Ys: ys, Xs: xs, Err: err}
+				if err != nil {
+					atomic.AddUint64(&failures, 1)
+				}
+			}
+			atomic.AddUint64(&evaluations, count)
+		}(integrators[w])
+	}
+
+	for k := 0; k < nm; k++ {
+		jobs <- k
+	}
+	close(jobs)
+
+	group.Wait()
+
+	stats.Evaluations = uint(evaluations)
+	stats.Members = uint(nm)
+	stats.Failures = uint(failures)
+
+	return results, stats, nil
+}
+
+// ComputeBatch integrates the system of differential equations dy/dx = f(x, y)
+// for each of the initial conditions in y0s like Compute but evaluates the
+// right-hand side at the states of all the members at once; see ode.Batch.
+//
+// The members are integrated in lockstep as a single system by one integrator
+// regardless of the number of workers. Consequently, they share the steps,
+// which are chosen so that the error tolerances are satisfied for all of them,
+// and a failure of the integration is a failure of every member. Batching
+// pays off when the members have similar dynamics and evaluating the
+// right-hand side at many states at once is much cheaper than one by one.
+func (self *Integrator) ComputeBatch(dydx ode.Batch, y0s [][]float64,
+	xs []float64) ([]Result, *Stats, error) {
+
+	stats := &Stats{}
+
+	nm := len(y0s)
+	if nm == 0 {
+		return nil, stats, errors.New("the initial conditions should be given")
+	}
+	nd := len(y0s[0])
+	for _, y0 := range y0s {
+		if len(y0) != nd {
+			return nil, stats, errors.New("the initial conditions should have the same dimension")
+		}
+	}
+
+	integrator, err := self.config.Integrator()
+	if err != nil {
+		return nil, stats, err
+	}
+
+	y0 := make([]float64, 0, nm*nd)
+	for _, y := range y0s {
+		y0 = append(y0, y...)
+	}
+
+	points := make([]float64, nm)
+	ys, xs, err := integrator.Compute(func(x float64, y, f []float64) {
+		for k := range points {
+			points[k] = x
+		}
+		dydx(points, y, f)
+		stats.Evaluations += uint(nm)
+	}, y0, xs)
+
+	results := make([]Result, nm)
+	stats.Members = uint(nm)
+	if err != nil {
+		for k := range results {
+			results[k].Err = err
+		}
+		stats.Failures = uint(nm)
+		return results, stats, nil
+	}
+
+	np := len(xs)
+	for k := range results {
+		results[k].Xs = xs
+		results[k].Ys = make([]float64, np*nd)
+		for i := 0; i < np; i++ {
+			copy(results[k].Ys[i*nd:(i+1)*nd], ys[(i*nm+k)*nd:])
+		}
+	}
+
+	return results, stats, nil
+}
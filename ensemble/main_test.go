@@ -0,0 +1,118 @@
+package ensemble
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/dopri"
+	"github.com/ready-steady/ode/rk4"
+)
+
+func TestCompute(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{
+		Integrator: func() (ode.Integrator, error) {
+			return dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10})
+		},
+		Workers: 3,
+	})
+
+	y0s := make([][]float64, 10)
+	for k := range y0s {
+		y0s[k] = []float64{float64(k), 0}
+	}
+	xs := []float64{0, 1, 2}
+
+	results, err := integrator.Compute(dydx, y0s, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(len(results), len(y0s), t)
+	for k, result := range results {
+		assert.Equal(result.Err, nil, t)
+		for i, x := range xs {
+			assert.Close(result.Ys[2*i], float64(k)*math.Cos(x), 1e-8, t)
+		}
+	}
+}
+
+func TestComputeWithStats(t *testing.T) {
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = -p[0] * y[0]
+	}
+
+	integrator, _ := New(&Config{
+		Integrator: func() (ode.Integrator, error) {
+			return rk4.New(&rk4.Config{Step: 0.1})
+		},
+	})
+
+	ps := [][]float64{{1}, {2}, {3}, {4}}
+
+	results, stats, err := integrator.ComputeWithStats(dydx, [][]float64{{1}}, ps,
+		[]float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Members, uint(4), t)
+	assert.Equal(stats.Failures, uint(0), t)
+	assert.Equal(stats.Evaluations, uint(4*4*10), t)
+	for k, result := range results {
+		n := len(result.Ys)
+		assert.Close(result.Ys[n-1], math.Exp(-ps[k][0]), 1e-4, t)
+	}
+
+	_, _, err = integrator.ComputeWithStats(dydx, [][]float64{{1}, {2}}, ps, []float64{0, 1})
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeBatch(t *testing.T) {
+	calls := 0
+	dydx := func(xs []float64, ys, fs []float64) {
+		calls++
+		for k := range xs {
+			fs[2*k] = ys[2*k+1]
+			fs[2*k+1] = -ys[2*k]
+		}
+	}
+
+	integrator, _ := New(&Config{
+		Integrator: func() (ode.Integrator, error) {
+			return dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10})
+		},
+	})
+
+	y0s := make([][]float64, 10)
+	for k := range y0s {
+		y0s[k] = []float64{float64(k), 0}
+	}
+	xs := []float64{0, 1, 2}
+
+	results, stats, err := integrator.ComputeBatch(dydx, y0s, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(len(results), len(y0s), t)
+	assert.Equal(stats.Evaluations, uint(10*calls), t)
+	for k, result := range results {
+		assert.Equal(result.Err, nil, t)
+		assert.Equal(result.Xs, xs, t)
+		for i, x := range xs {
+			assert.Close(result.Ys[2*i:2*i+2], []float64{
+				float64(k) * math.Cos(x),
+				-float64(k) * math.Sin(x),
+			}, 1e-8, t)
+		}
+	}
+
+	expected, _ := integrator.Compute(func(x float64, y, f []float64) {
+		dydx([]float64{x}, y, f)
+	}, y0s[3:4], xs)
+	batched, _, _ := integrator.ComputeBatch(ode.Batched(func(x float64, y, f []float64) {
+		dydx([]float64{x}, y, f)
+	}), y0s[3:4], xs)
+	assert.Equal(batched[0].Ys, expected[0].Ys, t)
+
+	_, _, err = integrator.ComputeBatch(dydx, [][]float64{{1, 0}, {1}}, xs)
+	assert.Equal(err != nil, true, t)
+}
@@ -0,0 +1,8 @@
+package ensemble
+
+// Stats contains information about the work done by an integrator.
+type Stats struct {
+	Evaluations uint // The number of invocations of the derivative function.
+	Members     uint // The number of members integrated.
+	Failures    uint // The number of members whose integration failed.
+}
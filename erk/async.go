@@ -0,0 +1,61 @@
+package erk
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var errCancel = errors.New("the integration has been cancelled")
+
+// Point is a point of a solution.
+type Point struct {
+	X float64
+	Y []float64
+}
+
+// ComputeAsync integrates the system of differential equations dy/dx = f(x, y)
+// in a separate goroutine and delivers the points of the solution, which are
+// the same as the ones of Compute, over the first channel as soon as they are
+// available. The first channel is closed when the integration is over, and
+// then the second channel delivers the outcome, which is nil on success. The
+// function returned as the third result cancels the integration; it can be
+// called any number of times, including after the integration is over.
+func (self *Integrator) ComputeAsync(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) (<-chan Point, <-chan error, func()) {
+
+	points := make(chan Point)
+	outcome := make(chan error, 1)
+
+	done := make(chan struct{})
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+
+	y0 = append([]float64(nil), y0...)
+	xs = append([]float64(nil), xs...)
+
+	go func() {
+		_, _, _, err := self.stream(context.Background(), dydx, &State{Y: y0}, xs, nil,
+			func(x float64, y []float64) error {
+				select {
+				case <-done:
+					return errCancel
+				default:
+				}
+				select {
+				case points <- Point{X: x, Y: append([]float64(nil), y...)}:
+					return nil
+				case <-done:
+					return errCancel
+				}
+			})
+		close(points)
+		outcome <- err
+	}()
+
+	return points, outcome, cancel
+}
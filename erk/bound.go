@@ -0,0 +1,72 @@
+package erk
+
+import (
+	"math"
+)
+
+// Bound is a constraint on a component of the solution, which should stay
+// within [Lower, Upper]. The derivative of a component at one of its bounds is
+// not allowed to point outward, a step whose solution violates a bound by more
+// than the error tolerance is rejected, and the solution of an accepted step
+// is projected onto the bounds, similarly to the NonNegative option of MATLAB.
+type Bound struct {
+	Index uint    // The index of the component.
+	Lower float64 // The lower bound, which can be -∞.
+	Upper float64 // The upper bound, which can be +∞.
+}
+
+// NonNegative returns the bound that keeps a component nonnegative.
+func NonNegative(index uint) Bound {
+	return Bound{Index: index, Lower: 0, Upper: math.Inf(1)}
+}
+
+// constrain wraps a derivative function in order to stop the components at
+// their bounds from moving outward, like MATLAB does.
+func constrain(bounds []Bound, dydx func(float64, []float64, []float64)) func(float64, []float64, []float64) {
+	return func(x float64, y, f []float64) {
+		dydx(x, y, f)
+		for _, bound := range bounds {
+			i := bound.Index
+			if y[i] <= bound.Lower && f[i] < 0 || y[i] >= bound.Upper && f[i] > 0 {
+				f[i] = 0
+			}
+		}
+	}
+}
+
+// violation computes the largest violation of the bounds by ynew relative to
+// max(|y|, |ynew|, threshold), which is to be compared with the relative error
+// tolerance.
+func violation(bounds []Bound, y, ynew []float64, threshold float64) float64 {
+	ε := 0.0
+	for _, bound := range bounds {
+		i := bound.Index
+		δ := math.Max(bound.Lower-ynew[i], ynew[i]-bound.Upper)
+		if δ <= 0 {
+			continue
+		}
+		scale := math.Max(math.Max(math.Abs(y[i]), math.Abs(ynew[i])), threshold)
+		ε = math.Max(ε, δ/scale)
+	}
+	return ε
+}
+
+// project clamps the components of y to the bounds and reports if any of them
+// has changed. The compensations of the clamped components in c, if given, are
+// reset.
+func project(bounds []Bound, y, c []float64) bool {
+	changed := false
+	for _, bound := range bounds {
+		i := bound.Index
+		v := math.Min(math.Max(y[i], bound.Lower), bound.Upper)
+		if v == y[i] {
+			continue
+		}
+		y[i] = v
+		if c != nil {
+			c[i] = 0
+		}
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,98 @@
+package erk
+
+// Checkpoint is a snapshot of a stepper, which allows a long integration to be
+// resumed after a crash or on another machine. The fields are exported so
+// that a checkpoint can be marshaled using, for instance, encoding/gob or
+// encoding/json.
+type Checkpoint struct {
+	X float64   // The current point.
+	Y []float64 // The solution at the current point.
+	H float64   // The step size to continue with.
+
+	Xold float64   // The point where the last step started.
+	Yold []float64 // The solution at the point where the last step started.
+
+	// The derivatives of the stages of the last step followed by the derivative
+	// at the current point. If no step has been taken, only the first entry is
+	// meaningful, and it is the derivative at the current point.
+	F [][]float64
+
+	// The relative errors of the previous accepted steps, which are used by
+	// the controller of the step size.
+	History [2]float64
+
+	Stats Stats // The work done so far.
+}
+
+// Checkpoint takes a snapshot of the stepper. The snapshot does not share
+// memory with the stepper.
+func (self *Stepper) Checkpoint() *Checkpoint {
+	f := make([][]float64, len(self.f))
+	for k := range f {
+		f[k] = append([]float64(nil), self.f[k]...)
+	}
+	return &Checkpoint{
+		X:       self.xnew,
+		Y:       append([]float64(nil), self.ynew...),
+		H:       self.h,
+		Xold:    self.x,
+		Yold:    append([]float64(nil), self.y...),
+		F:       f,
+		History: self.history,
+		Stats:   self.stats,
+	}
+}
+
+// Restore creates a stepper for the system of differential equations
+// dy/dx = f(x, y) from a checkpoint. The integrator should be configured in
+// the same way as the one that the checkpoint has been taken from.
+func (self *Integrator) Restore(dydx func(float64, []float64, []float64),
+	checkpoint *Checkpoint) (*Stepper, error) {
+
+	nd, ns := len(checkpoint.Y), len(self.tableau.C)
+
+	if len(checkpoint.Yold) != nd {
+		return nil, argument("the solutions of the checkpoint should have the same dimension")
+	}
+	if len(checkpoint.F) != ns+1 {
+		return nil, argument("the checkpoint should have a derivative for each stage")
+	}
+	for _, f := range checkpoint.F {
+		if len(f) != nd {
+			return nil, argument("the derivatives of the checkpoint have a wrong dimension")
+		}
+	}
+	if checkpoint.History[0] <= 0 || checkpoint.History[1] <= 0 {
+		return nil, argument("the errors of the checkpoint should be positive")
+	}
+	if checkpoint.H <= 0 {
+		return nil, argument("the step size of the checkpoint should be positive")
+	}
+
+	f := make([][]float64, ns+1)
+	for k := range f {
+		f[k] = append([]float64(nil), checkpoint.F[k]...)
+	}
+
+	stepper := self.NewStepper(dydx)
+
+	stepper.rhs = stepper.dydx
+	if self.config.Mass != nil {
+		var err error
+		if stepper.rhs, err = invert(self.config.Mass, nd, stepper.dydx); err != nil {
+			return nil, err
+		}
+	}
+
+	stepper.x, stepper.xnew = checkpoint.Xold, checkpoint.X
+	stepper.h, stepper.hlast = checkpoint.H, checkpoint.X-checkpoint.Xold
+	stepper.y = append([]float64(nil), checkpoint.Yold...)
+	stepper.ynew = append([]float64(nil), checkpoint.Y...)
+	stepper.z = make([]float64, nd)
+	stepper.f = f
+	stepper.history = checkpoint.History
+	stepper.stats = checkpoint.Stats
+	stepper.taken = checkpoint.X != checkpoint.Xold
+
+	return stepper, nil
+}
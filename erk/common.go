@@ -0,0 +1,67 @@
+package erk
+
+import (
+	"github.com/ready-steady/ode"
+)
+
+// ComputeWithSummary augments Compute by returning the work done in the form
+// common to all integrators; see ode.StatsIntegrator.
+func (self *Integrator) ComputeWithSummary(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *ode.Stats, error) {
+
+	ys, xs, stats, err := self.ComputeWithStats(dydx, y0, xs)
+	if stats == nil {
+		return ys, xs, nil, err
+	}
+
+	return ys, xs, &ode.Stats{
+		Evaluations: stats.Evaluations,
+		Rejections:  stats.Rejections,
+		Steps:       stats.Steps,
+	}, err
+}
+
+// ComputeDense integrates the system of differential equations like Solve;
+// see ode.DenseIntegrator.
+func (self *Integrator) ComputeDense(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) (ode.Dense, error) {
+
+	solution, _, err := self.Solve(dydx, y0, xs)
+	if err != nil {
+		return nil, err
+	}
+
+	return solution, nil
+}
+
+// ComputeWithCrossings integrates the system of differential equations like
+// ComputeWithEvents with the events of the configuration replaced by the
+// given ones; see ode.EventIntegrator.
+func (self *Integrator) ComputeWithCrossings(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64, events []ode.Event) ([]float64, []float64, []ode.Crossing, error) {
+
+	integrator := *self
+	integrator.config.Events = make([]Event, len(events))
+	for k, event := range events {
+		integrator.config.Events[k] = Event{
+			Function:  event.Function,
+			Direction: event.Direction,
+			Terminal:  event.Terminal,
+		}
+	}
+	if err := integrator.config.verify(); err != nil {
+		return nil, nil, nil, argument(err.Error())
+	}
+
+	// The workspaces of the integrator do not have room for the events.
+	integrator.workspaces = nil
+
+	ys, xs, crossings, _, err := integrator.ComputeWithEvents(dydx, y0, xs)
+
+	common := make([]ode.Crossing, len(crossings))
+	for k, crossing := range crossings {
+		common[k] = ode.Crossing{Index: crossing.Index, X: crossing.X, Y: crossing.Y}
+	}
+
+	return ys, xs, common, err
+}
@@ -0,0 +1,331 @@
+package erk
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The initial step of integration.
+	TryStep float64 `json:"tryStep"`
+	// The maximal step of integration.
+	MaxStep float64 `json:"maxStep"`
+	// The minimal step of integration. If it is zero, the minimal step is 16
+	// times the spacing of the floating-point numbers at the current point.
+	// The step is never smaller than the spacing itself.
+	MinStep float64 `json:"minStep"`
+	// The absolute error tolerance.
+	AbsError float64 `json:"absError"`
+	// The relative error tolerance.
+	RelError float64 `json:"relError"`
+	// The maximal number of steps, which is unlimited if zero.
+	MaxSteps uint `json:"maxSteps"`
+	// The maximal number of evaluations of the derivative, which is unlimited
+	// if zero.
+	MaxEvaluations uint `json:"maxEvaluations"`
+	// The maximal duration of an integration, which is unlimited if zero. The
+	// elapsed time is checked before each step, and it does not apply to
+	// Stepper.
+	MaxDuration time.Duration `json:"maxDuration"`
+	// The controller of the step size.
+	Controller Controller `json:"controller"`
+	// The norm of the error estimate, which is compared with RelError in
+	// order to decide if a step is accepted. Before taking the norm, each
+	// component of the estimate is divided by max(|y|, |ynew|, AbsError /
+	// RelError) where y and ynew are the solutions at the beginning and the
+	// end of the step, respectively.
+	Norm Norm `json:"norm"`
+	// The function computing the norm of the error estimate e given the
+	// solutions at the beginning and the end of the step, y and ynew,
+	// respectively. If it is not nil, it overrides Norm, and the result is
+	// compared with RelError.
+	ErrorNorm func(e, y, ynew []float64) float64 `json:"-"`
+	// The flag that disables the check that the derivatives and the solutions
+	// computed during each step are finite. By default, the integration fails
+	// with a FiniteError as soon as a NaN or an infinity is encountered.
+	SkipFiniteCheck bool `json:"skipFiniteCheck"`
+	// The flag that enables the compensated summation of the independent
+	// variable and the solution across steps, which reduces the accumulation
+	// of rounding errors in integrations with a large number of steps. It
+	// does not apply to Stepper.
+	Compensated bool `json:"compensated"`
+	// The number of goroutines among which the updates of the solution by the
+	// stages of the method and the computation of the norm of the error
+	// estimate are split for systems with at least 16384 components. If it is
+	// zero or one, or if the system is smaller, the updates are sequential.
+	// The work of each goroutine is only a few operations per component and
+	// stage; hence, parallelism pays off only for very large systems, such as
+	// those of the method of lines. With RMSNorm, the norm is summed in parts,
+	// and it can differ from the sequential one in the last bits.
+	Parallelism uint `json:"parallelism"`
+	// The tolerance of the detection of a steady state. If it is positive, the
+	// integration stops as soon as |f(x, y)| falls below the tolerance times
+	// max(|y|, AbsError/RelError) for all the components.
+	SteadyState float64 `json:"steadyState"`
+	// The function called after each accepted step with the interpolant of
+	// the step. The interpolant is valid only during the call and should be
+	// cloned in order to be used afterwards.
+	Callback func(*Interpolant) `json:"-"`
+	// The function called after each accepted step with the new point, the
+	// size of the step, and the solution at the new point, which is valid only
+	// during the call.
+	OnAccept func(x, h float64, y []float64) `json:"-"`
+	// The function called after each rejected step with the point where the
+	// step starts, the size of the step, and the estimate of the error, which
+	// is to be compared with RelError.
+	OnReject func(x, h, ε float64) `json:"-"`
+	// The function called every ProgressSteps accepted steps with the fraction
+	// of the interval of integration that has been covered, the size of the
+	// last step, and the work done so far.
+	Progress func(fraction, h float64, stats *Stats) `json:"-"`
+	// The number of accepted steps between two invocations of Progress. If it
+	// is zero, Progress is invoked after each accepted step.
+	ProgressSteps uint `json:"progressSteps"`
+	// The flag that enables the measurement of the time of the integration
+	// and of the time spent in the derivative function; see Stats.
+	Timing bool `json:"timing"`
+	// The bounds on the components of the solution; see Bound. It does not
+	// apply to Stepper.
+	Bounds []Bound `json:"bounds"`
+	// The constant mass matrix M of the system M y′ = f(x, y), which is stored
+	// in row-major order and should be nonsingular. The system is then
+	// integrated as y′ = M⁻¹ f(x, y) using an LU decomposition of M computed
+	// once. If it is not given, M is the identity matrix.
+	Mass []float64 `json:"mass"`
+	// The invariants of the system onto which the solution is projected
+	// orthogonally after accepted steps; see Invariant. It does not apply to
+	// Stepper.
+	Invariants []Invariant `json:"-"`
+	// The number of accepted steps between two projections onto the
+	// invariants. If it is zero, the solution is projected after each
+	// accepted step.
+	ProjectionSteps uint `json:"projectionSteps"`
+	// The quantities that are evaluated after each accepted step in order to
+	// report their drifts from the initial values; see Stats. Unlike
+	// Invariants, they do not affect the solution. It does not apply to
+	// Stepper.
+	Monitors []func(y []float64) float64 `json:"-"`
+	// The points where the right-hand side is discontinuous, which are sorted
+	// in the ascending order. The integrator lands exactly on each of them and
+	// restarts the selection of the step size afterwards, so that no step
+	// crosses a discontinuity.
+	Stops []float64 `json:"stops"`
+	// The events whose occurrences are located during the integration; see
+	// Integrator.ComputeWithEvents.
+	Events []Event `json:"-"`
+	// The number of parts into which each reported step is divided when the
+	// solution is returned at the points that the integrator internally
+	// traverses. If it is greater than one, the solution is additionally
+	// reported at Refine-1 equidistant points inside each step, which are
+	// computed using the interpolant of the method. It does not apply to
+	// Stepper.
+	Refine uint `json:"refine"`
+	// The number of accepted steps between two reported points when the
+	// solution is returned at the points that the integrator internally
+	// traverses. If it is greater than one, only every Decimate-th step is
+	// reported, along with the last point and the points where the
+	// integration stops or restarts. It does not apply to Stepper.
+	Decimate uint `json:"decimate"`
+	// The flag that enables the mode reproducing the semantics of MATLAB's
+	// ode45, or ode23 for the Bogacki–Shampine method: the stages are
+	// combined with the weights multiplied by the step size, the error is
+	// measured in the maximum norm and multiplied by the step size afterwards,
+	// the last step ends exactly at the end of the interval, and Refine
+	// defaults to four. The initial step, the bounds on the step, and the
+	// constants of the controller are those of MATLAB regardless of the mode.
+	// The results agree with those of MATLAB up to the order in which its
+	// linear algebra sums the stages. Norm and Parallelism are ignored in the
+	// mode, and it excludes Compensated and controllers other than Integral.
+	MATLAB bool `json:"matlab"`
+	// The flag that enables the deterministic mode, in which the results are
+	// bit-identical across architectures and versions of Go. The stages are
+	// combined with the products rounded before the additions, which keeps
+	// the compiler from fusing them into FMA instructions where they are
+	// available, such as on arm64; the error is summed in a fixed order; and
+	// the powers of the controller of the step size are computed using only
+	// the basic arithmetic operations instead of math.Pow, whose
+	// implementation differs among architectures. The same applies to the
+	// interpolant. Parallelism is ignored in the mode, and the projections
+	// onto Invariants are not covered by it. The derivative function and the
+	// other functions of the configuration should likewise round their
+	// products, as in float64(a*b) + c, and avoid the functions of the math
+	// package other than the exact ones, such as math.Sqrt and math.Abs. The
+	// price is a slightly slower integration.
+	Deterministic bool `json:"deterministic"`
+}
+
+// Norm is a choice of a norm of the error estimate.
+type Norm uint
+
+const (
+	// The maximum norm.
+	MaxNorm Norm = iota
+	// The root mean square, which is the norm used by Hairer et al. and CVODE.
+	RMSNorm
+)
+
+// Controller is a choice of a controller of the step size.
+type Controller uint
+
+const (
+	// The integral controller, which chooses the next step size based on the
+	// error of the current step only.
+	Integral Controller = iota
+	// The proportional–integral controller due to Gustafsson, which also
+	// takes into account the error of the previous step and reduces the
+	// oscillations of the step size and the rejections that they cause.
+	PI
+	// The proportional–integral–derivative controller due to Söderlind, which
+	// takes into account the errors of the two previous steps.
+	PID
+)
+
+// The exponents of the errors of the current and the two previous steps,
+// scaled by the reciprocal of the order of the error estimate plus one.
+var controllers = [...][3]float64{
+	Integral: {1, 0, 0},
+	PI:       {0.7, -0.4, 0},
+	PID:      {0.49, -0.34, 0.1},
+}
+
+// scale computes the factor by which the size of an accepted step is divided
+// in order to obtain the next one given the relative error of the step r. The
+// relative errors of the previous steps are updated accordingly. The powers
+// are computed by pow.
+func (c Controller) scale(r float64, history *[2]float64, power float64,
+	pow func(float64, float64) float64) float64 {
+
+	β := controllers[c]
+
+	scale := 1.25 * pow(r, β[0]*power)
+	if β[1] != 0 {
+		scale *= pow(history[0], β[1]*power)
+	}
+	if β[2] != 0 {
+		scale *= pow(history[1], β[2]*power)
+	}
+
+	history[1], history[0] = history[0], math.Max(r, 1e-4)
+
+	return scale
+}
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return &Config{
+		TryStep:  0,
+		MaxStep:  0,
+		AbsError: 1e-6,
+		RelError: 1e-3,
+	}
+}
+
+// parallelism returns the number of goroutines among which the updates of a
+// system of dimension nd are split.
+func (c *Config) parallelism(nd int) int {
+	if c.Parallelism < 2 || nd < parallelThreshold || c.MATLAB || c.Deterministic {
+		return 1
+	}
+	return int(c.Parallelism)
+}
+
+// pow computes x raised to the power of y, which is done by the deterministic
+// version in the deterministic mode.
+func (c *Config) pow(x, y float64) float64 {
+	if c.Deterministic {
+		return pow(x, y)
+	}
+	return math.Pow(x, y)
+}
+
+// refinement returns the number of parts into which each reported step is
+// divided.
+func (c *Config) refinement() uint {
+	if c.MATLAB && c.Refine == 0 {
+		return 4
+	}
+	return c.Refine
+}
+
+// minimum returns the minimal step of integration at a point.
+func (c *Config) minimum(x float64) float64 {
+	if c.MinStep == 0 {
+		return 16 * epsilon(x)
+	}
+	return math.Max(c.MinStep, epsilon(x))
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	if err := c.verify(); err != nil {
+		return classify(ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+func (c *Config) verify() error {
+	if c.TryStep < 0 {
+		return errors.New("the initial step should be nonnegative")
+	}
+	if c.MaxStep < 0 {
+		return errors.New("the maximal step should be nonnegative")
+	}
+	if c.MinStep < 0 {
+		return errors.New("the minimal step should be nonnegative")
+	}
+	if c.MaxStep > 0 && c.MinStep > c.MaxStep {
+		return errors.New("the minimal step should not exceed the maximal one")
+	}
+	if c.AbsError <= 0 {
+		return errors.New("the absolute error tolerance should be positive")
+	}
+	if c.RelError <= 0 {
+		return errors.New("the relative error tolerance should be positive")
+	}
+	if c.MaxDuration < 0 {
+		return errors.New("the maximal duration should be nonnegative")
+	}
+	if c.Norm > RMSNorm {
+		return errors.New("the norm is unknown")
+	}
+	if c.Controller > PID {
+		return errors.New("the controller is unknown")
+	}
+	if c.MATLAB && c.Compensated {
+		return errors.New("the compensated summation should not be used in the mode of MATLAB")
+	}
+	if c.MATLAB && c.Controller != Integral {
+		return errors.New("the controller should be integral in the mode of MATLAB")
+	}
+	for _, bound := range c.Bounds {
+		if !(bound.Lower <= bound.Upper) {
+			return errors.New("the lower bounds should not exceed the upper ones")
+		}
+	}
+	for _, invariant := range c.Invariants {
+		if invariant.Function == nil || invariant.Gradient == nil {
+			return errors.New("the invariants should have functions and gradients")
+		}
+	}
+	if c.SteadyState < 0 {
+		return errors.New("the steady-state tolerance should be nonnegative")
+	}
+	for i := 1; i < len(c.Stops); i++ {
+		if c.Stops[i] <= c.Stops[i-1] {
+			return errors.New("the stops should be sorted in the strictly ascending order")
+		}
+	}
+	for _, event := range c.Events {
+		if event.Function == nil {
+			return errors.New("the event functions should not be nil")
+		}
+		if event.Reset != nil && !event.Terminal {
+			return errors.New("the reset maps should belong to terminal events")
+		}
+	}
+
+	return nil
+}
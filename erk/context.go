@@ -0,0 +1,25 @@
+package erk
+
+import (
+	"context"
+)
+
+// ComputeContext integrates the system of differential equations
+// dy/dx = f(x, y) like Compute but checks the context before each step. If the
+// context is done, the integration stops, and the function returns the error
+// of the context along with the solution computed so far. The same applies to
+// the other errors that occur during the integration.
+func (self *Integrator) ComputeContext(ctx context.Context, dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	workspace := self.acquire(len(y0))
+	defer self.release(workspace)
+
+	var ys, zs []float64
+	_, _, _, err := self.stream(ctx, dydx, &State{Y: y0}, xs, workspace, func(x float64, y []float64) error {
+		ys, zs = append(ys, y...), append(zs, x)
+		return nil
+	})
+
+	return ys, zs, err
+}
@@ -0,0 +1,188 @@
+package erk
+
+import (
+	"math"
+)
+
+// The functions below are used in the deterministic mode; see
+// Config.Deterministic. The specification of Go allows an implementation to
+// fuse a multiplication and an addition into one FMA instruction, which
+// rounds once instead of twice, and the compilers do so on arm64, ppc64,
+// s390x, and, with GOAMD64=v3, on amd64. An explicit conversion to float64
+// forces the rounding of a product and thereby prevents the fusion.
+
+// combineStrict computes combine with the products rounded.
+func combineStrict(y []float64, h float64, w []float64, f [][]float64, ynew []float64) {
+	weighStrict(w, f, ynew)
+	for i := range ynew {
+		ynew[i] = y[i] + float64(h*ynew[i])
+	}
+}
+
+// accumulateStrict computes accumulate with the products rounded.
+func accumulateStrict(y []float64, h float64, w []float64, f [][]float64,
+	c, ynew, cnew []float64) {
+
+	weighStrict(w, f, ynew)
+	for i := range ynew {
+		ynew[i], cnew[i] = add(y[i], float64(h*ynew[i]), c[i])
+	}
+}
+
+// weighStrict computes weigh with the products rounded.
+func weighStrict(w []float64, f [][]float64, sum []float64) {
+	first := true
+	for k, w := range w {
+		if w == 0 {
+			continue
+		}
+		if first {
+			scale(w, f[k], sum)
+			first = false
+		} else {
+			axpyStrict(w, f[k], sum)
+		}
+	}
+	if first {
+		for i := range sum {
+			sum[i] = 0
+		}
+	}
+}
+
+// axpyStrict computes axpy with the products rounded.
+func axpyStrict(a float64, x, y []float64) {
+	x = x[:len(y)]
+	for i := range y {
+		y[i] += float64(a * x[i])
+	}
+}
+
+// interpolateStrict computes interpolate with the products rounded.
+func interpolateStrict(D [][]float64, x float64, y, ynew []float64, f [][]float64,
+	h, xnext float64, ynext []float64) {
+
+	nd, ns := len(y), len(f)-1
+
+	s := (xnext - x) / h
+
+	if D == nil {
+		h00 := (1 + float64(2*s)) * (1 - s) * (1 - s)
+		h10 := s * (1 - s) * (1 - s)
+		h01 := s * s * (3 - float64(2*s))
+		h11 := s * s * (s - 1)
+
+		for i := 0; i < nd; i++ {
+			ynext[i] = float64(h00*y[i]) + float64(h*h10*f[0][i]) +
+				float64(h01*ynew[i]) + float64(h*h11*f[ns][i])
+		}
+
+		return
+	}
+
+	nk := 0
+	for k := range D {
+		if len(D[k]) > nk {
+			nk = len(D[k])
+		}
+	}
+
+	for i := 0; i < nd; i++ {
+		ynext[i] = y[i]
+		power := 1.0
+		for l := 0; l < nk; l++ {
+			power *= s
+			sum := 0.0
+			for k := range D {
+				if l < len(D[k]) && D[k][l] != 0 {
+					sum += float64(D[k][l] * f[k][i])
+				}
+			}
+			ynext[i] += float64(h * power * sum)
+		}
+	}
+}
+
+// pow computes x raised to the power of y for nonnegative x as exp(y log(x))
+// using only the basic arithmetic operations with the products rounded, unlike
+// math.Pow, whose implementation and, thereby, last bits differ among
+// architectures and versions of Go. The relative error is a few times
+// |y log(x)| units in the last place, which is more than enough for the
+// control of the step size.
+func pow(x, y float64) float64 {
+	switch {
+	case y == 0 || x == 1:
+		return 1
+	case math.IsNaN(x) || math.IsNaN(y):
+		return math.NaN()
+	case x == 0:
+		if y > 0 {
+			return 0
+		}
+		return math.Inf(1)
+	case math.IsInf(x, 1):
+		if y > 0 {
+			return math.Inf(1)
+		}
+		return 0
+	case x < 0:
+		return math.NaN()
+	}
+	return exp(float64(y * log(x)))
+}
+
+const (
+	ln2Hi = 6.93147180369123816490e-01
+	ln2Lo = 1.90821492927058770002e-10
+)
+
+// log computes the natural logarithm of a positive finite x. The fraction of
+// x is brought into [√½, √2), and its logarithm is computed using the series
+// log(m) = 2 atanh(s) = 2 (s + s³/3 + s⁵/5 + …) with s = (m - 1) / (m + 1),
+// which is truncated after the term of s²⁵ as |s| < 0.172.
+func log(x float64) float64 {
+	m, e := math.Frexp(x)
+	if m < math.Sqrt2/2 {
+		m, e = 2*m, e-1
+	}
+
+	s := (m - 1) / (m + 1)
+	s2 := s * s
+
+	p := 1.0 / 25
+	for k := 11; k >= 0; k-- {
+		p = float64(p*s2) + 1/float64(2*k+1)
+	}
+
+	k := float64(e)
+	return float64(k*ln2Hi) + (float64(k*ln2Lo) + float64(2*s*p))
+}
+
+// exp computes the exponential of x. The argument is reduced to
+// r = x - k log(2) with |r| ≤ log(2) / 2, and the exponential of r is
+// computed using its Taylor series, which is truncated after the term of r¹⁴.
+func exp(x float64) float64 {
+	const (
+		overflow  = 7.09782712893383973096e+02
+		underflow = -7.45133219101941108420e+02
+	)
+
+	switch {
+	case math.IsNaN(x):
+		return x
+	case x > overflow:
+		return math.Inf(1)
+	case x < underflow:
+		return 0
+	}
+
+	k := math.Floor(float64(x*math.Log2E) + 0.5)
+	r := (x - float64(k*ln2Hi)) - float64(k*ln2Lo)
+
+	p := 1.0
+	for n := 14; n >= 1; n-- {
+		p = float64(p*r)/float64(n) + 1
+	}
+
+	return math.Ldexp(p, int(k))
+}
@@ -0,0 +1,123 @@
+package erk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// The kinds of the errors returned by the package, which can be checked using
+// errors.Is.
+var (
+	ErrInvalidConfig   = errors.New("the configuration is invalid")
+	ErrInvalidTableau  = errors.New("the tableau is invalid")
+	ErrInvalidArgument = errors.New("an argument is invalid")
+	ErrStepUnderflow   = errors.New("encountered a step-size underflow")
+	ErrMaxSteps        = errors.New("reached the maximal number of steps")
+	ErrMaxEvaluations  = errors.New("reached the maximal number of evaluations")
+	ErrMaxDuration     = errors.New("reached the maximal duration")
+	ErrNotFinite       = errors.New("encountered a non-finite value")
+)
+
+// Error is an error that occurs during an integration, which can be obtained
+// using errors.As. The kind of the error is ErrStepUnderflow, ErrMaxSteps,
+// ErrMaxEvaluations, ErrMaxDuration, or a FiniteError.
+type Error struct {
+	Kind  error   // The kind of the error or a more specific error of the kind.
+	X     float64 // The point where the failed step starts.
+	H     float64 // The size of the failed step.
+	Stats Stats   // The work done before the failure.
+}
+
+func (self *Error) Error() string {
+	return fmt.Sprintf("%v at x = %g with h = %g", self.Kind, self.X, self.H)
+}
+
+// Unwrap returns the kind of the error.
+func (self *Error) Unwrap() error {
+	return self.Kind
+}
+
+func fail(kind error, x, h float64, stats *Stats) error {
+	return &Error{Kind: kind, X: x, H: h, Stats: *stats}
+}
+
+// FiniteError is the error of the kind ErrNotFinite, which occurs when a
+// derivative or a solution computed during a step is not finite.
+type FiniteError struct {
+	X          float64 // The point of the value.
+	Stage      int     // The stage of the method or the number of stages at the end of the step.
+	Index      int     // The component of the value.
+	Derivative bool    // Whether the value is a derivative as opposed to a solution.
+}
+
+func (self *FiniteError) Error() string {
+	value := "solution"
+	if self.Derivative {
+		value = "derivative"
+	}
+	return fmt.Sprintf("%v in component %d of the %s at stage %d (x = %g)",
+		ErrNotFinite, self.Index, value, self.Stage, self.X)
+}
+
+// Unwrap returns ErrNotFinite.
+func (self *FiniteError) Unwrap() error {
+	return ErrNotFinite
+}
+
+// finite checks if the components of a value are finite and returns a
+// FiniteError otherwise.
+func finite(value []float64, x float64, stage int, derivative bool) error {
+	for i, v := range value {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return &FiniteError{X: x, Stage: stage, Index: i, Derivative: derivative}
+		}
+	}
+	return nil
+}
+
+// kindError is an error with a message of its own that belongs to one of the
+// kinds of errors.
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (self *kindError) Error() string {
+	return self.message
+}
+
+func (self *kindError) Is(target error) bool {
+	return target == self.kind
+}
+
+func classify(kind error, err error) error {
+	return &kindError{kind: kind, message: err.Error()}
+}
+
+func argument(message string) error {
+	return &kindError{kind: ErrInvalidArgument, message: message}
+}
+
+// limit checks if the given number of steps and evaluations on top of the
+// work in stats would exceed the limits of the configuration and returns the
+// kind of the error if so.
+func (c *Config) limit(stats *Stats, steps, evaluations uint) error {
+	if c.MaxSteps > 0 && stats.Steps+steps > c.MaxSteps {
+		return ErrMaxSteps
+	}
+	if c.MaxEvaluations > 0 && stats.Evaluations+evaluations > c.MaxEvaluations {
+		return ErrMaxEvaluations
+	}
+	return nil
+}
+
+// expire checks if the time elapsed since start exceeds the limit of the
+// configuration and returns the kind of the error if so.
+func (c *Config) expire(start time.Time) error {
+	if c.MaxDuration > 0 && time.Since(start) > c.MaxDuration {
+		return ErrMaxDuration
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package erk
+
+import (
+	"math"
+)
+
+const (
+	eventMaxIter = 100
+)
+
+// Event is an event whose occurrences are located during the integration. An
+// event occurs when its function changes its sign.
+type Event struct {
+	// The event function g(x, y).
+	Function func(x float64, y []float64) float64
+	// The direction of the crossings of zero that are located, which is
+	// positive for the ones where the function increases, negative for the
+	// ones where it decreases, and zero for both.
+	Direction int
+	// Should the integration stop at the first crossing?
+	Terminal bool
+	// The reset map, which applies only to terminal events. If it is given,
+	// the integration does not stop at the crossing; instead, the function
+	// modifies the solution at the crossing in place, and the integration
+	// restarts from the modified solution. The crossing itself reports the
+	// solution before the modification.
+	Reset func(x float64, y []float64)
+}
+
+// Crossing is an occurrence of an event located during the integration.
+type Crossing struct {
+	Index uint      // The index of the event.
+	X     float64   // The location of the crossing.
+	Y     []float64 // The solution at the location of the crossing.
+}
+
+// locate finds a root of g(x, y(x)) between x0 and x1 where g changes its sign
+// using the Illinois variant of the regula falsi method. The solution at
+// intermediate points is given by the interpolant, and the solution at the
+// root is stored in y.
+func locate(g func(float64, []float64) float64, x0, g0, x1, g1 float64,
+	interpolant func(float64, []float64), y []float64) float64 {
+
+	a, ga, b, gb := x0, g0, x1, g1
+
+	x := b
+	for side, k := 0, 0; k < eventMaxIter; k++ {
+		if math.Abs(b-a) <= 4*epsilon(math.Max(math.Abs(a), math.Abs(b))) {
+			break
+		}
+
+		x = (float64(a*gb) - float64(b*ga)) / (gb - ga)
+		if a < b && (x <= a || x >= b) || a > b && (x >= a || x <= b) {
+			x = (a + b) / 2
+		}
+
+		interpolant(x, y)
+		gx := g(x, y)
+
+		if gx == 0 {
+			return x
+		} else if (gx < 0) == (gb < 0) {
+			b, gb = x, gx
+			if side == -1 {
+				ga /= 2
+			}
+			side = -1
+		} else {
+			a, ga = x, gx
+			if side == 1 {
+				gb /= 2
+			}
+			side = 1
+		}
+	}
+
+	// Report the end of the bracket after the sign change.
+	x = b
+	interpolant(x, y)
+
+	return x
+}
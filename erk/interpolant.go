@@ -0,0 +1,59 @@
+package erk
+
+// Interpolant is the interpolant of the method over a step, which gives the
+// solution at any point of the step.
+type Interpolant struct {
+	X    float64     // The beginning of the step.
+	H    float64     // The signed size of the step.
+	Y    []float64   // The solution at the beginning of the step.
+	Ynew []float64   // The solution at the end of the step.
+	F    [][]float64 // The derivatives at the stages and at the end of the step.
+
+	tableau *Tableau
+	strict  bool
+}
+
+// Evaluate computes the solution at a point, which is normally within the
+// step, and stores the result in y.
+func (self *Interpolant) Evaluate(x float64, y []float64) {
+	switch x {
+	case self.X:
+		copy(y, self.Y)
+	case self.X + self.H:
+		copy(y, self.Ynew)
+	default:
+		interpolate(self.tableau.D, self.X, self.Y, self.Ynew, self.F, self.H, x, y, self.strict)
+	}
+}
+
+// Derivative computes the derivative of the solution at a point, which is
+// normally within the step, and stores the result in f. The derivative is the
+// one of the interpolant except at the ends of the step, where it is the one
+// given by F.
+func (self *Interpolant) Derivative(x float64, f []float64) {
+	switch x {
+	case self.X:
+		copy(f, self.F[0])
+	case self.X + self.H:
+		copy(f, self.F[len(self.F)-1])
+	default:
+		differentiate(self.tableau.D, self.X, self.Y, self.Ynew, self.F, self.H, x, f)
+	}
+}
+
+// Clone returns a deep copy of the interpolant.
+func (self *Interpolant) Clone() *Interpolant {
+	clone := &Interpolant{
+		X:       self.X,
+		H:       self.H,
+		Y:       append([]float64(nil), self.Y...),
+		Ynew:    append([]float64(nil), self.Ynew...),
+		F:       make([][]float64, len(self.F)),
+		tableau: self.tableau,
+		strict:  self.strict,
+	}
+	for k := range self.F {
+		clone.F[k] = append([]float64(nil), self.F[k]...)
+	}
+	return clone
+}
@@ -0,0 +1,101 @@
+package erk
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode/internal/dense"
+)
+
+const (
+	projectionMaxIter = 10
+)
+
+// Invariant is a quantity g(y) that is conserved by the system, such as the
+// energy, the mass, or the norm of a quaternion. The value of the quantity is
+// taken from the initial condition.
+type Invariant struct {
+	// The function g(y).
+	Function func(y []float64) float64
+	// The function computing the gradient of g at y and storing it in its
+	// second argument.
+	Gradient func(y, grad []float64)
+}
+
+// projector projects solutions orthogonally onto the manifold where the
+// invariants have the values they have at the initial condition.
+type projector struct {
+	invariants []Invariant
+	values     []float64
+
+	G  []float64
+	M  []float64
+	r  []float64
+	lu *dense.LU
+}
+
+func newProjector(invariants []Invariant, y0 []float64) *projector {
+	nd, ni := len(y0), len(invariants)
+
+	values := make([]float64, ni)
+	for k, invariant := range invariants {
+		values[k] = invariant.Function(y0)
+	}
+
+	return &projector{
+		invariants: invariants,
+		values:     values,
+
+		G:  make([]float64, ni*nd),
+		M:  make([]float64, ni*ni),
+		r:  make([]float64, ni),
+		lu: dense.NewLU(uint(ni)),
+	}
+}
+
+// project moves y to the closest point where the invariants have their
+// initial values using the simplified Newton method, in which the gradients
+// are evaluated once at the original point. If the gradients are linearly
+// dependent, y is left as it is.
+func (self *projector) project(y []float64) {
+	nd, ni := len(y), len(self.invariants)
+	G, M, r := self.G, self.M, self.r
+
+	for k, invariant := range self.invariants {
+		invariant.Gradient(y, G[k*nd:(k+1)*nd])
+	}
+
+	// M = G Gᵀ.
+	for k := 0; k < ni; k++ {
+		for l := 0; l < ni; l++ {
+			sum := 0.0
+			for i := 0; i < nd; i++ {
+				sum += G[k*nd+i] * G[l*nd+i]
+			}
+			M[k*ni+l] = sum
+		}
+	}
+	if self.lu.Factorize(M) != nil {
+		return
+	}
+
+	for j := 0; j < projectionMaxIter; j++ {
+		converged := true
+		for k, invariant := range self.invariants {
+			r[k] = invariant.Function(y) - self.values[k]
+			if math.Abs(r[k]) > 4*epsilon(math.Max(math.Abs(self.values[k]), 1)) {
+				converged = false
+			}
+		}
+		if converged {
+			return
+		}
+
+		// y = y - Gᵀ M⁻¹ r.
+		self.lu.Solve(r)
+		for k := 0; k < ni; k++ {
+			for i := 0; i < nd; i++ {
+				y[i] -= G[k*nd+i] * r[k]
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package erk
+
+// The kernels below operate on whole vectors, which lets the stages of a
+// method be combined one at a time with a simple stride and few bounds
+// checks. The loops are unrolled by four; each entry is still computed with
+// the same operations in the same order as by a plain loop, so the results do
+// not depend on the unrolling.
+
+// weigh computes sum = Σ w[k] f[k] where the terms with zero weights are
+// skipped.
+func weigh(w []float64, f [][]float64, sum []float64) {
+	first := true
+	for k, w := range w {
+		if w == 0 {
+			continue
+		}
+		if first {
+			scale(w, f[k], sum)
+			first = false
+		} else {
+			axpy(w, f[k], sum)
+		}
+	}
+	if first {
+		for i := range sum {
+			sum[i] = 0
+		}
+	}
+}
+
+// combineScaled computes ynew = y + Σ (h w[k]) f[k] where the terms with zero
+// weights are skipped, which is the order of operations of MATLAB. If strict
+// is true, the products are rounded before the additions.
+func combineScaled(y []float64, h float64, w []float64, f [][]float64, ynew []float64,
+	strict bool) {
+
+	first := true
+	for k, w := range w {
+		if w == 0 {
+			continue
+		}
+		if first {
+			scale(h*w, f[k], ynew)
+			first = false
+		} else if strict {
+			axpyStrict(h*w, f[k], ynew)
+		} else {
+			axpy(h*w, f[k], ynew)
+		}
+	}
+	if first {
+		copy(ynew, y)
+		return
+	}
+	xpay(y, 1, ynew, ynew)
+}
+
+// scale computes y = a x.
+func scale(a float64, x, y []float64) {
+	x = x[:len(y)]
+	for len(y) >= 4 {
+		x4, y4 := x[:4:4], y[:4:4]
+		y4[0] = a * x4[0]
+		y4[1] = a * x4[1]
+		y4[2] = a * x4[2]
+		y4[3] = a * x4[3]
+		x, y = x[4:], y[4:]
+	}
+	for i := range y {
+		y[i] = a * x[i]
+	}
+}
+
+// axpy computes y = y + a x.
+func axpy(a float64, x, y []float64) {
+	x = x[:len(y)]
+	for len(y) >= 4 {
+		x4, y4 := x[:4:4], y[:4:4]
+		y4[0] += a * x4[0]
+		y4[1] += a * x4[1]
+		y4[2] += a * x4[2]
+		y4[3] += a * x4[3]
+		x, y = x[4:], y[4:]
+	}
+	for i := range y {
+		y[i] += a * x[i]
+	}
+}
+
+// xpay computes z = x + a y, where z may coincide with y.
+func xpay(x []float64, a float64, y, z []float64) {
+	x, y = x[:len(z)], y[:len(z)]
+	for len(z) >= 4 {
+		x4, y4, z4 := x[:4:4], y[:4:4], z[:4:4]
+		z4[0] = x4[0] + a*y4[0]
+		z4[1] = x4[1] + a*y4[1]
+		z4[2] = x4[2] + a*y4[2]
+		z4[3] = x4[3] + a*y4[3]
+		x, y, z = x[4:], y[4:], z[4:]
+	}
+	for i := range z {
+		z[i] = x[i] + a*y[i]
+	}
+}
@@ -0,0 +1,1065 @@
+// Package erk provides integrators of systems of ordinary differential
+// equations based on embedded explicit Runge–Kutta methods given by Butcher
+// tableaus. The step size is controlled using the error estimate of the
+// embedded method, and the solution at intermediate points is computed using
+// the interpolant of the method.
+//
+// https://en.wikipedia.org/wiki/Runge%E2%80%93Kutta_methods#Adaptive_Runge%E2%80%93Kutta_methods
+package erk
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/ready-steady/ode/internal/interval"
+	"github.com/ready-steady/ode/internal/pool"
+)
+
+// Integrator is an integrator.
+//
+// An integrator is safe for concurrent use by multiple goroutines provided
+// that the functions of its configuration are. Each integration takes a
+// workspace from a pool of the integrator and returns it afterwards, so
+// consecutive integrations of systems of the same dimension reuse the storage
+// of the method instead of allocating it anew, and concurrent integrations
+// never share it.
+type Integrator struct {
+	tableau    *Tableau
+	config     Config
+	workspaces *pool.Pool[Workspace]
+
+	// Should the internally traversed points be omitted from the output,
+	// except for the endpoints?
+	quiet bool
+}
+
+// New creates a new integrator based on a tableau.
+func New(tableau *Tableau, config *Config) (*Integrator, error) {
+	if err := tableau.verify(); err != nil {
+		return nil, classify(ErrInvalidTableau, err)
+	}
+	if err := config.verify(); err != nil {
+		return nil, classify(ErrInvalidConfig, err)
+	}
+	return &Integrator{
+		tableau:    tableau,
+		config:     *config,
+		workspaces: pool.New[Workspace](),
+	}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses. If xend < x0, the integration is carried out backward.
+// The points of xs should be strictly monotonic; otherwise, an error of the
+// kind ErrInvalidArgument is returned.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	ys, xs, _, stats, err := self.ComputeWithEvents(dydx, y0, xs)
+
+	return ys, xs, stats, err
+}
+
+// ComputeWithEvents augments ComputeWithStats by reporting the occurrences of
+// the events of the configuration, which are given in the chronological order.
+//
+// After each step, the event functions are evaluated at the new point, and
+// each sign change is located using the interpolant of the method to nearly
+// the machine precision. Zeros at the initial point are not reported. If a
+// terminal event occurs, the integration stops at its first crossing, which
+// is then the last point of the solution, and the points of xs beyond it are
+// dropped. The same applies to the point where a steady state is detected;
+// see Config.SteadyState. If the terminal event has a reset map, the
+// integration restarts from the modified solution at the crossing instead,
+// and the zero of the event at the restart point is not reported; in this
+// case, unless the solution is returned at fixed points, the crossing appears
+// twice in the solution, before and after the modification.
+//
+// If the integration fails, for instance, due to a step-size underflow, the
+// function returns the error along with the solution computed so far and the
+// statistics. The context of the failure is given by an Error; see also the
+// kinds of errors of the package.
+func (self *Integrator) ComputeWithEvents(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, []Crossing, *Stats, error) {
+
+	workspace := self.acquire(len(y0))
+	defer self.release(workspace)
+
+	ys, xs, crossings, stats, _, err := self.compute(dydx, &State{Y: y0}, xs, workspace)
+
+	return ys, xs, crossings, detach(stats), err
+}
+
+// ComputeFunc integrates the system of differential equations dy/dx = f(x, y)
+// like ComputeWithEvents, but instead of accumulating the solution, each point
+// is passed to a callback as soon as it is available. The solution passed to
+// the callback is valid only until the callback returns. If the callback
+// returns an error, the integration stops, and the error is returned.
+func (self *Integrator) ComputeFunc(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64, callback func(float64, []float64) error) ([]Crossing, *Stats, error) {
+
+	workspace := self.acquire(len(y0))
+	defer self.release(workspace)
+
+	crossings, stats, _, err := self.stream(context.Background(), dydx, &State{Y: y0}, xs, workspace, callback)
+
+	return crossings, detach(stats), err
+}
+
+// ComputeWithDerivatives augments ComputeWithStats by returning the derivative
+// of the solution dy/dx at each point of the solution in the same layout as
+// the solution itself, which spares evaluating the derivative function over
+// the whole trajectory afterwards. The derivative at the points that the
+// integrator steps to is the one that the method evaluates anyway, and the one
+// at the other points is the derivative of the interpolant of the method, so
+// the derivative function is evaluated again only after a reset of an event.
+// The derivative of the interpolant is less accurate than the interpolated
+// solution by about one order.
+func (self *Integrator) ComputeWithDerivatives(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, []float64, *Stats, error) {
+
+	workspace := self.acquire(len(y0))
+	defer self.release(workspace)
+
+	workspace.slopes = true
+	defer func() {
+		workspace.slopes = false
+	}()
+
+	var ys, zs, fs []float64
+	if len(xs) > 2 {
+		ys, zs = make([]float64, 0, len(xs)*len(y0)), make([]float64, 0, len(xs))
+		fs = make([]float64, 0, len(xs)*len(y0))
+	}
+
+	_, stats, _, err := self.stream(context.Background(), dydx, &State{Y: y0}, xs, workspace,
+		func(x float64, y []float64) error {
+			ys, zs = append(ys, y...), append(zs, x)
+			fs = append(fs, workspace.fnext...)
+			return nil
+		})
+
+	return ys, zs, fs, detach(stats), err
+}
+
+// compute integrates the system starting from a state at x0 = xs[0] and
+// returns the state at the last point of the solution in addition to the
+// results of ComputeWithEvents. The point of the starting state is not used.
+// The returned statistics and state belong to the workspace.
+func (self *Integrator) compute(dydx func(float64, []float64, []float64),
+	start *State, xs []float64, workspace *Workspace) ([]float64, []float64, []Crossing, *Stats, *State, error) {
+
+	var ys, zs []float64
+	if len(xs) > 2 {
+		ys, zs = make([]float64, 0, len(xs)*len(start.Y)), make([]float64, 0, len(xs))
+	}
+
+	crossings, stats, state, err := self.stream(context.Background(), dydx, start, xs, workspace, func(x float64, y []float64) error {
+		ys, zs = append(ys, y...), append(zs, x)
+		return nil
+	})
+	if err != nil {
+		return ys, zs, crossings, stats, nil, err
+	}
+
+	return ys, zs, crossings, stats, state, nil
+}
+
+// stream integrates the system starting from a state at x0 = xs[0] and passes
+// the points of the solution to emit as soon as they are available. The
+// solution passed to emit is valid only until emit returns. The context is
+// checked before each step. If the workspace is nil, a new one is allocated;
+// otherwise, it should match the dimension of the system, and the returned
+// statistics and state belong to it.
+func (self *Integrator) stream(ctx context.Context, dydx func(float64, []float64, []float64),
+	start *State, xs []float64, workspace *Workspace,
+	emit func(float64, []float64) error) ([]Crossing, *Stats, *State, error) {
+
+	started := time.Now()
+
+	tableau := self.tableau
+
+	y0 := start.Y
+	if err := interval.Validate(y0, xs); err != nil {
+		return nil, &Stats{}, nil, argument(err.Error())
+	}
+	for _, bound := range self.config.Bounds {
+		if int(bound.Index) >= len(y0) {
+			return nil, &Stats{}, nil, argument("the bounds should refer to the components of the solution")
+		}
+	}
+	if self.config.Mass != nil {
+		var err error
+		if dydx, err = invert(self.config.Mass, len(y0), dydx); err != nil {
+			return nil, &Stats{}, nil, err
+		}
+	}
+	if len(self.config.Bounds) > 0 {
+		dydx = constrain(self.config.Bounds, dydx)
+	}
+
+	ns := len(tableau.C)
+
+	power := 1 / float64(tableau.Order+1)
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	if workspace == nil {
+		workspace = self.NewWorkspace(uint(nd))
+	}
+
+	stats := &workspace.stats
+	*stats = Stats{}
+
+	if self.config.Timing {
+		dydx = timed(dydx, &stats.Derivative)
+		defer func() {
+			stats.Duration = time.Since(started)
+		}()
+	}
+
+	z, y, ynew, ynext := workspace.z, workspace.y, workspace.ynew, workspace.ynext
+
+	f := workspace.f
+	f1, fnew := f[0], f[ns]
+
+	// The derivative at the point being emitted if it is requested.
+	var fnext []float64
+	if workspace.slopes {
+		fnext = workspace.fnext
+	}
+
+	x0, xend := xs[0], xs[nx-1]
+	x := x0
+
+	// The direction of integration. The step size h is kept positive, and the
+	// signed step is dir*h.
+	dir := 1.0
+	if xend < x0 {
+		dir = -1
+	}
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	if start.F != nil {
+		copy(f1, start.F)
+	} else {
+		dydx(x, y, f1)
+		stats.Evaluations++
+	}
+
+	config := &self.config
+
+	var crossings []Crossing
+
+	ne := len(config.Events)
+	g, gnew := workspace.g, workspace.gnew
+	for k, event := range config.Events {
+		g[k] = event.Function(x, y)
+	}
+
+	abserr, relerr := config.AbsError, config.RelError
+	threshold := abserr / relerr
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = 0.1 * math.Abs(xend-x)
+	}
+
+	// Choose the initial step size.
+	h := start.H
+	if h == 0 {
+		h = config.TryStep
+	}
+	if h == 0 {
+		h = math.Abs(xs[1] - x)
+		if h > hmax {
+			h = hmax
+		}
+		h = guess(y, f1, h, threshold, relerr, power, config.pow)
+	}
+
+	// The relative errors of the previous accepted steps.
+	history := [2]float64{1, 1}
+
+	var manifold *projector
+	if len(config.Invariants) > 0 {
+		manifold = newProjector(config.Invariants, y0)
+	}
+
+	// The initial values of the monitored quantities.
+	var monitored []float64
+	if len(config.Monitors) > 0 {
+		monitored = make([]float64, len(config.Monitors))
+		for k, monitor := range config.Monitors {
+			monitored[k] = monitor(y0)
+		}
+		stats.Drifts = make([]Drift, len(config.Monitors))
+	}
+
+	// The compensations of the rounding errors of x and y, if any.
+	var cx, cxnew float64
+	var cy, cynew []float64
+	if config.Compensated {
+		cy, cynew = make([]float64, nd), make([]float64, nd)
+	}
+
+	// Skip the stops that are not ahead.
+	stops := config.Stops
+	if dir < 0 {
+		stops = make([]float64, len(config.Stops))
+		for i, stop := range config.Stops {
+			stops[len(stops)-1-i] = stop
+		}
+	}
+	for len(stops) > 0 && dir*(stops[0]-x) <= 0 {
+		stops = stops[1:]
+	}
+
+	// Done with the first point.
+	if fnext != nil {
+		copy(fnext, f1)
+	}
+	if err := emit(x, y); err != nil {
+		return crossings, stats, nil, err
+	}
+	nc += 1
+
+	for done := false; ; {
+		if err := ctx.Err(); err != nil {
+			return crossings, stats, nil, err
+		}
+
+		if kind := config.limit(stats, 1, 0); kind != nil {
+			return crossings, stats, nil, fail(kind, x, h, stats)
+		}
+		if kind := config.expire(started); kind != nil {
+			return crossings, stats, nil, fail(kind, x, h, stats)
+		}
+
+		var xnew, ε float64
+		var err error
+
+		stats.Steps++
+
+		hmin := config.minimum(x)
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		// The step size to resume with if the integration ends with this step.
+		hnext := h
+
+		// Close to the end?
+		if 1.1*h >= dir*(xend-x) {
+			h = dir * (xend - x)
+			done = true
+		}
+
+		// Close to a stop?
+		stopping := false
+		if len(stops) > 0 && dir*(xend-stops[0]) > 0 && 1.1*h >= dir*(stops[0]-x) {
+			h = dir * (stops[0] - x)
+			done = false
+			stopping = true
+		}
+
+		rejected := false
+
+		for {
+			if kind := config.limit(stats, 0, uint(ns)); kind != nil {
+				return crossings, stats, nil, fail(kind, x, h, stats)
+			}
+
+			xnew, cxnew = x+dir*h, 0
+			if stopping {
+				xnew = stops[0]
+			} else if (config.Compensated || config.MATLAB) && done {
+				xnew = xend
+			} else if config.Compensated {
+				xnew, cxnew = add(x, dir*h, cx)
+			}
+
+			ε, err = self.attempt(dydx, x, xnew, dir*h, y, cy, f, z, ynew, cynew, threshold)
+
+			stats.Evaluations += uint(ns)
+
+			if err != nil {
+				return crossings, stats, nil, fail(err, x, h, stats)
+			}
+
+			if len(config.Bounds) > 0 {
+				ε = math.Max(ε, violation(config.Bounds, y, ynew, threshold))
+			}
+
+			if ε <= relerr {
+				break
+			}
+
+			stats.Rejections++
+
+			if config.OnReject != nil {
+				config.OnReject(x, h, ε)
+			}
+
+			if h <= hmin {
+				return crossings, stats, nil, fail(ErrStepUnderflow, x, h, stats)
+			}
+
+			// Shrink the step size as the current one has been rejected.
+			if rejected {
+				h = 0.5 * h
+			} else if scale := 0.8 * config.pow(relerr/ε, power); scale > 0.1 {
+				h = scale * h
+			} else {
+				h = 0.1 * h
+			}
+
+			if h < hmin {
+				h = hmin
+			}
+
+			done = false
+			stopping = false
+			rejected = true
+			hnext = h
+		}
+
+		// The signed step, which is exactly the distance to the new point in
+		// the mode of MATLAB.
+		step := dir * h
+		if config.MATLAB {
+			step = xnew - x
+		}
+
+		// Project the solution onto the bounds and the invariants, which
+		// invalidates the derivative at the new point.
+		projected := len(config.Bounds) > 0 && project(config.Bounds, ynew, cynew)
+		if manifold != nil && (config.ProjectionSteps == 0 || stats.Steps%config.ProjectionSteps == 0) {
+			manifold.project(ynew)
+			for i := range cynew {
+				cynew[i] = 0
+			}
+			projected = true
+		}
+		if projected {
+			dydx(xnew, ynew, fnew)
+			stats.Evaluations++
+		}
+
+		for k, monitor := range config.Monitors {
+			drift := &stats.Drifts[k]
+			drift.Final = monitor(ynew) - monitored[k]
+			drift.Max = math.Max(drift.Max, math.Abs(drift.Final))
+		}
+
+		stats.accept(math.Abs(xnew - x))
+
+		if config.OnAccept != nil {
+			config.OnAccept(xnew, h, ynew)
+		}
+		if config.Progress != nil && (config.ProgressSteps == 0 || stats.Steps%config.ProgressSteps == 0) {
+			config.Progress((xnew-x0)/(xend-x0), h, stats)
+		}
+
+		// The point where the integration stops prematurely, if any, and the
+		// reset map to restart with.
+		var stop *Crossing
+		var reset func(float64, []float64)
+		if ne > 0 {
+			crossings, stop = self.detect(crossings, x, y, g, xnew, ynew, gnew, f, step)
+			if stop != nil {
+				reset = config.Events[stop.Index].Reset
+			}
+		}
+		if stop == nil && config.SteadyState > 0 && steady(ynew, fnew, threshold, config.SteadyState) {
+			stats.Steady = true
+			stop = &Crossing{X: xnew, Y: ynew}
+		}
+
+		if config.Callback != nil {
+			config.Callback(&Interpolant{X: x, H: step, Y: y, Ynew: ynew, F: f, tableau: tableau,
+				strict: config.Deterministic})
+		}
+
+		if fixed {
+			for nc < nx && err == nil {
+				if dir*(xnew-xs[nc]) < 0 || stop != nil && dir*(xs[nc]-stop.X) > 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					if fnext != nil {
+						copy(fnext, fnew)
+					}
+					err = emit(xnew, ynew)
+				} else {
+					interpolate(tableau.D, x, y, ynew, f, step, xs[nc], ynext, config.Deterministic)
+					project(config.Bounds, ynext, nil)
+					stats.Interpolations++
+					if fnext != nil {
+						differentiate(tableau.D, x, y, ynew, f, step, xs[nc], fnext)
+					}
+					err = emit(xs[nc], ynext)
+				}
+
+				nc++
+			}
+			if err == nil && stop != nil && reset == nil && xs[nc-1] != stop.X {
+				self.slope(x, y, xnew, ynew, f, step, stop.X, fnext)
+				err = emit(stop.X, stop.Y)
+			}
+		} else if stop != nil {
+			if !self.quiet {
+				err = self.refine(emit, x, y, xnew, ynew, f, step, stop.X, ynext, fnext, stats)
+			}
+			if err == nil {
+				self.slope(x, y, xnew, ynew, f, step, stop.X, fnext)
+				err = emit(stop.X, stop.Y)
+			}
+		} else if done || !self.quiet && (config.Decimate < 2 || stats.Steps%config.Decimate == 0) {
+			if !self.quiet {
+				err = self.refine(emit, x, y, xnew, ynew, f, step, xnew, ynext, fnext, stats)
+			}
+			if err == nil {
+				if fnext != nil {
+					copy(fnext, fnew)
+				}
+				err = emit(xnew, ynew)
+			}
+			nc++
+		}
+		if err != nil {
+			return crossings, stats, nil, err
+		}
+
+		if reset != nil {
+			x = stop.X
+			copy(y, stop.Y)
+			reset(x, y)
+			stats.Resets++
+
+			// The derivative after the reset is needed ahead of time if it is
+			// requested along with the solution.
+			evaluated := !fixed && fnext != nil
+			if evaluated {
+				dydx(x, y, f1)
+				stats.Evaluations++
+				copy(fnext, f1)
+			}
+
+			// The solution after the reset is reported at the same point as
+			// the one before it unless the solution is returned at fixed
+			// points.
+			if !fixed {
+				if err := emit(x, y); err != nil {
+					return crossings, stats, nil, err
+				}
+			}
+
+			if dir*(xend-x) <= 0 {
+				return crossings, stats, &State{X: x, Y: append([]float64(nil), y...), H: hnext}, nil
+			}
+
+			if !evaluated {
+				dydx(x, y, f1)
+				stats.Evaluations++
+			}
+
+			// The zero of the event that has fired is not reported again.
+			for k, event := range config.Events {
+				g[k] = event.Function(x, y)
+			}
+			g[stop.Index] = 0
+
+			cx = 0
+			for i := range cy {
+				cy[i] = 0
+			}
+
+			// Restart the selection of the step size.
+			for len(stops) > 0 && dir*(stops[0]-x) <= 0 {
+				stops = stops[1:]
+			}
+			h = guess(y, f1, hmax, threshold, relerr, power, config.pow)
+			history = [2]float64{1, 1}
+			done = false
+			continue
+		}
+
+		if stop != nil {
+			// The derivative at the stop is not known unless it is the end of
+			// the step.
+			state := &State{X: stop.X, Y: append([]float64(nil), stop.Y...), H: h}
+			if stop.X == xnew {
+				state.F = fnew
+			}
+			return crossings, stats, state, nil
+		}
+
+		if done {
+			workspace.state = State{X: xnew, Y: ynew, H: hnext, F: fnew}
+			return crossings, stats, &workspace.state, nil
+		}
+
+		x = xnew
+		copy(f1, fnew)
+		copy(y, ynew)
+		copy(g, gnew)
+
+		cx, cy, cynew = cxnew, cynew, cy
+
+		// Restart the selection of the step size after a stop.
+		if stopping {
+			stops = stops[1:]
+			h = guess(y, f1, hmax, threshold, relerr, power, config.pow)
+			history = [2]float64{1, 1}
+			continue
+		}
+
+		// Compute a new step size.
+		if scale := config.Controller.scale(ε/relerr, &history, power, config.pow); rejected {
+			continue
+		} else if scale > 0.2 {
+			h = h / scale
+		} else {
+			h = 5 * h
+		}
+	}
+}
+
+// refine passes to emit the solution at the points that divide the step from
+// x to xnew into the number of equal parts given by the configuration, except
+// for xnew itself, which lie strictly before xend. The solution at the points
+// is computed using the interpolant of the method and stored in ynext. If
+// fnext is not nil, the derivative of the interpolant is stored in it.
+func (self *Integrator) refine(emit func(float64, []float64) error,
+	x float64, y []float64, xnew float64, ynew []float64, f [][]float64, h, xend float64,
+	ynext, fnext []float64, stats *Stats) error {
+
+	config := &self.config
+
+	refine := config.refinement()
+	for j := uint(1); j < refine; j++ {
+		xnext := x + float64((xnew-x)*(float64(j)/float64(refine)))
+		if (xend-xnext)*h <= 0 {
+			break
+		}
+		interpolate(self.tableau.D, x, y, ynew, f, h, xnext, ynext, config.Deterministic)
+		project(config.Bounds, ynext, nil)
+		stats.Interpolations++
+		if fnext != nil {
+			differentiate(self.tableau.D, x, y, ynew, f, h, xnext, fnext)
+		}
+		if err := emit(xnext, ynext); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// slope stores in fnext, unless it is nil, the derivative at a point where the
+// integration stops within the step from x to xnew, which is the one at the
+// end of the step if the point is xnew.
+func (self *Integrator) slope(x float64, y []float64, xnew float64, ynew []float64,
+	f [][]float64, h, xstop float64, fnext []float64) {
+
+	if fnext == nil {
+		return
+	}
+	if xstop == xnew {
+		copy(fnext, f[len(f)-1])
+	} else {
+		differentiate(self.tableau.D, x, y, ynew, f, h, xstop, fnext)
+	}
+}
+
+// attempt computes a step from x to xnew = x + h. If the compensation of the
+// rounding errors of y is given by c, the solution is accumulated using
+// compensated summation, and the compensation of ynew is stored in cnew. The
+// derivatives of the stages are stored in f, and the derivative at the new
+// point is stored in f[ns] where ns is the number of stages. The function
+// returns the error estimate relative to the tolerance threshold.
+func (self *Integrator) attempt(dydx func(float64, []float64, []float64),
+	x, xnew, h float64, y, c []float64, f [][]float64, z, ynew, cnew []float64,
+	threshold float64) (float64, error) {
+
+	tableau := self.tableau
+	config := &self.config
+
+	A, B, C, E := tableau.A, tableau.B, tableau.C, tableau.E
+	nd, ns := len(y), len(C)
+
+	check := !config.SkipFiniteCheck
+
+	// The number of goroutines among which the updates are split.
+	n := config.parallelism(nd)
+
+	if check {
+		if err := finite(f[0], x, 0, true); err != nil {
+			return 0, err
+		}
+	}
+	strict := config.Deterministic
+	for k := 1; k < ns; k++ {
+		if config.MATLAB {
+			combineScaled(y, h, A[k], f, z, strict)
+		} else if strict {
+			combineStrict(y, h, A[k], f, z)
+		} else {
+			combineParallel(n, y, h, A[k], f, z)
+		}
+		// The product is rounded so that the point of the stage does not
+		// depend on the fusion of the operations.
+		xk := x + float64(C[k]*h)
+		dydx(xk, z, f[k])
+		if check {
+			if err := finite(f[k], xk, k, true); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if c == nil && config.MATLAB {
+		combineScaled(y, h, B, f, ynew, strict)
+	} else if c == nil && strict {
+		combineStrict(y, h, B, f, ynew)
+	} else if c == nil {
+		combineParallel(n, y, h, B, f, ynew)
+	} else if strict {
+		accumulateStrict(y, h, B, f, c, ynew, cnew)
+	} else {
+		accumulateParallel(n, y, h, B, f, c, ynew, cnew)
+	}
+	if check {
+		if err := finite(ynew, xnew, ns, false); err != nil {
+			return 0, err
+		}
+	}
+
+	dydx(xnew, ynew, f[ns])
+	if check {
+		if err := finite(f[ns], xnew, ns, true); err != nil {
+			return 0, err
+		}
+	}
+
+	// The error estimate without the step size.
+	if strict {
+		weighStrict(E, f, z)
+	} else {
+		weighParallel(n, E, f, z)
+	}
+
+	if config.ErrorNorm != nil {
+		for i := range z {
+			z[i] *= h
+		}
+		return config.ErrorNorm(z, y, ynew), nil
+	}
+
+	// In the mode of MATLAB, the error is measured in the maximum norm and
+	// scaled by the step size at the end.
+	if config.MATLAB {
+		return math.Abs(h) * measure(y, ynew, z, 1, threshold, false), nil
+	}
+
+	// Compute the relative error.
+	rms := config.Norm == RMSNorm
+	ε := measureParallel(n, y, ynew, z, h, threshold, rms)
+	if rms {
+		ε = math.Sqrt(ε / float64(nd))
+	}
+
+	return ε, nil
+}
+
+// guess chooses a step size not exceeding h based on the derivative f at the
+// current point y. The power of the tolerance is computed by pow.
+func guess(y, f []float64, h, threshold, relerr, power float64,
+	pow func(float64, float64) float64) float64 {
+
+	scale := 0.0
+	for i := range y {
+		s := y[i]
+		if s < 0 {
+			s = -s
+		}
+
+		if s < threshold {
+			s = threshold
+		}
+
+		s = f[i] / s
+		if s < 0 {
+			s = -s
+		}
+
+		if s > scale {
+			scale = s
+		}
+	}
+	scale = scale / (0.8 * pow(relerr, power))
+
+	if h*scale > 1 {
+		h = 1 / scale
+	}
+
+	return h
+}
+
+// detect evaluates the event functions at the end of a step, locates the
+// crossings within the step, and appends them to crossings in the
+// chronological order. If a terminal event occurs, the crossings after its
+// first one are discarded, and the first one is returned as the second result.
+func (self *Integrator) detect(crossings []Crossing, x float64, y, g []float64,
+	xnew float64, ynew, gnew []float64, f [][]float64, h float64) ([]Crossing, *Crossing) {
+
+	interpolant := func(xnext float64, ynext []float64) {
+		interpolate(self.tableau.D, x, y, ynew, f, h, xnext, ynext, self.config.Deterministic)
+	}
+
+	start := len(crossings)
+	for k, event := range self.config.Events {
+		gnew[k] = event.Function(xnew, ynew)
+		if g[k] == 0 || (gnew[k] != 0 && (g[k] < 0) == (gnew[k] < 0)) {
+			continue
+		}
+		if rising := (g[k] < 0) == (xnew > x); event.Direction > 0 && !rising || event.Direction < 0 && rising {
+			continue
+		}
+
+		crossing := Crossing{Index: uint(k), Y: make([]float64, len(y))}
+		if gnew[k] == 0 {
+			crossing.X = xnew
+			copy(crossing.Y, ynew)
+		} else {
+			crossing.X = locate(event.Function, x, g[k], xnew, gnew[k], interpolant, crossing.Y)
+		}
+
+		// Keep the crossings of the step sorted.
+		i := len(crossings)
+		crossings = append(crossings, crossing)
+		for ; i > start && (crossings[i-1].X-crossing.X)*(xnew-x) > 0; i-- {
+			crossings[i] = crossings[i-1]
+		}
+		crossings[i] = crossing
+	}
+
+	for i := start; i < len(crossings); i++ {
+		if self.config.Events[crossings[i].Index].Terminal {
+			crossings = crossings[:i+1]
+			return crossings, &crossings[i]
+		}
+	}
+
+	return crossings, nil
+}
+
+// steady checks if the derivative is negligible relative to the solution.
+func steady(y, f []float64, threshold, tolerance float64) bool {
+	for i := range y {
+		if math.Abs(f[i]) > tolerance*math.Max(math.Abs(y[i]), threshold) {
+			return false
+		}
+	}
+	return true
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
+
+// measure computes the maximum of the components of the error estimate e
+// multiplied by |h| and divided by max(|y|, |ynew|, threshold) or, if rms is
+// true, the sum of their squares. The squares are rounded before they are
+// summed, so that the sum does not depend on the fusion of the operations.
+func measure(y, ynew, e []float64, h, threshold float64, rms bool) float64 {
+	ε := 0.0
+	for i := range e {
+		scale := y[i]
+		if scale < 0 {
+			scale = -scale
+		}
+		if ynew[i] > 0 {
+			if ynew[i] > scale {
+				scale = ynew[i]
+			}
+		} else {
+			if -ynew[i] > scale {
+				scale = -ynew[i]
+			}
+		}
+		if scale < threshold {
+			scale = threshold
+		}
+
+		δ := e[i]
+		if δ < 0 {
+			δ = -δ
+		}
+
+		δ = math.Abs(h) * δ / scale
+		if rms {
+			ε += float64(δ * δ)
+		} else if δ > ε {
+			ε = δ
+		}
+	}
+	return ε
+}
+
+// combine computes ynew = y + h Σ w[k] f[k].
+func combine(y []float64, h float64, w []float64, f [][]float64, ynew []float64) {
+	weigh(w, f, ynew)
+	xpay(y, h, ynew, ynew)
+}
+
+// accumulate computes ynew = y + h Σ w[k] f[k] using the compensated summation
+// of Kahan given the compensation c of y and stores the compensation of ynew
+// in cnew.
+func accumulate(y []float64, h float64, w []float64, f [][]float64,
+	c, ynew, cnew []float64) {
+
+	weigh(w, f, ynew)
+	for i := range ynew {
+		ynew[i], cnew[i] = add(y[i], h*ynew[i], c[i])
+	}
+}
+
+// add computes x + δ using the compensated summation of Kahan given the
+// compensation c of x and returns the sum and its compensation.
+func add(x, δ, c float64) (float64, float64) {
+	δ -= c
+	xnew := x + δ
+	return xnew, (xnew - x) - δ
+}
+
+// interpolate computes the solution at xnext within the step from x to x + h
+// using the interpolant of the method given by D or, if D is nil, using cubic
+// Hermite interpolation. If strict is true, the products are rounded before
+// the additions, so that the result does not depend on the fusion of the
+// operations.
+func interpolate(D [][]float64, x float64, y, ynew []float64, f [][]float64,
+	h, xnext float64, ynext []float64, strict bool) {
+
+	if strict {
+		interpolateStrict(D, x, y, ynew, f, h, xnext, ynext)
+		return
+	}
+
+	nd, ns := len(y), len(f)-1
+
+	s := (xnext - x) / h
+
+	if D == nil {
+		h00 := (1 + 2*s) * (1 - s) * (1 - s)
+		h10 := s * (1 - s) * (1 - s)
+		h01 := s * s * (3 - 2*s)
+		h11 := s * s * (s - 1)
+
+		for i := 0; i < nd; i++ {
+			ynext[i] = h00*y[i] + h*h10*f[0][i] + h01*ynew[i] + h*h11*f[ns][i]
+		}
+
+		return
+	}
+
+	nk := 0
+	for k := range D {
+		if len(D[k]) > nk {
+			nk = len(D[k])
+		}
+	}
+
+	for i := 0; i < nd; i++ {
+		ynext[i] = y[i]
+		power := 1.0
+		for l := 0; l < nk; l++ {
+			power *= s
+			sum := 0.0
+			for k := range D {
+				if l < len(D[k]) && D[k][l] != 0 {
+					sum += D[k][l] * f[k][i]
+				}
+			}
+			ynext[i] += h * power * sum
+		}
+	}
+}
+
+// differentiate computes the derivative at xnext of the interpolant used by
+// interpolate. The products are rounded before the additions as in the strict
+// mode of interpolate, which costs little as the derivative is computed only
+// on request.
+func differentiate(D [][]float64, x float64, y, ynew []float64, f [][]float64,
+	h, xnext float64, fnext []float64) {
+
+	nd, ns := len(y), len(f)-1
+
+	s := (xnext - x) / h
+
+	if D == nil {
+		// The derivatives of the Hermite basis functions with respect to s,
+		// of which the ones of h00 and h01 differ only in sign.
+		d00 := float64(6*s) * (s - 1)
+		d10 := (1 - s) * (1 - float64(3*s))
+		d11 := s * (float64(3*s) - 2)
+
+		for i := 0; i < nd; i++ {
+			fnext[i] = float64(d00*(y[i]-ynew[i]))/h + float64(d10*f[0][i]) + float64(d11*f[ns][i])
+		}
+
+		return
+	}
+
+	nk := 0
+	for k := range D {
+		if len(D[k]) > nk {
+			nk = len(D[k])
+		}
+	}
+
+	for i := 0; i < nd; i++ {
+		fnext[i] = 0
+		power := 1.0
+		for l := 0; l < nk; l++ {
+			sum := 0.0
+			for k := range D {
+				if l < len(D[k]) && D[k][l] != 0 {
+					sum += float64(D[k][l] * f[k][i])
+				}
+			}
+			fnext[i] += float64(float64(l+1) * power * sum)
+			power *= s
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package erk
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+// The Heun–Euler method of orders two and one.
+var heunEuler = &Tableau{
+	A: [][]float64{
+		{},
+		{1},
+	},
+	B:     []float64{1.0 / 2, 1.0 / 2},
+	C:     []float64{0, 1},
+	E:     []float64{-1.0 / 2, 1.0 / 2, 0},
+	Order: 1,
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(heunEuler, DefaultConfig())
+	assert.Equal(err, nil, t)
+
+	_, err = New(&Tableau{A: [][]float64{{}}, B: []float64{1}, C: []float64{0}, E: []float64{1}, Order: 1},
+		DefaultConfig())
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeDecay(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{0, 0.25, 0.5, 0.75, 1, 1.25, 1.5, 1.75, 2}
+
+	integrator, _ := New(heunEuler, &Config{AbsError: 1e-8, RelError: 1e-6})
+
+	ys, _, stats, err := integrator.ComputeWithStats(dydx, []float64{0.5}, xs)
+	assert.Equal(err, nil, t)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 1e-5, t)
+	}
+	assert.Equal(stats.Evaluations, 2*stats.Steps+stats.Rejections+1, t)
+}
+
+func TestCombine(t *testing.T) {
+	w := []float64{0.1, 0, -0.3, 0.7}
+	for nd := 0; nd < 10; nd++ {
+		y, f := random(nd, 1), make([][]float64, len(w))
+		for k := range f {
+			f[k] = random(nd, uint64(k+2))
+		}
+
+		ynew1, ynew2 := make([]float64, nd), make([]float64, nd)
+		combine(y, 0.01, w, f, ynew1)
+		combineScalar(y, 0.01, w, f, ynew2)
+		assert.Equal(ynew1, ynew2, t)
+	}
+}
+
+func BenchmarkCombine(b *testing.B) {
+	y, w, f, ynew := prepareCombine()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		combine(y, 0.01, w, f, ynew)
+	}
+}
+
+func BenchmarkCombineScalar(b *testing.B) {
+	y, w, f, ynew := prepareCombine()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		combineScalar(y, 0.01, w, f, ynew)
+	}
+}
+
+// combineScalar is the plain counterpart of combine.
+func combineScalar(y []float64, h float64, w []float64, f [][]float64, ynew []float64) {
+	for i := range ynew {
+		sum := 0.0
+		for k := range w {
+			if w[k] != 0 {
+				sum += w[k] * f[k][i]
+			}
+		}
+		ynew[i] = y[i] + h*sum
+	}
+}
+
+func prepareCombine() ([]float64, []float64, [][]float64, []float64) {
+	const (
+		nd = 50000
+	)
+
+	// The weights of the solution of the Dormand–Prince method.
+	w := []float64{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84, 0}
+
+	f := make([][]float64, len(w))
+	for k := range f {
+		f[k] = random(nd, uint64(k+2))
+	}
+
+	return random(nd, 1), w, f, make([]float64, nd)
+}
+
+// random generates a pseudorandom vector in [-1, 1).
+func random(n int, seed uint64) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		seed = seed*6364136223846793005 + 1442695040888963407
+		x[i] = float64(seed>>11)/(1<<52) - 1
+	}
+	return x
+}
+
+func TestParallelism(t *testing.T) {
+	const (
+		nd = 3*parallelThreshold + 2
+	)
+
+	dydx := func(_ float64, y, f []float64) {
+		for i := 0; i < nd; i += 2 {
+			f[i], f[i+1] = y[i+1], -y[i]*(1+float64(i)/nd)
+		}
+	}
+
+	y0 := make([]float64, nd)
+	for i := 0; i < nd; i += 2 {
+		y0[i] = 1
+	}
+
+	for _, norm := range []Norm{MaxNorm, RMSNorm} {
+		config := DefaultConfig()
+		config.Norm = norm
+		config.Compensated = norm == RMSNorm
+
+		integrator, _ := New(heunEuler, config)
+		ys1, xs1, stats1, err := integrator.ComputeWithStats(dydx, y0, []float64{0, 1})
+		assert.Equal(err, nil, t)
+
+		config.Parallelism = 5
+
+		integrator, _ = New(heunEuler, config)
+		ys2, xs2, stats2, err := integrator.ComputeWithStats(dydx, y0, []float64{0, 1})
+		assert.Equal(err, nil, t)
+
+		if norm == MaxNorm {
+			assert.Equal(ys2, ys1, t)
+			assert.Equal(xs2, xs1, t)
+			assert.Equal(stats2, stats1, t)
+		} else {
+			assert.Close(ys2[len(ys2)-nd:], ys1[len(ys1)-nd:], 1e-12, t)
+		}
+	}
+}
+
+func TestPow(t *testing.T) {
+	for _, x := range []float64{1e-300, 1e-10, 0.001, 0.3, 0.7071, 1.5, 2, 10, 1e4, 1e200} {
+		for _, y := range []float64{-1, -0.5, -0.2, -0.14, 0.07, 0.2, 1.0 / 3, 1} {
+			assert.Close(pow(x, y)/math.Pow(x, y), 1.0, 1e-12, t)
+		}
+	}
+
+	assert.Equal(pow(0, 0.2), 0.0, t)
+	assert.Equal(pow(0, -0.2), math.Inf(1), t)
+	assert.Equal(pow(math.Inf(1), -0.2), 0.0, t)
+	assert.Equal(pow(5, 0), 1.0, t)
+	assert.Equal(math.IsNaN(pow(math.NaN(), 0.2)), true, t)
+	assert.Equal(exp(1000), math.Inf(1), t)
+	assert.Equal(exp(-1000), 0.0, t)
+}
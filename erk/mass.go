@@ -0,0 +1,21 @@
+package erk
+
+import (
+	"github.com/ready-steady/ode/internal/dense"
+)
+
+// invert wraps a derivative function f of a system M y′ = f(x, y) in order to
+// compute y′ = M⁻¹ f(x, y) using an LU decomposition of M computed once.
+func invert(M []float64, nd int, dydx func(float64, []float64, []float64)) (func(float64, []float64, []float64), error) {
+	if len(M) != nd*nd {
+		return nil, argument("the mass matrix should match the dimension of the system")
+	}
+	lu := dense.NewLU(uint(nd))
+	if lu.Factorize(M) != nil {
+		return nil, argument("the mass matrix should be nonsingular; see the radau package for singular ones")
+	}
+	return func(x float64, y, f []float64) {
+		dydx(x, y, f)
+		lu.Solve(f)
+	}, nil
+}
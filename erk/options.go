@@ -0,0 +1,166 @@
+package erk
+
+import (
+	"errors"
+	"time"
+)
+
+// Option is a setting of an integrator; see NewWithOptions.
+type Option func(*Config) error
+
+// NewWithOptions creates a new integrator based on a tableau and the default
+// configuration modified by a number of options, which are applied in order.
+// Unlike the fields of Config, whose zero values often let the integrator
+// choose the corresponding settings itself, each option sets a definite value,
+// which is validated right away. If an option or the resulting configuration
+// is invalid, an error of the kind ErrInvalidConfig is returned.
+func NewWithOptions(tableau *Tableau, options ...Option) (*Integrator, error) {
+	config := DefaultConfig()
+	for _, option := range options {
+		if err := option(config); err != nil {
+			return nil, classify(ErrInvalidConfig, err)
+		}
+	}
+	return New(tableau, config)
+}
+
+// WithTryStep sets the initial step of integration, which should be positive.
+func WithTryStep(h float64) Option {
+	return func(c *Config) error {
+		if !(h > 0) {
+			return errors.New("the initial step should be positive")
+		}
+		c.TryStep = h
+		return nil
+	}
+}
+
+// WithMaxStep sets the maximal step of integration, which should be positive.
+func WithMaxStep(h float64) Option {
+	return func(c *Config) error {
+		if !(h > 0) {
+			return errors.New("the maximal step should be positive")
+		}
+		c.MaxStep = h
+		return nil
+	}
+}
+
+// WithMinStep sets the minimal step of integration, which should be positive.
+func WithMinStep(h float64) Option {
+	return func(c *Config) error {
+		if !(h > 0) {
+			return errors.New("the minimal step should be positive")
+		}
+		c.MinStep = h
+		return nil
+	}
+}
+
+// WithAbsError sets the absolute error tolerance, which should be positive.
+func WithAbsError(ε float64) Option {
+	return func(c *Config) error {
+		if !(ε > 0) {
+			return errors.New("the absolute error tolerance should be positive")
+		}
+		c.AbsError = ε
+		return nil
+	}
+}
+
+// WithRelError sets the relative error tolerance, which should be positive.
+func WithRelError(ε float64) Option {
+	return func(c *Config) error {
+		if !(ε > 0) {
+			return errors.New("the relative error tolerance should be positive")
+		}
+		c.RelError = ε
+		return nil
+	}
+}
+
+// WithMaxSteps sets the maximal number of steps, which should be positive.
+func WithMaxSteps(n uint) Option {
+	return func(c *Config) error {
+		if n == 0 {
+			return errors.New("the maximal number of steps should be positive")
+		}
+		c.MaxSteps = n
+		return nil
+	}
+}
+
+// WithMaxEvaluations sets the maximal number of evaluations of the derivative,
+// which should be positive.
+func WithMaxEvaluations(n uint) Option {
+	return func(c *Config) error {
+		if n == 0 {
+			return errors.New("the maximal number of evaluations should be positive")
+		}
+		c.MaxEvaluations = n
+		return nil
+	}
+}
+
+// WithMaxDuration sets the maximal duration of an integration, which should
+// be positive.
+func WithMaxDuration(d time.Duration) Option {
+	return func(c *Config) error {
+		if d <= 0 {
+			return errors.New("the maximal duration should be positive")
+		}
+		c.MaxDuration = d
+		return nil
+	}
+}
+
+// WithController sets the controller of the step size.
+func WithController(controller Controller) Option {
+	return func(c *Config) error {
+		if controller > PID {
+			return errors.New("the controller is unknown")
+		}
+		c.Controller = controller
+		return nil
+	}
+}
+
+// WithNorm sets the norm of the error estimate.
+func WithNorm(norm Norm) Option {
+	return func(c *Config) error {
+		if norm > RMSNorm {
+			return errors.New("the norm is unknown")
+		}
+		c.Norm = norm
+		return nil
+	}
+}
+
+// WithCompensation enables the compensated summation; see Config.Compensated.
+func WithCompensation() Option {
+	return func(c *Config) error {
+		c.Compensated = true
+		return nil
+	}
+}
+
+// WithDeterminism enables the deterministic mode; see Config.Deterministic.
+func WithDeterminism() Option {
+	return func(c *Config) error {
+		c.Deterministic = true
+		return nil
+	}
+}
+
+// WithEvents appends events to those of the configuration; see Config.Events.
+func WithEvents(events ...Event) Option {
+	return func(c *Config) error {
+		for _, event := range events {
+			if event.Function == nil {
+				return errors.New("the event functions should not be nil")
+			}
+		}
+		c.Events = append(c.Events, events...)
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package erk
+
+import (
+	"sync"
+)
+
+// parallelThreshold is the smallest dimension of a system whose updates are
+// split among goroutines; see Config.Parallelism.
+const parallelThreshold = 1 << 14
+
+// combineParallel computes combine splitting the work into n parts.
+func combineParallel(n int, y []float64, h float64, w []float64, f [][]float64, ynew []float64) {
+	if n < 2 {
+		combine(y, h, w, f, ynew)
+		return
+	}
+	split(n, len(y), func(_, i, j int) {
+		combine(y[i:j], h, w, slice(f, i, j), ynew[i:j])
+	})
+}
+
+// accumulateParallel computes accumulate splitting the work into n parts.
+func accumulateParallel(n int, y []float64, h float64, w []float64, f [][]float64,
+	c, ynew, cnew []float64) {
+
+	if n < 2 {
+		accumulate(y, h, w, f, c, ynew, cnew)
+		return
+	}
+	split(n, len(y), func(_, i, j int) {
+		accumulate(y[i:j], h, w, slice(f, i, j), c[i:j], ynew[i:j], cnew[i:j])
+	})
+}
+
+// weighParallel computes weigh splitting the work into n parts.
+func weighParallel(n int, w []float64, f [][]float64, sum []float64) {
+	if n < 2 {
+		weigh(w, f, sum)
+		return
+	}
+	split(n, len(sum), func(_, i, j int) {
+		weigh(w, slice(f, i, j), sum[i:j])
+	})
+}
+
+// measureParallel computes measure splitting the work into n parts. The
+// maxima or the sums of the parts are then combined.
+func measureParallel(n int, y, ynew, e []float64, h, threshold float64, rms bool) float64 {
+	if n < 2 {
+		return measure(y, ynew, e, h, threshold, rms)
+	}
+	parts := make([]float64, n)
+	split(n, len(e), func(k, i, j int) {
+		parts[k] = measure(y[i:j], ynew[i:j], e[i:j], h, threshold, rms)
+	})
+	ε := 0.0
+	for _, part := range parts {
+		if rms {
+			ε += part
+		} else if part > ε {
+			ε = part
+		}
+	}
+	return ε
+}
+
+// split divides the range from zero to nd into at most n contiguous parts,
+// whose boundaries are multiples of four, and calls body with the index and
+// the range of each part in a separate goroutine. The function returns when
+// all the calls have returned.
+func split(n, nd int, body func(k, i, j int)) {
+	size := ((nd+n-1)/n + 3) &^ 3
+
+	var group sync.WaitGroup
+	for k, i := 0, 0; i < nd; k, i = k+1, i+size {
+		j := min(i+size, nd)
+		group.Add(1)
+		go func(k, i, j int) {
+			defer group.Done()
+			body(k, i, j)
+		}(k, i, j)
+	}
+	group.Wait()
+}
+
+// slice restricts the vectors of f to the range from i to j.
+func slice(f [][]float64, i, j int) [][]float64 {
+	g := make([][]float64, len(f))
+	for k := range f {
+		g[k] = f[k][i:j]
+	}
+	return g
+}
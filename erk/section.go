@@ -0,0 +1,49 @@
+package erk
+
+// ComputeSection computes the Poincaré map of the system of differential
+// equations dy/dx = f(x, y) with respect to a section, that is, the points
+// where the trajectory crosses the section given by the zeros of an event
+// function in the direction of the event. The Terminal field of the section is
+// ignored, and the events of the configuration, if any, remain in effect; in
+// particular, a terminal event stops the integration.
+//
+// The crossings are returned in the chronological order as the first two
+// results, which are analogous to the ones of Compute. The trajectory itself
+// is not stored, which keeps the memory usage low for long integrations. The
+// interval of integration is [x0, xend] where x0 and xend are the first and
+// last entries of xs, respectively.
+func (self *Integrator) ComputeSection(dydx func(float64, []float64, []float64),
+	section Event, y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	if section.Function == nil {
+		return nil, nil, nil, argument("the section function should not be nil")
+	}
+	if len(xs) < 2 {
+		return nil, nil, nil, argument("the interval should have two endpoints")
+	}
+
+	section.Terminal = false
+
+	config := self.config
+	config.Events = append(append([]Event(nil), config.Events...), section)
+
+	integrator := &Integrator{tableau: self.tableau, config: config, quiet: true}
+
+	_, _, crossings, stats, err := integrator.ComputeWithEvents(dydx, y0,
+		[]float64{xs[0], xs[len(xs)-1]})
+	if err != nil {
+		return nil, nil, stats, err
+	}
+
+	index := uint(len(config.Events) - 1)
+
+	ys, xs := []float64{}, []float64{}
+	for _, crossing := range crossings {
+		if crossing.Index == index {
+			ys = append(ys, crossing.Y...)
+			xs = append(xs, crossing.X)
+		}
+	}
+
+	return ys, xs, stats, nil
+}
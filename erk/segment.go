@@ -0,0 +1,152 @@
+package erk
+
+import (
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Segment is a piece of a piecewise-defined system of differential equations,
+// which is the right-hand side on an interval ending at a switching point.
+type Segment struct {
+	End  float64                             // The end of the interval.
+	Dydx func(float64, []float64, []float64) // The right-hand side.
+}
+
+// ComputeSegments integrates a system of differential equations whose
+// right-hand side changes at known switching points, such as the stages of a
+// rocket or the doses of a drug. The kth segment is in effect from the end of
+// the previous one, or from x0 for the first one, up to its own end, and the
+// end of the last segment should be xend. The ends should be strictly
+// monotonic in the direction of integration. The points xs are treated as in
+// Compute; if they do not specify any intermediate points, the switching
+// points are reported along with the points that the integrator internally
+// traverses.
+//
+// The integration lands exactly on each switching point and restarts there
+// with a derivative of the new right-hand side, but the step size is carried
+// over from one segment to the next, which avoids choosing it anew. The
+// solution is returned as one trajectory, and the statistics are accumulated
+// over all the segments.
+func (self *Integrator) ComputeSegments(segments []Segment, y0 []float64,
+	xs []float64) ([]float64, []float64, *Stats, error) {
+
+	if err := interval.Validate(y0, xs); err != nil {
+		return nil, nil, nil, argument(err.Error())
+	}
+
+	nd, nx, ns := len(y0), len(xs), len(segments)
+	if ns == 0 {
+		return nil, nil, nil, argument("the segments should be given")
+	}
+
+	x0, xend := xs[0], xs[nx-1]
+	dir := 1.0
+	if xend < x0 {
+		dir = -1
+	}
+	for k, segment := range segments {
+		if segment.Dydx == nil {
+			return nil, nil, nil, argument("the right-hand sides of the segments should be given")
+		}
+		start := x0
+		if k > 0 {
+			start = segments[k-1].End
+		}
+		if !(dir*(segment.End-start) > 0) {
+			return nil, nil, nil, argument("the ends of the segments should be strictly monotonic")
+		}
+	}
+	if segments[ns-1].End != xend {
+		return nil, nil, nil, argument("the last segment should end at the end of the interval")
+	}
+
+	fixed := nx > 2
+
+	ys, zs := append([]float64(nil), y0...), []float64{x0}
+	total := &Stats{}
+
+	var state *State
+
+	for k, nc := 0, 1; k < ns; k++ {
+		a, b := x0, segments[k].End
+		if k > 0 {
+			a = segments[k-1].End
+		}
+
+		grid := []float64{a}
+		for ; fixed && nc < nx && dir*(b-xs[nc]) > 0; nc++ {
+			grid = append(grid, xs[nc])
+		}
+		grid = append(grid, b)
+
+		requested := !fixed || nc < nx && xs[nc] == b
+		if fixed && requested {
+			nc++
+		}
+
+		var local, points []float64
+		var stats *Stats
+		var err error
+		if state == nil {
+			local, points, state, stats, err = self.ComputeWithState(segments[k].Dydx, y0, grid)
+		} else {
+			// The derivative at the switching point belongs to the previous
+			// segment.
+			state.F = nil
+			local, points, state, stats, err = self.Continue(segments[k].Dydx, state, grid)
+		}
+		total.merge(stats)
+		if err != nil {
+			return ys, zs, total, err
+		}
+
+		// The integration might have stopped early, for instance, at a
+		// terminal event, in which case the rest of the segments are not
+		// reached.
+		early := state == nil || state.X != b
+
+		if fixed && len(grid) == 2 {
+			// Only the endpoints are of interest.
+			local = append(local[:nd:nd], local[len(local)-nd:]...)
+			points = []float64{a, points[len(points)-1]}
+		}
+
+		// Drop the switching point at the beginning, which has already been
+		// reported, and the one at the end unless it is requested.
+		local, points = local[nd:], points[1:]
+		if !requested && !early {
+			local, points = local[:len(local)-nd], points[:len(points)-1]
+		}
+		ys, zs = append(ys, local...), append(zs, points...)
+
+		if early {
+			break
+		}
+	}
+
+	return ys, zs, total, nil
+}
+
+// merge adds the statistics of a part of an integration to the total ones.
+func (self *Stats) merge(stats *Stats) {
+	if stats == nil {
+		return
+	}
+	self.Evaluations += stats.Evaluations
+	self.Rejections += stats.Rejections
+	self.Steps += stats.Steps
+	self.Interpolations += stats.Interpolations
+	if self.MinStep == 0 || stats.MinStep > 0 && stats.MinStep < self.MinStep {
+		self.MinStep = stats.MinStep
+	}
+	if stats.MaxStep > self.MaxStep {
+		self.MaxStep = stats.MaxStep
+	}
+	if stats.LastStep > 0 {
+		self.LastStep = stats.LastStep
+	}
+	self.Duration += stats.Duration
+	self.Derivative += stats.Derivative
+	self.Resets += stats.Resets
+	self.Steady = stats.Steady
+	self.Drifts = stats.Drifts
+}
@@ -0,0 +1,78 @@
+package erk
+
+import (
+	"sort"
+)
+
+// Solution is a continuous solution of a system of differential equations. It
+// is composed of the interpolants of the steps taken by an integrator.
+type Solution struct {
+	steps []*Interpolant
+	x0    float64
+	xend  float64
+}
+
+// Solve integrates the system of differential equations dy/dx = f(x, y) and
+// returns the solution in a form that can be evaluated at any point of the
+// interval of integration. The interval is [x0, xend] where x0 and xend are
+// the first and last entries of xs, respectively. Events, if any, are taken
+// into account; in particular, the solution ends at the first crossing of a
+// terminal event.
+func (self *Integrator) Solve(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) (*Solution, *Stats, error) {
+
+	if len(xs) < 2 {
+		return nil, nil, argument("the interval should have two endpoints")
+	}
+
+	solution := &Solution{}
+
+	callback := self.config.Callback
+
+	integrator := *self
+	integrator.quiet = true
+	integrator.config.Callback = func(interpolant *Interpolant) {
+		solution.steps = append(solution.steps, interpolant.Clone())
+		if callback != nil {
+			callback(interpolant)
+		}
+	}
+
+	_, xs, _, stats, err := integrator.ComputeWithEvents(dydx, y0, []float64{xs[0], xs[len(xs)-1]})
+	if err != nil {
+		return nil, stats, err
+	}
+
+	solution.x0, solution.xend = xs[0], xs[len(xs)-1]
+
+	return solution, stats, nil
+}
+
+// Span returns the interval where the solution is defined.
+func (self *Solution) Span() (float64, float64) {
+	return self.x0, self.xend
+}
+
+// At evaluates the solution at a point and stores the result in y.
+func (self *Solution) At(x float64, y []float64) error {
+	dir := 1.0
+	if self.xend < self.x0 {
+		dir = -1
+	}
+
+	if dir*(x-self.x0) < 0 || dir*(x-self.xend) > 0 {
+		return argument("the point should be within the span of the solution")
+	}
+
+	steps := self.steps
+	k := sort.Search(len(steps), func(k int) bool {
+		return dir*(steps[k].X+steps[k].H-x) >= 0
+	})
+	if k == len(steps) {
+		k = len(steps) - 1
+	}
+
+	steps[k].Evaluate(x, y)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package erk
+
+// State is the state of an integration at a point, which allows the
+// integration to be continued from this point; see Integrator.Continue.
+type State struct {
+	X float64   // The point.
+	Y []float64 // The solution at the point.
+	H float64   // The step size to continue with.
+	F []float64 // The derivative at the point, which might be nil.
+}
+
+// ComputeWithState augments ComputeWithStats by returning the state at the
+// last point of the solution.
+func (self *Integrator) ComputeWithState(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *State, *Stats, error) {
+
+	workspace := self.acquire(len(y0))
+	defer self.release(workspace)
+
+	ys, xs, _, stats, state, err := self.compute(dydx, &State{Y: y0}, xs, workspace)
+
+	return ys, xs, state.clone(), detach(stats), err
+}
+
+// Continue resumes an integration from a state produced by ComputeWithState
+// or Continue itself. The first entry of xs should coincide with the point of
+// the state, and the rest of xs is treated as in Compute. The step size and
+// the derivative of the state are reused instead of being chosen and evaluated
+// anew, which makes the continuation equivalent to carrying on the original
+// integration.
+func (self *Integrator) Continue(dydx func(float64, []float64, []float64),
+	state *State, xs []float64) ([]float64, []float64, *State, *Stats, error) {
+
+	if len(xs) < 2 {
+		return nil, nil, nil, nil, argument("the interval should have two endpoints")
+	}
+	if state.X != xs[0] {
+		return nil, nil, nil, nil, argument("the interval should start at the point of the state")
+	}
+	if state.F != nil && len(state.F) != len(state.Y) {
+		return nil, nil, nil, nil, argument("the derivative of the state has a wrong dimension")
+	}
+	if state.H < 0 {
+		return nil, nil, nil, nil, argument("the step size of the state should be nonnegative")
+	}
+
+	workspace := self.acquire(len(state.Y))
+	defer self.release(workspace)
+
+	ys, xs, _, stats, state, err := self.compute(dydx, state, xs, workspace)
+
+	return ys, xs, state.clone(), detach(stats), err
+}
+
+// clone copies a state that might refer to the storage of a workspace.
+func (self *State) clone() *State {
+	if self == nil {
+		return nil
+	}
+	state := *self
+	state.Y = append([]float64(nil), self.Y...)
+	if self.F != nil {
+		state.F = append([]float64(nil), self.F...)
+	}
+	return &state
+}
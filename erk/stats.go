@@ -0,0 +1,50 @@
+package erk
+
+import (
+	"time"
+)
+
+// Stats contains information about the work done by an integrator.
+type Stats struct {
+	Evaluations    uint          // The number of invocations of the derivative function.
+	Rejections     uint          // The number of rejected iterations of the algorithm.
+	Steps          uint          // The number of steps the algorithm has taken.
+	Interpolations uint          // The number of evaluations of the interpolant at output points.
+	MinStep        float64       // The size of the smallest accepted step.
+	MaxStep        float64       // The size of the largest accepted step.
+	LastStep       float64       // The size of the last accepted step.
+	Duration       time.Duration // The total time of the integration if timed.
+	Derivative     time.Duration // The time spent in the derivative function if timed.
+	Resets         uint          // The number of applications of the reset maps of the events.
+	Steady         bool          // Whether the integration has stopped at a steady state.
+	Drifts         []Drift       // The drifts of the monitored quantities.
+}
+
+// Drift is the deviation of a monitored quantity from its initial value.
+type Drift struct {
+	Max   float64 // The largest absolute deviation over the accepted steps.
+	Final float64 // The signed deviation at the last accepted step.
+}
+
+// accept records the size of an accepted step.
+func (self *Stats) accept(h float64) {
+	if self.MinStep == 0 || h < self.MinStep {
+		self.MinStep = h
+	}
+	if h > self.MaxStep {
+		self.MaxStep = h
+	}
+	self.LastStep = h
+}
+
+// timed wraps a derivative function in order to accumulate the time spent in
+// it.
+func timed(dydx func(float64, []float64, []float64),
+	total *time.Duration) func(float64, []float64, []float64) {
+
+	return func(x float64, y, f []float64) {
+		started := time.Now()
+		dydx(x, y, f)
+		*total += time.Since(started)
+	}
+}
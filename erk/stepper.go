@@ -0,0 +1,226 @@
+package erk
+
+import (
+	"math"
+	"time"
+)
+
+// Stepper integrates a system of differential equations one step at a time,
+// which is useful when the integration is driven from outside, for instance,
+// in co-simulation. The step size is controlled in the same way as in
+// Compute; however, the events and the stops of the configuration are not
+// taken into account.
+type Stepper struct {
+	integrator *Integrator
+	dydx       func(float64, []float64, []float64)
+
+	// The derivative function taking into account the mass matrix, if any,
+	// and the error encountered by Init, if any.
+	rhs func(float64, []float64, []float64)
+	err error
+
+	x, xnew, h, hlast float64
+
+	y, ynew, z []float64
+	f          [][]float64
+
+	// The relative errors of the previous accepted steps.
+	history [2]float64
+
+	stats Stats
+	taken bool
+}
+
+// NewStepper creates a stepper for the system of differential equations
+// dy/dx = f(x, y). The stepper should be initialized using Init before
+// taking steps.
+func (self *Integrator) NewStepper(dydx func(float64, []float64, []float64)) *Stepper {
+	stepper := &Stepper{integrator: self, dydx: dydx}
+	if self.config.Timing {
+		stepper.dydx = timed(dydx, &stepper.stats.Derivative)
+	}
+	return stepper
+}
+
+// Init sets the initial condition y0 at x0 and chooses the initial step size.
+// If neither the initial nor the maximal step of the configuration is given,
+// the initial step is chosen based on the derivative and does not exceed one.
+// If the mass matrix of the configuration is invalid, the error is returned by
+// Step.
+func (self *Stepper) Init(x0 float64, y0 []float64) {
+	nd, ns := len(y0), len(self.integrator.tableau.C)
+
+	config := &self.integrator.config
+
+	self.rhs, self.err = self.dydx, nil
+	if config.Mass != nil {
+		if self.rhs, self.err = invert(config.Mass, nd, self.dydx); self.err != nil {
+			return
+		}
+	}
+
+	self.x, self.xnew, self.hlast = x0, x0, 0
+	self.y = append([]float64(nil), y0...)
+	self.ynew = append([]float64(nil), y0...)
+	self.z = make([]float64, nd)
+	self.f = make([][]float64, ns+1)
+	for k := range self.f {
+		self.f[k] = make([]float64, nd)
+	}
+	self.history = [2]float64{1, 1}
+	self.stats = Stats{}
+	self.taken = false
+
+	self.rhs(x0, self.y, self.f[0])
+	self.stats.Evaluations++
+
+	self.h = config.TryStep
+	if self.h == 0 {
+		self.h = 1
+		if config.MaxStep > 0 && config.MaxStep < self.h {
+			self.h = config.MaxStep
+		}
+		self.h = guess(self.y, self.f[0], self.h, config.AbsError/config.RelError,
+			config.RelError, self.power(), config.pow)
+	}
+}
+
+// Step takes one accepted step and returns the new point, the solution at it,
+// and the size of the step. The solution is owned by the stepper and is valid
+// until the next step.
+func (self *Stepper) Step() (float64, []float64, float64, error) {
+	if self.err != nil {
+		return 0, nil, 0, self.err
+	}
+	if self.f == nil {
+		return 0, nil, 0, argument("the stepper should be initialized")
+	}
+
+	integrator := self.integrator
+	config := &integrator.config
+
+	ns := len(integrator.tableau.C)
+	f := self.f
+
+	if self.taken {
+		self.x = self.xnew
+		copy(f[0], f[ns])
+		copy(self.y, self.ynew)
+	}
+
+	relerr := config.RelError
+	threshold := config.AbsError / relerr
+	power := self.power()
+
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = math.Inf(1)
+	}
+
+	x, h := self.x, self.h
+
+	if config.Timing {
+		started := time.Now()
+		defer func() {
+			self.stats.Duration += time.Since(started)
+		}()
+	}
+
+	if kind := config.limit(&self.stats, 1, 0); kind != nil {
+		return 0, nil, 0, fail(kind, x, h, &self.stats)
+	}
+
+	self.stats.Steps++
+
+	hmin := config.minimum(x)
+
+	rejected := false
+
+	var ε float64
+	var err error
+	for {
+		if kind := config.limit(&self.stats, 0, uint(ns)); kind != nil {
+			return 0, nil, 0, fail(kind, x, h, &self.stats)
+		}
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		ε, err = integrator.attempt(self.rhs, x, x+h, h, self.y, nil, f, self.z, self.ynew, nil, threshold)
+		self.stats.Evaluations += uint(ns)
+
+		if err != nil {
+			return 0, nil, 0, fail(err, x, h, &self.stats)
+		}
+
+		if ε <= relerr {
+			break
+		}
+
+		self.stats.Rejections++
+
+		if config.OnReject != nil {
+			config.OnReject(x, h, ε)
+		}
+
+		if h <= hmin {
+			return 0, nil, 0, fail(ErrStepUnderflow, x, h, &self.stats)
+		}
+
+		// Shrink the step size as the current one has been rejected.
+		if rejected {
+			h = 0.5 * h
+		} else if scale := 0.8 * config.pow(relerr/ε, power); scale > 0.1 {
+			h = scale * h
+		} else {
+			h = 0.1 * h
+		}
+
+		rejected = true
+	}
+
+	self.xnew, self.hlast, self.taken = x+h, h, true
+
+	self.stats.accept(h)
+
+	if config.OnAccept != nil {
+		config.OnAccept(self.xnew, h, self.ynew)
+	}
+
+	// Compute a new step size.
+	if scale := config.Controller.scale(ε/relerr, &self.history, power, config.pow); rejected {
+		self.h = h
+	} else if scale > 0.2 {
+		self.h = h / scale
+	} else {
+		self.h = 5 * h
+	}
+
+	return self.xnew, self.ynew, h, nil
+}
+
+// Interpolate computes the solution at a point within the last step and
+// stores the result in y.
+func (self *Stepper) Interpolate(x float64, y []float64) {
+	if !self.taken || x == self.xnew {
+		copy(y, self.ynew)
+		return
+	}
+	interpolate(self.integrator.tableau.D, self.x, self.y, self.ynew, self.f,
+		self.hlast, x, y, self.integrator.config.Deterministic)
+	self.stats.Interpolations++
+}
+
+// Stats returns information about the work done by the stepper so far.
+func (self *Stepper) Stats() *Stats {
+	stats := self.stats
+	return &stats
+}
+
+func (self *Stepper) power() float64 {
+	return 1 / float64(self.integrator.tableau.Order+1)
+}
@@ -0,0 +1,30 @@
+package erk
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+var errBreak = errors.New("the iteration has been stopped")
+
+// Steps returns a sequence of the points that the integrator traverses while
+// integrating the system of differential equations dy/dx = f(x, y) over
+// [x0, xend]. The first element is the initial condition. The integration
+// advances lazily as the sequence is consumed and stops when the consumer
+// stops. The solution yielded is valid only until the next iteration. If the
+// integration fails, the sequence ends prematurely; use ComputeFunc in order
+// to obtain the error.
+func (self *Integrator) Steps(dydx func(float64, []float64, []float64),
+	y0 []float64, x0, xend float64) iter.Seq2[float64, []float64] {
+
+	return func(yield func(float64, []float64) bool) {
+		self.stream(context.Background(), dydx, &State{X: x0, Y: y0}, []float64{x0, xend}, nil,
+			func(x float64, y []float64) error {
+				if !yield(x, y) {
+					return errBreak
+				}
+				return nil
+			})
+	}
+}
@@ -0,0 +1,62 @@
+package erk
+
+import (
+	"errors"
+)
+
+// Tableau is the Butcher tableau of an embedded explicit Runge–Kutta method.
+//
+// The derivative at the new point is computed at the end of each step, since
+// it is needed by the next step anyway. It is treated as an extra stage, which
+// can be used by the error estimate and the interpolant. For the methods with
+// the first-same-as-last property, it is the last stage of the method.
+type Tableau struct {
+	// The coefficients of the stages, which form a strictly lower triangular
+	// matrix stored by rows. The first row is empty.
+	A [][]float64
+	// The weights of the solution.
+	B []float64
+	// The nodes of the stages.
+	C []float64
+	// The weights of the error estimate, that is, the differences between the
+	// weights of the solution and the ones of the embedded method. There is
+	// one more weight than stages, which corresponds to the extra stage.
+	E []float64
+	// The coefficients of the interpolant, which is optional. The kth element
+	// of the ith row is the coefficient of the (k+1)th power of the relative
+	// position within a step for the ith stage including the extra one. If
+	// the interpolant is not given, cubic Hermite interpolation is used.
+	D [][]float64
+	// The order of the embedded method, which defines the exponent of the
+	// step-size controller.
+	Order uint
+}
+
+func (t *Tableau) verify() error {
+	ns := len(t.C)
+	if ns == 0 {
+		return errors.New("the tableau should have at least one stage")
+	}
+	if len(t.A) != ns {
+		return errors.New("the tableau should have as many rows of coefficients as stages")
+	}
+	for i := range t.A {
+		if len(t.A[i]) > i {
+			return errors.New("the matrix of coefficients should be strictly lower triangular")
+		}
+	}
+	if len(t.B) != ns {
+		return errors.New("the tableau should have as many weights as stages")
+	}
+	if len(t.E) != ns+1 {
+		return errors.New("the tableau should have one more error weight than stages")
+	}
+	if t.D != nil && len(t.D) != ns+1 {
+		return errors.New("the interpolant should have one more row than stages")
+	}
+	if t.Order == 0 {
+		return errors.New("the order of the embedded method should be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package erk
+
+import (
+	"context"
+)
+
+// Workspace is the storage that an integrator needs in order to integrate a
+// system of a given dimension. A workspace can be reused across integrations
+// in order to avoid allocations; see Integrator.ComputeInto. It belongs to
+// the integrator that has created it and should not be used by several
+// integrations at the same time.
+type Workspace struct {
+	z, y, ynew, ynext []float64
+	f                 [][]float64
+	g, gnew           []float64
+
+	// The derivative at the point being emitted, which is computed only if
+	// slopes is set.
+	fnext  []float64
+	slopes bool
+
+	stats Stats
+	state State
+}
+
+// NewWorkspace allocates a workspace for systems of dimension nd.
+func (self *Integrator) NewWorkspace(nd uint) *Workspace {
+	ns, ne := len(self.tableau.C), len(self.config.Events)
+
+	f := make([][]float64, ns+1)
+	for k := range f {
+		f[k] = make([]float64, nd)
+	}
+
+	return &Workspace{
+		z:     make([]float64, nd),
+		y:     make([]float64, nd),
+		ynew:  make([]float64, nd),
+		ynext: make([]float64, nd),
+		fnext: make([]float64, nd),
+		f:     f,
+		g:     make([]float64, ne),
+		gnew:  make([]float64, ne),
+	}
+}
+
+// Stats returns the statistics of the last integration that has used the
+// workspace. They are overwritten by the next integration.
+func (self *Workspace) Stats() *Stats {
+	return &self.stats
+}
+
+// ComputeInto integrates the system of differential equations dy/dx = f(x, y)
+// like Compute but stores the solution at the points of xs in ys, which should
+// have room for len(xs)×nd entries, and uses the workspace instead of
+// allocating storage. If xs has only two points, only the solution at the
+// endpoints is stored. Given a workspace matching the dimension of the system,
+// the integration does not allocate as long as the configuration does not ask
+// for the features that need additional storage, such as events, invariants,
+// monitors, timing, or a mass matrix; the same applies to the errors, which
+// are allocated when they occur. The function returns the number of points
+// that have been stored, which is smaller than len(xs) if the integration
+// stops early.
+func (self *Integrator) ComputeInto(dydx func(float64, []float64, []float64),
+	y0 []float64, xs, ys []float64, workspace *Workspace) (uint, error) {
+
+	nd, nx := len(y0), len(xs)
+	if len(workspace.y) != nd {
+		return 0, argument("the workspace should match the dimension of the system")
+	}
+	if len(ys) < nx*nd {
+		return 0, argument("the output should have room for the solution at all the points")
+	}
+
+	fixed, k := nx > 2, 0
+	start := State{Y: y0}
+	_, _, _, err := self.stream(context.Background(), dydx, &start, xs, workspace,
+		func(x float64, y []float64) error {
+			if fixed || k == 0 {
+				copy(ys[k*nd:(k+1)*nd], y)
+				k++
+			} else {
+				copy(ys[nd:2*nd], y)
+				k = 2
+			}
+			return nil
+		})
+
+	return uint(k), err
+}
+
+// AppendTo integrates the system of differential equations dy/dx = f(x, y)
+// like Compute but appends the solution and the corresponding points to ys and
+// zs, respectively, and uses the workspace instead of allocating storage. It
+// is meant for the case when the number of points is not known in advance;
+// the integration does not allocate as long as the capacities of ys and zs
+// suffice, and the conditions of ComputeInto are met.
+func (self *Integrator) AppendTo(dydx func(float64, []float64, []float64),
+	y0 []float64, xs, ys, zs []float64, workspace *Workspace) ([]float64, []float64, error) {
+
+	if len(workspace.y) != len(y0) {
+		return ys, zs, argument("the workspace should match the dimension of the system")
+	}
+
+	start := State{Y: y0}
+	_, _, _, err := self.stream(context.Background(), dydx, &start, xs, workspace,
+		func(x float64, y []float64) error {
+			ys, zs = append(ys, y...), append(zs, x)
+			return nil
+		})
+
+	return ys, zs, err
+}
+
+// acquire takes a workspace for systems of dimension nd from the pool of the
+// integrator or allocates one if the pool has none.
+func (self *Integrator) acquire(nd int) *Workspace {
+	if workspace := self.workspaces.Get(uint(nd)); workspace != nil {
+		return workspace
+	}
+	return self.NewWorkspace(uint(nd))
+}
+
+// release returns a workspace to the pool of the integrator. Nothing that
+// refers to the workspace should be retained afterwards.
+func (self *Integrator) release(workspace *Workspace) {
+	self.workspaces.Put(uint(len(workspace.y)), workspace)
+}
+
+// detach copies statistics that might belong to a workspace.
+func detach(stats *Stats) *Stats {
+	if stats == nil {
+		return nil
+	}
+	clone := *stats
+	return &clone
+}
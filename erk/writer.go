@@ -0,0 +1,70 @@
+package erk
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Format is a format of the records written by Integrator.ComputeToWriter.
+type Format uint
+
+const (
+	// Comma-separated values with one line per point, which contains the
+	// point followed by the components of the solution. The numbers are
+	// written in the shortest form that reads back exactly.
+	CSV Format = iota
+	// Binary records with one record per point, which contains the point
+	// followed by the components of the solution as little-endian 64-bit
+	// floating-point numbers. The records have no separators, and each of
+	// them takes 8×(nd+1) bytes.
+	Binary
+)
+
+// ComputeToWriter integrates the system of differential equations
+// dy/dx = f(x, y) like ComputeFunc but writes each point of the solution as a
+// record in a format to w instead of accumulating the solution in memory,
+// which keeps the memory usage independent of the length of the trajectory.
+// The output is buffered and flushed before the function returns. If a write
+// fails, the integration stops, and the error is returned.
+func (self *Integrator) ComputeToWriter(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64, w io.Writer, format Format) ([]Crossing, *Stats, error) {
+
+	if format != CSV && format != Binary {
+		return nil, nil, argument("the format should be either CSV or Binary")
+	}
+
+	writer := bufio.NewWriter(w)
+
+	var record []byte
+	crossings, stats, err := self.ComputeFunc(dydx, y0, xs, func(x float64, y []float64) error {
+		record = encode(record[:0], format, x, y)
+		_, err := writer.Write(record)
+		return err
+	})
+	if ferr := writer.Flush(); err == nil {
+		err = ferr
+	}
+
+	return crossings, stats, err
+}
+
+// encode appends a record with a point and the solution at it to a buffer.
+func encode(buffer []byte, format Format, x float64, y []float64) []byte {
+	if format == Binary {
+		buffer = binary.LittleEndian.AppendUint64(buffer, math.Float64bits(x))
+		for _, y := range y {
+			buffer = binary.LittleEndian.AppendUint64(buffer, math.Float64bits(y))
+		}
+		return buffer
+	}
+
+	buffer = strconv.AppendFloat(buffer, x, 'g', -1, 64)
+	for _, y := range y {
+		buffer = append(buffer, ',')
+		buffer = strconv.AppendFloat(buffer, y, 'g', -1, 64)
+	}
+	return append(buffer, '\n')
+}
@@ -0,0 +1,25 @@
+package etdrk4
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The step of integration.
+	Step float64 `json:"step"`
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+
+	return nil
+}
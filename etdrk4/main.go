@@ -0,0 +1,284 @@
+// Package etdrk4 provides an integrator of semilinear systems of ordinary
+// differential equations of the form
+//
+//	y′ = L y + N(x, y)
+//
+// based on the fourth-order exponential time-differencing Runge–Kutta method
+// of Cox and Matthews. The linear part is treated exactly, which makes the
+// method suitable for stiff problems such as spectral discretizations of
+// partial differential equations, where L is diagonal.
+//
+// The method is given by
+//
+//	a = e^(hL/2) y₀ + Q N(x₀, y₀),
+//	b = e^(hL/2) y₀ + Q N(x₀ + h/2, a),
+//	c = e^(hL/2) a + Q (2 N(x₀ + h/2, b) - N(x₀, y₀)),
+//	y₁ = e^(hL) y₀ + F₁ N(x₀, y₀) + 2 F₂ (N(x₀ + h/2, a) + N(x₀ + h/2, b)) +
+//	     F₃ N(x₀ + h, c),
+//
+// where Q = h/2 φ₁(hL/2), F₁ = h (φ₁ - 3φ₂ + 4φ₃)(hL), F₂ = h (φ₂ - 2φ₃)(hL),
+// and F₃ = h (4φ₃ - φ₂)(hL). For a diagonal L, the φ-functions are evaluated
+// using the contour integrals proposed by Kassam and Trefethen, which avoids
+// the cancellation errors for small arguments. For a full L, they are
+// computed using the exponential of an augmented matrix.
+//
+// https://en.wikipedia.org/wiki/Exponential_integrator
+package etdrk4
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+
+	"github.com/ready-steady/ode/internal/dense"
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+const (
+	contourPoints = 32
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system y′ = L y + N(x, y).
+//
+// The linear operator L is given either by its diagonal or by the full matrix
+// stored in row-major order. The input function N(x, y, f) evaluates N(x, y)
+// for a given x and y in its first and second arguments and stores the result
+// in its third argument. The initial condition is y0, which corresponds to
+// x0 = xs[0].
+//
+// The solution is returned at a number of equidistant points starting from and
+// including x0. The final point is the closest point to the last element of xs
+// with respect to the integration step. If the interval is shorter than half
+// the step, a single step that spans it is taken instead. The points of xs
+// should be strictly increasing. The points are returned as the second result.
+func (self *Integrator) Compute(L []float64, N func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	nd := len(y0)
+	if len(L) != nd && len(L) != nd*nd {
+		return nil, nil, errors.New("the linear operator should be a diagonal or a full matrix")
+	}
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, err
+	}
+
+	h := self.config.Step
+
+	x0, xend := xs[0], xs[len(xs)-1]
+	ns := int((xend-x0)/h+0.5) + 1
+	if ns == 1 {
+		ns, h = 2, xend-x0
+	}
+
+	var E, E2, Q, F1, F2, F3 *operator
+	if len(L) == nd {
+		E, E2, Q, F1, F2, F3 = diagonal(L, h)
+	} else {
+		var err error
+		if E, E2, Q, F1, F2, F3, err = full(L, uint(nd), h); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ys := make([]float64, ns*nd)
+	xs = make([]float64, ns)
+	copy(ys, y0)
+	xs[0] = x0
+
+	buffer := make([]float64, 9*nd)
+	a, b, c := buffer[0*nd:1*nd], buffer[1*nd:2*nd], buffer[2*nd:3*nd]
+	Ny, Na := buffer[3*nd:4*nd], buffer[4*nd:5*nd]
+	Nb, Nc := buffer[5*nd:6*nd], buffer[6*nd:7*nd]
+	u, v := buffer[7*nd:8*nd], buffer[8*nd:9*nd]
+
+	for k := 1; k < ns; k++ {
+		x := x0 + float64(k-1)*h
+
+		y, ynew := ys[(k-1)*nd:k*nd], ys[k*nd:(k+1)*nd]
+
+		N(x, y, Ny)
+
+		E2.apply(y, u)
+		Q.apply(Ny, v)
+		for i := 0; i < nd; i++ {
+			a[i] = u[i] + v[i]
+		}
+		N(x+h/2, a, Na)
+
+		Q.apply(Na, v)
+		for i := 0; i < nd; i++ {
+			b[i] = u[i] + v[i]
+		}
+		N(x+h/2, b, Nb)
+
+		E2.apply(a, u)
+		for i := 0; i < nd; i++ {
+			c[i] = 2*Nb[i] - Ny[i]
+		}
+		Q.apply(c, v)
+		for i := 0; i < nd; i++ {
+			c[i] = u[i] + v[i]
+		}
+		N(x+h, c, Nc)
+
+		E.apply(y, ynew)
+		F1.apply(Ny, u)
+		for i := 0; i < nd; i++ {
+			ynew[i] += u[i]
+			Na[i] += Nb[i]
+		}
+		F2.apply(Na, u)
+		F3.apply(Nc, v)
+		for i := 0; i < nd; i++ {
+			ynew[i] += 2*u[i] + v[i]
+		}
+
+		xs[k] = x0 + float64(k)*h
+	}
+
+	return ys, xs, nil
+}
+
+// operator is a linear operator given by either a diagonal or a full matrix
+// stored in row-major order.
+type operator struct {
+	diagonal bool
+	a        []float64
+}
+
+// apply computes y = A x.
+func (self *operator) apply(x, y []float64) {
+	if self.diagonal {
+		for i := range x {
+			y[i] = self.a[i] * x[i]
+		}
+		return
+	}
+	nd := len(x)
+	for i := 0; i < nd; i++ {
+		sum := 0.0
+		for j := 0; j < nd; j++ {
+			sum += self.a[i*nd+j] * x[j]
+		}
+		y[i] = sum
+	}
+}
+
+func diagonal(L []float64, h float64) (E, E2, Q, F1, F2, F3 *operator) {
+	nd := len(L)
+
+	E = &operator{diagonal: true, a: make([]float64, nd)}
+	E2 = &operator{diagonal: true, a: make([]float64, nd)}
+	Q = &operator{diagonal: true, a: make([]float64, nd)}
+	F1 = &operator{diagonal: true, a: make([]float64, nd)}
+	F2 = &operator{diagonal: true, a: make([]float64, nd)}
+	F3 = &operator{diagonal: true, a: make([]float64, nd)}
+
+	for i := 0; i < nd; i++ {
+		z := h * L[i]
+
+		E.a[i] = math.Exp(z)
+		E2.a[i] = math.Exp(z / 2)
+
+		// Average the functions over a circle around the argument.
+		var q, f1, f2, f3 complex128
+		for j := 0; j < contourPoints; j++ {
+			r := cmplx.Exp(complex(0, math.Pi*(float64(j)+0.5)/contourPoints))
+
+			w := complex(z/2, 0) + r
+			q += (cmplx.Exp(w) - 1) / w
+
+			w = complex(z, 0) + r
+			e := cmplx.Exp(w)
+			f1 += (-4 - w + e*(4-3*w+w*w)) / (w * w * w)
+			f2 += (2 + w + e*(w-2)) / (w * w * w)
+			f3 += (-4 - 3*w - w*w + e*(4-w)) / (w * w * w)
+		}
+
+		Q.a[i] = h / 2 * real(q) / contourPoints
+		F1.a[i] = h * real(f1) / contourPoints
+		F2.a[i] = h * real(f2) / contourPoints
+		F3.a[i] = h * real(f3) / contourPoints
+	}
+
+	return
+}
+
+func full(L []float64, nd uint, h float64) (E, E2, Q, F1, F2, F3 *operator, err error) {
+	nn := nd * nd
+
+	// The exponential of [[hL, I, 0, 0], [0, 0, I, 0], [0, 0, 0, I], [0, 0, 0, 0]]
+	// contains e^(hL), φ₁(hL), φ₂(hL), and φ₃(hL) in its first block row.
+	n := 4 * nd
+	A := make([]float64, n*n)
+	for i := uint(0); i < nd; i++ {
+		for j := uint(0); j < nd; j++ {
+			A[i*n+j] = h * L[i*nd+j]
+		}
+		for k := uint(0); k < 3; k++ {
+			A[(k*nd+i)*n+(k+1)*nd+i] = 1
+		}
+	}
+	B := make([]float64, n*n)
+	if err = dense.Exp(A, n, B); err != nil {
+		return
+	}
+
+	block := func(k uint) []float64 {
+		b := make([]float64, nn)
+		for i := uint(0); i < nd; i++ {
+			copy(b[i*nd:(i+1)*nd], B[i*n+k*nd:i*n+(k+1)*nd])
+		}
+		return b
+	}
+
+	E = &operator{a: block(0)}
+	φ1, φ2, φ3 := block(1), block(2), block(3)
+
+	F1 = &operator{a: make([]float64, nn)}
+	F2 = &operator{a: make([]float64, nn)}
+	F3 = &operator{a: make([]float64, nn)}
+	for i := uint(0); i < nn; i++ {
+		F1.a[i] = h * (φ1[i] - 3*φ2[i] + 4*φ3[i])
+		F2.a[i] = h * (φ2[i] - 2*φ3[i])
+		F3.a[i] = h * (4*φ3[i] - φ2[i])
+	}
+
+	// The exponential of [[hL/2, I], [0, 0]] contains e^(hL/2) and φ₁(hL/2).
+	n = 2 * nd
+	A = A[:n*n]
+	for i := range A {
+		A[i] = 0
+	}
+	for i := uint(0); i < nd; i++ {
+		for j := uint(0); j < nd; j++ {
+			A[i*n+j] = h / 2 * L[i*nd+j]
+		}
+		A[i*n+nd+i] = 1
+	}
+	B = B[:n*n]
+	if err = dense.Exp(A, n, B); err != nil {
+		return
+	}
+
+	E2 = &operator{a: block(0)}
+	Q = &operator{a: block(1)}
+	for i := uint(0); i < nn; i++ {
+		Q.a[i] *= h / 2
+	}
+
+	return
+}
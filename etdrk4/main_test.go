@@ -0,0 +1,87 @@
+package etdrk4
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeLinear(t *testing.T) {
+	forcing := func(_ float64, _, f []float64) {
+		f[0], f[1] = 1, 1
+	}
+
+	// The method is exact for constant forcing regardless of the stiffness.
+	integrator, _ := New(&Config{Step: 0.5})
+	ys, xs, _ := integrator.Compute([]float64{-1e4, 0}, forcing, []float64{1, 0}, []float64{0, 10})
+
+	assert.Equal(len(xs), 21, t)
+	for k, x := range xs {
+		assert.Close(ys[2*k:2*k+2], []float64{1e-4 + (1-1e-4)*math.Exp(-1e4*x), x}, 1e-12, t)
+	}
+}
+
+func TestComputeOrder(t *testing.T) {
+	logistic := func(_ float64, y, f []float64) {
+		f[0] = y[0] * y[0]
+	}
+
+	solve := func(h float64) float64 {
+		integrator, _ := New(&Config{Step: h})
+		ys, _, _ := integrator.Compute([]float64{-1}, logistic, []float64{0.5}, []float64{0, 2})
+		return math.Abs(ys[len(ys)-1] - 1/(1+math.Exp(2)))
+	}
+
+	ratio := solve(0.1) / solve(0.05)
+	assert.Close(math.Log2(ratio), 4.0, 0.1, t)
+}
+
+func TestComputeFull(t *testing.T) {
+	quadratic := func(_ float64, y, f []float64) {
+		f[0], f[1] = y[1]*y[1], -y[0]*y[0]
+	}
+
+	y0, xs := []float64{1, -1}, []float64{0, 1}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	ys1, _, err := integrator.Compute([]float64{-1, -20}, quadratic, y0, xs)
+	assert.Equal(err, nil, t)
+	ys2, _, err := integrator.Compute([]float64{-1, 0, 0, -20}, quadratic, y0, xs)
+	assert.Equal(err, nil, t)
+
+	assert.Close(ys1, ys2, 1e-13, t)
+
+	_, _, err = integrator.Compute([]float64{-1, 0, 0}, quadratic, y0, xs)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeRotation(t *testing.T) {
+	zero := func(_ float64, _, f []float64) {
+		f[0], f[1] = 0, 0
+	}
+
+	integrator, _ := New(&Config{Step: 0.25})
+	ys, xs, _ := integrator.Compute([]float64{0, 1, -1, 0}, zero, []float64{1, 0}, []float64{0, 5})
+
+	for k, x := range xs {
+		assert.Close(ys[2*k:2*k+2], []float64{math.Cos(x), -math.Sin(x)}, 1e-13, t)
+	}
+}
+
+func TestComputeInterval(t *testing.T) {
+	forcing := func(_ float64, _, f []float64) {
+		f[0] = 1
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	ys, xs, err := integrator.Compute([]float64{-2}, forcing, []float64{1}, []float64{0, 0.01})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.01}, t)
+	assert.Close(ys[1], 0.5+0.5*math.Exp(-0.02), 1e-12, t)
+
+	_, _, err = integrator.Compute([]float64{-2}, forcing, []float64{1}, []float64{1, 0})
+	assert.Equal(err != nil, true, t)
+}
@@ -0,0 +1,8 @@
+package euler
+
+import (
+	"github.com/ready-steady/ode/rk"
+)
+
+// Config is the configuration of an integrator.
+type Config = rk.Config
@@ -0,0 +1,25 @@
+// Package euler provides an integrator of systems of ordinary differential
+// equations based on the explicit Euler method.
+//
+// https://en.wikipedia.org/wiki/Euler_method
+package euler
+
+import (
+	"github.com/ready-steady/ode/rk"
+)
+
+// Integrator is an integrator.
+type Integrator = rk.Integrator
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return rk.New(tableau, config)
+}
+
+var tableau = &rk.Tableau{
+	A: [][]float64{
+		{},
+	},
+	B: []float64{1},
+	C: []float64{0},
+}
@@ -0,0 +1,23 @@
+package euler
+
+import (
+	"github.com/ready-steady/ode"
+)
+
+func init() {
+	ode.Register("euler", func(options *ode.Options) (ode.Integrator, error) {
+		step, err := options.Fix()
+		if err != nil {
+			return nil, err
+		}
+		config := &Config{Step: step}
+		integrator, err := New(config)
+		if err != nil {
+			return nil, err
+		}
+		return integrator, nil
+	})
+	ode.RegisterDecoder("euler", func(config []byte) (ode.Integrator, error) {
+		return ode.Decode(config, &Config{}, New)
+	})
+}
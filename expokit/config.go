@@ -0,0 +1,53 @@
+package expokit
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The dimension of the Krylov subspace, which is 30 if zero. It is
+	// reduced to the dimension of the system if the latter is smaller.
+	Dimension uint `json:"dimension"`
+	// The tolerance on the local error of each substep per unit of the
+	// independent variable relative to the norm of the solution.
+	Tolerance float64 `json:"tolerance"`
+	// The initial substep. If zero, it is chosen based on an estimate of the
+	// norm of the matrix.
+	TryStep float64 `json:"tryStep"`
+	// The flag indicating that the matrix is symmetric, in which case the
+	// Lanczos process replaces the Arnoldi one.
+	Symmetric bool `json:"symmetric"`
+	// The maximal number of consecutive rejections of a substep, which is 10
+	// if zero.
+	MaxRejections uint `json:"maxRejections"`
+}
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return &Config{
+		Dimension:     30,
+		Tolerance:     1e-7,
+		MaxRejections: 10,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Dimension == 1 {
+		return errors.New("the dimension of the Krylov subspace should be at least two")
+	}
+	if c.Tolerance <= 0 {
+		return errors.New("the tolerance should be positive")
+	}
+	if c.TryStep < 0 {
+		return errors.New("the initial step should be nonnegative")
+	}
+
+	return nil
+}
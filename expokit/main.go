@@ -0,0 +1,290 @@
+// Package expokit provides an integrator of large linear systems of ordinary
+// differential equations with constant coefficients of the form
+//
+//	y′ = A y,
+//
+// where the matrix A is accessed only through its products with vectors,
+// following the Expokit package of Sidje. Such systems arise, for instance,
+// from semi-discretized diffusion equations and from the Schrödinger
+// equation, whose complex solution can be represented by its real and
+// imaginary parts stacked together.
+//
+// The solution y(x + τ) = e^(τA) y(x) is approximated in the Krylov subspace
+// spanned by y, A y, …, A^(m-1) y, which is built by the Arnoldi process, or
+// by the Lanczos process if A is symmetric. This reduces the exponential of
+// A to the exponential of a small Hessenberg matrix of order m, which is
+// computed using scaling and squaring. The substeps τ are chosen adaptively
+// based on an estimate of the local error derived from the next term of the
+// expansion of the error, and the process is restarted after each substep.
+//
+// https://en.wikipedia.org/wiki/Matrix_exponential
+package expokit
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/dense"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute solves the system y′ = A y.
+//
+// The input function av(v, w) evaluates the product of A with the vector v in
+// its first argument and stores the result in its second argument. The
+// initial condition is y0, which corresponds to x0 = xs[0]. The solution is
+// returned at all the points of xs, which should be monotonic, and the
+// substeps are carried over from one point to the next.
+func (self *Integrator) Compute(av func([]float64, []float64), y0 []float64,
+	xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(av, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(av func([]float64, []float64), y0 []float64,
+	xs []float64) ([]float64, []float64, *Stats, error) {
+
+	const (
+		breakdown = 1e-7
+		gamma     = 0.9
+		delta     = 1.2
+	)
+
+	stats := &Stats{}
+
+	config := &self.config
+
+	nd, nx := len(y0), len(xs)
+	if nx < 2 {
+		return nil, nil, stats, errors.New("the interval should have two endpoints")
+	}
+
+	m := int(config.Dimension)
+	if m == 0 {
+		m = 30
+	}
+	if m > nd {
+		m = nd
+	}
+	rejections := config.MaxRejections
+	if rejections == 0 {
+		rejections = 10
+	}
+	tolerance := config.Tolerance
+
+	product := func(v, w []float64) {
+		av(v, w)
+		stats.Products++
+	}
+
+	// The basis of the Krylov subspace, which has an extra vector for the
+	// estimate of the error.
+	V := make([][]float64, m+1)
+	for j := range V {
+		V[j] = make([]float64, nd)
+	}
+	p := make([]float64, nd)
+
+	// The Hessenberg matrix augmented as in Expokit, so that the first column
+	// of its exponential contains the terms of the estimate of the error.
+	n := m + 2
+	H := make([]float64, n*n)
+	M := make([]float64, n*n)
+	E := make([]float64, n*n)
+
+	ys := make([]float64, nx*nd)
+	copy(ys, y0)
+
+	w := make([]float64, nd)
+	copy(w, y0)
+
+	norm, τnew := 0.0, config.TryStep
+
+	for k := 1; k < nx; k++ {
+		span := xs[k] - xs[k-1]
+		sign := 1.0
+		if span < 0 {
+			sign, span = -1, -span
+		}
+
+		for x, done := 0.0, false; !done; {
+			β := nrm2(w)
+			if β == 0 {
+				break
+			}
+
+			stats.Steps++
+
+			for i := range H {
+				H[i] = 0
+			}
+			for i := range w {
+				V[0][i] = w[i] / β
+			}
+
+			// Build the basis and stop early if the subspace is invariant, in
+			// which case the approximation is exact.
+			happy, mb := false, m
+			for j := 0; j < m; j++ {
+				product(V[j], p)
+				scale := nrm2(p)
+				norm = math.Max(norm, scale)
+
+				first := 0
+				if config.Symmetric && j > 0 {
+					first = j - 1
+				}
+				for i := first; i <= j; i++ {
+					h := dot(V[i], p)
+					H[i*n+j] = h
+					axpy(-h, V[i], p)
+				}
+
+				s := nrm2(p)
+				if s <= breakdown*math.Min(tolerance, 1)*scale {
+					happy, mb = true, j+1
+					break
+				}
+				H[(j+1)*n+j] = s
+				for i := range p {
+					V[j+1][i] = p[i] / s
+				}
+			}
+
+			if τnew == 0 {
+				τnew = initial(m, norm, tolerance)
+			}
+			τ := span - x
+			if !happy && τnew < τ {
+				τ = τnew
+			}
+
+			var anorm float64
+			if !happy {
+				H[(m+1)*n+m] = 1
+				product(V[m], p)
+				anorm = nrm2(p)
+			}
+
+			// Shrink the substep until the estimate of the error is acceptable.
+			var ε, power float64
+			for rejected := uint(0); ; rejected++ {
+				done = τ == span-x
+
+				mx := mb
+				if !happy {
+					mx = m + 2
+				}
+				for i := 0; i < mx; i++ {
+					for j := 0; j < mx; j++ {
+						M[i*mx+j] = sign * τ * H[i*n+j]
+					}
+				}
+				if err := dense.Exp(M[:mx*mx], uint(mx), E[:mx*mx]); err != nil {
+					return ys[:k*nd], xs[:k], stats, err
+				}
+				for i := 0; i < mx; i++ {
+					E[i] = E[i*mx]
+				}
+
+				if happy {
+					ε, power = 0, 1/float64(m)
+					break
+				}
+
+				φ1, φ2 := math.Abs(E[m]), math.Abs(E[m+1]*anorm)
+				if φ1 > 10*φ2 {
+					ε, power = φ2, 1/float64(m)
+				} else if φ1 > φ2 {
+					ε, power = φ1*φ2/(φ1-φ2), 1/float64(m)
+				} else {
+					ε, power = φ1, 1/float64(m-1)
+				}
+				if ε <= delta*τ*tolerance {
+					break
+				}
+
+				stats.Rejections++
+				if rejected+1 >= rejections {
+					return ys[:k*nd], xs[:k], stats,
+						errors.New("the tolerance could not be met by reducing the substep")
+				}
+				τ = round(gamma * τ * math.Pow(τ*tolerance/ε, power))
+			}
+
+			mx := mb
+			if !happy {
+				mx = m + 1
+			}
+			for i := range w {
+				w[i] = 0
+			}
+			for j := 0; j < mx; j++ {
+				axpy(β*E[j], V[j], w)
+			}
+
+			x += τ
+			if !happy {
+				τnew = round(gamma * τ * math.Pow(τ*tolerance/ε, power))
+			}
+			stats.Error += ε
+		}
+
+		copy(ys[k*nd:(k+1)*nd], w)
+	}
+
+	return ys, xs, stats, nil
+}
+
+// initial chooses the first substep following Expokit.
+func initial(m int, norm, tolerance float64) float64 {
+	if norm == 0 {
+		return math.Inf(1)
+	}
+	k := float64(m + 1)
+	factor := math.Pow(k/math.E, k) * math.Sqrt(2*math.Pi*k)
+	return round(math.Pow(factor*tolerance/(4*norm), 1/float64(m)) / norm)
+}
+
+// round rounds a substep up to two significant digits.
+func round(τ float64) float64 {
+	if τ == 0 || math.IsInf(τ, 0) {
+		return τ
+	}
+	s := math.Pow(10, math.Floor(math.Log10(τ))-1)
+	return math.Ceil(τ/s) * s
+}
+
+func axpy(α float64, x, y []float64) {
+	for i := range x {
+		y[i] += α * x[i]
+	}
+}
+
+func dot(x, y []float64) float64 {
+	sum := 0.0
+	for i := range x {
+		sum += x[i] * y[i]
+	}
+	return sum
+}
+
+func nrm2(x []float64) float64 {
+	return math.Sqrt(dot(x, x))
+}
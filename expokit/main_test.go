@@ -0,0 +1,158 @@
+package expokit
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/linear"
+)
+
+func TestComputeHeat(t *testing.T) {
+	const (
+		nd = 500
+		Δ  = 1.0 / (nd + 1)
+	)
+
+	// The heat equation on [0, 1] with zero boundary values discretized by
+	// central differences.
+	av := func(v, w []float64) {
+		for i := range v {
+			w[i] = -2 * v[i]
+			if i > 0 {
+				w[i] += v[i-1]
+			}
+			if i < nd-1 {
+				w[i] += v[i+1]
+			}
+			w[i] /= Δ * Δ
+		}
+	}
+
+	y0 := make([]float64, nd)
+	for i := range y0 {
+		y0[i] = math.Sin(math.Pi*float64(i+1)*Δ) + math.Sin(3*math.Pi*float64(i+1)*Δ)
+	}
+	λ1 := 4 / (Δ * Δ) * math.Pow(math.Sin(math.Pi*Δ/2), 2)
+	λ3 := 4 / (Δ * Δ) * math.Pow(math.Sin(3*math.Pi*Δ/2), 2)
+
+	xs := []float64{0, 0.001, 0.01, 0.1}
+
+	for _, symmetric := range []bool{false, true} {
+		config := DefaultConfig()
+		config.Tolerance = 1e-10
+		config.Symmetric = symmetric
+
+		integrator, _ := New(config)
+
+		ys, _, stats, err := integrator.ComputeWithStats(av, y0, xs)
+		assert.Equal(err, nil, t)
+		for k, x := range xs {
+			for i := 0; i < nd; i++ {
+				y := math.Exp(-λ1*x)*math.Sin(math.Pi*float64(i+1)*Δ) +
+					math.Exp(-λ3*x)*math.Sin(3*math.Pi*float64(i+1)*Δ)
+				assert.Close(ys[k*nd+i], y, 1e-8, t)
+			}
+		}
+		assert.Equal(stats.Products < 10000, true, t)
+	}
+}
+
+func TestComputeDense(t *testing.T) {
+	const nd = 40
+
+	generator := rand.New(rand.NewSource(0))
+
+	A := make([]float64, nd*nd)
+	for i := range A {
+		A[i] = generator.NormFloat64() / math.Sqrt(nd)
+	}
+	for i := 0; i < nd; i++ {
+		A[i*nd+i] -= 1
+	}
+	av := func(v, w []float64) {
+		for i := 0; i < nd; i++ {
+			w[i] = dot(A[i*nd:(i+1)*nd], v)
+		}
+	}
+
+	y0 := make([]float64, nd)
+	for i := range y0 {
+		y0[i] = generator.Float64()
+	}
+
+	xs := []float64{0, 0.5, 2, 1, 5}
+
+	expected, _, _ := linear.New().Compute(A, nil, y0, xs)
+
+	config := DefaultConfig()
+	config.Dimension = 10
+	config.Tolerance = 1e-10
+
+	integrator, _ := New(config)
+
+	ys, _, stats, err := integrator.ComputeWithStats(av, y0, xs)
+	assert.Equal(err, nil, t)
+	assert.Close(ys, expected, 1e-8, t)
+	assert.Equal(stats.Steps > uint(len(xs)-1), true, t)
+}
+
+func TestComputeSchrodinger(t *testing.T) {
+	const (
+		nd = 200
+		Δ  = 1.0 / nd
+	)
+
+	// The free Schrödinger equation on a periodic grid, whose real and
+	// imaginary parts u and v satisfy u′ = H v and v′ = -H u.
+	H := func(v, w []float64) {
+		for i := range v {
+			w[i] = (2*v[i] - v[(i+nd-1)%nd] - v[(i+1)%nd]) / (Δ * Δ) / 2
+		}
+	}
+	av := func(v, w []float64) {
+		H(v[nd:], w[:nd])
+		H(v[:nd], w[nd:])
+		for i := nd; i < 2*nd; i++ {
+			w[i] = -w[i]
+		}
+	}
+
+	y0 := make([]float64, 2*nd)
+	for i := 0; i < nd; i++ {
+		x := float64(i)*Δ - 0.5
+		y0[i] = math.Exp(-x*x/0.005) * math.Cos(40*math.Pi*x)
+		y0[nd+i] = math.Exp(-x*x/0.005) * math.Sin(40*math.Pi*x)
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	ys, _, err := integrator.Compute(av, y0, []float64{0, 0.01, 0.02})
+	assert.Equal(err, nil, t)
+
+	// The norm is conserved, and the evolution is reversible.
+	assert.Close(nrm2(ys[4*nd:]), nrm2(y0), 1e-6, t)
+
+	back, _, err := integrator.Compute(av, ys[4*nd:], []float64{0.02, 0})
+	assert.Equal(err, nil, t)
+	assert.Close(back[2*nd:], y0, 1e-6, t)
+}
+
+func TestComputeInvariant(t *testing.T) {
+	av := func(v, w []float64) {
+		for i := range v {
+			w[i] = -float64(i%2+1) * v[i]
+		}
+	}
+
+	y0 := []float64{1, 0, 1, 0, 1, 0}
+
+	integrator, _ := New(DefaultConfig())
+
+	ys, _, stats, err := integrator.ComputeWithStats(av, y0, []float64{0, 1, 3})
+	assert.Equal(err, nil, t)
+	assert.Close(ys[6:12], []float64{math.Exp(-1), 0, math.Exp(-1), 0, math.Exp(-1), 0}, 1e-14, t)
+	assert.Close(ys[12:], []float64{math.Exp(-3), 0, math.Exp(-3), 0, math.Exp(-3), 0}, 1e-14, t)
+	assert.Equal(stats.Steps, uint(2), t)
+}
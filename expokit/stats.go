@@ -0,0 +1,9 @@
+package expokit
+
+// Stats contains information about the work done by an integrator.
+type Stats struct {
+	Products   uint    // The number of products of the matrix with vectors.
+	Rejections uint    // The number of rejected substeps.
+	Steps      uint    // The number of substeps the algorithm has taken.
+	Error      float64 // The sum of the relative estimates of the local errors.
+}
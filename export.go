@@ -0,0 +1,108 @@
+package ode
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WriteCSV writes the solution to w as comma-separated values. The first line
+// is a header with the names of the columns, which are x followed by the names
+// of the components if given or by y1, y2, …, ynd otherwise, and each of the
+// following lines contains a point followed by the components of the solution
+// at it. The names are quoted if needed. The numbers are written in the
+// shortest form that reads back exactly.
+func (self *Result) WriteCSV(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+
+	writer.WriteString("x")
+	for j, nd := 0, self.dimension(); j < nd; j++ {
+		if j < len(self.Names) {
+			writer.WriteString("," + quote(self.Names[j]))
+		} else {
+			writer.WriteString(",y" + strconv.Itoa(j+1))
+		}
+	}
+	writer.WriteByte('\n')
+
+	var record []byte
+	for i, x := range self.Xs {
+		record = strconv.AppendFloat(record[:0], x, 'g', -1, 64)
+		for _, y := range self.Ys[i] {
+			record = append(record, ',')
+			record = strconv.AppendFloat(record, y, 'g', -1, 64)
+		}
+		record = append(record, '\n')
+		writer.Write(record)
+	}
+
+	return writer.Flush()
+}
+
+// WriteJSON writes the result to w as a JSON object whose fields are those of
+// Result starting with a lowercase letter; the names, the work done, and the
+// events are omitted if absent. Since JSON has no representation of NaNs and
+// infinities, an error is returned if the solution contains any.
+func (self *Result) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(self)
+}
+
+// WriteNPY writes the solution to w in the NPY format of NumPy as a
+// two-dimensional array of 64-bit floating-point numbers in the row-major
+// order whose rows correspond to the points. The first column contains the
+// points, and the other ones contain the components of the solution, so that
+// the array has the shape (points, nd+1) and can be read by numpy.load. The
+// names of the components are not written.
+func (self *Result) WriteNPY(w io.Writer) error {
+	const (
+		alignment = 64
+		magic     = "\x93NUMPY\x01\x00"
+	)
+
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%d, %d), }",
+		len(self.Xs), self.dimension()+1)
+	// The header is padded with spaces and terminated by a newline so that
+	// the data are aligned; the length of the header is a 16-bit integer.
+	padding := alignment - (len(magic)+2+len(header)+1)%alignment
+	header += strings.Repeat(" ", padding%alignment) + "\n"
+
+	writer := bufio.NewWriter(w)
+
+	writer.WriteString(magic)
+	binary.Write(writer, binary.LittleEndian, uint16(len(header)))
+	writer.WriteString(header)
+
+	var record []byte
+	for i, x := range self.Xs {
+		record = binary.LittleEndian.AppendUint64(record[:0], math.Float64bits(x))
+		for _, y := range self.Ys[i] {
+			record = binary.LittleEndian.AppendUint64(record, math.Float64bits(y))
+		}
+		writer.Write(record)
+	}
+
+	return writer.Flush()
+}
+
+// quote quotes a field of comma-separated values if it contains a comma, a
+// quotation mark, or a line break, in which case the quotation marks are
+// doubled.
+func quote(field string) string {
+	if !strings.ContainsAny(field, ",\"\r\n") {
+		return field
+	}
+	return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+}
+
+// dimension returns the number of components of the solution.
+func (self *Result) dimension() int {
+	if len(self.Ys) == 0 {
+		return 0
+	}
+	return len(self.Ys[0])
+}
@@ -0,0 +1,25 @@
+package forcing
+
+import (
+	"github.com/ready-steady/ode/dopri"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The configuration of the underlying integrator. The breakpoints of the
+	// table of the input are added to its stops.
+	Integrator dopri.Config `json:"integrator"`
+}
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return &Config{
+		Integrator: *dopri.DefaultConfig(),
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.Integrator.Validate()
+}
@@ -0,0 +1,90 @@
+// Package forcing provides an integrator of systems of ordinary differential
+// equations driven by a tabulated input of the form
+//
+//	y′(x) = f(x, y(x), u(x))
+//
+// where u is interpolated from a table of measured or precomputed values.
+//
+// The system is integrated by the Dormand–Prince method. The interpolant of
+// the table is smooth only between the points of the table, and a step that
+// straddles a point degrades the error estimate and the accuracy of the
+// method. Therefore, the points of the table are added to the stops of the
+// integrator, so that it lands exactly on each of them and never steps across.
+//
+// https://en.wikipedia.org/wiki/Interpolation
+package forcing
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ready-steady/ode/dopri"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations driven by a table.
+//
+// The input function dydx(x, y, u, f) evaluates f for a given x, y(x), and
+// u(x) and stores the result in its last argument. The initial condition is
+// y0, and xs is treated as by Integrator.Compute in the parent package.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64, []float64),
+	table *Table, y0, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, table, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64, []float64),
+	table *Table, y0, xs []float64) ([]float64, []float64, *dopri.Stats, error) {
+
+	inner := self.config.Integrator
+
+	if nx := len(xs); nx > 0 {
+		a, b := math.Min(xs[0], xs[nx-1]), math.Max(xs[0], xs[nx-1])
+		stops := append([]float64(nil), inner.Stops...)
+		for _, x := range table.Breakpoints() {
+			if a < x && x < b {
+				stops = append(stops, x)
+			}
+		}
+		inner.Stops = unique(stops)
+	}
+
+	integrator, err := dopri.New(&inner)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	u := make([]float64, table.Dimensions())
+
+	return integrator.ComputeWithStats(func(x float64, y, f []float64) {
+		table.Evaluate(x, u)
+		dydx(x, y, u, f)
+	}, y0, xs)
+}
+
+// unique sorts points and removes duplicates.
+func unique(points []float64) []float64 {
+	sort.Float64s(points)
+
+	var result []float64
+	for _, x := range points {
+		if n := len(result); n == 0 || x > result[n-1] {
+			result = append(result, x)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,110 @@
+package forcing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestNewTable(t *testing.T) {
+	_, err := NewTable([]float64{0}, []float64{1}, Linear)
+	assert.Equal(err != nil, true, t)
+
+	_, err = NewTable([]float64{0, 0}, []float64{1, 2}, Linear)
+	assert.Equal(err != nil, true, t)
+
+	_, err = NewTable([]float64{0, 1}, []float64{1, 2, 3}, Linear)
+	assert.Equal(err != nil, true, t)
+
+	table, err := NewTable([]float64{0, 1}, []float64{1, 2, 3, 4}, Spline)
+	assert.Equal(err, nil, t)
+	assert.Equal(table.Dimensions(), uint(2), t)
+}
+
+func TestTableLinear(t *testing.T) {
+	table, _ := NewTable([]float64{0, 1, 3}, []float64{0, 10, 2, 20, 0, 0}, Linear)
+
+	u := make([]float64, 2)
+
+	table.Evaluate(-1, u)
+	assert.Equal(u, []float64{0, 10}, t)
+	table.Evaluate(0.5, u)
+	assert.Close(u, []float64{1, 15}, 1e-15, t)
+	table.Evaluate(1, u)
+	assert.Equal(u, []float64{2, 20}, t)
+	table.Evaluate(2.5, u)
+	assert.Close(u, []float64{0.5, 5}, 1e-15, t)
+	table.Evaluate(4, u)
+	assert.Equal(u, []float64{0, 0}, t)
+}
+
+func TestTablePCHIP(t *testing.T) {
+	// A step, which a cubic spline overshoots.
+	xs := []float64{0, 1, 2, 3, 4, 5}
+	us := []float64{0, 0, 0, 1, 1, 1}
+
+	table, _ := NewTable(xs, us, PCHIP)
+	spline, _ := NewTable(xs, us, Spline)
+
+	u := []float64{0}
+	previous, overshoot := 0.0, 0.0
+	for k := 0; k <= 500; k++ {
+		x := 5 * float64(k) / 500
+
+		table.Evaluate(x, u)
+		assert.Equal(u[0] >= previous-1e-15 && u[0] <= 1+1e-15, true, t)
+		previous = u[0]
+
+		spline.Evaluate(x, u)
+		overshoot = math.Max(overshoot, math.Max(u[0]-1, -u[0]))
+	}
+	assert.Equal(overshoot > 0.01, true, t)
+}
+
+func TestTableSpline(t *testing.T) {
+	xs := make([]float64, 21)
+	us := make([]float64, 21)
+	for i := range xs {
+		xs[i] = math.Pi * float64(i) / 20
+		us[i] = math.Sin(xs[i])
+	}
+
+	table, _ := NewTable(xs, us, Spline)
+
+	u := []float64{0}
+	for k := 0; k <= 100; k++ {
+		x := math.Pi * float64(k) / 100
+		table.Evaluate(x, u)
+		assert.Close(u[0], math.Sin(x), 1e-4, t)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	// y′ = u where u is piecewise linear, so that the solution is piecewise
+	// quadratic and is integrated exactly between the breakpoints.
+	table, _ := NewTable([]float64{0, 0.3, 0.7, 1.1, 2}, []float64{0, 1, -2, 4, 1}, Linear)
+
+	dydx := func(_ float64, _, u, f []float64) {
+		f[0] = u[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	xs := []float64{0, 0.5, 1, 1.5, 2, 2.5}
+	ys, _, err := integrator.Compute(dydx, table, []float64{0}, xs)
+	assert.Equal(err, nil, t)
+
+	exact := func(x float64) float64 {
+		const n = 100000
+		sum, u := 0.0, []float64{0}
+		for k := 0; k < n; k++ {
+			table.Evaluate(x*(float64(k)+0.5)/n, u)
+			sum += u[0] * x / n
+		}
+		return sum
+	}
+	for i, x := range xs {
+		assert.Close(ys[i], exact(x), 1e-8, t)
+	}
+}
@@ -0,0 +1,213 @@
+package forcing
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Interpolation is a choice of an interpolation scheme of a table.
+type Interpolation uint
+
+const (
+	// The piecewise linear interpolation.
+	Linear Interpolation = iota
+	// The piecewise cubic Hermite interpolation with the derivatives chosen
+	// by the method of Fritsch and Carlson, which preserves the monotonicity
+	// of the data and does not overshoot.
+	PCHIP
+	// The natural cubic spline interpolation, which has continuous first and
+	// second derivatives.
+	Spline
+)
+
+// Table is a tabulated input u(x) with nu components.
+type Table struct {
+	xs []float64
+	us []float64
+	ds []float64
+	nu int
+
+	interpolation Interpolation
+}
+
+// NewTable creates a table given the strictly increasing points xs and the
+// values us of the input at these points, which are stored one after another;
+// that is, the input has len(us)/len(xs) components, and us[i*nu+j] is the jth
+// component at xs[i]. Outside the range of xs, the input is held at the values
+// at the nearest endpoint.
+func NewTable(xs, us []float64, interpolation Interpolation) (*Table, error) {
+	nx := len(xs)
+	if nx < 2 {
+		return nil, errors.New("the table should have at least two points")
+	}
+	for i := 1; i < nx; i++ {
+		if !(xs[i] > xs[i-1]) {
+			return nil, errors.New("the points of the table should be strictly increasing")
+		}
+	}
+	if len(us) == 0 || len(us)%nx != 0 {
+		return nil, errors.New("the values of the table should match its points")
+	}
+
+	self := &Table{
+		xs: append([]float64(nil), xs...),
+		us: append([]float64(nil), us...),
+		nu: len(us) / nx,
+
+		interpolation: interpolation,
+	}
+
+	switch interpolation {
+	case Linear:
+	case PCHIP:
+		self.ds = pchip(self.xs, self.us, self.nu)
+	case Spline:
+		self.ds = spline(self.xs, self.us, self.nu)
+	default:
+		return nil, errors.New("the interpolation scheme is unknown")
+	}
+
+	return self, nil
+}
+
+// Dimensions returns the number of the components of the input.
+func (self *Table) Dimensions() uint {
+	return uint(self.nu)
+}
+
+// Breakpoints returns the points of the table.
+func (self *Table) Breakpoints() []float64 {
+	return self.xs
+}
+
+// Evaluate computes the input at x and stores it in u.
+func (self *Table) Evaluate(x float64, u []float64) {
+	xs, us, nu := self.xs, self.us, self.nu
+	nx := len(xs)
+
+	if x <= xs[0] {
+		copy(u, us[:nu])
+		return
+	}
+	if x >= xs[nx-1] {
+		copy(u, us[(nx-1)*nu:])
+		return
+	}
+
+	i := sort.SearchFloat64s(xs, x) - 1
+	if xs[i+1] == x {
+		copy(u, us[(i+1)*nu:(i+2)*nu])
+		return
+	}
+
+	h := xs[i+1] - xs[i]
+	s := (x - xs[i]) / h
+
+	u0, u1 := us[i*nu:(i+1)*nu], us[(i+1)*nu:(i+2)*nu]
+
+	if self.interpolation == Linear {
+		for j := range u[:nu] {
+			u[j] = u0[j] + s*(u1[j]-u0[j])
+		}
+		return
+	}
+
+	h00 := (1 + 2*s) * (1 - s) * (1 - s)
+	h10 := s * (1 - s) * (1 - s)
+	h01 := s * s * (3 - 2*s)
+	h11 := s * s * (s - 1)
+
+	d0, d1 := self.ds[i*nu:(i+1)*nu], self.ds[(i+1)*nu:(i+2)*nu]
+	for j := range u[:nu] {
+		u[j] = h00*u0[j] + h*h10*d0[j] + h01*u1[j] + h*h11*d1[j]
+	}
+}
+
+// pchip computes the derivatives of the monotone piecewise cubic Hermite
+// interpolant.
+func pchip(xs, us []float64, nu int) []float64 {
+	nx := len(xs)
+	ds := make([]float64, nx*nu)
+
+	for j := 0; j < nu; j++ {
+		δ := func(i int) float64 {
+			return (us[(i+1)*nu+j] - us[i*nu+j]) / (xs[i+1] - xs[i])
+		}
+		h := func(i int) float64 {
+			return xs[i+1] - xs[i]
+		}
+
+		if nx == 2 {
+			ds[j], ds[nu+j] = δ(0), δ(0)
+			continue
+		}
+
+		for i := 1; i < nx-1; i++ {
+			δ0, δ1 := δ(i-1), δ(i)
+			if δ0*δ1 <= 0 {
+				continue
+			}
+			w0, w1 := 2*h(i)+h(i-1), h(i)+2*h(i-1)
+			ds[i*nu+j] = (w0 + w1) / (w0/δ0 + w1/δ1)
+		}
+
+		ds[j] = endpoint(h(0), h(1), δ(0), δ(1))
+		ds[(nx-1)*nu+j] = endpoint(h(nx-2), h(nx-3), δ(nx-2), δ(nx-3))
+	}
+
+	return ds
+}
+
+// endpoint computes the derivative of the monotone piecewise cubic Hermite
+// interpolant at an endpoint using a noncentered three-point formula.
+func endpoint(h0, h1, δ0, δ1 float64) float64 {
+	d := ((2*h0+h1)*δ0 - h0*δ1) / (h0 + h1)
+	if math.Signbit(d) != math.Signbit(δ0) || d == 0 || δ0 == 0 {
+		return 0
+	}
+	if math.Signbit(δ0) != math.Signbit(δ1) && math.Abs(d) > math.Abs(3*δ0) {
+		return 3 * δ0
+	}
+	return d
+}
+
+// spline computes the derivatives of the natural cubic spline interpolant.
+func spline(xs, us []float64, nu int) []float64 {
+	nx := len(xs)
+	ds := make([]float64, nx*nu)
+
+	// The second derivatives, which vanish at the endpoints, are found by
+	// solving a tridiagonal system using the Thomas algorithm.
+	M := make([]float64, nx)
+	c := make([]float64, nx)
+
+	for j := 0; j < nu; j++ {
+		δ := func(i int) float64 {
+			return (us[(i+1)*nu+j] - us[i*nu+j]) / (xs[i+1] - xs[i])
+		}
+		h := func(i int) float64 {
+			return xs[i+1] - xs[i]
+		}
+
+		M[0], c[0] = 0, 0
+		for i := 1; i < nx-1; i++ {
+			a, b := h(i-1), 2*(h(i-1)+h(i))
+			r := 6 * (δ(i) - δ(i-1))
+			m := b - a*c[i-1]
+			c[i] = h(i) / m
+			M[i] = (r - a*M[i-1]) / m
+		}
+		M[nx-1] = 0
+		for i := nx - 2; i > 0; i-- {
+			M[i] -= c[i] * M[i+1]
+		}
+
+		for i := 0; i < nx-1; i++ {
+			ds[i*nu+j] = δ(i) - h(i)*(2*M[i]+M[i+1])/6
+		}
+		ds[(nx-1)*nu+j] = δ(nx-2) + h(nx-2)*(M[nx-2]+2*M[nx-1])/6
+	}
+
+	return ds
+}
@@ -0,0 +1,55 @@
+package gauss
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The step of integration.
+	Step float64 `json:"step"`
+	// The number of stages, which is two or three for the methods of order
+	// four or six, respectively. If zero, it is three.
+	Stages uint `json:"stages"`
+	// The Jacobian matrix of the right-hand side, which is stored in row-major
+	// order; see ode.Jacobian. If it is not given, it is approximated using finite
+	// differences.
+	Jacobian ode.Jacobian `json:"-"`
+	// The tolerance of the Newton iterations on the stages relative to the
+	// magnitude of the solution, which is 1e-12 if zero.
+	Tolerance float64 `json:"tolerance"`
+	// The maximal number of Newton iterations per step, which is 20 if zero.
+	MaxIterations uint `json:"maxIterations"`
+}
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return &Config{
+		Step:          0,
+		Stages:        3,
+		Tolerance:     1e-12,
+		MaxIterations: 20,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Stages != 0 && c.Stages != 2 && c.Stages != 3 {
+		return errors.New("the number of stages should be two or three")
+	}
+	if c.Tolerance < 0 {
+		return errors.New("the tolerance should be nonnegative")
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+// Package gauss provides an integrator of systems of ordinary differential
+// equations based on the Gauss–Legendre implicit Runge–Kutta methods, which
+// are the collocation methods at the nodes of the Gauss–Legendre quadrature.
+//
+// The methods with two and three stages are available, which are of orders
+// four and six, respectively; see Config. They are A-stable, symmetric, and
+// symplectic. Consequently, they suit both stiff problems and long-time
+// integration of Hamiltonian systems whose Hamiltonians are not separable,
+// for which explicit symplectic splitting methods are unavailable; the
+// energy of such a system does not drift but oscillates around its exact
+// value, and quadratic invariants, such as the angular momentum, are
+// conserved exactly up to the tolerance of the iterations.
+//
+// The integration proceeds with a constant step. The nonlinear equations of
+// each step are solved by simplified Newton iterations, in which the Jacobian
+// matrix is kept for as long as the iterations converge quickly, and the
+// initial guesses are obtained by extrapolating the collocation polynomial of
+// the previous step.
+//
+// https://en.wikipedia.org/wiki/Gauss–Legendre_method
+package gauss
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/dense"
+	"github.com/ready-steady/ode/internal/interval"
+	"github.com/ready-steady/ode/internal/jacobian"
+)
+
+// ErrConvergence is the error of a step where the Newton iterations have not
+// converged even with a fresh Jacobian matrix.
+var ErrConvergence = errors.New("the Newton iterations have not converged")
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// The interval from x0 = xs[0] to xend, the last element of xs, is divided
+// into the number of equal steps that brings their size the closest to the
+// step of the configuration. If xs does not specify any intermediate points,
+// the solution is returned at the endpoints of the steps, which are returned
+// as the second result. Otherwise, the solution is returned at the points of
+// xs, which should be monotonic, and it is computed between the endpoints of
+// the steps using the collocation polynomials, which are of degree equal to
+// the number of stages. If the iterations of a step do not converge, the
+// solution up to the previous point is returned along with ErrConvergence.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	if err := interval.Validate(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	config := &self.config
+
+	nd, nx := len(y0), len(xs)
+
+	stages := config.Stages
+	if stages == 0 {
+		stages = 3
+	}
+	tolerance := config.Tolerance
+	if tolerance == 0 {
+		tolerance = 1e-12
+	}
+	iterations := config.MaxIterations
+	if iterations == 0 {
+		iterations = 20
+	}
+
+	tableau := tableaus[stages]
+	a, c, d, e := tableau.a, tableau.c, tableau.d, tableau.e
+	ns, nz := int(stages), int(stages)*nd
+
+	x0, xend := xs[0], xs[nx-1]
+	nh := int(math.Abs(xend-x0)/config.Step + 0.5)
+	if nh == 0 {
+		nh = 1
+	}
+	h := (xend - x0) / float64(nh)
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, (nh+1)*nd)
+		xs = make([]float64, nh+1)
+		xs[0] = x0
+	}
+	copy(ys, y0)
+	nc := 1
+
+	y := make([]float64, nd)
+	ynew := make([]float64, nd)
+	f := make([]float64, nd)
+	z := make([]float64, nd)
+	scale := make([]float64, nd)
+
+	Z := make([]float64, nz)
+	Zold := make([]float64, nz)
+	F := make([]float64, nz)
+	G := make([]float64, nz)
+
+	J := make([]float64, nd*nd)
+	approximator := jacobian.New(uint(nd), nil)
+	M := make([]float64, nz*nz)
+	lu := dense.NewLU(uint(nz))
+
+	evaluate := func(x float64, y, f []float64) {
+		dydx(x, y, f)
+		stats.Evaluations++
+	}
+
+	// Evaluate the Jacobian matrix at (x, y) and factorize the iteration
+	// matrix I - h A ⊗ J of the stages.
+	refresh := func(x float64, y, f []float64) error {
+		if config.Jacobian != nil {
+			config.Jacobian(x, y, J)
+		} else {
+			stats.Evaluations += approximator.Compute(dydx, x, y, f, J)
+		}
+		stats.Jacobians++
+
+		for i := 0; i < ns; i++ {
+			for j := 0; j < ns; j++ {
+				for k := 0; k < nd; k++ {
+					row := M[(i*nd+k)*nz+j*nd:][:nd]
+					for l := 0; l < nd; l++ {
+						row[l] = -h * a[i][j] * J[k*nd+l]
+					}
+					if i == j {
+						row[k] += 1
+					}
+				}
+			}
+		}
+		stats.Decompositions++
+
+		return lu.Factorize(M)
+	}
+
+	// Solve the equations Zᵢ = h Σ aᵢⱼ f(x + cⱼh, y + Zⱼ) of the stages
+	// starting from the current values of Z. The function returns whether the
+	// iterations have converged and their rate of convergence.
+	iterate := func(x float64) (bool, float64) {
+		θ, previous := 0.0, 0.0
+		for k := uint(0); k < iterations; k++ {
+			stats.Iterations++
+
+			for j := 0; j < ns; j++ {
+				for l := 0; l < nd; l++ {
+					z[l] = y[l] + Z[j*nd+l]
+				}
+				evaluate(x+c[j]*h, z, F[j*nd:(j+1)*nd])
+			}
+			for i := 0; i < ns; i++ {
+				for l := 0; l < nd; l++ {
+					sum := 0.0
+					for j := 0; j < ns; j++ {
+						sum += a[i][j] * F[j*nd+l]
+					}
+					G[i*nd+l] = h*sum - Z[i*nd+l]
+				}
+			}
+			lu.Solve(G)
+
+			norm := 0.0
+			for i := 0; i < ns; i++ {
+				for l := 0; l < nd; l++ {
+					Z[i*nd+l] += G[i*nd+l]
+					if δ := math.Abs(G[i*nd+l]) / scale[l]; δ > norm {
+						norm = δ
+					}
+				}
+			}
+			if math.IsNaN(norm) || math.IsInf(norm, 0) {
+				return false, θ
+			}
+			if k > 0 {
+				θ = norm / previous
+			}
+			if norm <= tolerance {
+				return true, θ
+			}
+			if k > 0 && θ >= 1 {
+				return false, θ
+			}
+			previous = norm
+		}
+		return false, θ
+	}
+
+	copy(y, y0)
+	evaluate(x0, y, f)
+	if err := refresh(x0, y, f); err != nil {
+		return nil, nil, stats, err
+	}
+	for i := 0; i < ns; i++ {
+		for l := 0; l < nd; l++ {
+			Z[i*nd+l] = c[i] * h * f[l]
+		}
+	}
+
+	// Is the Jacobian matrix evaluated at the current point?
+	current := true
+	// Have the iterations of the previous step converged slowly?
+	slow := false
+
+	for n := 1; n <= nh; n++ {
+		stats.Steps++
+
+		x := x0 + float64(n-1)*h
+		xnew := x0 + float64(n)*h
+		if n == nh {
+			xnew = xend
+		}
+
+		for l := 0; l < nd; l++ {
+			scale[l] = math.Max(math.Abs(y[l]), 1)
+		}
+
+		if slow && !current {
+			evaluate(x, y, f)
+			if err := refresh(x, y, f); err != nil {
+				return ys[:nc*nd], xs[:nc], stats, err
+			}
+			current = true
+		}
+
+		copy(Zold, Z)
+		for {
+			converged, θ := iterate(x)
+			if converged {
+				slow = θ > 0.5
+				break
+			}
+			if current {
+				return ys[:nc*nd], xs[:nc], stats, ErrConvergence
+			}
+
+			// Try a fresh Jacobian matrix before giving up.
+			evaluate(x, y, f)
+			if err := refresh(x, y, f); err != nil {
+				return ys[:nc*nd], xs[:nc], stats, err
+			}
+			current = true
+			copy(Z, Zold)
+		}
+
+		copy(ynew, y)
+		for j := 0; j < ns; j++ {
+			for l := 0; l < nd; l++ {
+				ynew[l] += d[j] * Z[j*nd+l]
+			}
+		}
+
+		if fixed {
+			for ; nc < nx; nc++ {
+				if (xnew-xs[nc])*h < 0 {
+					break
+				}
+				if xs[nc] == xnew {
+					copy(ys[nc*nd:(nc+1)*nd], ynew)
+					continue
+				}
+				θ := (xs[nc] - x) / h
+				point := ys[nc*nd : (nc+1)*nd]
+				copy(point, y)
+				for j := 0; j < ns; j++ {
+					L := lagrange(c, j, θ)
+					for l := 0; l < nd; l++ {
+						point[l] += L * Z[j*nd+l]
+					}
+				}
+			}
+		} else {
+			copy(ys[n*nd:(n+1)*nd], ynew)
+			xs[n] = xnew
+			nc++
+		}
+
+		// Extrapolate the collocation polynomial to the stages of the next
+		// step.
+		for i := 0; i < ns; i++ {
+			for l := 0; l < nd; l++ {
+				sum := 0.0
+				for j := 0; j < ns; j++ {
+					sum += e[i][j] * Z[j*nd+l]
+				}
+				G[i*nd+l] = sum
+			}
+		}
+		copy(Z, G)
+
+		copy(y, ynew)
+		current = false
+	}
+
+	return ys, xs, stats, nil
+}
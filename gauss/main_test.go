@@ -0,0 +1,124 @@
+package gauss
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeOrder(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	solve := func(stages uint, h float64) float64 {
+		integrator, _ := New(&Config{Step: h, Stages: stages})
+		ys, _, _ := integrator.Compute(dydx, []float64{0.5}, []float64{0, 2})
+		return math.Abs(ys[len(ys)-1] - 0.5*(math.Cos(2)+math.Sin(2)))
+	}
+
+	assert.Close(math.Log2(solve(2, 0.2)/solve(2, 0.1)), 4.0, 0.1, t)
+	assert.Close(math.Log2(solve(3, 0.5)/solve(3, 0.25)), 6.0, 0.1, t)
+}
+
+func TestComputePoints(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{0, 0.3, 0.5, 1.25, 2}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	ys, points, err := integrator.Compute(dydx, []float64{0.5}, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(points, xs, t)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 1e-7, t)
+	}
+}
+
+func TestComputePendulum(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0], f[1] = y[1], -math.Sin(y[0])
+	}
+	energy := func(y []float64) float64 {
+		return y[1]*y[1]/2 - math.Cos(y[0])
+	}
+
+	y0 := []float64{2, 0}
+
+	for _, stages := range []uint{2, 3} {
+		integrator, _ := New(&Config{Step: 0.1, Stages: stages})
+
+		ys, xs, stats, err := integrator.ComputeWithStats(dydx, y0, []float64{0, 1000})
+		assert.Equal(err, nil, t)
+		assert.Equal(len(xs), 10001, t)
+		assert.Equal(xs[len(xs)-1], 1000.0, t)
+		assert.Equal(stats.Steps, uint(10000), t)
+
+		for i := range xs {
+			assert.Close(energy(ys[2*i:]), energy(y0), 1e-5, t)
+		}
+	}
+}
+
+func TestComputeKepler(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		r3 := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+		f[0], f[1], f[2], f[3] = y[2], y[3], -y[0]/r3, -y[1]/r3
+	}
+	momentum := func(y []float64) float64 {
+		return y[0]*y[3] - y[1]*y[2]
+	}
+
+	const e = 0.5
+
+	y0 := []float64{1 - e, 0, 0, math.Sqrt((1 + e) / (1 - e))}
+
+	integrator, _ := New(&Config{Step: 0.05})
+
+	ys, xs, err := integrator.Compute(dydx, y0, []float64{0, 20 * math.Pi})
+	assert.Equal(err, nil, t)
+	for i := range xs {
+		assert.Close(momentum(ys[4*i:]), momentum(y0), 1e-10, t)
+	}
+	assert.Close(ys[len(ys)-4:], y0, 1e-3, t)
+}
+
+func TestComputeStiff(t *testing.T) {
+	const λ = -1e6
+
+	dydx := func(x float64, y, f []float64) {
+		f[0] = λ*(y[0]-math.Cos(x)) - math.Sin(x)
+	}
+	jacobian := func(_ float64, _, J []float64) {
+		J[0] = λ
+	}
+
+	for _, J := range []func(float64, []float64, []float64){nil, jacobian} {
+		integrator, _ := New(&Config{Step: 0.1, Jacobian: J})
+
+		ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1}, []float64{0, 2})
+		assert.Equal(err, nil, t)
+		for i, x := range xs {
+			assert.Close(ys[i], math.Cos(x), 1e-6, t)
+		}
+		assert.Equal(stats.Jacobians, uint(1), t)
+	}
+}
+
+func TestComputeBackward(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	ys, xs, err := integrator.Compute(dydx, []float64{0.5 * (math.Cos(2) + math.Sin(2))},
+		[]float64{2, 0})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs[len(xs)-1], 0.0, t)
+	assert.Close(ys[len(ys)-1], 0.5, 1e-10, t)
+}
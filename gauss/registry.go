@@ -0,0 +1,27 @@
+package gauss
+
+import (
+	"github.com/ready-steady/ode"
+)
+
+func init() {
+	ode.Register("gauss", func(options *ode.Options) (ode.Integrator, error) {
+		step, err := options.Fix()
+		if err != nil {
+			return nil, err
+		}
+		config := DefaultConfig()
+		config.Step = step
+		if options.Jacobian != nil {
+			config.Jacobian = options.Jacobian
+		}
+		integrator, err := New(config)
+		if err != nil {
+			return nil, err
+		}
+		return integrator, nil
+	})
+	ode.RegisterDecoder("gauss", func(config []byte) (ode.Integrator, error) {
+		return ode.Decode(config, DefaultConfig(), New)
+	})
+}
@@ -0,0 +1,87 @@
+package gauss
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode/internal/dense"
+)
+
+// tableau is the Butcher tableau of a Gauss method along with the derived
+// quantities used by the integrator.
+type tableau struct {
+	a [][]float64
+	b []float64
+	c []float64
+
+	// The weights of the stages in the new solution, which is y + Σ dⱼ Zⱼ with
+	// Zⱼ = Yⱼ - y and dᵀ = bᵀ A⁻¹.
+	d []float64
+	// The weights of the stages of a step in the initial guesses of the stages
+	// of the next step, which extrapolate the collocation polynomial.
+	e [][]float64
+}
+
+var (
+	sqrt3  = math.Sqrt(3)
+	sqrt15 = math.Sqrt(15)
+)
+
+var tableaus = map[uint]*tableau{
+	2: newTableau(
+		[][]float64{
+			{1.0 / 4, 1.0/4 - sqrt3/6},
+			{1.0/4 + sqrt3/6, 1.0 / 4},
+		},
+		[]float64{1.0 / 2, 1.0 / 2},
+		[]float64{1.0/2 - sqrt3/6, 1.0/2 + sqrt3/6},
+	),
+	3: newTableau(
+		[][]float64{
+			{5.0 / 36, 2.0/9 - sqrt15/15, 5.0/36 - sqrt15/30},
+			{5.0/36 + sqrt15/24, 2.0 / 9, 5.0/36 - sqrt15/24},
+			{5.0/36 + sqrt15/30, 2.0/9 + sqrt15/15, 5.0 / 36},
+		},
+		[]float64{5.0 / 18, 4.0 / 9, 5.0 / 18},
+		[]float64{1.0/2 - sqrt15/10, 1.0 / 2, 1.0/2 + sqrt15/10},
+	),
+}
+
+func newTableau(a [][]float64, b, c []float64) *tableau {
+	s := len(b)
+
+	At := make([]float64, s*s)
+	for i := 0; i < s; i++ {
+		for j := 0; j < s; j++ {
+			At[j*s+i] = a[i][j]
+		}
+	}
+	d := append([]float64(nil), b...)
+	lu := dense.NewLU(uint(s))
+	if err := lu.Factorize(At); err != nil {
+		panic(err)
+	}
+	lu.Solve(d)
+
+	e := make([][]float64, s)
+	for i := range e {
+		e[i] = make([]float64, s)
+		for j := range e[i] {
+			e[i][j] = lagrange(c, j, 1+c[i]) - lagrange(c, j, 1)
+		}
+	}
+
+	return &tableau{a: a, b: b, c: c, d: d, e: e}
+}
+
+// lagrange evaluates at θ the Lagrange basis polynomial of the jth node on the
+// nodes 0, c₁, …, cₛ, which multiplies Zⱼ in the collocation polynomial
+// u(x + θh) = y + Σ Zⱼ Lⱼ(θ).
+func lagrange(c []float64, j int, θ float64) float64 {
+	value := θ / c[j]
+	for m := range c {
+		if m != j {
+			value *= (θ - c[m]) / (c[j] - c[m])
+		}
+	}
+	return value
+}
@@ -0,0 +1,44 @@
+package gautschi
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The step of integration.
+	Step float64 `json:"step"`
+	// The filter functions.
+	Filter Filter `json:"filter"`
+}
+
+// Filter is a choice of the filter functions ψ and φ of a trigonometric
+// integrator.
+type Filter uint
+
+const (
+	// ψ(ξ) = sinc(ξ) and φ(ξ) = 1, which is the impulse method.
+	Deuflhard Filter = iota
+	// ψ(ξ) = sinc²(ξ) and φ(ξ) = 1.
+	HochbruckLubich
+	// ψ(ξ) = sinc²(ξ) and φ(ξ) = sinc(ξ), which is the mollified impulse
+	// method.
+	GarciaArchilla
+)
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Filter > GarciaArchilla {
+		return errors.New("the filter is unknown")
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package gautschi provides trigonometric integrators of highly oscillatory
+// systems of second-order ordinary differential equations of the form
+//
+//	y″ = -Ω² y + g(x, y)
+//
+// where Ω is a diagonal matrix of frequencies. The linear part is solved
+// exactly, which allows for steps far larger than the periods of the fast
+// oscillations.
+//
+// The state of the system is composed of the positions y followed by the
+// velocities y′. The methods are given by
+//
+//	y₁ = cos(hΩ) y₀ + h sinc(hΩ) y′₀ + h²/2 Ψ g₀,
+//	y′₁ = -Ω sin(hΩ) y₀ + cos(hΩ) y′₀ + h/2 (Ψ₀ g₀ + Ψ₁ g₁),
+//
+// where gₙ = g(xₙ, Φ yₙ), Ψ = ψ(hΩ), Φ = φ(hΩ), Ψ₁ = Ψ sinc⁻¹(hΩ), and
+// Ψ₀ = cos(hΩ) Ψ₁.
+//
+// https://en.wikipedia.org/wiki/Exponential_integrator
+package gautschi
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system y″ = -Ω² y + g(x, y).
+//
+// The frequencies, which are the diagonal elements of Ω, are given by ω. The
+// input function g(x, y, f) evaluates g(x, y) for a given x and y in its first
+// and second arguments and stores the result in its third argument. The
+// initial condition y0 contains the positions followed by the velocities.
+//
+// The solution is returned at a number of equidistant points starting from and
+// including x0 = xs[0]. The final point is the closest point to the last
+// element of xs with respect to the integration step. If the interval is
+// shorter than half the step, a single step that spans it is taken instead.
+// The points of xs should be strictly increasing. The points are returned as
+// the second result.
+func (self *Integrator) Compute(ω []float64, g func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	nd := len(ω)
+	if len(y0) != 2*nd {
+		return nil, nil, errors.New("the initial condition should contain positions and velocities")
+	}
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, err
+	}
+
+	h := self.config.Step
+
+	x0, xend := xs[0], xs[len(xs)-1]
+	ns := int((xend-x0)/h+0.5) + 1
+	if ns == 1 {
+		ns, h = 2, xend-x0
+	}
+
+	c, s, w, ψ, φ, ψ1 := coefficients(ω, h, self.config.Filter)
+
+	ys := make([]float64, ns*2*nd)
+	xs = make([]float64, ns)
+	copy(ys, y0)
+	xs[0] = x0
+
+	z := make([]float64, nd)
+	gn := make([]float64, nd)
+	gnew := make([]float64, nd)
+
+	evaluate := func(x float64, y, f []float64) {
+		for i := 0; i < nd; i++ {
+			z[i] = φ[i] * y[i]
+		}
+		g(x, z, f)
+	}
+
+	evaluate(x0, y0[:nd], gn)
+
+	for k := 1; k < ns; k++ {
+		x := x0 + float64(k-1)*h
+
+		y, v := ys[(k-1)*2*nd:][:nd], ys[(k-1)*2*nd+nd:][:nd]
+		ynew, vnew := ys[k*2*nd:][:nd], ys[k*2*nd+nd:][:nd]
+
+		for i := 0; i < nd; i++ {
+			ynew[i] = c[i]*y[i] + h*s[i]*v[i] + h*h/2*ψ[i]*gn[i]
+		}
+
+		evaluate(x+h, ynew, gnew)
+
+		for i := 0; i < nd; i++ {
+			vnew[i] = -w[i]*y[i] + c[i]*v[i] +
+				h/2*ψ1[i]*(c[i]*gn[i]+gnew[i])
+		}
+
+		gn, gnew = gnew, gn
+		xs[k] = x0 + float64(k)*h
+	}
+
+	return ys, xs, nil
+}
+
+func coefficients(ω []float64, h float64, filter Filter) (c, s, w, ψ, φ, ψ1 []float64) {
+	nd := len(ω)
+
+	c = make([]float64, nd)
+	s = make([]float64, nd)
+	w = make([]float64, nd)
+	ψ = make([]float64, nd)
+	φ = make([]float64, nd)
+	ψ1 = make([]float64, nd)
+
+	for i := 0; i < nd; i++ {
+		ξ := h * ω[i]
+
+		c[i] = math.Cos(ξ)
+		s[i] = sinc(ξ)
+		w[i] = ω[i] * math.Sin(ξ)
+
+		switch filter {
+		case Deuflhard:
+			ψ[i], φ[i], ψ1[i] = s[i], 1, 1
+		case HochbruckLubich:
+			ψ[i], φ[i], ψ1[i] = s[i]*s[i], 1, s[i]
+		case GarciaArchilla:
+			ψ[i], φ[i], ψ1[i] = s[i]*s[i], s[i], s[i]
+		}
+	}
+
+	return
+}
+
+func sinc(x float64) float64 {
+	if math.Abs(x) < 1e-4 {
+		return 1 - x*x/6
+	}
+	return math.Sin(x) / x
+}
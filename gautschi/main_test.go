@@ -0,0 +1,73 @@
+package gautschi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeLinear(t *testing.T) {
+	const ω = 100.0
+
+	zero := func(_ float64, _, f []float64) {
+		f[0] = 0
+	}
+
+	for _, filter := range []Filter{Deuflhard, HochbruckLubich, GarciaArchilla} {
+		// The step is much larger than the period of the oscillations.
+		integrator, _ := New(&Config{Step: 0.5, Filter: filter})
+		ys, xs, _ := integrator.Compute([]float64{ω}, zero, []float64{1, 0}, []float64{0, 10})
+
+		assert.Equal(len(xs), 21, t)
+		for k, x := range xs {
+			assert.Close(ys[2*k:2*k+2], []float64{math.Cos(ω * x), -ω * math.Sin(ω*x)}, 1e-10, t)
+		}
+	}
+}
+
+func TestComputeVerlet(t *testing.T) {
+	const h = 1e-2
+
+	spring := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: h})
+	ys, xs, _ := integrator.Compute([]float64{0}, spring, []float64{1, 0}, []float64{0, 1})
+
+	n := len(xs)
+	assert.Equal(xs[n-1], 1.0, t)
+	assert.Close(ys[2*(n-1):], []float64{math.Cos(1), -math.Sin(1)}, 1e-5, t)
+}
+
+func TestComputeOscillatory(t *testing.T) {
+	// y″ = -(ω² + 1) y where only ω is known to the integrator.
+	const ω = 50.0
+
+	perturbation := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+	ys, xs, _ := integrator.Compute([]float64{ω}, perturbation, []float64{1, 0}, []float64{0, 1})
+
+	n := len(xs)
+	assert.Close(ys[2*(n-1)], math.Cos(math.Sqrt(ω*ω+1)), 1e-5, t)
+}
+
+func TestComputeInterval(t *testing.T) {
+	spring := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	ys, xs, err := integrator.Compute([]float64{0}, spring, []float64{1, 0}, []float64{0, 0.01})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.01}, t)
+	assert.Close(ys[2:], []float64{math.Cos(0.01), -math.Sin(0.01)}, 1e-6, t)
+
+	_, _, err = integrator.Compute([]float64{0}, spring, []float64{1, 0}, []float64{1, 0})
+	assert.Equal(err != nil, true, t)
+}
@@ -0,0 +1,385 @@
+// Package gbs provides an integrator of systems of ordinary differential
+// equations based on the Gragg–Bulirsch–Stoer extrapolation algorithm.
+//
+// Each step is computed several times using the modified midpoint rule with
+// increasing numbers of substeps, and the results are extrapolated to the zero
+// substep size. Both the step size and the number of extrapolated columns,
+// which determines the order, are chosen adaptively following the strategy of
+// the ODEX code by Hairer and Wanner. The method is particularly efficient for
+// smooth problems and stringent tolerances.
+//
+// The method has no continuous extension; therefore, when the solution is
+// requested at fixed points, the steps are shortened in order to hit these
+// points exactly.
+//
+// https://en.wikipedia.org/wiki/Bulirsch%E2%80%93Stoer_algorithm
+package gbs
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+const (
+	maxColumns = 9
+
+	safety  = 0.94
+	minimal = 0.02
+	maximal = 4.0
+)
+
+// The numbers of substeps of the modified midpoint rule.
+var sequence = [maxColumns]float64{2, 4, 6, 8, 10, 12, 14, 16, 18}
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	y := make([]float64, nd)
+	f := make([]float64, nd)
+	z := make([]float64, 3*nd)
+
+	// The extrapolation table, which is lower triangular.
+	T := make([][][]float64, maxColumns)
+	for j := range T {
+		T[j] = make([][]float64, j+1)
+		for l := range T[j] {
+			T[j][l] = make([]float64, nd)
+		}
+	}
+
+	// The work needed to compute each column of the table.
+	var A [maxColumns]float64
+	A[0] = 1 + sequence[0]
+	for j := 1; j < maxColumns; j++ {
+		A[j] = A[j-1] + sequence[j]
+	}
+
+	var H, W [maxColumns]float64
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	dydx(x, y, f)
+	stats.Evaluations++
+
+	config := &self.config
+
+	abserr, relerr := config.AbsError, config.RelError
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = xend - x
+	}
+
+	// Choose the initial number of columns.
+	k := int(-math.Log10(relerr+abserr)*0.6 + 1.5)
+	if k < 2 {
+		k = 2
+	} else if k > maxColumns-2 {
+		k = maxColumns - 2
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = initialize(y, f, hmax, abserr, relerr)
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	for done := false; ; {
+		var xnew float64
+		var ynew []float64
+		var j int
+
+		stats.Steps++
+
+		hmin := 16 * epsilon(x)
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		// Close to a point where the solution is needed?
+		hit := false
+		if fixed {
+			for xs[nc] <= x {
+				nc++
+			}
+			if xs[nc] < xend && 1.01*h >= xs[nc]-x {
+				h = xs[nc] - x
+				hit = true
+			}
+		}
+
+		// Close to the end?
+		if !hit && 1.01*h >= xend-x {
+			h = xend - x
+			done = true
+		}
+
+		rejected := false
+
+		for {
+			converged := false
+
+			for j = 0; j <= k+1; j++ {
+				midpoint(dydx, x, y, f, h, sequence[j], z, T[j][0])
+				stats.Evaluations += uint(sequence[j])
+
+				for l := 1; l <= j; l++ {
+					ρ := sequence[j] / sequence[j-l]
+					ρ = ρ*ρ - 1
+					for i := 0; i < nd; i++ {
+						T[j][l][i] = T[j][l-1][i] + (T[j][l-1][i]-T[j-1][l-1][i])/ρ
+					}
+				}
+
+				if j == 0 {
+					continue
+				}
+
+				// Compute the error of the column.
+				ε := 0.0
+				for i := 0; i < nd; i++ {
+					scale := abserr + relerr*math.Max(math.Abs(y[i]), math.Abs(T[j][j][i]))
+					e := (T[j][j][i] - T[j][j-1][i]) / scale
+					ε += e * e
+				}
+				ε = math.Sqrt(ε / float64(nd))
+
+				power := 1 / float64(2*j+1)
+				scale := safety * math.Pow(0.65/ε, power)
+				if min := math.Pow(minimal, power) / maximal; scale < min {
+					scale = min
+				} else if max := 1 / math.Pow(minimal, power); scale > max {
+					scale = max
+				}
+				H[j] = h * scale
+				W[j] = A[j] / H[j]
+
+				if j < k-1 {
+					continue
+				}
+				if ε <= 1 {
+					converged = true
+					break
+				}
+
+				// Give up early if convergence is not expected.
+				if j == k-1 {
+					ρ := sequence[k] * sequence[k+1] / (sequence[0] * sequence[0])
+					if ε > ρ*ρ {
+						break
+					}
+				} else if j == k {
+					ρ := sequence[k+1] / sequence[0]
+					if ε > ρ*ρ {
+						break
+					}
+				}
+			}
+
+			if converged {
+				break
+			}
+
+			stats.Rejections++
+
+			if h <= hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			// Shrink the step size and possibly the order as the current step
+			// has been rejected.
+			if j > k+1 {
+				j = k + 1
+			}
+			if j < k {
+				k = j
+				if k < 2 {
+					k = 2
+				}
+			}
+			h = math.Min(H[j], 0.5*h)
+
+			if h < hmin {
+				h = hmin
+			}
+
+			done, hit = false, false
+			rejected = true
+		}
+
+		ynew = T[j][j]
+		if hit {
+			xnew = xs[nc]
+		} else {
+			xnew = x + h
+		}
+
+		if fixed {
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+				copy(ys[nc*nd:(nc+1)*nd], ynew)
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		if done {
+			break
+		}
+
+		x = xnew
+		copy(y, ynew)
+		dydx(x, y, f)
+		stats.Evaluations++
+
+		// Choose the number of columns and the step size for the next step.
+		knew := j
+		if j >= 2 && W[j-1] < 0.8*W[j] {
+			knew = j - 1
+		} else if W[j] < 0.9*W[j-1] && !rejected {
+			knew = j + 1
+		}
+		if knew < 2 {
+			knew = 2
+		} else if knew > maxColumns-2 {
+			knew = maxColumns - 2
+		}
+
+		if knew <= j {
+			h = H[knew]
+		} else {
+			h = H[j] * A[knew] / A[j]
+		}
+		if rejected && h > H[j] {
+			h = H[j]
+		}
+		k = knew
+	}
+
+	return ys, xs, stats, nil
+}
+
+// midpoint performs a step of size h using the modified midpoint rule with n
+// substeps, which is followed by Gragg's smoothing.
+func midpoint(dydx func(float64, []float64, []float64), x float64, y, f []float64,
+	h, n float64, z, ynew []float64) {
+
+	nd := len(y)
+
+	z0, z1, fz := z[0*nd:1*nd], z[1*nd:2*nd], z[2*nd:3*nd]
+
+	hs := h / n
+
+	for i := 0; i < nd; i++ {
+		z0[i] = y[i]
+		z1[i] = y[i] + hs*f[i]
+	}
+	for m := 1; m < int(n); m++ {
+		dydx(x+float64(m)*hs, z1, fz)
+		for i := 0; i < nd; i++ {
+			z0[i], z1[i] = z1[i], z0[i]+2*hs*fz[i]
+		}
+	}
+	dydx(x+h, z1, fz)
+	for i := 0; i < nd; i++ {
+		ynew[i] = (z0[i] + z1[i] + hs*fz[i]) / 2
+	}
+}
+
+func initialize(y, f []float64, hmax, abserr, relerr float64) float64 {
+	nd := len(y)
+
+	dnf, dny := 0.0, 0.0
+	for i := 0; i < nd; i++ {
+		scale := abserr + relerr*math.Abs(y[i])
+		dnf += (f[i] / scale) * (f[i] / scale)
+		dny += (y[i] / scale) * (y[i] / scale)
+	}
+
+	var h float64
+	if dnf <= 1e-10 || dny <= 1e-10 {
+		h = 1e-6
+	} else {
+		h = 0.01 * math.Sqrt(dny/dnf)
+	}
+	return math.Min(h, hmax)
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
@@ -0,0 +1,55 @@
+package generic
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The initial step of integration.
+	TryStep float64 `json:"tryStep"`
+	// The maximal step of integration.
+	MaxStep float64 `json:"maxStep"`
+	// The absolute error tolerance.
+	AbsError float64 `json:"absError"`
+	// The relative error tolerance, which should be at least 100 times the
+	// machine epsilon of the floating-point type of the integrator.
+	RelError float64 `json:"relError"`
+	// The maximal number of steps, which is unlimited if zero.
+	MaxSteps uint `json:"maxSteps"`
+}
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return &Config{
+		TryStep:  0,
+		MaxStep:  0,
+		AbsError: 1e-6,
+		RelError: 1e-3,
+	}
+}
+
+// Validate checks that the configuration is valid for integrators working in
+// double precision. Integrators working in single precision additionally
+// require RelError to be attainable in that precision, which is checked when
+// they are created.
+func (c *Config) Validate() error {
+	return c.verify(0x1p-52)
+}
+
+func (c *Config) verify(epsilon float64) error {
+	if c.TryStep < 0 {
+		return errors.New("the initial step should be nonnegative")
+	}
+	if c.MaxStep < 0 {
+		return errors.New("the maximal step should be nonnegative")
+	}
+	if c.AbsError <= 0 {
+		return errors.New("the absolute error tolerance should be positive")
+	}
+	if c.RelError < 100*epsilon {
+		return errors.New("the relative error tolerance should be attainable in the precision of the integrator")
+	}
+
+	return nil
+}
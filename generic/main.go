@@ -0,0 +1,315 @@
+// Package generic provides an integrator of systems of ordinary differential
+// equations based on embedded explicit Runge–Kutta methods, which is
+// parameterized by the floating-point type of the solution.
+//
+// All the arithmetic of the integration is carried out in the type of the
+// solution, so that single precision halves the memory traffic at the cost of
+// tolerances that are looser in proportion to the machine epsilon. The method
+// is given by a tableau of package erk, and the step-size selection follows
+// the integral controller of that package with the maximum norm of the error
+// estimate. Unlike package erk, the solution is not interpolated, and the
+// integrator lands exactly on the requested points instead.
+//
+// https://en.wikipedia.org/wiki/Runge–Kutta_methods#Adaptive_Runge–Kutta_methods
+package generic
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/erk"
+)
+
+// Float is a floating-point type that the integrator can work with.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Integrator is an integrator.
+type Integrator[T Float] struct {
+	a      [][]T
+	b, c   []T
+	e      []T
+	power  float64
+	config Config
+}
+
+// New creates a new integrator based on a tableau. The coefficients of the
+// tableau are rounded to the type of the integrator.
+func New[T Float](tableau *erk.Tableau, config *Config) (*Integrator[T], error) {
+	ns := len(tableau.C)
+	if ns == 0 || len(tableau.A) != ns || len(tableau.B) != ns ||
+		len(tableau.E) != ns+1 || tableau.Order == 0 {
+
+		return nil, errors.New("the tableau should be a valid embedded method")
+	}
+	if err := config.verify(float64(epsilon[T]())); err != nil {
+		return nil, err
+	}
+
+	a := make([][]T, ns)
+	for i := range a {
+		a[i] = convert[T](tableau.A[i])
+	}
+
+	return &Integrator[T]{
+		a:      a,
+		b:      convert[T](tableau.B),
+		c:      convert[T](tableau.C),
+		e:      convert[T](tableau.E),
+		power:  1 / float64(tableau.Order+1),
+		config: *config,
+	}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// If xs does not specify any intermediate points, the solution is returned at
+// the points that the integrator internally traverses. Otherwise, the steps
+// are shortened in order to land exactly on the points of xs, which should be
+// strictly monotonic, and the solution is returned only at them. If
+// xend < x0, the integration is carried out backward.
+func (self *Integrator[T]) Compute(dydx func(T, []T, []T), y0 []T,
+	xs []T) ([]T, []T, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator[T]) ComputeWithStats(dydx func(T, []T, []T), y0 []T,
+	xs []T) ([]T, []T, *Stats, error) {
+
+	if err := validate(y0, xs); err != nil {
+		return nil, nil, nil, err
+	}
+
+	config := &self.config
+	a, b, c, e := self.a, self.b, self.c, self.e
+
+	nd, nx, ns := len(y0), len(xs), len(self.c)
+	fixed := nx > 2
+
+	x, xend := xs[0], xs[nx-1]
+	dir := T(1)
+	if xend < x {
+		dir = -1
+	}
+
+	abserr, relerr := T(config.AbsError), T(config.RelError)
+	threshold := abserr / relerr
+	hmax := abs(xend - x)
+	if config.MaxStep > 0 && T(config.MaxStep) < hmax {
+		hmax = T(config.MaxStep)
+	}
+
+	stats := &Stats{}
+
+	y := append([]T(nil), y0...)
+	ynew, z := make([]T, nd), make([]T, nd)
+	f := make([][]T, ns+1)
+	for k := range f {
+		f[k] = make([]T, nd)
+	}
+
+	dydx(x, y, f[0])
+	stats.Evaluations++
+
+	h := T(config.TryStep)
+	if h == 0 || h > hmax {
+		h = self.guess(y, f[0], hmax, threshold, relerr)
+	}
+
+	ys, zs := append([]T(nil), y0...), []T{x}
+	if fixed {
+		ys = make([]T, nx*nd)
+		copy(ys, y0)
+		zs = xs
+	}
+
+	rejected := false
+	for nc := 1; nc < nx; {
+		if config.MaxSteps > 0 && stats.Steps == config.MaxSteps {
+			return ys, zs, stats, errors.New("the maximal number of steps has been reached")
+		}
+
+		hmin := 16 * epsilon[T]() * abs(x)
+		if h < hmin {
+			h = hmin
+		}
+
+		// Land exactly on the next requested point.
+		target, landing := xs[nc], false
+		if dir*(x+dir*h-target) >= 0 {
+			h, landing = abs(target-x), true
+		}
+
+		for l := 1; l < ns; l++ {
+			combine(y, dir*h, a[l], f, z)
+			dydx(x+c[l]*dir*h, z, f[l])
+		}
+		combine(y, dir*h, b, f, ynew)
+
+		xnew := x + dir*h
+		if landing {
+			xnew = target
+		}
+
+		dydx(xnew, ynew, f[ns])
+		stats.Evaluations += uint(ns)
+
+		// The maximum norm of the error estimate relative to the solution.
+		ε := T(0)
+		for i := 0; i < nd; i++ {
+			sum := T(0)
+			for k := range e {
+				sum += e[k] * f[k][i]
+			}
+			scale := threshold
+			if s := abs(y[i]); s > scale {
+				scale = s
+			}
+			if s := abs(ynew[i]); s > scale {
+				scale = s
+			}
+			if s := abs(h*sum) / scale; s > ε {
+				ε = s
+			}
+		}
+
+		if ε > relerr || ε != ε {
+			stats.Rejections++
+
+			if h <= hmin {
+				return ys, zs, stats, errors.New("the step size has become too small")
+			}
+
+			// Shrink the step size as the current one has been rejected.
+			if rejected {
+				h = 0.5 * h
+			} else if scale := T(0.8 * math.Pow(float64(relerr/ε), self.power)); scale > 0.1 {
+				h = scale * h
+			} else {
+				h = 0.1 * h
+			}
+
+			rejected = true
+			continue
+		}
+
+		stats.Steps++
+
+		x = xnew
+		y, ynew = ynew, y
+		f[0], f[ns] = f[ns], f[0]
+
+		if !fixed {
+			ys, zs = append(ys, y...), append(zs, x)
+		} else if landing {
+			copy(ys[nc*nd:(nc+1)*nd], y)
+		}
+		if landing {
+			nc++
+		}
+
+		// Grow the step size, which cannot happen right after a rejection.
+		scale := T(1.25 * math.Pow(float64(ε/relerr), self.power))
+		if scale < 0.2 {
+			scale = 0.2
+		}
+		if rejected && scale < 1 {
+			scale = 1
+		}
+		h = h / scale
+		if h > hmax {
+			h = hmax
+		}
+
+		rejected = false
+	}
+
+	return ys, zs, stats, nil
+}
+
+// guess chooses a step size not exceeding h based on the derivative f at the
+// current point y.
+func (self *Integrator[T]) guess(y, f []T, h, threshold, relerr T) T {
+	scale := T(0)
+	for i := range y {
+		s := abs(y[i])
+		if s < threshold {
+			s = threshold
+		}
+		if s = abs(f[i]) / s; s > scale {
+			scale = s
+		}
+	}
+	scale = scale / T(0.8*math.Pow(float64(relerr), self.power))
+
+	if h*scale > 1 {
+		h = 1 / scale
+	}
+
+	return h
+}
+
+// validate checks that the initial condition is not empty and that the points
+// of the interval are strictly monotonic.
+func validate[T Float](y0, xs []T) error {
+	if len(y0) == 0 {
+		return errors.New("the initial condition should not be empty")
+	}
+	nx := len(xs)
+	if nx < 2 {
+		return errors.New("the interval should have two endpoints")
+	}
+	dir := T(1)
+	if xs[nx-1] < xs[0] {
+		dir = -1
+	}
+	for i := 1; i < nx; i++ {
+		if !(dir*(xs[i]-xs[i-1]) > 0) {
+			return errors.New("the points of the interval should be strictly monotonic")
+		}
+	}
+	return nil
+}
+
+// combine computes ynew = y + h Σ w[k] f[k].
+func combine[T Float](y []T, h T, w []T, f [][]T, ynew []T) {
+	for i := range y {
+		sum := T(0)
+		for k := range w {
+			sum += w[k] * f[k][i]
+		}
+		ynew[i] = y[i] + h*sum
+	}
+}
+
+// convert rounds a vector to a floating-point type.
+func convert[T Float](x []float64) []T {
+	y := make([]T, len(x))
+	for i := range x {
+		y[i] = T(x[i])
+	}
+	return y
+}
+
+// epsilon returns the machine epsilon of a floating-point type.
+func epsilon[T Float]() T {
+	one, ε := T(1), T(1)
+	for one+ε/2 > one {
+		ε /= 2
+	}
+	return ε
+}
+
+func abs[T Float](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,70 @@
+package generic
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/dopri"
+)
+
+func TestComputeFloat32(t *testing.T) {
+	// y′ = -y with y(0) = 1, whose solution is y(x) = exp(-x).
+	dydx := func(_ float32, y, f []float32) {
+		f[0] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-6
+	config.RelError = 1e-4
+
+	integrator, err := New[float32](dopri.Tableau, config)
+	assert.Equal(err, nil, t)
+
+	xs := []float32{0, 0.5, 1, 2}
+	ys, zs, stats, err := integrator.ComputeWithStats(dydx, []float32{1}, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(zs, xs, t)
+	assert.Equal(stats.Steps > 0, true, t)
+	for i, x := range xs {
+		assert.Close(float64(ys[i]), math.Exp(-float64(x)), 5e-4, t)
+	}
+
+	ys, zs, err = integrator.Compute(dydx, []float32{1}, []float32{0, 2})
+	assert.Equal(err, nil, t)
+	assert.Equal(zs[len(zs)-1], float32(2), t)
+	assert.Close(float64(ys[len(ys)-1]), math.Exp(-2), 5e-4, t)
+}
+
+func TestComputeFloat64(t *testing.T) {
+	// The harmonic oscillator y″ = -y with y(0) = 0 and y′(0) = 1, whose
+	// solution is y(x) = sin(x), integrated backward.
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-12
+	config.RelError = 1e-12
+
+	integrator, _ := New[float64](dopri.Tableau, config)
+
+	xs := []float64{0, -1, -2, -5}
+	ys, _, err := integrator.Compute(dydx, []float64{0, 1}, xs)
+	assert.Equal(err, nil, t)
+	for i, x := range xs {
+		assert.Close(ys[2*i], math.Sin(x), 1e-10, t)
+	}
+}
+
+func TestNew(t *testing.T) {
+	config := DefaultConfig()
+	config.RelError = 1e-10
+
+	_, err := New[float32](dopri.Tableau, config)
+	assert.Equal(err != nil, true, t)
+
+	_, err = New[float64](dopri.Tableau, config)
+	assert.Equal(err, nil, t)
+}
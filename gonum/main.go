@@ -0,0 +1,83 @@
+// Package gonum provides adapters between the integrators and the vectors and
+// matrices of Gonum.
+//
+// The adapters share memory with the slices of the integrators wherever the
+// layouts agree: a vector of Gonum with a unit increment wraps a slice as is,
+// and the solution computed at a number of points is exactly the row-major
+// storage of a matrix whose rows correspond to the points and columns to the
+// components.
+//
+// https://www.gonum.org
+package gonum
+
+import (
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/ready-steady/ode"
+)
+
+// Derivative converts a right-hand side written in terms of vectors of Gonum
+// into the form accepted by the integrators. The function dydx(x, y, f)
+// evaluates f(x, y) for a given x and y and stores the result in f. The two
+// vectors wrap the slices of the integrator without copies and are valid only
+// during the call. Since the vectors are reused from one call to another, the
+// resulting function should not be shared by concurrent integrations.
+func Derivative(dydx func(float64, mat.Vector, *mat.VecDense)) func(float64, []float64, []float64) {
+	y, f := &mat.VecDense{}, &mat.VecDense{}
+	return func(x float64, ys, fs []float64) {
+		y.SetRawVector(wrap(ys))
+		f.SetRawVector(wrap(fs))
+		dydx(x, y, f)
+	}
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y)
+// using an integrator; see ode.Integrator.Compute. The right-hand side is
+// written in terms of vectors of Gonum; see Derivative. The solution is
+// returned as a matrix whose rows correspond to the returned points and
+// columns to the components, and it shares memory with the solution computed
+// by the integrator.
+func Compute(integrator ode.Integrator, dydx func(float64, mat.Vector, *mat.VecDense),
+	y0 mat.Vector, xs []float64) (*mat.Dense, []float64, error) {
+
+	ys, xs, err := integrator.Compute(Derivative(dydx), Slice(y0), xs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return Matrix(ys, y0.Len()), xs, nil
+}
+
+// Matrix wraps a solution computed by an integrator for a system of dimension
+// nd into a matrix whose rows correspond to the points and columns to the
+// components. No copies are made.
+func Matrix(ys []float64, nd int) *mat.Dense {
+	return mat.NewDense(len(ys)/nd, nd, ys)
+}
+
+// Vector wraps a slice into a vector. No copies are made.
+func Vector(y []float64) *mat.VecDense {
+	return mat.NewVecDense(len(y), y)
+}
+
+// Slice returns the elements of a vector as a slice. If the vector is a dense
+// vector with a unit increment, the slice shares memory with it; otherwise,
+// the elements are copied.
+func Slice(v mat.Vector) []float64 {
+	if v, ok := v.(*mat.VecDense); ok {
+		if raw := v.RawVector(); raw.Inc == 1 {
+			return raw.Data[:raw.N:raw.N]
+		}
+	}
+	n := v.Len()
+	y := make([]float64, n)
+	for i := range y {
+		y[i] = v.AtVec(i)
+	}
+	return y
+}
+
+func wrap(y []float64) blas64.Vector {
+	return blas64.Vector{N: len(y), Inc: 1, Data: y}
+}
@@ -0,0 +1,40 @@
+package gonum
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/dopri"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCompute(t *testing.T) {
+	dydx := func(_ float64, y mat.Vector, f *mat.VecDense) {
+		f.SetVec(0, y.AtVec(1))
+		f.SetVec(1, -y.AtVec(0))
+	}
+
+	integrator, _ := dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10})
+
+	ys, xs, err := Compute(integrator, dydx, Vector([]float64{1, 0}), []float64{0, 1, 2})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 1, 2}, t)
+
+	r, c := ys.Dims()
+	assert.Equal([]int{r, c}, []int{3, 2}, t)
+	for i, x := range xs {
+		assert.Close(ys.At(i, 0), math.Cos(x), 1e-8, t)
+		assert.Close(ys.At(i, 1), -math.Sin(x), 1e-8, t)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	y := []float64{1, 2, 3}
+	z := Slice(Vector(y))
+	z[0] = 4
+	assert.Equal(y[0], 4.0, t)
+
+	ys := []float64{1, 2, 3, 4, 5, 6}
+	assert.Equal(Slice(Matrix(ys, 2).RowView(1)), []float64{3, 4}, t)
+}
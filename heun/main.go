@@ -0,0 +1,28 @@
+// Package heun provides an integrator of systems of ordinary differential
+// equations based on Heun's method, which is the explicit trapezoidal rule.
+//
+// https://en.wikipedia.org/wiki/Heun%27s_method
+package heun
+
+import (
+	"github.com/ready-steady/ode/rk"
+)
+
+// Integrator is an integrator.
+type Integrator = rk.Integrator
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return rk.New(tableau, config)
+}
+
+var tableau = &rk.Tableau{
+	A: [][]float64{
+		{},
+		{1},
+	},
+	B: []float64{1, 1},
+	C: []float64{0, 1},
+
+	Denominator: 2,
+}
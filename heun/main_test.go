@@ -0,0 +1,23 @@
+package heun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeOrder(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	solve := func(h float64) float64 {
+		integrator, _ := New(&Config{Step: h})
+		ys, _, _ := integrator.Compute(dydx, []float64{0.5}, []float64{0, 2})
+		return math.Abs(ys[len(ys)-1] - 0.5*(math.Cos(2)+math.Sin(2)))
+	}
+
+	ratio := solve(0.01) / solve(0.005)
+	assert.Close(math.Log2(ratio), 2.0, 0.05, t)
+}
@@ -0,0 +1,41 @@
+package hybrid
+
+import (
+	"errors"
+)
+
+// Config is the configuration of a solver.
+type Config struct {
+	// The step of integration of the deterministic part.
+	Step float64 `json:"step"`
+	// The copy number starting from which a species is treated as continuous.
+	Threshold float64 `json:"threshold"`
+	// The seed of the random number generator.
+	Seed int64 `json:"seed"`
+}
+
+// DefaultConfig returns the default configuration of a solver.
+func DefaultConfig() *Config {
+	return &Config{
+		Step:      1e-2,
+		Threshold: 1e3,
+		Seed:      0,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when a
+// solver is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Threshold < 0 {
+		return errors.New("the threshold should be nonnegative")
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+// Package hybrid provides a solver of chemical reaction networks that combines
+// the stochastic simulation algorithm for species present in low copy numbers
+// with the integration of the reaction rate equations for abundant species.
+//
+// A reaction is treated deterministically if all the species it changes are
+// above a threshold and stochastically otherwise. The partition is revised
+// after every step. The stochastic reactions fire when the integral of their
+// total propensity reaches an exponentially distributed random variable, which
+// keeps the two parts synchronized.
+//
+// https://en.wikipedia.org/wiki/Gillespie_algorithm
+package hybrid
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// System is a reaction network.
+type System struct {
+	Reactions []Reaction
+}
+
+// Reaction is a reaction channel.
+type Reaction struct {
+	// The change in the copy numbers of the species caused by one occurrence of
+	// the reaction.
+	Change []float64
+	// The propensity of the reaction at time x and copy numbers y.
+	Propensity func(x float64, y []float64) float64
+}
+
+// Solver is a solver.
+type Solver struct {
+	config Config
+}
+
+// New creates a new solver.
+func New(config *Config) (*Solver, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Solver{config: *config}, nil
+}
+
+// Compute simulates the reaction network starting from the copy numbers y0.
+//
+// The simulated interval is [x0, xend] where x0 and xend are the first and
+// last entries of xs, respectively. Apart from the endpoints, the state is
+// returned at a number of intermediate points. These points can be specified
+// by xs. If xs does not specify any intermediate points, the algorithm reports
+// the points that it internally traverses.
+func (self *Solver) Compute(system *System, y0 []float64,
+	xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(system, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Solver) ComputeWithStats(system *System, y0 []float64,
+	xs []float64) ([]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	reactions := system.Reactions
+
+	nd, nr, nx := len(y0), len(reactions), len(xs)
+	if nx < 2 {
+		return nil, nil, stats, errors.New("the interval should have two endpoints")
+	}
+	for j := range reactions {
+		if len(reactions[j].Change) != nd {
+			return nil, nil, stats, errors.New("the change vectors should match the number of species")
+		}
+	}
+
+	config := &self.config
+	generator := rand.New(rand.NewSource(config.Seed))
+
+	continuous := make([]bool, nr)
+	a := make([]float64, nr)
+
+	partition := func(y []float64) {
+		for j := range reactions {
+			continuous[j] = true
+			for i, c := range reactions[j].Change {
+				if c != 0 && y[i] < config.Threshold {
+					continuous[j] = false
+					break
+				}
+			}
+		}
+	}
+
+	// The last component is the integral of the stochastic propensities.
+	dydx := func(x float64, y, f []float64) {
+		for i := 0; i <= nd; i++ {
+			f[i] = 0
+		}
+		for j := range reactions {
+			p := reactions[j].Propensity(x, y[:nd])
+			if p < 0 {
+				p = 0
+			}
+			if continuous[j] {
+				for i, c := range reactions[j].Change {
+					f[i] += c * p
+				}
+			} else {
+				f[nd] += p
+			}
+		}
+		stats.Evaluations++
+	}
+
+	y := make([]float64, nd+1)
+	ynew := make([]float64, nd+1)
+	copy(y, y0)
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+		copy(ys, y0)
+	} else {
+		ys = append(make([]float64, 0, 2*nd), y0...)
+		xs = append(make([]float64, 0, 2), x)
+	}
+	nc := 1
+
+	ξ := generator.ExpFloat64()
+
+	s := newStepper(nd + 1)
+
+	for x < xend {
+		stats.Steps++
+
+		partition(y)
+
+		xnext := xend
+		if fixed {
+			xnext = xs[nc]
+		}
+
+		h, land := config.Step, false
+		if x+h >= xnext {
+			h, land = xnext-x, true
+		}
+
+		s.step(dydx, x, y, h, ynew)
+
+		if ynew[nd] >= ξ {
+			// Shorten the step to arrive at the moment of the reaction.
+			θ := (ξ - y[nd]) / (ynew[nd] - y[nd])
+			if θ < 1 {
+				h, land = θ*h, false
+				s.step(dydx, x, y, h, ynew)
+			}
+
+			fire(reactions, continuous, a, x+h, ynew[:nd], generator)
+			stats.Evaluations++
+			stats.Firings++
+
+			ynew[nd] = 0
+			ξ = generator.ExpFloat64()
+		}
+
+		if land {
+			x = xnext
+		} else {
+			x += h
+		}
+		y, ynew = ynew, y
+
+		if fixed {
+			if land {
+				copy(ys[nc*nd:(nc+1)*nd], y[:nd])
+				nc++
+			}
+		} else {
+			ys = append(ys, y[:nd]...)
+			xs = append(xs, x)
+		}
+	}
+
+	return ys, xs, stats, nil
+}
+
+func fire(reactions []Reaction, continuous []bool, a []float64, x float64,
+	y []float64, generator *rand.Rand) {
+
+	total := 0.0
+	for j := range reactions {
+		a[j] = 0
+		if continuous[j] {
+			continue
+		}
+		if p := reactions[j].Propensity(x, y); p > 0 {
+			a[j] = p
+			total += p
+		}
+	}
+	if total == 0 {
+		return
+	}
+
+	target, k := total*generator.Float64(), 0
+	for j := range reactions {
+		if a[j] == 0 {
+			continue
+		}
+		if k = j; target < a[j] {
+			break
+		}
+		target -= a[j]
+	}
+
+	for i, c := range reactions[k].Change {
+		y[i] += c
+	}
+}
+
+type stepper struct {
+	z, f1, f2, f3, f4 []float64
+}
+
+func newStepper(nd int) *stepper {
+	f := make([]float64, 5*nd)
+	return &stepper{
+		z:  f[0*nd : 1*nd],
+		f1: f[1*nd : 2*nd],
+		f2: f[2*nd : 3*nd],
+		f3: f[3*nd : 4*nd],
+		f4: f[4*nd : 5*nd],
+	}
+}
+
+func (self *stepper) step(dydx func(float64, []float64, []float64),
+	x float64, y []float64, h float64, ynew []float64) {
+
+	nd := len(y)
+	z, f1, f2, f3, f4 := self.z, self.f1, self.f2, self.f3, self.f4
+
+	dydx(x, y, f1)
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h*f1[i]/2
+	}
+	dydx(x+h/2, z, f2)
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h*f2[i]/2
+	}
+	dydx(x+h/2, z, f3)
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h*f3[i]
+	}
+	dydx(x+h, z, f4)
+	for i := 0; i < nd; i++ {
+		ynew[i] = y[i] + h*(f1[i]+2*f2[i]+2*f3[i]+f4[i])/6
+	}
+}
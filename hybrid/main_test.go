@@ -0,0 +1,87 @@
+package hybrid
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeDeterministic(t *testing.T) {
+	system := decay(1)
+
+	solver, _ := New(&Config{Step: 1e-2, Threshold: 0})
+
+	xs := []float64{0, 0.5, 1}
+	ys, _, stats, _ := solver.ComputeWithStats(system, []float64{1e4}, xs)
+
+	assert.Close(ys, []float64{1e4, 1e4 * math.Exp(-0.5), 1e4 * math.Exp(-1)}, 1e-6, t)
+	assert.Equal(stats.Firings, uint(0), t)
+	assert.Equal(stats.Steps, uint(100), t)
+}
+
+func TestComputeStochastic(t *testing.T) {
+	const (
+		runs = 200
+		y0   = 100
+	)
+
+	system := decay(1)
+
+	mean := 0.0
+	for k := 0; k < runs; k++ {
+		solver, _ := New(&Config{Step: 1e-2, Threshold: math.Inf(1), Seed: int64(k)})
+		ys, _, stats, _ := solver.ComputeWithStats(system, []float64{y0}, []float64{0, 0.5, 1})
+
+		assert.Equal(ys[2], math.Floor(ys[2]), t)
+		assert.Equal(float64(stats.Firings), y0-ys[2], t)
+
+		mean += ys[2] / runs
+	}
+
+	assert.Close(mean, y0*math.Exp(-1), 2, t)
+}
+
+func TestComputeHybrid(t *testing.T) {
+	// A decays deterministically and occasionally produces B, which is
+	// present in low copy numbers.
+	system := &System{
+		Reactions: []Reaction{
+			Reaction{
+				Change: []float64{-1, 0},
+				Propensity: func(_ float64, y []float64) float64 {
+					return y[0]
+				},
+			},
+			Reaction{
+				Change: []float64{0, 1},
+				Propensity: func(_ float64, y []float64) float64 {
+					return 1e-3 * y[0]
+				},
+			},
+		},
+	}
+
+	solver, _ := New(DefaultConfig())
+
+	ys, xs, stats, _ := solver.ComputeWithStats(system, []float64{1e4, 0}, []float64{0, 1})
+
+	n := len(xs)
+	assert.Equal(xs[n-1], 1.0, t)
+	assert.Close(ys[2*(n-1)], 1e4*math.Exp(-1), 1e-6, t)
+	assert.Equal(ys[2*(n-1)+1], float64(stats.Firings), t)
+	assert.Equal(stats.Firings > 0, true, t)
+}
+
+func decay(rate float64) *System {
+	return &System{
+		Reactions: []Reaction{
+			Reaction{
+				Change: []float64{-1},
+				Propensity: func(_ float64, y []float64) float64 {
+					return rate * y[0]
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,8 @@
+package hybrid
+
+// Stats contains information about the work done by a solver.
+type Stats struct {
+	Evaluations uint // The number of evaluations of the propensity functions.
+	Firings     uint // The number of simulated stochastic reaction events.
+	Steps       uint // The number of steps of the deterministic part.
+}
@@ -0,0 +1,430 @@
+// Package imex provides an integrator of systems of ordinary differential
+// equations whose right-hand side is split into a stiff and a nonstiff part,
+//
+//	y′ = fI(x, y) + fE(x, y),
+//
+// based on an implicit–explicit additive Runge–Kutta method of order three
+// with an embedded method of order two.
+//
+// The stiff part fI is treated implicitly using an L-stable singly diagonally
+// implicit method, and the nonstiff part fE is treated explicitly. Therefore,
+// the nonlinear systems solved at each stage involve only the Jacobian matrix
+// of the stiff part, and the nonstiff part, which might be expensive or not
+// differentiable, is evaluated once per stage. Typical examples are
+// reaction–diffusion and advection–diffusion problems where the diffusion is
+// stiff and the rest is not.
+//
+// The method is ARK3(2)4L[2]SA of Kennedy and Carpenter. The local error is
+// estimated using the embedded method, and the part of the estimate due to
+// the stiff part is filtered by the iteration matrix of the Newton method in
+// order to avoid excessive step-size reductions.
+//
+// https://en.wikipedia.org/wiki/Runge%E2%80%93Kutta_methods
+package imex
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/jacobian"
+	"github.com/ready-steady/ode/internal/newton"
+	"github.com/ready-steady/ode/internal/sparse"
+)
+
+const (
+	newtonMaxIter = 7
+	power         = 1.0 / 3
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system y′ = fI(x, y) + fE(x, y).
+//
+// The input functions fI(x, y, f) and fE(x, y, f) evaluate the implicit and
+// explicit parts, respectively, for a given x and y in their first and second
+// arguments and store the result in their third arguments. The initial
+// condition is y0. The interval of integration is [x0, xend] where x0 and xend
+// are the first and last entries of xs, respectively.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(fI, fE func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(fI, fE, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(fI, fE func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	if len(xs) < 2 {
+		return nil, nil, nil, errors.New("the interval should have two endpoints")
+	}
+
+	stats := &Stats{}
+
+	config := &self.config
+
+	ns := len(c)
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	if config.Pattern != nil {
+		if err := sparse.Verify(config.Pattern, uint(nd)); err != nil {
+			return nil, nil, stats, err
+		}
+	}
+
+	y := make([]float64, nd)
+	ynew := make([]float64, nd)
+	ψ := make([]float64, nd)
+	δ := make([]float64, nd)
+	scale := make([]float64, nd)
+
+	f := make([]float64, 4*nd)
+	f0 := f[0*nd : 1*nd]
+	f1 := f[1*nd : 2*nd]
+	z := f[2*nd : 3*nd]
+	fz := f[3*nd : 4*nd]
+
+	// The implicit and explicit parts of the stages including the extra one at
+	// the new point.
+	kI := make([][]float64, ns+1)
+	kE := make([][]float64, ns+1)
+	for k := range kI {
+		kI[k] = make([]float64, nd)
+		kE[k] = make([]float64, nd)
+	}
+
+	approximator := jacobian.New(uint(nd), config.Pattern)
+	var J []float64
+	if config.Krylov == nil {
+		J = make([]float64, approximator.Size())
+	}
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	fI(x, y, kI[0])
+	fE(x, y, kE[0])
+	stats.Evaluations += 2
+	for i := 0; i < nd; i++ {
+		f0[i] = kI[0][i] + kE[0][i]
+	}
+
+	relerr := config.RelError
+	threshold := config.AbsError / relerr
+
+	solver := newton.New(uint(nd), config.Pattern, config.LinearSolver, newtonMaxIter,
+		relerr*math.Max(10*epsilon(1)/relerr, math.Min(0.03, math.Sqrt(relerr))))
+	if config.Krylov != nil {
+		solver.UseKrylov(config.Krylov)
+	}
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = 0.1 * (xend - x)
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = xs[1] - x
+		if h > hmax {
+			h = hmax
+		}
+
+		scale := 0.0
+		for i := 0; i < nd; i++ {
+			s := math.Abs(f0[i]) / math.Max(math.Abs(y[i]), threshold)
+			if s > scale {
+				scale = s
+			}
+		}
+		scale = scale / (0.8 * math.Pow(relerr, power))
+
+		if h*scale > 1 {
+			h = 1 / scale
+		}
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	// Is the Jacobian matrix evaluated at the current point?
+	current := false
+	// The step size for which the iteration matrix has been factorized.
+	hfactorized := 0.0
+
+	jacobian := func() {
+		// In the Jacobian-free mode, the Jacobian matrix is never formed.
+		if config.Krylov == nil {
+			if config.Jacobian != nil {
+				config.Jacobian(x, y, J)
+			} else {
+				stats.Evaluations += approximator.Compute(fI, x, y, kI[0], J)
+			}
+			stats.Jacobians++
+		}
+		current = true
+	}
+
+	for done := false; ; {
+		var xnew, ε float64
+
+		stats.Steps++
+
+		hmin := 16 * epsilon(x)
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		// Close to the end?
+		if 1.1*h >= xend-x {
+			h = xend - x
+			done = true
+		}
+
+		if stats.Jacobians == 0 {
+			jacobian()
+		}
+
+		for i := 0; i < nd; i++ {
+			scale[i] = math.Max(math.Abs(y[i]), threshold)
+		}
+
+		rejected := false
+
+		for {
+			if h != hfactorized {
+				stats.Decompositions++
+				if err := solver.Factorize(J, γ*h); err != nil {
+					return nil, nil, stats, err
+				}
+				hfactorized = h
+			}
+
+			xnew = x + h
+
+			// The first stage is explicit and coincides with the current point.
+			converged := true
+			for j := 1; j < ns && converged; j++ {
+				for i := 0; i < nd; i++ {
+					ψ[i] = y[i] + h*(sum(ae[j], kE, i)+sum(ai[j], kI, i))
+				}
+
+				// Extrapolate from the previous stage.
+				for i := 0; i < nd; i++ {
+					z[i] = ψ[i] + γ*h*kI[j-1][i]
+				}
+
+				var evaluations uint
+				converged, evaluations = solver.Iterate(fI, x+c[j]*h, ψ, scale, z, fz)
+				stats.Evaluations += evaluations
+
+				for i := 0; i < nd; i++ {
+					kI[j][i] = (z[i] - ψ[i]) / (γ * h)
+				}
+
+				fE(x+c[j]*h, z, kE[j])
+				stats.Evaluations++
+			}
+
+			if !converged {
+				// Try a fresh Jacobian matrix before shrinking the step size.
+				if !current {
+					jacobian()
+					hfactorized = 0
+					continue
+				}
+
+				stats.Rejections++
+
+				if h <= hmin {
+					return nil, nil, stats, errors.New("encountered a step-size underflow")
+				}
+
+				h = 0.5 * h
+				if h < hmin {
+					h = hmin
+				}
+
+				done = false
+				rejected = true
+				continue
+			}
+
+			for i := 0; i < nd; i++ {
+				ynew[i] = y[i] + h*(sum(b, kI, i)+sum(b, kE, i))
+			}
+
+			// Estimate the local error. Since the first stage is explicit, the
+			// estimate of the implicit part is contaminated by the stiff
+			// components of the current point, which are damped by applying
+			// (I - γhJ)⁻¹.
+			for i := 0; i < nd; i++ {
+				δ[i] = h * sum(e, kI, i)
+			}
+			stats.Evaluations += solver.Solve(δ)
+			for i := 0; i < nd; i++ {
+				δ[i] += h * sum(e, kE, i)
+			}
+
+			// Compute the relative error.
+			ε = 0
+			for i := 0; i < nd; i++ {
+				scale := math.Max(math.Max(math.Abs(y[i]), math.Abs(ynew[i])), threshold)
+				if e := math.Abs(δ[i]) / scale; e > ε {
+					ε = e
+				}
+			}
+
+			if ε <= relerr {
+				break
+			}
+
+			stats.Rejections++
+
+			if h <= hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			// Shrink the step size as the current one has been rejected.
+			if rejected {
+				h = 0.5 * h
+			} else {
+				h = h * math.Max(0.5, 0.8*math.Pow(relerr/ε, power))
+			}
+
+			if h < hmin {
+				h = hmin
+			}
+
+			done = false
+			rejected = true
+		}
+
+		fI(xnew, ynew, kI[ns])
+		fE(xnew, ynew, kE[ns])
+		stats.Evaluations += 2
+		for i := 0; i < nd; i++ {
+			f1[i] = kI[ns][i] + kE[ns][i]
+		}
+
+		if fixed {
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					copy(ys[nc*nd:(nc+1)*nd], ynew)
+				} else {
+					interpolate(x, y, f0, xnew, ynew, f1, xs[nc], ys[nc*nd:(nc+1)*nd])
+				}
+
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		if done {
+			break
+		}
+
+		x = xnew
+		copy(kI[0], kI[ns])
+		copy(kE[0], kE[ns])
+		copy(f0, f1)
+		copy(y, ynew)
+		current = false
+
+		if rejected {
+			continue
+		}
+
+		// Compute a new step size.
+		if scale := 1.25 * math.Pow(ε/relerr, power); scale > 0.2 {
+			h = h / scale
+		} else {
+			h = 5 * h
+		}
+	}
+
+	return ys, xs, stats, nil
+}
+
+func interpolate(x0 float64, y0, f0 []float64, x1 float64, y1, f1 []float64,
+	xnext float64, ynext []float64) {
+
+	h := x1 - x0
+	s := (xnext - x0) / h
+
+	h00 := (1 + 2*s) * (1 - s) * (1 - s)
+	h10 := s * (1 - s) * (1 - s)
+	h01 := s * s * (3 - 2*s)
+	h11 := s * s * (s - 1)
+
+	for i := range ynext {
+		ynext[i] = h00*y0[i] + h*h10*f0[i] + h01*y1[i] + h*h11*f1[i]
+	}
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
+
+// sum computes Σ w[k] f[k][i].
+func sum(w []float64, f [][]float64, i int) float64 {
+	s := 0.0
+	for k := range w {
+		s += w[k] * f[k][i]
+	}
+	return s
+}
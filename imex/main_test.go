@@ -0,0 +1,147 @@
+package imex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/sdirk"
+)
+
+func TestComputeDecay(t *testing.T) {
+	fI := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+	fE := func(x float64, _, f []float64) {
+		f[0] = math.Cos(x)
+	}
+
+	xs := []float64{0, 0.5, 1, 1.5, 2}
+
+	integrator, _ := New(&Config{AbsError: 1e-8, RelError: 1e-6})
+
+	ys, _, err := integrator.Compute(fI, fE, []float64{0.5}, xs)
+	assert.Equal(err, nil, t)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 5e-5, t)
+	}
+}
+
+// https://doi.org/10.1090/S0025-5718-1974-0331793-2
+func TestComputeProtheroRobinson(t *testing.T) {
+	const λ = -1e6
+
+	fI := func(x float64, y, f []float64) {
+		f[0] = λ * (y[0] - math.Cos(x))
+	}
+	fE := func(x float64, _, f []float64) {
+		f[0] = -math.Sin(x)
+	}
+	jacobian := func(_ float64, _, J []float64) {
+		J[0] = λ
+	}
+
+	config := DefaultConfig()
+	config.RelError = 1e-6
+	config.Jacobian = jacobian
+
+	integrator, _ := New(config)
+
+	ys, xs, stats, err := integrator.ComputeWithStats(fI, fE, []float64{1}, []float64{0, 10})
+	assert.Equal(err, nil, t)
+
+	n := len(xs)
+	assert.Equal(xs[n-1], 10.0, t)
+	assert.Close(ys[n-1], math.Cos(10), 1e-3, t)
+	assert.Equal(stats.Jacobians, uint(1), t)
+	assert.Equal(stats.Steps < 500, true, t)
+}
+
+func TestComputeReactionDiffusion(t *testing.T) {
+	const (
+		nd = 50
+		dx = 1.0 / (nd + 1)
+	)
+
+	// Diffusion with homogeneous Dirichlet conditions.
+	fI := func(_ float64, y, f []float64) {
+		for i := 0; i < nd; i++ {
+			f[i] = -2 * y[i]
+			if i > 0 {
+				f[i] += y[i-1]
+			}
+			if i < nd-1 {
+				f[i] += y[i+1]
+			}
+			f[i] /= dx * dx
+		}
+	}
+	// Logistic reaction.
+	fE := func(_ float64, y, f []float64) {
+		for i := 0; i < nd; i++ {
+			f[i] = y[i] * (1 - y[i])
+		}
+	}
+
+	y0 := make([]float64, nd)
+	for i := range y0 {
+		y0[i] = math.Sin(math.Pi * float64(i+1) * dx)
+	}
+
+	config := DefaultConfig()
+	config.AbsError = 1e-8
+	config.RelError = 1e-6
+
+	integrator, _ := New(config)
+
+	ys, xs, stats, err := integrator.ComputeWithStats(fI, fE, y0, []float64{0, 0.1})
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Steps < 500, true, t)
+
+	dydx := func(x float64, y, f []float64) {
+		g := make([]float64, nd)
+		fI(x, y, f)
+		fE(x, y, g)
+		for i := range f {
+			f[i] += g[i]
+		}
+	}
+
+	reference, _ := sdirk.New(&sdirk.Config{AbsError: 1e-10, RelError: 1e-8, Order: 4})
+	zs, zxs, _ := reference.Compute(dydx, y0, []float64{0, 0.1})
+
+	n, m := len(xs), len(zxs)
+	for i := 0; i < nd; i++ {
+		assert.Close(ys[(n-1)*nd+i], zs[(m-1)*nd+i], 1e-5, t)
+	}
+}
+
+func TestComputeOrder(t *testing.T) {
+	fI := func(_ float64, y, f []float64) {
+		f[0] = -2 * y[0]
+		f[1] = -y[1]
+	}
+	fE := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	exact := func(x float64) (float64, float64) {
+		// The eigenvalues of [[-2, 1], [-1, -1]] are (-3 ± i√3)/2.
+		ω := math.Sqrt(3) / 2
+		e := math.Exp(-1.5 * x)
+		s, c := math.Sincos(ω * x)
+		return e * (c - s/(2*ω)), e * (-s / ω)
+	}
+
+	errors := make([]float64, 2)
+	for k, h := range []float64{0.02, 0.01} {
+		integrator, _ := New(&Config{TryStep: h, MaxStep: h, AbsError: 1, RelError: 1})
+		ys, xs, _ := integrator.Compute(fI, fE, []float64{1, 0}, []float64{0, 1})
+		n := len(xs)
+		y1, y2 := exact(xs[n-1])
+		errors[k] = math.Hypot(ys[(n-1)*2]-y1, ys[(n-1)*2+1]-y2)
+	}
+
+	assert.Close(math.Log2(errors[0]/errors[1]), 3.0, 0.2, t)
+}
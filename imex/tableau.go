@@ -0,0 +1,46 @@
+package imex
+
+// The additive Runge–Kutta method ARK3(2)4L[2]SA of order three due to
+// Kennedy and Carpenter with an embedded method of order two. The explicit
+// part is given by ae, and the implicit part, which is an L-stable and stiffly
+// accurate ESDIRK method with the diagonal element γ, is given by ai. Both
+// parts share the weights b and the nodes c.
+const (
+	γ = 1767732205903.0 / 4055673282236
+)
+
+var (
+	ae = [][]float64{
+		{},
+		{1767732205903.0 / 2027836641118},
+		{5535828885825.0 / 10492691773637, 788022342437.0 / 10882634858940},
+		{
+			6485989280629.0 / 16251701735622,
+			-4246266847089.0 / 9704473918619,
+			10755448449292.0 / 10357097424841,
+		},
+	}
+	ai = [][]float64{
+		{},
+		{γ},
+		{2746238789719.0 / 10658868560708, -640167445237.0 / 6845629431997},
+		{
+			1471266399579.0 / 7840856788654,
+			-4482444167858.0 / 7529755066697,
+			11266239266428.0 / 11593286722821,
+		},
+	}
+	b = []float64{
+		1471266399579.0 / 7840856788654,
+		-4482444167858.0 / 7529755066697,
+		11266239266428.0 / 11593286722821,
+		γ,
+	}
+	c = []float64{0, 1767732205903.0 / 2027836641118, 3.0 / 5, 1}
+	e = []float64{
+		b[0] - 2756255671327.0/12835298489170,
+		b[1] + 10771552573575.0/22201958757719,
+		b[2] - 9247589265047.0/10645013368117,
+		b[3] - 2193209047091.0/5459859503100,
+	}
+)
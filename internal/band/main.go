@@ -0,0 +1,129 @@
+// Package band provides the LU decomposition of banded matrices needed by the
+// solvers of boundary value problems.
+package band
+
+import (
+	"errors"
+	"math"
+)
+
+// LU is an LU decomposition with partial pivoting of an n-by-n matrix with ml
+// subdiagonals and mu superdiagonals.
+//
+// The matrix is given in the banded layout in which each row stores the
+// ml + mu + 1 entries of the band in order; that is, the entry in the ith row
+// and jth column is stored at A[i*(ml+mu+1)+j-i+ml]. The entries outside the
+// matrix are ignored.
+type LU struct {
+	n, ml, mu int
+
+	w      int
+	a      []float64
+	pivots []int
+}
+
+// NewLU allocates an LU decomposition of an n-by-n matrix with ml subdiagonals
+// and mu superdiagonals.
+func NewLU(n, ml, mu uint) *LU {
+	// Pivoting fills up to ml additional superdiagonals.
+	w := int(2*ml + mu + 1)
+	return &LU{
+		n:  int(n),
+		ml: int(ml),
+		mu: int(mu),
+
+		w:      w,
+		a:      make([]float64, int(n)*w),
+		pivots: make([]int, n),
+	}
+}
+
+// Index returns the position of the entry in the ith row and jth column in the
+// banded layout.
+func Index(i, j, ml, mu uint) uint {
+	return i*(ml+mu+1) + j + ml - i
+}
+
+// Factorize computes the decomposition of a matrix in the banded layout. The
+// matrix is not modified.
+func (self *LU) Factorize(A []float64) error {
+	n, ml, mu, w, a, pivots := self.n, self.ml, self.mu, self.w, self.a, self.pivots
+
+	// The ith row stores the columns from i - ml to i - ml + w - 1.
+	at := func(i, j int) *float64 {
+		return &a[i*w+j-i+ml]
+	}
+
+	for i := range a {
+		a[i] = 0
+	}
+	for i := 0; i < n; i++ {
+		for j := max(0, i-ml); j <= min(n-1, i+mu); j++ {
+			*at(i, j) = A[i*(ml+mu+1)+j-i+ml]
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		last := min(n-1, k+ml)
+		right := min(n-1, k+ml+mu)
+
+		p, value := k, math.Abs(*at(k, k))
+		for i := k + 1; i <= last; i++ {
+			if v := math.Abs(*at(i, k)); v > value {
+				p, value = i, v
+			}
+		}
+		pivots[k] = p
+
+		if value == 0 {
+			return errors.New("the matrix is singular")
+		}
+
+		if p != k {
+			for j := k; j <= right; j++ {
+				*at(k, j), *at(p, j) = *at(p, j), *at(k, j)
+			}
+		}
+
+		pivot := *at(k, k)
+		for i := k + 1; i <= last; i++ {
+			l := *at(i, k) / pivot
+			*at(i, k) = l
+			if l == 0 {
+				continue
+			}
+			for j := k + 1; j <= right; j++ {
+				*at(i, j) -= l * *at(k, j)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Solve solves the system of linear equations A x = b using the decomposition
+// of A. The solution overwrites b.
+func (self *LU) Solve(b []float64) {
+	n, ml, mu, w, a, pivots := self.n, self.ml, self.mu, self.w, self.a, self.pivots
+
+	at := func(i, j int) float64 {
+		return a[i*w+j-i+ml]
+	}
+
+	for k := 0; k < n; k++ {
+		if p := pivots[k]; p != k {
+			b[k], b[p] = b[p], b[k]
+		}
+		for i := k + 1; i <= min(n-1, k+ml); i++ {
+			b[i] -= at(i, k) * b[k]
+		}
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		s := b[i]
+		for j := i + 1; j <= min(n-1, i+ml+mu); j++ {
+			s -= at(i, j) * b[j]
+		}
+		b[i] = s / at(i, i)
+	}
+}
@@ -0,0 +1,48 @@
+package band
+
+import (
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/internal/dense"
+)
+
+func TestLU(t *testing.T) {
+	const (
+		n  = 7
+		ml = 2
+		mu = 1
+	)
+
+	// A matrix that requires pivoting as its diagonal is zero.
+	full := make([]float64, n*n)
+	A := make([]float64, n*(ml+mu+1))
+	for i := uint(0); i < n; i++ {
+		for j := uint(0); j < n; j++ {
+			if j+ml < i || j > i+mu || i == j {
+				continue
+			}
+			v := float64(1+i) + 0.5*float64(j) - 0.1*float64(i*j)
+			full[i*n+j] = v
+			A[Index(i, j, ml, mu)] = v
+		}
+	}
+
+	b := []float64{1, -2, 3, 0.5, -1, 2, 4}
+	x := append([]float64(nil), b...)
+
+	lu := NewLU(n, ml, mu)
+	assert.Equal(lu.Factorize(A), nil, t)
+	lu.Solve(b)
+
+	reference := dense.NewLU(n)
+	assert.Equal(reference.Factorize(full), nil, t)
+	reference.Solve(x)
+
+	assert.Close(b, x, 1e-12, t)
+}
+
+func TestLUSingular(t *testing.T) {
+	lu := NewLU(2, 1, 1)
+	assert.Equal(lu.Factorize([]float64{0, 1, 2, 2, 4, 0}) != nil, true, t)
+}
@@ -0,0 +1,91 @@
+package dense
+
+import (
+	"errors"
+	"math/cmplx"
+)
+
+// ComplexLU is an LU decomposition with partial pivoting of a complex matrix.
+type ComplexLU struct {
+	n      uint
+	a      []complex128
+	pivots []uint
+}
+
+// NewComplexLU allocates an LU decomposition of an n-by-n complex matrix.
+func NewComplexLU(n uint) *ComplexLU {
+	return &ComplexLU{
+		n:      n,
+		a:      make([]complex128, n*n),
+		pivots: make([]uint, n),
+	}
+}
+
+// Factorize computes the decomposition of a matrix. The matrix is not
+// modified.
+func (self *ComplexLU) Factorize(A []complex128) error {
+	n, a, pivots := self.n, self.a, self.pivots
+
+	copy(a, A)
+
+	for k := uint(0); k < n; k++ {
+		p, max := k, cmplx.Abs(a[k*n+k])
+		for i := k + 1; i < n; i++ {
+			if v := cmplx.Abs(a[i*n+k]); v > max {
+				p, max = i, v
+			}
+		}
+		pivots[k] = p
+
+		if max == 0 {
+			return errors.New("the matrix is singular")
+		}
+
+		if p != k {
+			for j := uint(0); j < n; j++ {
+				a[k*n+j], a[p*n+j] = a[p*n+j], a[k*n+j]
+			}
+		}
+
+		for i := k + 1; i < n; i++ {
+			a[i*n+k] /= a[k*n+k]
+			l := a[i*n+k]
+			if l == 0 {
+				continue
+			}
+			for j := k + 1; j < n; j++ {
+				a[i*n+j] -= l * a[k*n+j]
+			}
+		}
+	}
+
+	return nil
+}
+
+// Solve solves the system of linear equations A x = b using the decomposition
+// of A. The solution overwrites b.
+func (self *ComplexLU) Solve(b []complex128) {
+	n, a, pivots := self.n, self.a, self.pivots
+
+	for k := uint(0); k < n; k++ {
+		if p := pivots[k]; p != k {
+			b[k], b[p] = b[p], b[k]
+		}
+	}
+
+	for i := uint(1); i < n; i++ {
+		s := b[i]
+		for j := uint(0); j < i; j++ {
+			s -= a[i*n+j] * b[j]
+		}
+		b[i] = s
+	}
+
+	for i := int(n) - 1; i >= 0; i-- {
+		s := b[i]
+		for j := uint(i) + 1; j < n; j++ {
+			s -= a[uint(i)*n+j] * b[j]
+		}
+		b[i] = s / a[uint(i)*n+uint(i)]
+	}
+}
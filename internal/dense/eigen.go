@@ -0,0 +1,240 @@
+package dense
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	maxEigenIterations = 30
+)
+
+// Eigenvalues computes the eigenvalues of an n-by-n matrix. The matrix is
+// reduced to the upper Hessenberg form by stabilized elementary similarity
+// transformations, and the eigenvalues of the latter are computed by the
+// Francis double-shift QR algorithm. The matrix is not modified.
+func Eigenvalues(A []float64, n uint) ([]complex128, error) {
+	// The algorithm is stated with indices starting from one.
+	N := int(n)
+	a := make([][]float64, N+1)
+	for i := 1; i <= N; i++ {
+		a[i] = make([]float64, N+1)
+		copy(a[i][1:], A[(i-1)*N:i*N])
+	}
+
+	hessenberg(a, N)
+
+	wr, wi := make([]float64, N+1), make([]float64, N+1)
+
+	anorm := 0.0
+	for i := 1; i <= N; i++ {
+		for j := i - 1; j <= N; j++ {
+			if j == 0 {
+				continue
+			}
+			anorm += math.Abs(a[i][j])
+		}
+	}
+
+	var p, q, r, s, t, w, x, y, z float64
+	for nn := N; nn >= 1; {
+		its := 0
+		for {
+			// Look for a single small subdiagonal element.
+			l := nn
+			for ; l >= 2; l-- {
+				s = math.Abs(a[l-1][l-1]) + math.Abs(a[l][l])
+				if s == 0 {
+					s = anorm
+				}
+				if math.Abs(a[l][l-1])+s == s {
+					a[l][l-1] = 0
+					break
+				}
+			}
+
+			x = a[nn][nn]
+			if l == nn {
+				// One root has been found.
+				wr[nn], wi[nn] = x+t, 0
+				nn--
+				break
+			}
+
+			y = a[nn-1][nn-1]
+			w = a[nn][nn-1] * a[nn-1][nn]
+			if l == nn-1 {
+				// Two roots have been found.
+				p = 0.5 * (y - x)
+				q = p*p + w
+				z = math.Sqrt(math.Abs(q))
+				x += t
+				if q >= 0 {
+					z = p + math.Copysign(z, p)
+					wr[nn-1], wr[nn] = x+z, x+z
+					if z != 0 {
+						wr[nn] = x - w/z
+					}
+					wi[nn-1], wi[nn] = 0, 0
+				} else {
+					wr[nn-1], wr[nn] = x+p, x+p
+					wi[nn-1], wi[nn] = -z, z
+				}
+				nn -= 2
+				break
+			}
+
+			if its == maxEigenIterations {
+				return nil, errors.New("the eigenvalues have not converged")
+			}
+			if its == 10 || its == 20 {
+				// Use an exceptional shift.
+				t += x
+				for i := 1; i <= nn; i++ {
+					a[i][i] -= x
+				}
+				s = math.Abs(a[nn][nn-1]) + math.Abs(a[nn-1][nn-2])
+				x, y = 0.75*s, 0.75*s
+				w = -0.4375 * s * s
+			}
+			its++
+
+			// Look for two consecutive small subdiagonal elements.
+			m := nn - 2
+			for ; m >= l; m-- {
+				z = a[m][m]
+				r = x - z
+				s = y - z
+				p = (r*s-w)/a[m+1][m] + a[m][m+1]
+				q = a[m+1][m+1] - z - r - s
+				r = a[m+2][m+1]
+				s = math.Abs(p) + math.Abs(q) + math.Abs(r)
+				p /= s
+				q /= s
+				r /= s
+				if m == l {
+					break
+				}
+				u := math.Abs(a[m][m-1]) * (math.Abs(q) + math.Abs(r))
+				v := math.Abs(p) * (math.Abs(a[m-1][m-1]) + math.Abs(z) +
+					math.Abs(a[m+1][m+1]))
+				if u+v == v {
+					break
+				}
+			}
+			for i := m + 2; i <= nn; i++ {
+				a[i][i-2] = 0
+				if i != m+2 {
+					a[i][i-3] = 0
+				}
+			}
+
+			// Perform a double QR step on the rows from l to nn and the
+			// columns from m to nn.
+			for k := m; k <= nn-1; k++ {
+				if k != m {
+					p = a[k][k-1]
+					q = a[k+1][k-1]
+					r = 0
+					if k != nn-1 {
+						r = a[k+2][k-1]
+					}
+					if x = math.Abs(p) + math.Abs(q) + math.Abs(r); x != 0 {
+						p /= x
+						q /= x
+						r /= x
+					}
+				}
+				if s = math.Copysign(math.Sqrt(p*p+q*q+r*r), p); s == 0 {
+					continue
+				}
+				if k == m {
+					if l != m {
+						a[k][k-1] = -a[k][k-1]
+					}
+				} else {
+					a[k][k-1] = -s * x
+				}
+				p += s
+				x = p / s
+				y = q / s
+				z = r / s
+				q /= p
+				r /= p
+				for j := k; j <= nn; j++ {
+					p = a[k][j] + q*a[k+1][j]
+					if k != nn-1 {
+						p += r * a[k+2][j]
+						a[k+2][j] -= p * z
+					}
+					a[k+1][j] -= p * y
+					a[k][j] -= p * x
+				}
+				last := k + 3
+				if last > nn {
+					last = nn
+				}
+				for i := l; i <= last; i++ {
+					p = x*a[i][k] + y*a[i][k+1]
+					if k != nn-1 {
+						p += z * a[i][k+2]
+						a[i][k+2] -= p * r
+					}
+					a[i][k+1] -= p * q
+					a[i][k] -= p
+				}
+			}
+		}
+	}
+
+	λ := make([]complex128, N)
+	for i := 0; i < N; i++ {
+		λ[i] = complex(wr[i+1], wi[i+1])
+	}
+
+	return λ, nil
+}
+
+// hessenberg reduces a matrix indexed starting from one to the upper
+// Hessenberg form by Gaussian elimination with pivoting.
+func hessenberg(a [][]float64, n int) {
+	for m := 2; m < n; m++ {
+		x, i := 0.0, m
+		for j := m; j <= n; j++ {
+			if math.Abs(a[j][m-1]) > math.Abs(x) {
+				x, i = a[j][m-1], j
+			}
+		}
+		if i != m {
+			for j := m - 1; j <= n; j++ {
+				a[i][j], a[m][j] = a[m][j], a[i][j]
+			}
+			for j := 1; j <= n; j++ {
+				a[j][i], a[j][m] = a[j][m], a[j][i]
+			}
+		}
+		if x == 0 {
+			continue
+		}
+		for i := m + 1; i <= n; i++ {
+			y := a[i][m-1]
+			if y == 0 {
+				continue
+			}
+			y /= x
+			a[i][m-1] = y
+			for j := m; j <= n; j++ {
+				a[i][j] -= y * a[m][j]
+			}
+			for j := 1; j <= n; j++ {
+				a[j][m] += y * a[j][i]
+			}
+		}
+	}
+
+	for i := 3; i <= n; i++ {
+		for j := 1; j < i-1; j++ {
+			a[i][j] = 0
+		}
+	}
+}
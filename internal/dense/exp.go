@@ -0,0 +1,167 @@
+package dense
+
+import (
+	"math"
+)
+
+// The degrees of the diagonal Padé approximations, their maximal norms, and
+// their coefficients following Higham (2005).
+var (
+	padeDegrees = [...]uint{3, 5, 7, 9, 13}
+	padeNorms   = [...]float64{
+		1.495585217958292e-2,
+		2.539398330063230e-1,
+		9.504178996162932e-1,
+		2.097847961257068e+0,
+		5.371920351148152e+0,
+	}
+	padeCoefficients = [...][]float64{
+		{120, 60, 12, 1},
+		{30240, 15120, 3360, 420, 30, 1},
+		{17297280, 8648640, 1995840, 277200, 25200, 1512, 56, 1},
+		{17643225600, 8821612800, 2075673600, 302702400, 30270240, 2162160, 110880,
+			3960, 90, 1},
+		{64764752532480000, 32382376266240000, 7771770303897600, 1187353796428800,
+			129060195264000, 10559470521600, 670442572800, 33522128640, 1323241920,
+			40840800, 960960, 16380, 182, 1},
+	}
+)
+
+// Exp computes the exponential of an n-by-n matrix using scaling and squaring
+// with a diagonal Padé approximation. The matrix is not modified, and the
+// result is stored in E.
+func Exp(A []float64, n uint, E []float64) error {
+	nn := n * n
+
+	norm := 0.0
+	for j := uint(0); j < n; j++ {
+		sum := 0.0
+		for i := uint(0); i < n; i++ {
+			sum += math.Abs(A[i*n+j])
+		}
+		norm = math.Max(norm, sum)
+	}
+
+	// Choose the degree of the approximation and, for the highest degree, the
+	// number of squarings.
+	last := len(padeDegrees) - 1
+	m, s := last, 0
+	for k := 0; k < last; k++ {
+		if norm <= padeNorms[k] {
+			m = k
+			break
+		}
+	}
+	if m == last && norm > padeNorms[last] {
+		s = int(math.Ceil(math.Log2(norm / padeNorms[last])))
+	}
+	scale := math.Ldexp(1, -s)
+
+	a := make([]float64, nn)
+	for i := range a {
+		a[i] = scale * A[i]
+	}
+
+	b := padeCoefficients[m]
+
+	// The even powers of the matrix up to the degree of the approximation.
+	powers := [][]float64{identity(n), make([]float64, nn)}
+	Multiply(a, a, n, powers[1])
+
+	U := make([]float64, nn)
+	V := make([]float64, nn)
+	W := make([]float64, nn)
+
+	if padeDegrees[m] < 13 {
+		for j := uint(2); 2*j < padeDegrees[m]; j++ {
+			power := make([]float64, nn)
+			Multiply(powers[j-1], powers[1], n, power)
+			powers = append(powers, power)
+		}
+		for j := range powers {
+			for i := range W {
+				W[i] += b[2*j+1] * powers[j][i]
+				V[i] += b[2*j] * powers[j][i]
+			}
+		}
+	} else {
+		A2 := powers[1]
+		A4 := make([]float64, nn)
+		A6 := make([]float64, nn)
+		Multiply(A2, A2, n, A4)
+		Multiply(A4, A2, n, A6)
+
+		T := make([]float64, nn)
+		for i := range T {
+			T[i] = b[13]*A6[i] + b[11]*A4[i] + b[9]*A2[i]
+		}
+		Multiply(A6, T, n, W)
+		for i := range T {
+			T[i] = b[12]*A6[i] + b[10]*A4[i] + b[8]*A2[i]
+		}
+		Multiply(A6, T, n, V)
+		for i := uint(0); i < n; i++ {
+			W[i*n+i] += b[1]
+			V[i*n+i] += b[0]
+		}
+		for i := range W {
+			W[i] += b[7]*A6[i] + b[5]*A4[i] + b[3]*A2[i]
+			V[i] += b[6]*A6[i] + b[4]*A4[i] + b[2]*A2[i]
+		}
+	}
+	Multiply(a, W, n, U)
+
+	// Solve (V - U) E = V + U.
+	for i := range W {
+		W[i] = V[i] - U[i]
+		V[i] += U[i]
+	}
+	lu := NewLU(n)
+	if err := lu.Factorize(W); err != nil {
+		return err
+	}
+	column := make([]float64, n)
+	for j := uint(0); j < n; j++ {
+		for i := uint(0); i < n; i++ {
+			column[i] = V[i*n+j]
+		}
+		lu.Solve(column)
+		for i := uint(0); i < n; i++ {
+			E[i*n+j] = column[i]
+		}
+	}
+
+	for ; s > 0; s-- {
+		Multiply(E, E, n, W)
+		copy(E, W)
+	}
+
+	return nil
+}
+
+// Multiply computes the product of two n-by-n matrices and stores it in C,
+// which should not coincide with A or B.
+func Multiply(A, B []float64, n uint, C []float64) {
+	for i := uint(0); i < n; i++ {
+		for j := uint(0); j < n; j++ {
+			C[i*n+j] = 0
+		}
+		for k := uint(0); k < n; k++ {
+			a := A[i*n+k]
+			if a == 0 {
+				continue
+			}
+			for j := uint(0); j < n; j++ {
+				C[i*n+j] += a * B[k*n+j]
+			}
+		}
+	}
+}
+
+func identity(n uint) []float64 {
+	I := make([]float64, n*n)
+	for i := uint(0); i < n; i++ {
+		I[i*n+i] = 1
+	}
+	return I
+}
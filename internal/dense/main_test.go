@@ -0,0 +1,94 @@
+package dense
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestLU(t *testing.T) {
+	A := []float64{
+		0, 2, 1,
+		1, 1, 0,
+		3, 0, 4,
+	}
+	b := []float64{7, 3, 15}
+
+	lu := NewLU(3)
+	assert.Equal(lu.Factorize(A), nil, t)
+
+	lu.Solve(b)
+	assert.Close(b, []float64{1, 2, 3}, 1e-14, t)
+}
+
+func TestLUSingular(t *testing.T) {
+	lu := NewLU(2)
+	assert.Equal(lu.Factorize([]float64{1, 2, 2, 4}) != nil, true, t)
+}
+
+func TestComplexLU(t *testing.T) {
+	A := []complex128{
+		1 + 1i, 2,
+		0, 1i,
+	}
+	b := []complex128{4i, -1}
+
+	lu := NewComplexLU(2)
+	assert.Equal(lu.Factorize(A), nil, t)
+
+	lu.Solve(b)
+	assert.Close([]float64{real(b[0]), imag(b[0]), real(b[1]), imag(b[1])},
+		[]float64{1, 1, 0, 1}, 1e-14, t)
+}
+
+func TestExp(t *testing.T) {
+	// The rotation generator, whose exponential is a rotation matrix.
+	A := []float64{
+		0, -3,
+		3, 0,
+	}
+
+	E := make([]float64, 4)
+	assert.Equal(Exp(A, 2, E), nil, t)
+	assert.Close(E, []float64{math.Cos(3), -math.Sin(3), math.Sin(3), math.Cos(3)}, 1e-14, t)
+
+	A = []float64{
+		-1, 1,
+		0, -2,
+	}
+
+	assert.Equal(Exp(A, 2, E), nil, t)
+	assert.Close(E, []float64{math.Exp(-1), math.Exp(-1) - math.Exp(-2), 0, math.Exp(-2)}, 1e-15, t)
+}
+
+func TestEigenvalues(t *testing.T) {
+	// The companion matrix of (x - 1)(x - 2)(x² + 2x + 5).
+	A := []float64{
+		1, -1, 11, -10,
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+	}
+
+	λ, err := Eigenvalues(A, 4)
+	assert.Equal(err, nil, t)
+
+	sort.Slice(λ, func(i, j int) bool {
+		if real(λ[i]) != real(λ[j]) {
+			return real(λ[i]) < real(λ[j])
+		}
+		return imag(λ[i]) < imag(λ[j])
+	})
+
+	actual := make([]float64, 0, 8)
+	for _, v := range λ {
+		actual = append(actual, real(v), imag(v))
+	}
+	assert.Close(actual, []float64{-1, -2, -1, 2, 1, 0, 2, 0}, 1e-12, t)
+
+	λ, err = Eigenvalues([]float64{0, -3, 3, 0}, 2)
+	assert.Equal(err, nil, t)
+	assert.Close([]float64{real(λ[0]), math.Abs(imag(λ[0]))}, []float64{0, 3}, 1e-14, t)
+}
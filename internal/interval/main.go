@@ -0,0 +1,42 @@
+// Package interval provides the checks of the input of integrators, which
+// are the initial condition and the points of the interval of integration.
+package interval
+
+import (
+	"errors"
+)
+
+// Validate checks that the initial condition is not empty and that the points
+// of the interval are strictly monotonic, so that they all lie within it. The
+// points can be either increasing or decreasing.
+func Validate(y0, xs []float64) error {
+	if len(y0) == 0 {
+		return errors.New("the initial condition should not be empty")
+	}
+	nx := len(xs)
+	if nx < 2 {
+		return errors.New("the interval should have two endpoints")
+	}
+	dir := 1.0
+	if xs[nx-1] < xs[0] {
+		dir = -1
+	}
+	for i := 1; i < nx; i++ {
+		if !(dir*(xs[i]-xs[i-1]) > 0) {
+			return errors.New("the points of the interval should be strictly monotonic")
+		}
+	}
+	return nil
+}
+
+// Forward is Validate for integrators that do not integrate backward, which
+// require the points of the interval to be strictly increasing.
+func Forward(y0, xs []float64) error {
+	if err := Validate(y0, xs); err != nil {
+		return err
+	}
+	if xs[len(xs)-1] < xs[0] {
+		return errors.New("the points of the interval should be strictly increasing")
+	}
+	return nil
+}
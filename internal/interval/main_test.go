@@ -0,0 +1,24 @@
+package interval
+
+import (
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.Equal(Validate([]float64{1}, []float64{0, 0.5, 1}), nil, t)
+	assert.Equal(Validate([]float64{1}, []float64{1, 0.5, 0}), nil, t)
+
+	assert.Equal(Validate(nil, []float64{0, 1}) != nil, true, t)
+	assert.Equal(Validate([]float64{1}, []float64{0}) != nil, true, t)
+	assert.Equal(Validate([]float64{1}, []float64{0, 0.5, 0.2, 1}) != nil, true, t)
+	assert.Equal(Validate([]float64{1}, []float64{0, 0, 1}) != nil, true, t)
+}
+
+func TestForward(t *testing.T) {
+	assert.Equal(Forward([]float64{1}, []float64{0, 0.5, 1}), nil, t)
+
+	assert.Equal(Forward([]float64{1}, []float64{1, 0.5, 0}) != nil, true, t)
+	assert.Equal(Forward(nil, []float64{0, 1}) != nil, true, t)
+}
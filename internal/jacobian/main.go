@@ -0,0 +1,267 @@
+// Package jacobian provides finite-difference approximations of the Jacobian
+// matrices of the right-hand sides of systems of differential equations. The
+// matrices are either dense and stored in row-major order, see ode.Jacobian,
+// or sparse and stored in the order of a sparsity pattern, see ode.Pattern.
+//
+// In the sparse case, the columns are partitioned into groups such that no two
+// columns of a group have nonzero entries in the same row, which is a coloring
+// of the column-intersection graph of the matrix computed greedily. All the
+// columns of a group are then perturbed simultaneously, and the number of
+// evaluations of the right-hand side per matrix is proportional to the number
+// of groups instead of the number of columns.
+package jacobian
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode/internal/sparse"
+)
+
+// Scheme is a finite-difference scheme.
+type Scheme uint
+
+const (
+	// Forward differences, which are of order one and take nd evaluations of
+	// the right-hand side per matrix given its value at the point.
+	Forward Scheme = iota
+	// Central differences, which are of order two and take 2×nd evaluations of
+	// the right-hand side per matrix.
+	Central
+)
+
+const (
+	epsilon = 2.220446049250313e-16
+
+	minTypical = 1e-5
+)
+
+// Approximator approximates Jacobian matrices using finite differences.
+//
+// The jth column is computed by perturbing the jth component of y by
+// η max(|y[j]|, typical[j]) where η is the square root of the machine epsilon
+// for forward differences and its cube root for central ones, and typical[j]
+// is the typical magnitude of the jth component, which prevents the
+// perturbation from vanishing when the component passes through zero.
+type Approximator struct {
+	// The finite-difference scheme.
+	Scheme Scheme
+	// The typical magnitudes of the components of the solution. If it is not
+	// given, the magnitudes are assumed to be 1e-5.
+	Typical []float64
+
+	// The number of evaluations of the right-hand side performed so far.
+	Evaluations uint
+
+	z  []float64
+	fz []float64
+	fw []float64
+	δ  []float64
+
+	size   uint
+	groups [][]uint
+	rows   [][]uint
+	places [][]uint
+}
+
+// New creates an approximator for systems with nd unknowns. If pattern is not
+// nil, the matrices are sparse with the given pattern, which is assumed to be
+// valid.
+func New(nd uint, pattern [][]uint) *Approximator {
+	self := &Approximator{
+		z:  make([]float64, nd),
+		fz: make([]float64, nd),
+		fw: make([]float64, nd),
+		δ:  make([]float64, nd),
+
+		size: nd * nd,
+	}
+	if pattern != nil {
+		self.size = sparse.Count(pattern)
+		self.rows, self.places = transpose(nd, pattern)
+		self.groups = color(nd, pattern, self.rows)
+	}
+	return self
+}
+
+// Size returns the number of entries of the matrices.
+func (self *Approximator) Size() uint {
+	return self.size
+}
+
+// Groups returns the number of groups of columns perturbed simultaneously,
+// which is nd for dense matrices.
+func (self *Approximator) Groups() uint {
+	if self.groups == nil {
+		return uint(len(self.z))
+	}
+	return uint(len(self.groups))
+}
+
+// Compute approximates the Jacobian matrix of dydx at (x, y) and stores it in
+// J. The value of dydx at (x, y) is given by f, which is needed only by the
+// forward scheme. The function returns the number of evaluations of dydx,
+// which is also added to Evaluations.
+func (self *Approximator) Compute(dydx func(float64, []float64, []float64), x float64,
+	y, f, J []float64) uint {
+
+	if self.groups != nil {
+		return self.computeSparse(dydx, x, y, f, J)
+	}
+
+	nd := len(y)
+	z, fz, fw := self.z, self.fz, self.fw
+
+	η := self.factor()
+
+	evaluations := uint(0)
+
+	copy(z, y)
+	for j := 0; j < nd; j++ {
+		δ := self.perturbation(η, y, uint(j))
+
+		switch self.Scheme {
+		case Central:
+			z[j] = y[j] + δ
+			δ1 := z[j] - y[j]
+			dydx(x, z, fz)
+			z[j] = y[j] - δ
+			δ2 := y[j] - z[j]
+			dydx(x, z, fw)
+			for i := 0; i < nd; i++ {
+				J[i*nd+j] = (fz[i] - fw[i]) / (δ1 + δ2)
+			}
+			evaluations += 2
+		default:
+			z[j] = y[j] + δ
+			δ = z[j] - y[j]
+			dydx(x, z, fz)
+			for i := 0; i < nd; i++ {
+				J[i*nd+j] = (fz[i] - f[i]) / δ
+			}
+			evaluations++
+		}
+
+		z[j] = y[j]
+	}
+
+	self.Evaluations += evaluations
+
+	return evaluations
+}
+
+func (self *Approximator) computeSparse(dydx func(float64, []float64, []float64),
+	x float64, y, f, J []float64) uint {
+
+	z, fz, fw, δ := self.z, self.fz, self.fw, self.δ
+
+	η := self.factor()
+
+	evaluations := uint(0)
+
+	copy(z, y)
+	for _, group := range self.groups {
+		switch self.Scheme {
+		case Central:
+			for _, j := range group {
+				z[j] = y[j] + self.perturbation(η, y, j)
+			}
+			dydx(x, z, fz)
+			for _, j := range group {
+				δ[j] = z[j] - y[j]
+				z[j] = 2*y[j] - z[j]
+			}
+			dydx(x, z, fw)
+			for _, j := range group {
+				δ[j] += y[j] - z[j]
+				for k, i := range self.rows[j] {
+					J[self.places[j][k]] = (fz[i] - fw[i]) / δ[j]
+				}
+				z[j] = y[j]
+			}
+			evaluations += 2
+		default:
+			for _, j := range group {
+				z[j] = y[j] + self.perturbation(η, y, j)
+				δ[j] = z[j] - y[j]
+			}
+			dydx(x, z, fz)
+			for _, j := range group {
+				for k, i := range self.rows[j] {
+					J[self.places[j][k]] = (fz[i] - f[i]) / δ[j]
+				}
+				z[j] = y[j]
+			}
+			evaluations++
+		}
+	}
+
+	self.Evaluations += evaluations
+
+	return evaluations
+}
+
+func (self *Approximator) factor() float64 {
+	if self.Scheme == Central {
+		return math.Cbrt(epsilon)
+	}
+	return math.Sqrt(epsilon)
+}
+
+func (self *Approximator) perturbation(η float64, y []float64, j uint) float64 {
+	typical := minTypical
+	if self.Typical != nil {
+		typical = self.Typical[j]
+	}
+	return η * math.Max(math.Abs(y[j]), typical)
+}
+
+// transpose computes, for each column of a pattern, the rows of its nonzero
+// entries and the positions of these entries in the order of the pattern.
+func transpose(nd uint, pattern [][]uint) ([][]uint, [][]uint) {
+	rows := make([][]uint, nd)
+	places := make([][]uint, nd)
+	offset := uint(0)
+	for i, row := range pattern {
+		for _, j := range row {
+			rows[j] = append(rows[j], uint(i))
+			places[j] = append(places[j], offset)
+			offset++
+		}
+	}
+	return rows, places
+}
+
+// color partitions the columns of a pattern into groups of structurally
+// orthogonal columns using the greedy algorithm.
+func color(nd uint, pattern [][]uint, rows [][]uint) [][]uint {
+	colors := make([]int, nd)
+	for j := range colors {
+		colors[j] = -1
+	}
+	forbidden := make([]int, nd)
+	for k := range forbidden {
+		forbidden[k] = -1
+	}
+
+	var groups [][]uint
+	for j := 0; j < int(nd); j++ {
+		for _, i := range rows[j] {
+			for _, k := range pattern[i] {
+				if c := colors[k]; c >= 0 {
+					forbidden[c] = j
+				}
+			}
+		}
+		c := 0
+		for c < len(groups) && forbidden[c] == j {
+			c++
+		}
+		if c == len(groups) {
+			groups = append(groups, nil)
+		}
+		colors[j] = c
+		groups[c] = append(groups[c], uint(j))
+	}
+
+	return groups
+}
@@ -0,0 +1,96 @@
+package jacobian
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeForward(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[0] * y[1]
+		f[1] = y[0] + 3*y[1]
+	}
+
+	y, f := []float64{2, -1}, make([]float64, 2)
+	dydx(0, y, f)
+
+	J := make([]float64, 4)
+	approximator := New(2, nil)
+	assert.Equal(approximator.Compute(dydx, 0, y, f, J), uint(2), t)
+	assert.Equal(approximator.Evaluations, uint(2), t)
+
+	assert.Close(J, []float64{-1, 2, 1, 3}, 1e-7, t)
+}
+
+func TestComputeCentral(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = math.Sin(y[0]) * y[1]
+		f[1] = y[0] * y[0]
+	}
+
+	y := []float64{0.5, 2}
+
+	J := make([]float64, 4)
+	approximator := New(2, nil)
+	approximator.Scheme = Central
+	approximator.Typical = []float64{1, 1}
+	assert.Equal(approximator.Compute(dydx, 0, y, nil, J), uint(4), t)
+	assert.Equal(approximator.Evaluations, uint(4), t)
+
+	assert.Close(J, []float64{2 * math.Cos(0.5), math.Sin(0.5), 1, 0}, 1e-9, t)
+}
+
+func TestComputeSparse(t *testing.T) {
+	// A tridiagonal system, whose columns form three groups.
+	const nd = 6
+
+	dydx := func(_ float64, y, f []float64) {
+		for i := 0; i < nd; i++ {
+			f[i] = -2 * y[i] * y[i]
+			if i > 0 {
+				f[i] += y[i-1]
+			}
+			if i < nd-1 {
+				f[i] += 3 * y[i+1]
+			}
+		}
+	}
+
+	pattern := make([][]uint, nd)
+	for i := uint(0); i < nd; i++ {
+		if i > 0 {
+			pattern[i] = append(pattern[i], i-1)
+		}
+		pattern[i] = append(pattern[i], i)
+		if i < nd-1 {
+			pattern[i] = append(pattern[i], i+1)
+		}
+	}
+
+	y, f := []float64{1, 2, 3, 4, 5, 6}, make([]float64, nd)
+	dydx(0, y, f)
+
+	var expected []float64
+	for i := 0; i < nd; i++ {
+		if i > 0 {
+			expected = append(expected, 1)
+		}
+		expected = append(expected, -4*y[i])
+		if i < nd-1 {
+			expected = append(expected, 3)
+		}
+	}
+
+	for _, scheme := range []Scheme{Forward, Central} {
+		approximator := New(nd, pattern)
+		approximator.Scheme = scheme
+		assert.Equal(approximator.Groups(), uint(3), t)
+		assert.Equal(approximator.Size(), uint(len(expected)), t)
+
+		J := make([]float64, approximator.Size())
+		assert.Equal(approximator.Compute(dydx, 0, y, f, J), uint(3*(1+uint(scheme))), t)
+		assert.Close(J, expected, 1e-6, t)
+	}
+}
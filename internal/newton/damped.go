@@ -0,0 +1,72 @@
+package newton
+
+import (
+	"math"
+)
+
+const (
+	minDamping = 1.0 / 64
+)
+
+// IterateDamped is similar to Iterate but performs damped Newton iterations,
+// which converge from initial guesses farther away from the solution. Each
+// step is repeatedly halved until the natural monotonicity test is satisfied,
+// that is, until the norm of the next simplified Newton correction decreases
+// sufficiently. The iterations are assumed to have converged when the norm of
+// a correction drops below the tolerance.
+func (self *Solver) IterateDamped(dydx func(float64, []float64, []float64), x float64,
+	ψ, scale, z, f []float64) (bool, uint) {
+
+	c, δ, δnew, trial := self.c, self.δ, self.δnew, self.trial
+
+	evaluations := uint(0)
+
+	correct := func(z, δ []float64) bool {
+		dydx(x, z, f)
+		evaluations++
+		for i := range δ {
+			if math.IsNaN(f[i]) || math.IsInf(f[i], 0) {
+				return false
+			}
+			δ[i] = ψ[i] + c*f[i] - z[i]
+		}
+		evaluations += self.solve(dydx, x, z, f, δ)
+		return true
+	}
+
+	if !correct(z, δ) {
+		return false, evaluations
+	}
+	norm := rms(δ, scale)
+
+	for k := uint(0); k < self.MaxIterations; k++ {
+		if norm < self.Tolerance {
+			for i := range z {
+				z[i] += δ[i]
+			}
+			return true, evaluations
+		}
+
+		λ, normnew := 1.0, 0.0
+		for {
+			for i := range trial {
+				trial[i] = z[i] + λ*δ[i]
+			}
+			if correct(trial, δnew) {
+				normnew = rms(δnew, scale)
+				if normnew <= (1-λ/2)*norm {
+					break
+				}
+			}
+			if λ /= 2; λ < minDamping {
+				return false, evaluations
+			}
+		}
+
+		copy(z, trial)
+		δ, δnew = δnew, δ
+		norm = normnew
+	}
+
+	return false, evaluations
+}
@@ -0,0 +1,247 @@
+package newton
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode"
+)
+
+const (
+	defaultRestart   = 20
+	defaultTolerance = 1e-3
+)
+
+type krylov struct {
+	config ode.Krylov
+
+	dydx func(float64, []float64, []float64)
+	x    float64
+	y    []float64
+	f    []float64
+
+	V []float64
+	H []float64
+	g []float64
+	γ []float64
+	σ []float64
+
+	u  []float64
+	w  []float64
+	s  []float64
+	z  []float64
+	fz []float64
+}
+
+// UseKrylov switches the solver to the Jacobian-free Newton–Krylov mode; see
+// ode.Krylov. In this mode, the Jacobian matrices given to Factorize are
+// ignored and can be nil.
+func (self *Solver) UseKrylov(config *ode.Krylov) {
+	nd := self.nd
+
+	m := config.Restart
+	if m == 0 {
+		m = defaultRestart
+	}
+	if m > nd {
+		m = nd
+	}
+
+	self.krylov = &krylov{
+		config: *config,
+
+		y: make([]float64, nd),
+		f: make([]float64, nd),
+
+		V: make([]float64, (m+1)*nd),
+		H: make([]float64, (m+1)*m),
+		g: make([]float64, m+1),
+		γ: make([]float64, m),
+		σ: make([]float64, m),
+
+		u:  make([]float64, nd),
+		w:  make([]float64, nd),
+		s:  make([]float64, nd),
+		z:  make([]float64, nd),
+		fz: make([]float64, nd),
+	}
+	self.krylov.config.Restart = m
+	if self.krylov.config.Tolerance == 0 {
+		self.krylov.config.Tolerance = defaultTolerance
+	}
+}
+
+// linearize sets the point at which the Jacobian matrix is taken.
+func (self *krylov) linearize(dydx func(float64, []float64, []float64), x float64,
+	y, f []float64) {
+
+	self.dydx, self.x = dydx, x
+	copy(self.y, y)
+	copy(self.f, f)
+}
+
+// multiply computes (I - c J) v and stores the result in u. The function
+// returns the number of evaluations of dydx.
+func (self *krylov) multiply(c float64, v, u []float64) uint {
+	y, f, z, fz := self.y, self.f, self.z, self.fz
+
+	normv := norm(v)
+	if normv == 0 {
+		for i := range u {
+			u[i] = 0
+		}
+		return 0
+	}
+
+	σ := math.Sqrt((1+norm(y))*epsilon) / normv
+	for i := range z {
+		z[i] = y[i] + σ*v[i]
+	}
+	self.dydx(self.x, z, fz)
+	for i := range u {
+		u[i] = v[i] - c*(fz[i]-f[i])/σ
+	}
+
+	return 1
+}
+
+func (self *krylov) precondition(c float64, v []float64) {
+	if self.config.Preconditioner != nil {
+		self.config.Preconditioner(self.x, self.y, c, v)
+	}
+}
+
+// solve solves (I - c J) x = b in place using the restarted GMRES method with
+// right preconditioning. The function returns the number of evaluations of
+// dydx.
+func (self *krylov) solve(c float64, b []float64) uint {
+	nd, m := len(b), int(self.config.Restart)
+	V, H, g, γ, σ := self.V, self.H, self.g, self.γ, self.σ
+	u, w, s := self.u, self.w, self.s
+
+	evaluations := uint(0)
+
+	tolerance := self.config.Tolerance * norm(b)
+
+	// The right-hand side is kept in s, and the solution is accumulated in b.
+	copy(s, b)
+	for i := range b {
+		b[i] = 0
+	}
+
+	for restart := uint(0); restart <= self.config.MaxRestarts; restart++ {
+		r := V[:nd]
+		if restart == 0 {
+			copy(r, s)
+		} else {
+			evaluations += self.multiply(c, b, w)
+			for i := range r {
+				r[i] = s[i] - w[i]
+			}
+		}
+
+		β := norm(r)
+		if β <= tolerance {
+			break
+		}
+		for i := range r {
+			r[i] /= β
+		}
+		for i := range g {
+			g[i] = 0
+		}
+		g[0] = β
+
+		k, breakdown := 0, false
+		for j := 0; j < m; j++ {
+			copy(u, V[j*nd:(j+1)*nd])
+			self.precondition(c, u)
+			next := V[(j+1)*nd : (j+2)*nd]
+			evaluations += self.multiply(c, u, next)
+
+			for i := 0; i <= j; i++ {
+				vi := V[i*nd : (i+1)*nd]
+				h := dot(next, vi)
+				H[i*m+j] = h
+				for l := range next {
+					next[l] -= h * vi[l]
+				}
+			}
+			h := norm(next)
+			H[(j+1)*m+j] = h
+			if h != 0 {
+				for l := range next {
+					next[l] /= h
+				}
+			}
+
+			for i := 0; i < j; i++ {
+				p, q := H[i*m+j], H[(i+1)*m+j]
+				H[i*m+j] = γ[i]*p + σ[i]*q
+				H[(i+1)*m+j] = -σ[i]*p + γ[i]*q
+			}
+			a, d := H[j*m+j], H[(j+1)*m+j]
+			ρ := math.Hypot(a, d)
+			if ρ == 0 {
+				γ[j], σ[j] = 1, 0
+			} else {
+				γ[j], σ[j] = a/ρ, d/ρ
+			}
+			H[j*m+j], H[(j+1)*m+j] = ρ, 0
+			g[j+1] = -σ[j] * g[j]
+			g[j] = γ[j] * g[j]
+
+			k, breakdown = j+1, h == 0
+			if math.Abs(g[j+1]) <= tolerance || breakdown {
+				break
+			}
+		}
+
+		residual := math.Abs(g[k])
+
+		// Solve the triangular system and update the solution.
+		for i := k - 1; i >= 0; i-- {
+			sum := g[i]
+			for l := i + 1; l < k; l++ {
+				sum -= H[i*m+l] * g[l]
+			}
+			if H[i*m+i] != 0 {
+				g[i] = sum / H[i*m+i]
+			} else {
+				g[i] = 0
+			}
+		}
+		for l := range u {
+			u[l] = 0
+		}
+		for i := 0; i < k; i++ {
+			vi := V[i*nd : (i+1)*nd]
+			for l := range u {
+				u[l] += g[i] * vi[l]
+			}
+		}
+		self.precondition(c, u)
+		for l := range b {
+			b[l] += u[l]
+		}
+
+		if residual <= tolerance || breakdown {
+			break
+		}
+	}
+
+	return evaluations
+}
+
+func dot(u, v []float64) float64 {
+	sum := 0.0
+	for i := range u {
+		sum += u[i] * v[i]
+	}
+	return sum
+}
+
+func norm(v []float64) float64 {
+	return math.Sqrt(dot(v, v))
+}
+
+const epsilon = 2.220446049250313e-16
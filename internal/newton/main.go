@@ -0,0 +1,184 @@
+// Package newton provides a solver of the nonlinear systems arising in
+// diagonally implicit integrators.
+package newton
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/internal/dense"
+	"github.com/ready-steady/ode/internal/sparse"
+)
+
+// Solver solves systems of the form z = ψ + c f(x, z) using the simplified
+// Newton method with the iteration matrix I - c J where J is an approximation
+// of the Jacobian matrix of f. The matrix is either dense or sparse with a
+// given pattern, in which case the iteration matrix is decomposed using a
+// sparse LU decomposition.
+type Solver struct {
+	// The maximal number of iterations.
+	MaxIterations uint
+	// The tolerance on the weighted root-mean-square norm of the increment.
+	Tolerance float64
+
+	nd uint
+	c  float64
+	W  []float64
+	lu ode.LinearSolver
+	δ  []float64
+
+	positions []uint
+	diagonal  []uint
+
+	krylov *krylov
+
+	trial []float64
+	δnew  []float64
+}
+
+// New creates a solver for systems with nd unknowns. If pattern is not nil,
+// the Jacobian matrices are sparse with the given pattern, which is assumed to
+// be valid. If linear is not nil, it is used to create the solver of the
+// linear systems with the iteration matrix; see ode.LinearSolver.
+func New(nd uint, pattern [][]uint, linear func(uint, ode.Pattern) ode.LinearSolver,
+	maxIterations uint, tolerance float64) *Solver {
+
+	self := &Solver{
+		MaxIterations: maxIterations,
+		Tolerance:     tolerance,
+
+		nd: nd,
+		δ:  make([]float64, nd),
+
+		trial: make([]float64, nd),
+		δnew:  make([]float64, nd),
+	}
+	if pattern == nil {
+		self.W = make([]float64, nd*nd)
+		if linear != nil {
+			self.lu = linear(nd, nil)
+		} else {
+			self.lu = dense.NewLU(nd)
+		}
+	} else {
+		var augmented [][]uint
+		augmented, self.positions, self.diagonal = sparse.Augment(pattern)
+		self.W = make([]float64, sparse.Count(augmented))
+		if linear != nil {
+			self.lu = linear(nd, augmented)
+		} else {
+			self.lu = sparse.NewLU(augmented)
+		}
+	}
+	return self
+}
+
+// Factorize prepares the iteration matrix I - c J.
+func (self *Solver) Factorize(J []float64, c float64) error {
+	if self.krylov != nil {
+		self.c = c
+		return nil
+	}
+
+	nd, W := self.nd, self.W
+	if self.positions == nil {
+		for i := range W {
+			W[i] = -c * J[i]
+		}
+		for i := uint(0); i < nd; i++ {
+			W[i*nd+i] += 1
+		}
+	} else {
+		for i := range W {
+			W[i] = 0
+		}
+		for k, p := range self.positions {
+			W[p] = -c * J[k]
+		}
+		for _, p := range self.diagonal {
+			W[p] += 1
+		}
+	}
+	self.c = c
+	return self.lu.Factorize(W)
+}
+
+// Solve solves the linear system (I - c J) x = b in place. In the
+// Jacobian-free mode, J is the Jacobian matrix at the point of the last
+// iteration. The function returns the number of evaluations of the right-hand
+// side, which is zero unless the solver is in the Jacobian-free mode.
+func (self *Solver) Solve(b []float64) uint {
+	if self.krylov != nil {
+		return self.krylov.solve(self.c, b)
+	}
+	self.lu.Solve(b)
+	return 0
+}
+
+// solve is similar to Solve but sets the point at which the Jacobian matrix
+// is taken in the Jacobian-free mode.
+func (self *Solver) solve(dydx func(float64, []float64, []float64), x float64,
+	z, f, b []float64) uint {
+
+	if self.krylov != nil {
+		self.krylov.linearize(dydx, x, z, f)
+	}
+	return self.Solve(b)
+}
+
+// Iterate performs the iterations starting from the initial guess stored in z,
+// which is overwritten by the solution. The increments are measured relative
+// to scale. The function returns whether the iterations have converged and
+// the number of evaluations of dydx, which is used as f.
+func (self *Solver) Iterate(dydx func(float64, []float64, []float64), x float64,
+	ψ, scale, z, f []float64) (bool, uint) {
+
+	c, δ := self.c, self.δ
+
+	old := -1.0
+
+	evaluations := uint(0)
+
+	for k := uint(0); k < self.MaxIterations; k++ {
+		dydx(x, z, f)
+		evaluations++
+		for i := range δ {
+			if math.IsNaN(f[i]) || math.IsInf(f[i], 0) {
+				return false, evaluations
+			}
+			δ[i] = ψ[i] + c*f[i] - z[i]
+		}
+		evaluations += self.solve(dydx, x, z, f, δ)
+
+		norm := rms(δ, scale)
+
+		rate := 0.0
+		if old >= 0 {
+			rate = norm / old
+			if rate >= 1 || math.Pow(rate, float64(self.MaxIterations-k))/(1-rate)*norm > self.Tolerance {
+				return false, evaluations
+			}
+		}
+
+		for i := range z {
+			z[i] += δ[i]
+		}
+
+		if norm == 0 || (old >= 0 && rate/(1-rate)*norm < self.Tolerance) {
+			return true, evaluations
+		}
+
+		old = norm
+	}
+
+	return false, evaluations
+}
+
+func rms(δ, scale []float64) float64 {
+	norm := 0.0
+	for i := range δ {
+		e := δ[i] / scale[i]
+		norm += e * e
+	}
+	return math.Sqrt(norm / float64(len(δ)))
+}
@@ -0,0 +1,95 @@
+package newton
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode"
+)
+
+func TestIterate(t *testing.T) {
+	// z = 1 + 0.1 exp(-z)
+	dydx := func(_ float64, z, f []float64) {
+		f[0] = math.Exp(-z[0])
+	}
+
+	solver := New(1, nil, nil, 10, 1e-12)
+	assert.Equal(solver.Factorize([]float64{-math.Exp(-1)}, 0.1), nil, t)
+
+	z, f := []float64{1}, []float64{0}
+	converged, _ := solver.Iterate(dydx, 0, []float64{1}, []float64{1}, z, f)
+
+	assert.Equal(converged, true, t)
+	assert.Close(z[0], 1+0.1*math.Exp(-z[0]), 1e-12, t)
+}
+
+func TestIterateDamped(t *testing.T) {
+	// z = 10 arctan(z)
+	dydx := func(_ float64, z, f []float64) {
+		f[0] = math.Atan(z[0])
+	}
+
+	solver := New(1, nil, nil, 50, 1e-12)
+	assert.Equal(solver.Factorize([]float64{0}, 10), nil, t)
+
+	z, f := []float64{10}, []float64{0}
+	converged, _ := solver.IterateDamped(dydx, 0, []float64{0}, []float64{1}, z, f)
+
+	assert.Equal(converged, true, t)
+	assert.Close(z[0], 10*math.Atan(z[0]), 1e-10, t)
+}
+
+func TestSolveKrylov(t *testing.T) {
+	A := []float64{
+		-2, 1, 0, 0.5,
+		1, -3, 1, 0,
+		0, 1, -4, 1,
+		0.5, 0, 1, -5,
+	}
+
+	dydx := func(_ float64, y, f []float64) {
+		for i := 0; i < 4; i++ {
+			f[i] = 0
+			for j := 0; j < 4; j++ {
+				f[i] += A[i*4+j] * y[j]
+			}
+		}
+	}
+
+	for _, restart := range []uint{0, 2} {
+		solver := New(4, nil, nil, 10, 1e-12)
+		solver.UseKrylov(&ode.Krylov{Restart: restart, MaxRestarts: 10, Tolerance: 1e-12})
+		assert.Equal(solver.Factorize(nil, 0.5), nil, t)
+
+		y, f := []float64{1, 2, 3, 4}, make([]float64, 4)
+		dydx(0, y, f)
+		b := []float64{1, -1, 2, 0.5}
+		evaluations := solver.solve(dydx, 0, y, f, b)
+		assert.Equal(evaluations > 0, true, t)
+
+		// Check the residual of (I - 0.5 A) x = b.
+		dydx(0, b, f)
+		for i := range b {
+			f[i] = b[i] - 0.5*f[i]
+		}
+		assert.Close(f, []float64{1, -1, 2, 0.5}, 1e-6, t)
+	}
+}
+
+func TestIterateKrylov(t *testing.T) {
+	// z = 1 + 0.1 exp(-z)
+	dydx := func(_ float64, z, f []float64) {
+		f[0] = math.Exp(-z[0])
+	}
+
+	solver := New(1, nil, nil, 10, 1e-12)
+	solver.UseKrylov(&ode.Krylov{})
+	assert.Equal(solver.Factorize(nil, 0.1), nil, t)
+
+	z, f := []float64{1}, []float64{0}
+	converged, _ := solver.Iterate(dydx, 0, []float64{1}, []float64{1}, z, f)
+
+	assert.Equal(converged, true, t)
+	assert.Close(z[0], 1+0.1*math.Exp(-z[0]), 1e-12, t)
+}
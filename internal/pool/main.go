@@ -0,0 +1,51 @@
+// Package pool provides pools of workspaces keyed by the dimension of the
+// system, which let integrators reuse their storage across integrations.
+package pool
+
+import (
+	"sync"
+)
+
+// Pool is a pool of workspaces of type T. A nil pool is valid and keeps
+// nothing. A pool is safe for concurrent use.
+type Pool[T any] struct {
+	mutex sync.Mutex
+	pools map[uint]*sync.Pool
+}
+
+// New creates a pool.
+func New[T any]() *Pool[T] {
+	return &Pool[T]{pools: make(map[uint]*sync.Pool)}
+}
+
+// Get takes a workspace for systems of dimension nd from the pool. It returns
+// nil if the pool has none, in which case the caller should allocate one.
+func (self *Pool[T]) Get(nd uint) *T {
+	if self == nil {
+		return nil
+	}
+	if workspace, ok := self.find(nd).Get().(*T); ok {
+		return workspace
+	}
+	return nil
+}
+
+// Put returns a workspace for systems of dimension nd to the pool.
+func (self *Pool[T]) Put(nd uint, workspace *T) {
+	if self == nil || workspace == nil {
+		return
+	}
+	self.find(nd).Put(workspace)
+}
+
+func (self *Pool[T]) find(nd uint) *sync.Pool {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	pool, ok := self.pools[nd]
+	if !ok {
+		pool = &sync.Pool{}
+		self.pools[nd] = pool
+	}
+	return pool
+}
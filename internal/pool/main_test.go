@@ -0,0 +1,40 @@
+package pool
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestPool(t *testing.T) {
+	pool := New[[]float64]()
+
+	assert.Equal(pool.Get(2) == nil, true, t)
+
+	workspace := make([]float64, 2)
+	pool.Put(2, &workspace)
+	assert.Equal(pool.Get(3) == nil, true, t)
+
+	var nilPool *Pool[[]float64]
+	assert.Equal(nilPool.Get(2) == nil, true, t)
+	nilPool.Put(2, &workspace)
+
+	var group sync.WaitGroup
+	for k := 0; k < 8; k++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for i := 0; i < 100; i++ {
+				workspace := pool.Get(2)
+				if workspace == nil {
+					buffer := make([]float64, 2)
+					workspace = &buffer
+				}
+				assert.Equal(len(*workspace), 2, t)
+				pool.Put(2, workspace)
+			}
+		}()
+	}
+	group.Wait()
+}
@@ -0,0 +1,229 @@
+// Package sparse provides basic operations on sparse matrices needed by the
+// implicit integrators.
+//
+// A sparsity pattern of an n-by-n matrix is given by n rows, each of which
+// lists the columns of the nonzero entries of the row in increasing order; see
+// ode.Pattern. The values of a matrix with a given pattern are stored row by
+// row in the order of the pattern.
+package sparse
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+)
+
+// Verify checks that a pattern describes an n-by-n matrix.
+func Verify(pattern [][]uint, n uint) error {
+	if uint(len(pattern)) != n {
+		return errors.New("the sparsity pattern should match the dimension of the system")
+	}
+	for _, row := range pattern {
+		for k, j := range row {
+			if j >= n {
+				return errors.New("the sparsity pattern should refer to existing columns")
+			}
+			if k > 0 && row[k-1] >= j {
+				return errors.New("the columns of the sparsity pattern should be increasing")
+			}
+		}
+	}
+	return nil
+}
+
+// Count returns the number of nonzero entries of a pattern.
+func Count(pattern [][]uint) uint {
+	count := uint(0)
+	for _, row := range pattern {
+		count += uint(len(row))
+	}
+	return count
+}
+
+// Augment includes the diagonal in a pattern. The function returns the new
+// pattern, the positions of the entries of the original pattern in the new
+// one, and the positions of the diagonal entries in the new one.
+func Augment(pattern [][]uint) ([][]uint, []uint, []uint) {
+	n := uint(len(pattern))
+
+	augmented := make([][]uint, n)
+	positions := make([]uint, 0, Count(pattern))
+	diagonal := make([]uint, n)
+
+	offset := uint(0)
+	for i := uint(0); i < n; i++ {
+		row := make([]uint, 0, len(pattern[i])+1)
+		found := false
+		for _, j := range pattern[i] {
+			if !found && j >= i {
+				found = true
+				diagonal[i] = offset + uint(len(row))
+				if j > i {
+					row = append(row, i)
+				}
+			}
+			positions = append(positions, offset+uint(len(row)))
+			row = append(row, j)
+		}
+		if !found {
+			diagonal[i] = offset + uint(len(row))
+			row = append(row, i)
+		}
+		augmented[i] = row
+		offset += uint(len(row))
+	}
+
+	return augmented, positions, diagonal
+}
+
+// LU is an LU decomposition without pivoting of a sparse matrix.
+//
+// The pattern of the factors, including fill-in, is computed once when the
+// decomposition is allocated, and only numerical factorizations are performed
+// afterwards. Since no pivoting is done, the decomposition is intended for
+// matrices with a dominant diagonal, such as the iteration matrices I - c J of
+// the Newton methods with small c.
+type LU struct {
+	n        uint
+	pattern  [][]uint
+	rows     [][]uint
+	values   [][]float64
+	diagonal []uint
+	work     []float64
+}
+
+// NewLU allocates an LU decomposition of an n-by-n matrix with a given
+// pattern. The pattern should include the diagonal; see Augment.
+func NewLU(pattern [][]uint) *LU {
+	n := uint(len(pattern))
+
+	rows := make([][]uint, n)
+	values := make([][]float64, n)
+	diagonal := make([]uint, n)
+
+	marked := make([]bool, n)
+	lower := &queue{}
+	for i := uint(0); i < n; i++ {
+		var row []uint
+		add := func(j uint) {
+			if marked[j] {
+				return
+			}
+			marked[j] = true
+			row = append(row, j)
+			if j < i {
+				heap.Push(lower, j)
+			}
+		}
+
+		add(i)
+		for _, j := range pattern[i] {
+			add(j)
+		}
+		for lower.Len() > 0 {
+			k := heap.Pop(lower).(uint)
+			for _, j := range rows[k][diagonal[k]+1:] {
+				add(j)
+			}
+		}
+
+		sort.Slice(row, func(i, j int) bool { return row[i] < row[j] })
+		for k, j := range row {
+			marked[j] = false
+			if j == i {
+				diagonal[i] = uint(k)
+			}
+		}
+		rows[i] = row
+		values[i] = make([]float64, len(row))
+	}
+
+	return &LU{
+		n:        n,
+		pattern:  pattern,
+		rows:     rows,
+		values:   values,
+		diagonal: diagonal,
+		work:     make([]float64, n),
+	}
+}
+
+// Factorize computes the decomposition of a matrix whose values are given in
+// the order of the pattern. The matrix is not modified.
+func (self *LU) Factorize(A []float64) error {
+	n, rows, values, diagonal, work := self.n, self.rows, self.values, self.diagonal, self.work
+
+	offset := 0
+	for i := uint(0); i < n; i++ {
+		row := rows[i]
+		for _, j := range row {
+			work[j] = 0
+		}
+		for _, j := range self.pattern[i] {
+			work[j] = A[offset]
+			offset++
+		}
+
+		for _, k := range row[:diagonal[i]] {
+			l := work[k] / values[k][diagonal[k]]
+			work[k] = l
+			if l == 0 {
+				continue
+			}
+			upper := diagonal[k] + 1
+			for p, j := range rows[k][upper:] {
+				work[j] -= l * values[k][upper+uint(p)]
+			}
+		}
+
+		for p, j := range row {
+			values[i][p] = work[j]
+		}
+		if values[i][diagonal[i]] == 0 {
+			return errors.New("the matrix is singular")
+		}
+	}
+
+	return nil
+}
+
+// Solve solves the system of linear equations A x = b using the decomposition
+// of A. The solution overwrites b.
+func (self *LU) Solve(b []float64) {
+	n, rows, values, diagonal := self.n, self.rows, self.values, self.diagonal
+
+	for i := uint(0); i < n; i++ {
+		s := b[i]
+		for p, j := range rows[i][:diagonal[i]] {
+			s -= values[i][p] * b[j]
+		}
+		b[i] = s
+	}
+
+	for i := int(n) - 1; i >= 0; i-- {
+		s := b[i]
+		upper := diagonal[i] + 1
+		for p, j := range rows[i][upper:] {
+			s -= values[i][upper+uint(p)] * b[j]
+		}
+		b[i] = s / values[i][diagonal[i]]
+	}
+}
+
+type queue []uint
+
+func (self queue) Len() int           { return len(self) }
+func (self queue) Less(i, j int) bool { return self[i] < self[j] }
+func (self queue) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+
+func (self *queue) Push(x interface{}) {
+	*self = append(*self, x.(uint))
+}
+
+func (self *queue) Pop() interface{} {
+	old := *self
+	n := len(old)
+	x := old[n-1]
+	*self = old[:n-1]
+	return x
+}
@@ -0,0 +1,49 @@
+package sparse
+
+import (
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestAugment(t *testing.T) {
+	pattern := [][]uint{{1}, {0, 1, 2}, {0}}
+
+	augmented, positions, diagonal := Augment(pattern)
+	assert.Equal(augmented, [][]uint{{0, 1}, {0, 1, 2}, {0, 2}}, t)
+	assert.Equal(positions, []uint{1, 2, 3, 4, 5}, t)
+	assert.Equal(diagonal, []uint{0, 3, 6}, t)
+}
+
+func TestLU(t *testing.T) {
+	// An arrow matrix whose factors fill in completely.
+	pattern := [][]uint{{0, 1, 2, 3}, {0, 1}, {0, 2}, {0, 3}}
+	A := []float64{
+		4, 1, 1, 1,
+		1, 3,
+		1, 5,
+		1, 2,
+	}
+	b := []float64{4 + 2 + 3 + 4, 1 + 6, 1 + 15, 1 + 8}
+
+	assert.Equal(Verify(pattern, 4), nil, t)
+	assert.Equal(Count(pattern), uint(10), t)
+
+	lu := NewLU(pattern)
+	assert.Equal(lu.rows, [][]uint{{0, 1, 2, 3}, {0, 1, 2, 3}, {0, 1, 2, 3}, {0, 1, 2, 3}}, t)
+	assert.Equal(lu.Factorize(A), nil, t)
+
+	lu.Solve(b)
+	assert.Close(b, []float64{1, 2, 3, 4}, 1e-14, t)
+}
+
+func TestLUSingular(t *testing.T) {
+	lu := NewLU([][]uint{{0, 1}, {0, 1}})
+	assert.Equal(lu.Factorize([]float64{1, 2, 2, 4}) != nil, true, t)
+}
+
+func TestVerify(t *testing.T) {
+	assert.Equal(Verify([][]uint{{0}}, 2) != nil, true, t)
+	assert.Equal(Verify([][]uint{{0}, {2}}, 2) != nil, true, t)
+	assert.Equal(Verify([][]uint{{1, 0}, {1}}, 2) != nil, true, t)
+}
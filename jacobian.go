@@ -0,0 +1,28 @@
+package ode
+
+// Jacobian evaluates the Jacobian matrix of the right-hand side of a system of
+// differential equations dy/dx = f(x, y).
+//
+// The function evaluates the Jacobian matrix ∂f/∂y for a given x and y in its
+// first and second arguments and stores the result in its third argument. The
+// matrix is dense and stored in row-major order; that is, if nd is the
+// dimension of the system, J[i*nd+j] is the partial derivative of the ith
+// component of f with respect to the jth component of y. The function is
+// allowed to assume that J has length nd×nd and should overwrite all of its
+// entries. Integrators that accept a sparsity pattern use a compressed layout
+// instead when the pattern is given; see Pattern.
+//
+// Implicit integrators use the matrix to form the iteration matrices of their
+// Newton methods, and stiffness detection uses it to estimate the spectral
+// radius of the system. When a Jacobian is not given, it is approximated using
+// finite differences, which costs nd evaluations of f per matrix.
+type Jacobian func(x float64, y, J []float64)
+
+// Pattern is the sparsity pattern of a Jacobian matrix.
+//
+// If nd is the dimension of the system, the pattern has nd rows, and the ith
+// row lists, in increasing order, the columns of the entries of the ith row of
+// the matrix that can be nonzero. Given a pattern, a Jacobian callback should
+// store only these entries, row by row in the order of the pattern, so that J
+// has as many entries as the pattern instead of nd×nd.
+type Pattern [][]uint
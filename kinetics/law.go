@@ -0,0 +1,79 @@
+package kinetics
+
+import (
+	"math"
+)
+
+// Law is a rate law.
+type Law interface {
+	// Rate computes the rate of a reaction given the concentrations c of its
+	// reactants and their stoichiometric coefficients ν.
+	Rate(c, ν []float64) float64
+	// Gradient computes the derivatives of the rate with respect to the
+	// concentrations of the reactants and stores them in g.
+	Gradient(c, ν, g []float64)
+}
+
+type massAction struct {
+	k float64
+}
+
+// MassAction returns the law of mass action with rate constant k.
+func MassAction(k float64) Law {
+	return &massAction{k: k}
+}
+
+func (self *massAction) Rate(c, ν []float64) float64 {
+	rate := self.k
+	for i := range c {
+		rate *= power(c[i], ν[i])
+	}
+	return rate
+}
+
+func (self *massAction) Gradient(c, ν, g []float64) {
+	for i := range c {
+		g[i] = self.k * ν[i] * power(c[i], ν[i]-1)
+		for j := range c {
+			if j != i {
+				g[i] *= power(c[j], ν[j])
+			}
+		}
+	}
+}
+
+type michaelisMenten struct {
+	vmax float64
+	km   float64
+}
+
+// MichaelisMenten returns the Michaelis–Menten law with maximal rate vmax and
+// Michaelis constant km. The law depends only on the first reactant, which is
+// the substrate.
+func MichaelisMenten(vmax, km float64) Law {
+	return &michaelisMenten{vmax: vmax, km: km}
+}
+
+func (self *michaelisMenten) Rate(c, _ []float64) float64 {
+	return self.vmax * c[0] / (self.km + c[0])
+}
+
+func (self *michaelisMenten) Gradient(c, _, g []float64) {
+	d := self.km + c[0]
+	g[0] = self.vmax * self.km / (d * d)
+	for i := 1; i < len(g); i++ {
+		g[i] = 0
+	}
+}
+
+func power(x, ν float64) float64 {
+	switch ν {
+	case 0:
+		return 1
+	case 1:
+		return x
+	case 2:
+		return x * x
+	}
+	return math.Pow(x, ν)
+}
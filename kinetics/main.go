@@ -0,0 +1,204 @@
+// Package kinetics provides a builder of systems of ordinary differential
+// equations describing chemical reaction networks.
+//
+// A network is declared in terms of species and reactions, each reaction
+// having a stoichiometry and a rate law. The network is then compiled into a
+// model evaluating the right-hand side of the corresponding system, its
+// Jacobian matrix computed analytically, and the sparsity pattern of the
+// Jacobian matrix. Matrices are stored in row-major order.
+//
+// https://en.wikipedia.org/wiki/Chemical_kinetics
+package kinetics
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Network is a reaction network.
+type Network struct {
+	species   []string
+	reactions []Reaction
+}
+
+// Term is a species participating in a reaction together with its
+// stoichiometric coefficient.
+type Term struct {
+	Species     string
+	Coefficient float64
+}
+
+// Reaction is a reaction.
+type Reaction struct {
+	Reactants []Term
+	Products  []Term
+	Law       Law
+}
+
+// Model is a compiled reaction network. A model is not safe for concurrent
+// use.
+type Model struct {
+	species   []string
+	reactions []reaction
+	buffer    []float64
+}
+
+type reaction struct {
+	reactants []uint
+	orders    []float64
+	changes   []change
+	law       Law
+}
+
+type change struct {
+	species uint
+	delta   float64
+}
+
+// AddSpecies declares species.
+func (self *Network) AddSpecies(names ...string) {
+	self.species = append(self.species, names...)
+}
+
+// AddReaction declares a reaction.
+func (self *Network) AddReaction(reaction Reaction) {
+	self.reactions = append(self.reactions, reaction)
+}
+
+// Compile verifies the network and creates a model of it.
+func (self *Network) Compile() (*Model, error) {
+	index := make(map[string]uint)
+	for i, name := range self.species {
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("the species %q is declared twice", name)
+		}
+		index[name] = uint(i)
+	}
+
+	lookup := func(term Term) (uint, error) {
+		i, ok := index[term.Species]
+		if !ok {
+			return 0, fmt.Errorf("the species %q is not declared", term.Species)
+		}
+		if term.Coefficient <= 0 {
+			return 0, errors.New("the stoichiometric coefficients should be positive")
+		}
+		return i, nil
+	}
+
+	nmax := 0
+	reactions := make([]reaction, 0, len(self.reactions))
+	for _, r := range self.reactions {
+		if r.Law == nil {
+			return nil, errors.New("the rate law should be specified")
+		}
+
+		deltas := make(map[uint]float64)
+		reactants := make([]uint, 0, len(r.Reactants))
+		orders := make([]float64, 0, len(r.Reactants))
+		for _, term := range r.Reactants {
+			i, err := lookup(term)
+			if err != nil {
+				return nil, err
+			}
+			reactants = append(reactants, i)
+			orders = append(orders, term.Coefficient)
+			deltas[i] -= term.Coefficient
+		}
+		for _, term := range r.Products {
+			i, err := lookup(term)
+			if err != nil {
+				return nil, err
+			}
+			deltas[i] += term.Coefficient
+		}
+
+		changes := make([]change, 0, len(deltas))
+		for i := range self.species {
+			if delta := deltas[uint(i)]; delta != 0 {
+				changes = append(changes, change{species: uint(i), delta: delta})
+			}
+		}
+
+		if len(reactants) > nmax {
+			nmax = len(reactants)
+		}
+
+		reactions = append(reactions, reaction{
+			reactants: reactants,
+			orders:    orders,
+			changes:   changes,
+			law:       r.Law,
+		})
+	}
+
+	return &Model{
+		species:   append([]string(nil), self.species...),
+		reactions: reactions,
+		buffer:    make([]float64, 2*nmax),
+	}, nil
+}
+
+// Species returns the names of the species in the order of the components of
+// the state vector.
+func (self *Model) Species() []string {
+	return append([]string(nil), self.species...)
+}
+
+// Dydx evaluates the right-hand side of the system at concentrations y and
+// stores the result in f. The signature matches the one expected by the
+// integrators.
+func (self *Model) Dydx(_ float64, y, f []float64) {
+	for i := range f {
+		f[i] = 0
+	}
+	for _, r := range self.reactions {
+		c := self.gather(&r, y)
+		rate := r.law.Rate(c, r.orders)
+		for _, change := range r.changes {
+			f[change.species] += change.delta * rate
+		}
+	}
+}
+
+// Jacobian evaluates the Jacobian matrix of the right-hand side of the system
+// at concentrations y and stores the result in J.
+func (self *Model) Jacobian(_ float64, y, J []float64) {
+	nd := len(self.species)
+	for i := range J {
+		J[i] = 0
+	}
+	for _, r := range self.reactions {
+		c := self.gather(&r, y)
+		g := self.buffer[len(self.buffer)/2:][:len(c)]
+		r.law.Gradient(c, r.orders, g)
+		for _, change := range r.changes {
+			for k, j := range r.reactants {
+				J[change.species*uint(nd)+j] += change.delta * g[k]
+			}
+		}
+	}
+}
+
+// Sparsity returns the sparsity pattern of the Jacobian matrix, which has
+// true in the positions that can be nonzero.
+func (self *Model) Sparsity() []bool {
+	nd := uint(len(self.species))
+	pattern := make([]bool, nd*nd)
+	for _, r := range self.reactions {
+		for _, change := range r.changes {
+			for _, j := range r.reactants {
+				pattern[change.species*nd+j] = true
+			}
+		}
+	}
+	return pattern
+}
+
+func (self *Model) gather(r *reaction, y []float64) []float64 {
+	c := self.buffer[:len(r.reactants)]
+	for k, i := range r.reactants {
+		c[k] = y[i]
+	}
+	return c
+}
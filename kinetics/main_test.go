@@ -0,0 +1,85 @@
+package kinetics
+
+import (
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestRobertson(t *testing.T) {
+	network := &Network{}
+	network.AddSpecies("A", "B", "C")
+	network.AddReaction(Reaction{
+		Reactants: []Term{{"A", 1}},
+		Products:  []Term{{"B", 1}},
+		Law:       MassAction(0.04),
+	})
+	network.AddReaction(Reaction{
+		Reactants: []Term{{"B", 2}},
+		Products:  []Term{{"B", 1}, {"C", 1}},
+		Law:       MassAction(3e7),
+	})
+	network.AddReaction(Reaction{
+		Reactants: []Term{{"B", 1}, {"C", 1}},
+		Products:  []Term{{"A", 1}, {"C", 1}},
+		Law:       MassAction(1e4),
+	})
+
+	model, err := network.Compile()
+	assert.Equal(err, nil, t)
+
+	y := []float64{0.9, 2e-5, 0.1}
+
+	f := make([]float64, 3)
+	model.Dydx(0, y, f)
+	assert.Close(f, []float64{
+		-0.04*y[0] + 1e4*y[1]*y[2],
+		0.04*y[0] - 1e4*y[1]*y[2] - 3e7*y[1]*y[1],
+		3e7 * y[1] * y[1],
+	}, 1e-15, t)
+
+	J := make([]float64, 9)
+	model.Jacobian(0, y, J)
+	assert.Close(J, []float64{
+		-0.04, 1e4 * y[2], 1e4 * y[1],
+		0.04, -1e4*y[2] - 6e7*y[1], -1e4 * y[1],
+		0, 6e7 * y[1], 0,
+	}, 1e-12, t)
+
+	assert.Equal(model.Sparsity(), []bool{
+		true, true, true,
+		true, true, true,
+		false, true, false,
+	}, t)
+}
+
+func TestMichaelisMenten(t *testing.T) {
+	network := &Network{}
+	network.AddSpecies("S", "P")
+	network.AddReaction(Reaction{
+		Reactants: []Term{{"S", 1}},
+		Products:  []Term{{"P", 1}},
+		Law:       MichaelisMenten(2, 0.5),
+	})
+
+	model, _ := network.Compile()
+
+	f, J := make([]float64, 2), make([]float64, 4)
+	model.Dydx(0, []float64{1.5, 0}, f)
+	model.Jacobian(0, []float64{1.5, 0}, J)
+
+	assert.Close(f, []float64{-1.5, 1.5}, 1e-15, t)
+	assert.Close(J, []float64{-0.25, 0, 0.25, 0}, 1e-15, t)
+}
+
+func TestCompileFailure(t *testing.T) {
+	network := &Network{}
+	network.AddSpecies("A")
+	network.AddReaction(Reaction{
+		Reactants: []Term{{"B", 1}},
+		Law:       MassAction(1),
+	})
+
+	_, err := network.Compile()
+	assert.Equal(err != nil, true, t)
+}
@@ -0,0 +1,31 @@
+package ode
+
+// Krylov is the configuration of the Jacobian-free Newton–Krylov mode of
+// implicit integrators.
+//
+// In this mode, the Jacobian matrix is never formed. Instead, its products
+// with vectors are approximated by directional finite differences of the
+// right-hand side,
+//
+//	J v ≈ (f(x, y + σ v) - f(x, y)) / σ,
+//
+// each of which costs one evaluation of f, and the linear systems of the
+// Newton method are solved using the restarted generalized minimal residual
+// method (GMRES) with optional right preconditioning. The memory required is
+// proportional to the dimension of the system times the restart length.
+//
+// https://en.wikipedia.org/wiki/Generalized_minimal_residual_method
+type Krylov struct {
+	// The dimension of the Krylov subspace after which the method is
+	// restarted. If it is zero, 20 is used.
+	Restart uint `json:"restart"`
+	// The maximal number of restarts.
+	MaxRestarts uint `json:"maxRestarts"`
+	// The tolerance on the norm of the residual relative to the norm of the
+	// right-hand side of a linear system. If it is zero, 1e-3 is used.
+	Tolerance float64 `json:"tolerance"`
+	// The preconditioner, which overwrites v with an approximation of the
+	// solution u of (I - c J) u = v where J is the Jacobian matrix at (x, y).
+	// If it is not given, no preconditioning is done.
+	Preconditioner func(x float64, y []float64, c float64, v []float64) `json:"-"`
+}
@@ -0,0 +1,44 @@
+package liegroup
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The step of integration.
+	Step float64 `json:"step"`
+	// The group on which the state evolves.
+	Group Group `json:"group"`
+}
+
+// Group is a choice of the matrix Lie group on which the state evolves.
+type Group uint
+
+const (
+	// The special orthogonal group SO(3) of rotations. The state is a 3-by-3
+	// rotation matrix, and an element of the algebra is an angular velocity
+	// ω = (ω₁, ω₂, ω₃).
+	SO3 Group = iota
+	// The special Euclidean group SE(3) of rigid-body motions. The state is a
+	// 4-by-4 homogeneous transformation, and an element of the algebra is a
+	// twist (ω, v) composed of an angular and a linear velocity.
+	SE3
+)
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Group > SE3 {
+		return errors.New("the group is unknown")
+	}
+
+	return nil
+}
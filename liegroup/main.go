@@ -0,0 +1,238 @@
+// Package liegroup provides integrators of systems of ordinary differential
+// equations of the form
+//
+//	Y′ = ξ(x, Y) Y
+//
+// where the state Y evolves on a matrix Lie group, and ξ takes values in the
+// corresponding Lie algebra. The supported groups are SO(3) and SE(3); see
+// Group.
+//
+// The integrator is the fourth-order Runge–Kutta–Munthe-Kaas method. Each step
+// is computed in the algebra and mapped back to the group using the exponential
+// map, which is evaluated in closed form. Consequently, the solution stays on
+// the group up to rounding errors regardless of the step size, and it needs no
+// reorthogonalization. Given k₁ = h ξ(x₀, Y₀), the method is given by
+//
+//	k₂ = h ξ(x₀ + h/2, exp(k₁/2) Y₀),
+//	k₃ = h ξ(x₀ + h/2, exp(k₂/2 - [k₁, k₂]/8) Y₀),
+//	k₄ = h ξ(x₀ + h, exp(k₃) Y₀),
+//	Y₁ = exp((k₁ + 2k₂ + 2k₃ + k₄)/6 - [k₁, k₄]/12) Y₀,
+//
+// where [·, ·] is the bracket of the algebra.
+//
+// Note that ξ is the velocity expressed in the fixed (spatial) frame. If the
+// velocity of a body is known in the body frame, as it is common for rigid
+// bodies with R′ = R ω̂, it should be transformed by the adjoint action of
+// the state; for instance, ξ = R ω for SO(3).
+//
+// https://en.wikipedia.org/wiki/Lie_group_integrator
+package liegroup
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/dense"
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system Y′ = ξ(x, Y) Y.
+//
+// The input function ξ(x, Y, u) evaluates the element of the algebra for a
+// given x and Y in its first and second arguments and stores the result in its
+// third argument. The states are matrices stored in row-major order, which are
+// 3-by-3 for SO(3) and 4-by-4 for SE(3). The elements of the algebra are given
+// by three components for SO(3) and six components for SE(3). The initial
+// condition is Y0, which corresponds to x0 = xs[0].
+//
+// The solution is returned at a number of equidistant points starting from and
+// including x0. The final point is the closest point to the last element of xs
+// with respect to the integration step. If the interval is shorter than half
+// the step, a single step that spans it is taken instead. The points of xs
+// should be strictly increasing. The points are returned as the second result.
+func (self *Integrator) Compute(ξ func(float64, []float64, []float64),
+	Y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	group := groups[self.config.Group]
+
+	nn := int(group.order * group.order)
+	if len(Y0) != nn {
+		return nil, nil, errors.New("the initial condition should be a matrix of the group")
+	}
+	if err := interval.Forward(Y0, xs); err != nil {
+		return nil, nil, err
+	}
+
+	nd := group.dimension
+
+	h := self.config.Step
+
+	x0, xend := xs[0], xs[len(xs)-1]
+	ns := int((xend-x0)/h+0.5) + 1
+	if ns == 1 {
+		ns, h = 2, xend-x0
+	}
+
+	Ys := make([]float64, ns*nn)
+	xs = make([]float64, ns)
+	copy(Ys, Y0)
+	xs[0] = x0
+
+	buffer := make([]float64, 6*nd)
+	k1, k2 := buffer[0*nd:1*nd], buffer[1*nd:2*nd]
+	k3, k4 := buffer[2*nd:3*nd], buffer[3*nd:4*nd]
+	u, w := buffer[4*nd:5*nd], buffer[5*nd:6*nd]
+
+	E := make([]float64, nn)
+	Z := make([]float64, nn)
+
+	// evaluate computes h ξ(x, exp(u) Y) and stores it in k.
+	evaluate := func(x float64, Y []float64, k []float64) {
+		group.exp(u, E)
+		dense.Multiply(E, Y, group.order, Z)
+		ξ(x, Z, k)
+		for i := range k {
+			k[i] *= h
+		}
+	}
+
+	for j := 1; j < ns; j++ {
+		x := x0 + float64(j-1)*h
+
+		Y, Ynew := Ys[(j-1)*nn:j*nn], Ys[j*nn:(j+1)*nn]
+
+		ξ(x, Y, k1)
+		for i := range k1 {
+			k1[i] *= h
+		}
+
+		for i := range u {
+			u[i] = k1[i] / 2
+		}
+		evaluate(x+h/2, Y, k2)
+
+		group.bracket(k1, k2, w)
+		for i := range u {
+			u[i] = k2[i]/2 - w[i]/8
+		}
+		evaluate(x+h/2, Y, k3)
+
+		copy(u, k3)
+		evaluate(x+h, Y, k4)
+
+		group.bracket(k1, k4, w)
+		for i := range u {
+			u[i] = (k1[i]+2*k2[i]+2*k3[i]+k4[i])/6 - w[i]/12
+		}
+		group.exp(u, E)
+		dense.Multiply(E, Y, group.order, Ynew)
+
+		xs[j] = x0 + float64(j)*h
+	}
+
+	return Ys, xs, nil
+}
+
+type group struct {
+	// The order of the matrices of the group.
+	order uint
+	// The dimension of the algebra.
+	dimension uint
+	// The exponential map, which stores the matrix of the group corresponding
+	// to u in E.
+	exp func(u []float64, E []float64)
+	// The bracket of the algebra, which stores [u, w] in r.
+	bracket func(u, w []float64, r []float64)
+}
+
+var groups = [...]group{
+	SO3: {order: 3, dimension: 3, exp: expSO3, bracket: bracketSO3},
+	SE3: {order: 4, dimension: 6, exp: expSE3, bracket: bracketSE3},
+}
+
+func expSO3(ω []float64, R []float64) {
+	a, b, _ := rodrigues(ω)
+	rotation(ω, a, b, R, 3)
+}
+
+func bracketSO3(ω1, ω2 []float64, r []float64) {
+	cross(ω1, ω2, r)
+}
+
+func expSE3(u []float64, T []float64) {
+	ω, v := u[:3], u[3:]
+
+	a, b, c := rodrigues(ω)
+	rotation(ω, a, b, T, 4)
+
+	// The translation is V v where V = I + b ω̂ + c ω̂².
+	var ωv, ωωv [3]float64
+	cross(ω, v, ωv[:])
+	cross(ω, ωv[:], ωωv[:])
+	for i := 0; i < 3; i++ {
+		T[i*4+3] = v[i] + b*ωv[i] + c*ωωv[i]
+		T[12+i] = 0
+	}
+	T[15] = 1
+}
+
+func bracketSE3(u1, u2 []float64, r []float64) {
+	var t [3]float64
+	cross(u1[:3], u2[:3], r[:3])
+	cross(u1[:3], u2[3:], r[3:])
+	cross(u2[:3], u1[3:], t[:])
+	for i := 0; i < 3; i++ {
+		r[3+i] -= t[i]
+	}
+}
+
+// rodrigues computes the coefficients sin(θ)/θ, (1 - cos(θ))/θ², and
+// (θ - sin(θ))/θ³ where θ = |ω|.
+func rodrigues(ω []float64) (a, b, c float64) {
+	θ2 := ω[0]*ω[0] + ω[1]*ω[1] + ω[2]*ω[2]
+	if θ2 < 1e-6 {
+		a = 1 - θ2/6*(1-θ2/20)
+		b = 0.5 - θ2/24*(1-θ2/30)
+		c = 1.0/6 - θ2/120*(1-θ2/42)
+		return
+	}
+	θ := math.Sqrt(θ2)
+	s, co := math.Sincos(θ)
+	a = s / θ
+	b = (1 - co) / θ2
+	c = (θ - s) / (θ2 * θ)
+	return
+}
+
+// rotation computes I + a ω̂ + b ω̂² and stores it in the top-left 3-by-3
+// block of a matrix of order n.
+func rotation(ω []float64, a, b float64, R []float64, n int) {
+	x, y, z := ω[0], ω[1], ω[2]
+
+	R[0*n+0] = 1 - b*(y*y+z*z)
+	R[0*n+1] = -a*z + b*x*y
+	R[0*n+2] = a*y + b*x*z
+	R[1*n+0] = a*z + b*x*y
+	R[1*n+1] = 1 - b*(x*x+z*z)
+	R[1*n+2] = -a*x + b*y*z
+	R[2*n+0] = -a*y + b*x*z
+	R[2*n+1] = a*x + b*y*z
+	R[2*n+2] = 1 - b*(x*x+y*y)
+}
+
+func cross(u, v []float64, r []float64) {
+	r[0], r[1], r[2] = u[1]*v[2]-u[2]*v[1], u[2]*v[0]-u[0]*v[2], u[0]*v[1]-u[1]*v[0]
+}
@@ -0,0 +1,112 @@
+package liegroup
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeConstant(t *testing.T) {
+	cases := []struct {
+		group Group
+		u     []float64
+		I     []float64
+	}{
+		{SO3, []float64{0.3, -1, 2}, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1}},
+		{SE3, []float64{0.3, -1, 2, 1, 0.5, -2}, []float64{
+			1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}},
+	}
+
+	for _, c := range cases {
+		constant := func(_ float64, _, u []float64) {
+			copy(u, c.u)
+		}
+
+		integrator, _ := New(&Config{Step: 0.1, Group: c.group})
+		Ys, xs, err := integrator.Compute(constant, c.I, []float64{0, 5})
+		assert.Equal(err, nil, t)
+
+		group := groups[c.group]
+		nn := len(c.I)
+		u := make([]float64, len(c.u))
+		E := make([]float64, nn)
+		for k, x := range xs {
+			for i := range u {
+				u[i] = x * c.u[i]
+			}
+			group.exp(u, E)
+			assert.Close(Ys[k*nn:(k+1)*nn], E, 1e-13, t)
+		}
+	}
+}
+
+func TestComputeOrthogonality(t *testing.T) {
+	varying := func(x float64, R, ω []float64) {
+		ω[0], ω[1], ω[2] = math.Sin(x), math.Cos(x), R[2]
+	}
+
+	integrator, _ := New(&Config{Step: 0.5})
+	Rs, xs, _ := integrator.Compute(varying, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1},
+		[]float64{0, 1000})
+
+	n := len(xs)
+	R := Rs[9*(n-1):]
+	RR := make([]float64, 9)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				RR[i*3+j] += R[k*3+i] * R[k*3+j]
+			}
+		}
+	}
+	assert.Close(RR, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1}, 1e-12, t)
+}
+
+func TestComputeOrder(t *testing.T) {
+	varying := func(x float64, R, ω []float64) {
+		ω[0], ω[1], ω[2] = math.Sin(x), math.Cos(x), R[2]
+	}
+
+	solve := func(h float64) []float64 {
+		integrator, _ := New(&Config{Step: h})
+		Rs, xs, _ := integrator.Compute(varying, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1},
+			[]float64{0, 2})
+		n := len(xs)
+		return Rs[9*(n-1):]
+	}
+
+	reference := solve(0.001)
+
+	distance := func(R []float64) float64 {
+		d := 0.0
+		for i := range R {
+			d = math.Max(d, math.Abs(R[i]-reference[i]))
+		}
+		return d
+	}
+
+	ratio := distance(solve(0.1)) / distance(solve(0.05))
+	assert.Close(math.Log2(ratio), 4.0, 0.2, t)
+}
+
+func TestComputeInterval(t *testing.T) {
+	constant := func(_ float64, _, u []float64) {
+		u[0], u[1], u[2] = 0.3, -1, 2
+	}
+
+	I := []float64{1, 0, 0, 0, 1, 0, 0, 0, 1}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	Ys, xs, err := integrator.Compute(constant, I, []float64{0, 0.01})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.01}, t)
+
+	E := make([]float64, len(I))
+	groups[SO3].exp([]float64{0.003, -0.01, 0.02}, E)
+	assert.Close(Ys[len(I):], E, 1e-13, t)
+
+	_, _, err = integrator.Compute(constant, I, []float64{1, 0})
+	assert.Equal(err != nil, true, t)
+}
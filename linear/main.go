@@ -0,0 +1,103 @@
+// Package linear provides a solver of linear systems of ordinary differential
+// equations with constant coefficients of the form
+//
+//	y′ = A y + b.
+//
+// Instead of stepping, the solution is computed directly as
+//
+//	y(x) = e^(A(x - x₀)) y₀ + (x - x₀) φ₁(A(x - x₀)) b,
+//
+// where φ₁(z) = (e^z - 1) / z. Both terms are obtained at once from the
+// exponential of the augmented matrix [[A, b], [0, 0]], which is computed
+// using scaling and squaring. When the points of xs are equidistant, the
+// exponential is computed only once and applied repeatedly.
+//
+// The result is accurate to a small multiple of the machine precision unless
+// the norm of A times the distance between the points is large, in which case
+// the error grows with the number of squarings.
+//
+// https://en.wikipedia.org/wiki/Matrix_exponential
+package linear
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode/internal/dense"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+}
+
+// New creates a new integrator.
+func New() *Integrator {
+	return &Integrator{}
+}
+
+// Compute solves the system y′ = A y + b.
+//
+// The matrix A is stored in row-major order, and the vector b can be nil, in
+// which case the system is homogeneous. The initial condition is y0, which
+// corresponds to x0 = xs[0]. The solution is returned at all the points of
+// xs.
+func (self *Integrator) Compute(A, b []float64, y0 []float64,
+	xs []float64) ([]float64, []float64, error) {
+
+	nd, nx := len(y0), len(xs)
+	if len(A) != nd*nd {
+		return nil, nil, errors.New("the matrix should be square and match the initial condition")
+	}
+	if b != nil && len(b) != nd {
+		return nil, nil, errors.New("the vector should match the initial condition")
+	}
+	if nx < 2 {
+		return nil, nil, errors.New("the interval should have two endpoints")
+	}
+
+	// The order of the augmented matrix.
+	n := uint(nd + 1)
+
+	M := make([]float64, n*n)
+	E := make([]float64, n*n)
+
+	ys := make([]float64, nx*nd)
+	copy(ys, y0)
+
+	// The augmented state, which is [y; 1].
+	z := make([]float64, n)
+	znew := make([]float64, n)
+	copy(z, y0)
+	z[nd] = 1
+
+	Δ := 0.0
+	for k := 1; k < nx; k++ {
+		if δ := xs[k] - xs[k-1]; δ != Δ {
+			Δ = δ
+			for i := 0; i < nd; i++ {
+				for j := 0; j < nd; j++ {
+					M[uint(i)*n+uint(j)] = Δ * A[i*nd+j]
+				}
+				if b != nil {
+					M[uint(i)*n+uint(nd)] = Δ * b[i]
+				}
+			}
+			if err := dense.Exp(M, n, E); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		for i := uint(0); i < n; i++ {
+			sum := 0.0
+			for j := uint(0); j < n; j++ {
+				sum += E[i*n+j] * z[j]
+			}
+			znew[i] = sum
+		}
+		z, znew = znew, z
+		z[nd] = 1
+
+		copy(ys[k*nd:(k+1)*nd], z[:nd])
+	}
+
+	return ys, xs, nil
+}
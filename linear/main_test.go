@@ -0,0 +1,52 @@
+package linear
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeOscillator(t *testing.T) {
+	const (
+		ω = 10.0
+		ζ = 0.1
+	)
+
+	// y″ + 2ζω y′ + ω² y = ω², which settles at one.
+	A := []float64{
+		0, 1,
+		-ω * ω, -2 * ζ * ω,
+	}
+	b := []float64{0, ω * ω}
+
+	xs := []float64{0, 0.1, 0.2, 0.3, 0.5, 0.7, 1, 2, 5}
+
+	ys, _, err := New().Compute(A, b, []float64{0, 0}, xs)
+	assert.Equal(err, nil, t)
+
+	ωd := ω * math.Sqrt(1-ζ*ζ)
+	for k, x := range xs {
+		e := math.Exp(-ζ * ω * x)
+		y := 1 - e*(math.Cos(ωd*x)+ζ*ω/ωd*math.Sin(ωd*x))
+		assert.Close(ys[2*k], y, 1e-13, t)
+	}
+}
+
+func TestComputeStiff(t *testing.T) {
+	A := []float64{
+		-1e6, 0,
+		0, -1,
+	}
+
+	xs := []float64{0, 1, 2, 3, 4}
+
+	ys, _, err := New().Compute(A, nil, []float64{1, 1}, xs)
+	assert.Equal(err, nil, t)
+	for k, x := range xs {
+		assert.Close(ys[2*k:2*k+2], []float64{math.Exp(-1e6 * x), math.Exp(-x)}, 1e-10, t)
+	}
+
+	_, _, err = New().Compute(A, []float64{1}, []float64{1, 1}, xs)
+	assert.Equal(err != nil, true, t)
+}
@@ -0,0 +1,40 @@
+package lowstorage
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The maximal step of integration.
+	Step float64 `json:"step"`
+	// The method of integration.
+	Scheme Scheme `json:"scheme"`
+}
+
+// Scheme is a choice of a low-storage method.
+type Scheme uint
+
+const (
+	// The five-stage method of order four due to Carpenter and Kennedy.
+	CarpenterKennedy4 Scheme = iota
+	// The three-stage method of order three due to Williamson.
+	Williamson3
+)
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Scheme > Williamson3 {
+		return errors.New("the scheme is unknown")
+	}
+
+	return nil
+}
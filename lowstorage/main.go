@@ -0,0 +1,134 @@
+// Package lowstorage provides integrators of large systems of ordinary
+// differential equations based on low-storage explicit Runge–Kutta methods of
+// Williamson's 2N type.
+//
+// Each stage of a step is given by
+//
+//	Δ = Aᵢ Δ + h f(x + cᵢ h, y),
+//	y = y + Bᵢ Δ,
+//
+// with A₁ = 0. Apart from the solution itself, only the increment Δ and the
+// derivative need to be stored, regardless of the number of stages, which is
+// beneficial for very large systems such as discretizations of partial
+// differential equations.
+//
+// https://en.wikipedia.org/wiki/Runge%E2%80%93Kutta_methods
+package lowstorage
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// The solution is returned only at the points of xs, which keeps the memory
+// usage low for large systems. Each interval between two consecutive points is
+// traversed using equal steps that do not exceed the step of the
+// configuration. If xend < x0, the integration is carried out backward.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	if err := interval.Validate(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	nd, nx := len(y0), len(xs)
+
+	A, B, C := coefficients(self.config.Scheme)
+
+	Δ := make([]float64, nd)
+	f := make([]float64, nd)
+
+	ys := make([]float64, nx*nd)
+	copy(ys, y0)
+
+	for k := 1; k < nx; k++ {
+		y := ys[k*nd : (k+1)*nd]
+		copy(y, ys[(k-1)*nd:k*nd])
+
+		x, length := xs[k-1], xs[k]-xs[k-1]
+
+		// The step is signed, so the interval is traversed backward if the
+		// points are decreasing.
+		ns := math.Ceil(math.Abs(length) / self.config.Step)
+		if ns < 1 {
+			ns = 1
+		}
+		h := length / ns
+
+		for j := 0; j < int(ns); j++ {
+			for s := range A {
+				dydx(x+C[s]*h, y, f)
+				stats.Evaluations++
+				for i := 0; i < nd; i++ {
+					Δ[i] = A[s]*Δ[i] + h*f[i]
+					y[i] += B[s] * Δ[i]
+				}
+			}
+			x = xs[k-1] + float64(j+1)*h
+			stats.Steps++
+		}
+	}
+
+	return ys, xs, stats, nil
+}
+
+func coefficients(scheme Scheme) (A, B, C []float64) {
+	switch scheme {
+	case Williamson3:
+		A = []float64{0, -5.0 / 9, -153.0 / 128}
+		B = []float64{1.0 / 3, 15.0 / 16, 8.0 / 15}
+		C = []float64{0, 1.0 / 3, 3.0 / 4}
+	default:
+		A = []float64{
+			0,
+			-567301805773.0 / 1357537059087,
+			-2404267990393.0 / 2016746695238,
+			-3550918686646.0 / 2091501179385,
+			-1275806237668.0 / 842570457699,
+		}
+		B = []float64{
+			1432997174477.0 / 9575080441755,
+			5161836677717.0 / 13612068292357,
+			1720146321549.0 / 2090206949498,
+			3134564353537.0 / 4481467310338,
+			2277821191437.0 / 14882151754819,
+		}
+		C = []float64{
+			0,
+			1432997174477.0 / 9575080441755,
+			2526269341429.0 / 6820363962896,
+			2006345519317.0 / 3224310063776,
+			2802321613138.0 / 2924317926251,
+		}
+	}
+	return
+}
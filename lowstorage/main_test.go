@@ -0,0 +1,64 @@
+package lowstorage
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeDecay(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{0, 0.3, 0.5, 1.1, 2}
+
+	integrator, _ := New(&Config{Step: 0.01})
+
+	ys, _, _ := integrator.Compute(dydx, []float64{0.5}, xs)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 1e-10, t)
+	}
+}
+
+func TestComputeBackward(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{2, 1.1, 0.5, 0.3, 0}
+
+	integrator, _ := New(&Config{Step: 0.01})
+
+	y0 := 0.5 * (math.Cos(2) + math.Sin(2))
+	ys, _, _ := integrator.Compute(dydx, []float64{y0}, xs)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 1e-10, t)
+	}
+}
+
+func TestComputeOrder(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0]*y[0] + math.Cos(x)
+	}
+
+	reference := func() float64 {
+		integrator, _ := New(&Config{Step: 1e-4})
+		ys, _, _ := integrator.Compute(dydx, []float64{1}, []float64{0, 2})
+		return ys[1]
+	}()
+
+	orders := map[Scheme]float64{CarpenterKennedy4: 4, Williamson3: 3}
+
+	for scheme, order := range orders {
+		solve := func(h float64) float64 {
+			integrator, _ := New(&Config{Step: h, Scheme: scheme})
+			ys, _, _ := integrator.Compute(dydx, []float64{1}, []float64{0, 2})
+			return math.Abs(ys[1] - reference)
+		}
+
+		ratio := solve(0.1) / solve(0.05)
+		assert.Close(math.Log2(ratio), order, 0.15, t)
+	}
+}
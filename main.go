@@ -13,3 +13,74 @@ type Integrator interface {
 	Compute(dydx func(float64, []float64, []float64), y0 []float64,
 		xs []float64) ([]float64, []float64, error)
 }
+
+// Stats is the work done by an integrator in the form common to all
+// integrators.
+type Stats struct {
+	Evaluations uint `json:"evaluations"` // The number of invocations of the derivative function.
+	Rejections  uint `json:"rejections"`  // The number of rejected steps.
+	Steps       uint `json:"steps"`       // The number of accepted steps.
+}
+
+// StatsIntegrator is an integrator that reports the work done.
+type StatsIntegrator interface {
+	Integrator
+
+	// ComputeWithSummary augments Compute by returning the work done.
+	ComputeWithSummary(dydx func(float64, []float64, []float64), y0 []float64,
+		xs []float64) ([]float64, []float64, *Stats, error)
+}
+
+// Dense is a solution that can be evaluated at any point of the interval of
+// integration.
+type Dense interface {
+	// Span returns the interval where the solution is defined.
+	Span() (float64, float64)
+
+	// At evaluates the solution at a point and stores the result in y.
+	At(x float64, y []float64) error
+}
+
+// DenseIntegrator is an integrator that provides dense output.
+type DenseIntegrator interface {
+	Integrator
+
+	// ComputeDense integrates the system of differential equations like
+	// Compute but returns the solution in a form that can be evaluated at any
+	// point of the interval of integration, whose endpoints are the first and
+	// last entries of xs.
+	ComputeDense(dydx func(float64, []float64, []float64), y0 []float64,
+		xs []float64) (Dense, error)
+}
+
+// Event is an event whose occurrences are located during an integration. An
+// event occurs when its function changes its sign.
+type Event struct {
+	// The event function g(x, y).
+	Function func(x float64, y []float64) float64
+	// The direction of the crossings of zero that are located, which is
+	// positive for the ones where the function increases, negative for the
+	// ones where it decreases, and zero for both.
+	Direction int
+	// Should the integration stop at the first crossing?
+	Terminal bool
+}
+
+// Crossing is an occurrence of an event.
+type Crossing struct {
+	Index uint      `json:"index"` // The index of the event.
+	X     float64   `json:"x"`     // The location of the crossing.
+	Y     []float64 `json:"y"`     // The solution at the location of the crossing.
+}
+
+// EventIntegrator is an integrator that locates events.
+type EventIntegrator interface {
+	Integrator
+
+	// ComputeWithCrossings integrates the system of differential equations
+	// like Compute and reports the occurrences of the events in the
+	// chronological order. If a terminal event occurs, the integration stops
+	// at its first crossing, which is then the last point of the solution.
+	ComputeWithCrossings(dydx func(float64, []float64, []float64), y0 []float64,
+		xs []float64, events []Event) ([]float64, []float64, []Crossing, error)
+}
@@ -1,19 +1,537 @@
-package ode
+package ode_test
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
 	"testing"
 
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/adams"
+	"github.com/ready-steady/ode/auto"
+	"github.com/ready-steady/ode/bdf"
+	"github.com/ready-steady/ode/beuler"
+	"github.com/ready-steady/ode/bs23"
+	"github.com/ready-steady/ode/cashkarp"
+	"github.com/ready-steady/ode/dop853"
 	"github.com/ready-steady/ode/dopri"
+	"github.com/ready-steady/ode/euler"
+	"github.com/ready-steady/ode/gbs"
+	"github.com/ready-steady/ode/heun"
+	"github.com/ready-steady/ode/lowstorage"
+	"github.com/ready-steady/ode/midpoint"
+	"github.com/ready-steady/ode/radau"
 	"github.com/ready-steady/ode/rk4"
+	"github.com/ready-steady/ode/rk4a"
+	"github.com/ready-steady/ode/rosenbrock"
+	"github.com/ready-steady/ode/sdirk"
+	"github.com/ready-steady/ode/ssp"
+	"github.com/ready-steady/ode/trbdf2"
+	"github.com/ready-steady/ode/tsit5"
+	"github.com/ready-steady/ode/verner87"
 )
 
 func TestIntegrator(t *testing.T) {
-	var integrator Integrator
+	var integrator ode.Integrator
 
+	integrator, _ = adams.New(adams.DefaultConfig())
+	integrator, _ = auto.New(auto.DefaultConfig())
+	integrator, _ = bdf.New(bdf.DefaultConfig())
+	integrator, _ = beuler.New(beuler.DefaultConfig())
+	integrator, _ = bs23.New(bs23.DefaultConfig())
+	integrator, _ = cashkarp.New(cashkarp.DefaultConfig())
+	integrator, _ = dop853.New(dop853.DefaultConfig())
 	integrator, _ = dopri.New(dopri.DefaultConfig())
+	integrator, _ = euler.New(&euler.Config{Step: 42})
+	integrator, _ = gbs.New(gbs.DefaultConfig())
+	integrator, _ = heun.New(&heun.Config{Step: 42})
+	integrator, _ = lowstorage.New(&lowstorage.Config{Step: 42})
+	integrator, _ = midpoint.New(&midpoint.Config{Step: 42})
+	integrator, _ = radau.New(radau.DefaultConfig())
 	integrator, _ = rk4.New(&rk4.Config{Step: 42})
+	integrator, _ = rk4a.New(rk4a.DefaultConfig())
+	integrator, _ = rosenbrock.New(rosenbrock.DefaultConfig())
+	integrator, _ = sdirk.New(sdirk.DefaultConfig())
+	integrator, _ = ssp.New(&ssp.Config{Step: 42})
+	integrator, _ = trbdf2.New(trbdf2.DefaultConfig())
+	integrator, _ = tsit5.New(tsit5.DefaultConfig())
+	integrator, _ = verner87.New(verner87.DefaultConfig())
 
 	blackbox(integrator)
 }
 
+func TestNew(t *testing.T) {
+	methods := ode.Methods()
+	for _, method := range []string{"bdf", "dopri", "radau", "rk4", "tsit5"} {
+		found := false
+		for _, name := range methods {
+			found = found || name == method
+		}
+		assert.Equal(found, true, t)
+	}
+
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	for _, method := range methods {
+		options := &ode.Options{AbsError: 1e-8, RelError: 1e-8, Step: 1e-3}
+
+		integrator, err := ode.New(method, options)
+		assert.Equal(err, nil, t)
+
+		ys, _, err := integrator.Compute(dydx, []float64{1}, []float64{0, 1})
+		assert.Equal(err, nil, t)
+		assert.Close(ys[len(ys)-1], math.Exp(-1), 1e-3, t)
+	}
+
+	_, err := ode.New("rk4", nil)
+	assert.Equal(err != nil, true, t)
+
+	_, err = ode.New("unknown", nil)
+	assert.Equal(err != nil, true, t)
+
+	ode.Register("custom", func(options *ode.Options) (ode.Integrator, error) {
+		config := dopri.DefaultConfig()
+		options.Adapt(&config.TryStep, &config.MaxStep, &config.AbsError, &config.RelError)
+		integrator, err := dopri.New(config)
+		if err != nil {
+			return nil, err
+		}
+		return integrator, nil
+	})
+	_, err = ode.New("custom", &ode.Options{RelError: -1})
+	assert.Equal(err != nil, true, t)
+
+	defer func() {
+		assert.Equal(recover() != nil, true, t)
+	}()
+	ode.Register("dopri", func(*ode.Options) (ode.Integrator, error) {
+		return nil, nil
+	})
+}
+
+func TestValidate(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	cases := []struct {
+		y0, xs []float64
+	}{
+		{[]float64{}, []float64{0, 1}},
+		{[]float64{1}, []float64{0}},
+		{[]float64{1}, []float64{0, 0.5, 0.2, 1}},
+		{[]float64{1}, []float64{0, 0.5, 0.5, 1}},
+	}
+
+	for _, method := range ode.Methods() {
+		integrator, err := ode.New(method, &ode.Options{AbsError: 1e-8, RelError: 1e-8, Step: 1e-3})
+		assert.Equal(err, nil, t)
+
+		for _, c := range cases {
+			_, _, err := integrator.Compute(dydx, c.y0, c.xs)
+			assert.Equal(err != nil, true, t)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	integrator, err := ode.Load(strings.NewReader(`{
+		"method": "dopri",
+		"config": {"absError": 1e-10, "relError": 1e-10, "maxStep": 0.1}
+	}`))
+	assert.Equal(err, nil, t)
+
+	ys1, _, _ := integrator.Compute(func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}, []float64{1}, []float64{0, 1})
+
+	reference, _ := dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10, MaxStep: 0.1})
+	ys2, _, _ := reference.Compute(func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}, []float64{1}, []float64{0, 1})
+
+	assert.Equal(ys1, ys2, t)
+
+	config, err := json.Marshal(dopri.DefaultConfig())
+	assert.Equal(err, nil, t)
+	_, err = (&ode.Spec{Method: "tsit5", Config: config}).New()
+	assert.Equal(err, nil, t)
+
+	_, err = (&ode.Spec{Method: "rk4", Config: []byte(`{"step": 0.1}`)}).New()
+	assert.Equal(err, nil, t)
+
+	for _, method := range []string{"euler", "heun", "midpoint", "rk4"} {
+		_, err = ode.Load(strings.NewReader(`{"method": "` + method + `", "config": {"step": 0}}`))
+		assert.Equal(err != nil, true, t)
+	}
+	_, err = rk4.New(&rk4.Config{Step: 0})
+	assert.Equal(err != nil, true, t)
+
+	_, err = ode.Load(strings.NewReader(`{"method": "dopri", "config": {"relError": -1}}`))
+	assert.Equal(errors.Is(err, dopri.ErrInvalidConfig), true, t)
+
+	_, err = ode.Load(strings.NewReader(`{"method": "dopri", "config": {"tolerance": 1}}`))
+	assert.Equal(err != nil, true, t)
+
+	_, err = ode.Load(strings.NewReader(`{"method": "unknown"}`))
+	assert.Equal(err != nil, true, t)
+
+	assert.Equal(dopri.DefaultConfig().Validate(), nil, t)
+	assert.Equal(errors.Is((&dopri.Config{}).Validate(), dopri.ErrInvalidConfig), true, t)
+	assert.Equal((&bdf.Config{AbsError: 1}).Validate() != nil, true, t)
+}
+
+func TestInterfaces(t *testing.T) {
+	var _ ode.StatsIntegrator = (*dopri.Integrator)(nil)
+	var _ ode.StatsIntegrator = (*rk4.Integrator)(nil)
+	var _ ode.StatsIntegrator = (*bdf.Integrator)(nil)
+	var _ ode.StatsIntegrator = (*radau.Integrator)(nil)
+	var _ ode.DenseIntegrator = (*dopri.Integrator)(nil)
+	var _ ode.EventIntegrator = (*dopri.Integrator)(nil)
+
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	var integrator ode.Integrator
+	integrator, _ = dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10})
+
+	_, _, stats, err := integrator.(ode.StatsIntegrator).ComputeWithSummary(dydx,
+		[]float64{1, 0}, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Steps > 0, true, t)
+	assert.Equal(stats.Evaluations > 6*stats.Steps, true, t)
+
+	solution, err := integrator.(ode.DenseIntegrator).ComputeDense(dydx,
+		[]float64{1, 0}, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	y := make([]float64, 2)
+	assert.Equal(solution.At(0.3, y), nil, t)
+	assert.Close(y, []float64{math.Cos(0.3), -math.Sin(0.3)}, 1e-8, t)
+
+	events := []ode.Event{{
+		Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		},
+		Direction: -1,
+		Terminal:  true,
+	}}
+	_, xs, crossings, err := integrator.(ode.EventIntegrator).ComputeWithCrossings(dydx,
+		[]float64{1, 0}, []float64{0, 10}, events)
+	assert.Equal(err, nil, t)
+	assert.Equal(len(crossings), 1, t)
+	assert.Close(crossings[0].X, math.Pi/2, 1e-8, t)
+	assert.Equal(xs[len(xs)-1], crossings[0].X, t)
+
+	integrator, _ = rk4.New(&rk4.Config{Step: 0.1})
+
+	_, _, stats, err = integrator.(ode.StatsIntegrator).ComputeWithSummary(dydx,
+		[]float64{1, 0}, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(*stats, ode.Stats{Evaluations: 40, Steps: 10}, t)
+}
+
+func TestSolve(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	options := &ode.SolveOptions{
+		Options: ode.Options{AbsError: 1e-10, RelError: 1e-10},
+		Points:  []float64{1, 2},
+	}
+	result, err := ode.Solve(dydx, []float64{1, 0}, [2]float64{0, 3}, options)
+	assert.Equal(err, nil, t)
+	assert.Equal(result.Len(), 4, t)
+	assert.Equal(result.Xs, []float64{0, 1, 2, 3}, t)
+	assert.Equal(result.Status, ode.StatusDone, t)
+	assert.Equal(result.Stats.Steps > 0, true, t)
+	for i := range result.Xs {
+		x, y := result.At(i)
+		assert.Close(y, []float64{math.Cos(x), -math.Sin(x)}, 1e-8, t)
+	}
+	assert.Close(result.Component(1), []float64{0, -math.Sin(1), -math.Sin(2), -math.Sin(3)}, 1e-8, t)
+
+	options.Method = "rk4"
+	options.Step = 1e-2
+	options.Events = []ode.Event{{
+		Function: func(_ float64, y []float64) float64 {
+			return y[0]
+		},
+		Terminal: true,
+	}}
+	_, err = ode.Solve(dydx, []float64{1, 0}, [2]float64{0, 3}, options)
+	assert.Equal(err != nil, true, t)
+
+	options.Method = ""
+	options.Points = nil
+	result, err = ode.Solve(dydx, []float64{1, 0}, [2]float64{0, 3}, options)
+	assert.Equal(err, nil, t)
+	assert.Equal(result.Status, ode.StatusTerminal, t)
+	assert.Equal(len(result.Events), 1, t)
+	x, y := result.Last()
+	assert.Close(x, math.Pi/2, 1e-8, t)
+	assert.Equal(y, result.Events[0].Y, t)
+}
+
+func TestSolveStats(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	for _, method := range ode.Methods() {
+		options := &ode.SolveOptions{
+			Method:  method,
+			Options: ode.Options{AbsError: 1e-8, RelError: 1e-8, Step: 1e-2},
+		}
+		result, err := ode.Solve(dydx, []float64{1}, [2]float64{0, 1}, options)
+		assert.Equal(err, nil, t)
+		assert.Equal(result.Stats != nil, true, t)
+		assert.Equal(result.Stats.Evaluations > 0, true, t)
+		assert.Equal(result.Stats.Steps > 0, true, t)
+	}
+}
+
+func TestSolvePoints(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	for _, method := range ode.Methods() {
+		options := &ode.SolveOptions{
+			Method:  method,
+			Options: ode.Options{AbsError: 1e-8, RelError: 1e-8, Step: 1e-1},
+			Points:  []float64{0.25},
+		}
+		result, err := ode.Solve(dydx, []float64{1}, [2]float64{0, 1}, options)
+		assert.Equal(err, nil, t)
+		assert.Equal(result.Xs, []float64{0, 0.25, 1}, t)
+		assert.Equal(len(result.Ys), 3, t)
+		x, y := result.Last()
+		assert.Close(y[0], math.Exp(-x), 5e-2, t)
+	}
+}
+
+func TestSolveNames(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	options := &ode.SolveOptions{
+		Options: ode.Options{AbsError: 1e-10, RelError: 1e-10},
+		Points:  []float64{1, 2},
+		Names:   []string{"position", "velocity"},
+	}
+	result, err := ode.Solve(dydx, []float64{1, 0}, [2]float64{0, 3}, options)
+	assert.Equal(err, nil, t)
+	assert.Equal(result.Names, options.Names, t)
+	assert.Equal(result.Named("velocity"), result.Component(1), t)
+	assert.Equal(result.Named("acceleration") == nil, true, t)
+
+	options.Names = []string{"position"}
+	_, err = ode.Solve(dydx, []float64{1, 0}, [2]float64{0, 3}, options)
+	assert.Equal(err != nil, true, t)
+
+	options.Names = []string{"position", "position"}
+	_, err = ode.Solve(dydx, []float64{1, 0}, [2]float64{0, 3}, options)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestResultWrite(t *testing.T) {
+	result := &ode.Result{
+		Xs:     []float64{0, 0.5, 1},
+		Ys:     [][]float64{{1, 0}, {0.25, -1e-20}, {-2, 3}},
+		Stats:  &ode.Stats{Evaluations: 7, Steps: 1},
+		Status: ode.StatusDone,
+	}
+
+	var buffer bytes.Buffer
+	assert.Equal(result.WriteCSV(&buffer), nil, t)
+	assert.Equal(buffer.String(), "x,y1,y2\n0,1,0\n0.5,0.25,-1e-20\n1,-2,3\n", t)
+
+	buffer.Reset()
+	assert.Equal(result.WriteJSON(&buffer), nil, t)
+	var decoded ode.Result
+	assert.Equal(json.Unmarshal(buffer.Bytes(), &decoded), nil, t)
+	assert.Equal(decoded, *result, t)
+	assert.Equal(strings.Contains(buffer.String(), `"events"`), false, t)
+
+	buffer.Reset()
+	assert.Equal(result.WriteNPY(&buffer), nil, t)
+	data := buffer.Bytes()
+	assert.Equal(string(data[:8]), "\x93NUMPY\x01\x00", t)
+	length := int(binary.LittleEndian.Uint16(data[8:10]))
+	assert.Equal((10+length)%64, 0, t)
+	header := string(data[10 : 10+length])
+	assert.Equal(strings.HasPrefix(header,
+		"{'descr': '<f8', 'fortran_order': False, 'shape': (3, 3), }"), true, t)
+	assert.Equal(strings.HasSuffix(header, "\n"), true, t)
+	values := make([]float64, 9)
+	assert.Equal(binary.Read(bytes.NewReader(data[10+length:]), binary.LittleEndian, values), nil, t)
+	assert.Equal(values, []float64{0, 1, 0, 0.5, 0.25, -1e-20, 1, -2, 3}, t)
+
+	result.Names = []string{"temperature", "flux, net"}
+
+	buffer.Reset()
+	assert.Equal(result.WriteCSV(&buffer), nil, t)
+	assert.Equal(strings.HasPrefix(buffer.String(), "x,temperature,\"flux, net\"\n0,1,0\n"), true, t)
+
+	buffer.Reset()
+	assert.Equal(result.WriteJSON(&buffer), nil, t)
+	decoded = ode.Result{}
+	assert.Equal(json.Unmarshal(buffer.Bytes(), &decoded), nil, t)
+	assert.Equal(decoded.Names, result.Names, t)
+
+	result.Ys[1][0] = math.NaN()
+	assert.Equal(result.WriteJSON(&buffer) != nil, true, t)
+}
+
+func TestResample(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10})
+	solution, err := integrator.ComputeDense(dydx, []float64{1, 0}, []float64{0, 10})
+	assert.Equal(err, nil, t)
+
+	ys, xs, err := ode.Resample(solution, 2, 101)
+	assert.Equal(err, nil, t)
+	assert.Equal(len(xs), 101, t)
+	assert.Equal(len(ys), 202, t)
+	assert.Equal([]float64{xs[0], xs[50], xs[100]}, []float64{0, 5, 10}, t)
+	for i, x := range xs {
+		assert.Close(ys[2*i:2*i+2], []float64{math.Cos(x), -math.Sin(x)}, 1e-8, t)
+	}
+
+	_, _, err = ode.Resample(solution, 2, 1)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeScalar(t *testing.T) {
+	integrator, _ := dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10})
+
+	ys, xs, err := ode.ComputeScalar(integrator, func(x, y float64) float64 {
+		return -2 * x * y
+	}, 1, []float64{0, 0.5, 1, 2})
+	assert.Equal(err, nil, t)
+	assert.Equal(len(ys), len(xs), t)
+	for i, x := range xs {
+		assert.Close(ys[i], math.Exp(-x*x), 1e-8, t)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = -p[0] * y[0]
+	}
+
+	integrator, _ := dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10})
+
+	ps := [][]float64{{1}, {2}, {3}}
+	xs := []float64{0, 0.5, 1}
+
+	results := ode.Sweep(integrator, dydx, []float64{1}, ps, xs)
+	assert.Equal(len(results), len(ps), t)
+	for k, result := range results {
+		assert.Equal(result.Err, nil, t)
+		assert.Equal(result.P, ps[k], t)
+		assert.Equal(result.Xs, xs, t)
+		for i, x := range xs {
+			assert.Close(result.Ys[i], math.Exp(-ps[k][0]*x), 1e-9, t)
+		}
+	}
+}
+
+func TestComputeComplex(t *testing.T) {
+	// The Schrödinger equation of a two-level system with the Hamiltonian
+	// ω σx, whose solution starting from the first level is
+	// y(x) = (cos(ωx), -i sin(ωx)).
+	const ω = 2.0
+
+	dydx := func(_ float64, y, f []complex128) {
+		f[0] = -1i * ω * y[1]
+		f[1] = -1i * ω * y[0]
+	}
+
+	integrator, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-12})
+
+	xs := []float64{0, 0.5, 1, 2}
+	ys, zs, err := ode.ComputeComplex(integrator, dydx, []complex128{1, 0}, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(zs, xs, t)
+	assert.Equal(len(ys), 2*len(xs), t)
+	for k, x := range xs {
+		assert.Close([]float64{real(ys[2*k]), imag(ys[2*k]), real(ys[2*k+1]), imag(ys[2*k+1])},
+			[]float64{math.Cos(ω * x), 0, 0, -math.Sin(ω * x)}, 1e-10, t)
+	}
+}
+
+func TestComputeMatrix(t *testing.T) {
+	A := []float64{
+		-1, 2,
+		0, -3,
+	}
+	Q := []float64{
+		1, 0,
+		0, 2,
+	}
+
+	integrator, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-12})
+
+	xs := []float64{0, 0.5, 1, 20}
+
+	Xs, zs, err := ode.ComputeMatrix(integrator, ode.Lyapunov(A, Q, 2), make([]float64, 4),
+		2, 2, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(zs, xs, t)
+	assert.Equal(len(Xs), len(xs), t)
+
+	Ys, zs, err := ode.ComputeSymmetric(integrator, ode.Lyapunov(A, Q, 2),
+		make([]float64, 4), 2, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(zs, xs, t)
+	for k := range xs {
+		assert.Equal(Ys[k][1], Ys[k][2], t)
+		assert.Close(Ys[k], Xs[k], 1e-10, t)
+	}
+
+	// The steady state satisfies A X + X Aᵀ + Q = 0.
+	F := make([]float64, 4)
+	ode.Lyapunov(A, Q, 2)(0, Ys[3], F)
+	assert.Close(F, make([]float64, 4), 1e-8, t)
+
+	_, _, err = ode.ComputeSymmetric(integrator, ode.Lyapunov(A, Q, 2),
+		[]float64{0, 1, 2, 0}, 2, xs)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestRiccati(t *testing.T) {
+	// X′ = I - X² with X(0) = 0, whose solution is X(x) = tanh(x) I.
+	I := []float64{
+		1, 0,
+		0, 1,
+	}
+
+	integrator, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-12})
+
+	xs := []float64{0, 0.5, 1, 2}
+	Xs, _, err := ode.ComputeSymmetric(integrator, ode.Riccati(make([]float64, 4), I, I, 2),
+		make([]float64, 4), 2, xs)
+	assert.Equal(err, nil, t)
+	for k, x := range xs {
+		assert.Close(Xs[k], []float64{math.Tanh(x), 0, 0, math.Tanh(x)}, 1e-10, t)
+	}
+}
+
 func blackbox(_ interface{}) {}
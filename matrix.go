@@ -0,0 +1,169 @@
+package ode
+
+import (
+	"errors"
+)
+
+// ComputeMatrix integrates the matrix differential equation dX/dx = F(x, X)
+// where X is an nr-by-nc matrix using an integrator. See Integrator.Compute.
+//
+// The input function dXdx(x, X, F) evaluates F(x, X) for a given x and X and
+// stores the result in its last argument. All matrices are stored in
+// row-major order. The initial condition is X0. The function returns the
+// solution as one matrix per point of the output grid, which is also
+// returned.
+func ComputeMatrix(integrator Integrator, dXdx func(float64, []float64, []float64),
+	X0 []float64, nr, nc uint, xs []float64) ([][]float64, []float64, error) {
+
+	nd := nr * nc
+	if nd == 0 {
+		return nil, nil, errors.New("the matrix should not be empty")
+	}
+	if uint(len(X0)) != nd {
+		return nil, nil, errors.New("the initial condition should match the dimensions of the matrix")
+	}
+
+	ys, xs, err := integrator.Compute(dXdx, X0, xs)
+
+	return unflatten(ys, nd), xs, err
+}
+
+// ComputeSymmetric integrates the matrix differential equation
+// dX/dx = F(x, X) where X is a symmetric n-by-n matrix using an integrator.
+// See ComputeMatrix.
+//
+// Only the upper triangle of X is integrated, which reduces the dimension of
+// the system from n² to n(n+1)/2 and keeps X exactly symmetric regardless of
+// the rounding errors in F. The function dXdx receives the full matrix, and
+// the upper triangle of its result is replaced by that of (F + Fᵀ)/2 before
+// being passed to the integrator. The initial condition X0 should be
+// symmetric.
+func ComputeSymmetric(integrator Integrator, dXdx func(float64, []float64, []float64),
+	X0 []float64, n uint, xs []float64) ([][]float64, []float64, error) {
+
+	nn := n * n
+	if nn == 0 {
+		return nil, nil, errors.New("the matrix should not be empty")
+	}
+	if uint(len(X0)) != nn {
+		return nil, nil, errors.New("the initial condition should match the dimensions of the matrix")
+	}
+	for i := uint(0); i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if X0[i*n+j] != X0[j*n+i] {
+				return nil, nil, errors.New("the initial condition should be symmetric")
+			}
+		}
+	}
+
+	X, F := make([]float64, nn), make([]float64, nn)
+
+	dydx := func(x float64, y, f []float64) {
+		expand(y, n, X)
+		dXdx(x, X, F)
+		for i, k := uint(0), 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				f[k] = (F[i*n+j] + F[j*n+i]) / 2
+				k++
+			}
+		}
+	}
+
+	y0 := make([]float64, n*(n+1)/2)
+	for i, k := uint(0), 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			y0[k] = X0[i*n+j]
+			k++
+		}
+	}
+
+	ys, xs, err := integrator.Compute(dydx, y0, xs)
+
+	nt := uint(len(y0))
+	Xs := make([][]float64, uint(len(ys))/nt)
+	for k := range Xs {
+		Xs[k] = make([]float64, nn)
+		expand(ys[uint(k)*nt:uint(k+1)*nt], n, Xs[k])
+	}
+
+	return Xs, xs, err
+}
+
+// Lyapunov returns the right-hand side of the differential Lyapunov equation
+//
+//	dX/dx = A X + X Aᵀ + Q
+//
+// where A and Q are constant n-by-n matrices stored in row-major order. The
+// matrices are copied.
+func Lyapunov(A, Q []float64, n uint) func(float64, []float64, []float64) {
+	A = append([]float64(nil), A...)
+	Q = append([]float64(nil), Q...)
+
+	return func(_ float64, X, F []float64) {
+		for i := uint(0); i < n; i++ {
+			for j := uint(0); j < n; j++ {
+				sum := Q[i*n+j]
+				for k := uint(0); k < n; k++ {
+					sum += A[i*n+k]*X[k*n+j] + X[i*n+k]*A[j*n+k]
+				}
+				F[i*n+j] = sum
+			}
+		}
+	}
+}
+
+// Riccati returns the right-hand side of the differential Riccati equation
+//
+//	dX/dx = Aᵀ X + X A - X S X + Q
+//
+// where A, S, and Q are constant n-by-n matrices stored in row-major order. In
+// the linear-quadratic regulator, S = B R⁻¹ Bᵀ, and the equation is
+// integrated backward from the terminal cost. The matrices are copied.
+func Riccati(A, S, Q []float64, n uint) func(float64, []float64, []float64) {
+	A = append([]float64(nil), A...)
+	S = append([]float64(nil), S...)
+	Q = append([]float64(nil), Q...)
+
+	SX := make([]float64, n*n)
+
+	return func(_ float64, X, F []float64) {
+		for i := uint(0); i < n; i++ {
+			for j := uint(0); j < n; j++ {
+				sum := 0.0
+				for k := uint(0); k < n; k++ {
+					sum += S[i*n+k] * X[k*n+j]
+				}
+				SX[i*n+j] = sum
+			}
+		}
+		for i := uint(0); i < n; i++ {
+			for j := uint(0); j < n; j++ {
+				sum := Q[i*n+j]
+				for k := uint(0); k < n; k++ {
+					sum += A[k*n+i]*X[k*n+j] + X[i*n+k]*A[k*n+j] - X[i*n+k]*SX[k*n+j]
+				}
+				F[i*n+j] = sum
+			}
+		}
+	}
+}
+
+// expand fills a symmetric n-by-n matrix given its upper triangle stored row
+// by row.
+func expand(y []float64, n uint, X []float64) {
+	for i, k := uint(0), 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			X[i*n+j], X[j*n+i] = y[k], y[k]
+			k++
+		}
+	}
+}
+
+// unflatten splits a solution into the matrices at the points of its grid.
+func unflatten(ys []float64, nd uint) [][]float64 {
+	Xs := make([][]float64, uint(len(ys))/nd)
+	for k := range Xs {
+		Xs[k] = ys[uint(k)*nd : uint(k+1)*nd]
+	}
+	return Xs
+}
@@ -0,0 +1,26 @@
+// Package midpoint provides an integrator of systems of ordinary differential
+// equations based on the explicit midpoint method.
+//
+// https://en.wikipedia.org/wiki/Midpoint_method
+package midpoint
+
+import (
+	"github.com/ready-steady/ode/rk"
+)
+
+// Integrator is an integrator.
+type Integrator = rk.Integrator
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return rk.New(tableau, config)
+}
+
+var tableau = &rk.Tableau{
+	A: [][]float64{
+		{},
+		{1.0 / 2},
+	},
+	B: []float64{0, 1},
+	C: []float64{0, 1.0 / 2},
+}
@@ -0,0 +1,39 @@
+package montecarlo
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The function creating the integrator of a worker; see ensemble.Config.
+	Integrator func() (ode.Integrator, error) `json:"-"`
+	// The number of workers. If it is zero, runtime.GOMAXPROCS(0) is used.
+	Workers uint `json:"workers"`
+	// The number of samples drawn when a sampler is used.
+	Samples uint `json:"samples"`
+	// The probabilities of the quantiles to compute, which should be in
+	// [0, 1].
+	Quantiles []float64 `json:"quantiles"`
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Integrator == nil {
+		return errors.New("the function creating integrators should be given")
+	}
+	for _, q := range c.Quantiles {
+		if q < 0 || q > 1 {
+			return errors.New("the probabilities of the quantiles should be in [0, 1]")
+		}
+	}
+
+	return nil
+}
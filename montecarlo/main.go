@@ -0,0 +1,179 @@
+// Package montecarlo provides a propagator of uncertainty through systems of
+// ordinary differential equations based on Monte Carlo sampling.
+//
+// The initial conditions and parameters of a system are either drawn by a
+// user-supplied sampler or given as pre-drawn samples. Each sample is
+// integrated as a member of an ensemble, and the solutions are summarized by
+// their means, variances, and quantiles at the output points. Failed
+// integrations are excluded from the summary and counted.
+//
+// https://en.wikipedia.org/wiki/Monte_Carlo_method
+package montecarlo
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/ensemble"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// Sampler draws the kth sample of the initial condition and parameters and
+// stores them in y0 and p, respectively. Samples are drawn sequentially before
+// the integration starts, so the sampler does not need to be safe for
+// concurrent use.
+type Sampler func(k uint, y0, p []float64)
+
+// Result is a statistical summary of the solutions of the samples.
+type Result struct {
+	Xs        []float64   // The output points.
+	Mean      []float64   // The mean of the solution.
+	Variance  []float64   // The unbiased sample variance of the solution.
+	Quantiles [][]float64 // The quantiles of the solution, one per probability.
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute draws Config.Samples samples of the initial condition, which has nd
+// components, and of the parameters, of which there are np, and propagates
+// them through the system of differential equations dy/dx = f(x, y, p); see
+// ode.Parametric and ComputeSamples.
+func (self *Integrator) Compute(dydx ode.Parametric, sampler Sampler, nd, np uint,
+	xs []float64) (*Result, *ensemble.Stats, error) {
+
+	ns := self.config.Samples
+	if ns == 0 {
+		return nil, nil, errors.New("the number of samples should be positive")
+	}
+
+	y0s := make([][]float64, ns)
+	ps := make([][]float64, ns)
+	for k := uint(0); k < ns; k++ {
+		y0s[k] = make([]float64, nd)
+		ps[k] = make([]float64, np)
+		sampler(k, y0s[k], ps[k])
+	}
+
+	return self.ComputeSamples(dydx, y0s, ps, xs)
+}
+
+// ComputeSamples propagates pre-drawn samples of the initial condition and
+// parameters through the system of differential equations dy/dx = f(x, y, p).
+// The samples are paired as in ensemble.Integrator.ComputeWithStats.
+//
+// The statistics are computed at the points of xs. If xs specifies only the
+// endpoints of the interval, the statistics are computed at the endpoints.
+// Each of the statistics occupies nd entries per point.
+func (self *Integrator) ComputeSamples(dydx ode.Parametric, y0s, ps [][]float64,
+	xs []float64) (*Result, *ensemble.Stats, error) {
+
+	config := &self.config
+
+	integrator, err := ensemble.New(&ensemble.Config{
+		Integrator: config.Integrator,
+		Workers:    config.Workers,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, stats, err := integrator.ComputeWithStats(dydx, y0s, ps, xs)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	nd, nx := len(y0s[0]), len(xs)
+
+	// Extract the solutions at the output points.
+	var solutions [][]float64
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		ys := result.Ys
+		if len(result.Xs) != nx {
+			if nx != 2 {
+				return nil, stats, errors.New("the samples should share the output points")
+			}
+			ys = append(append([]float64(nil), ys[:nd]...), ys[len(ys)-nd:]...)
+		}
+		solutions = append(solutions, ys)
+	}
+
+	ns := len(solutions)
+	if ns == 0 {
+		return nil, stats, errors.New("the integration of all the samples failed")
+	}
+
+	size := nx * nd
+
+	mean := make([]float64, size)
+	variance := make([]float64, size)
+	for k, ys := range solutions {
+		for i := 0; i < size; i++ {
+			δ := ys[i] - mean[i]
+			mean[i] += δ / float64(k+1)
+			variance[i] += δ * (ys[i] - mean[i])
+		}
+	}
+	for i := range variance {
+		if ns > 1 {
+			variance[i] /= float64(ns - 1)
+		} else {
+			variance[i] = 0
+		}
+	}
+
+	quantiles := make([][]float64, len(config.Quantiles))
+	for j := range quantiles {
+		quantiles[j] = make([]float64, size)
+	}
+	if len(quantiles) > 0 {
+		values := make([]float64, ns)
+		for i := 0; i < size; i++ {
+			for k, ys := range solutions {
+				values[k] = ys[i]
+			}
+			sort.Float64s(values)
+			for j, q := range config.Quantiles {
+				quantiles[j][i] = quantile(values, q)
+			}
+		}
+	}
+
+	result := &Result{
+		Xs:        append([]float64(nil), xs...),
+		Mean:      mean,
+		Variance:  variance,
+		Quantiles: quantiles,
+	}
+
+	return result, stats, nil
+}
+
+// quantile computes the quantile of sorted values using linear interpolation
+// between the closest ranks.
+func quantile(values []float64, q float64) float64 {
+	n := len(values)
+	if n == 1 {
+		return values[0]
+	}
+	h := q * float64(n-1)
+	i := int(math.Floor(h))
+	if i >= n-1 {
+		return values[n-1]
+	}
+	return values[i] + (h-float64(i))*(values[i+1]-values[i])
+}
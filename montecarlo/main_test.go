@@ -0,0 +1,72 @@
+package montecarlo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/dopri"
+)
+
+func TestCompute(t *testing.T) {
+	// y′ = -a y with y(0) uniform on [1, 2] and a fixed, so that y(x) is
+	// uniform on [e^(-a x), 2 e^(-a x)].
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = -p[0] * y[0]
+	}
+
+	generator := rand.New(rand.NewSource(42))
+	sampler := func(_ uint, y0, p []float64) {
+		y0[0] = 1 + generator.Float64()
+		p[0] = 0.5
+	}
+
+	integrator, _ := New(&Config{
+		Integrator: func() (ode.Integrator, error) {
+			return dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10})
+		},
+		Workers:   4,
+		Samples:   20000,
+		Quantiles: []float64{0, 0.5, 1},
+	})
+
+	xs := []float64{0, 1, 2}
+
+	result, stats, err := integrator.Compute(dydx, sampler, 1, 1, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Members, uint(20000), t)
+	assert.Equal(stats.Failures, uint(0), t)
+
+	for i, x := range xs {
+		e := math.Exp(-0.5 * x)
+		assert.Close(result.Mean[i], 1.5*e, 1e-2, t)
+		assert.Close(result.Variance[i], e*e/12, 1e-2, t)
+		assert.Close(result.Quantiles[0][i], e, 1e-3, t)
+		assert.Close(result.Quantiles[1][i], 1.5*e, 2e-2, t)
+		assert.Close(result.Quantiles[2][i], 2*e, 1e-3, t)
+	}
+}
+
+func TestComputeSamples(t *testing.T) {
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = p[0]
+	}
+
+	integrator, _ := New(&Config{
+		Integrator: func() (ode.Integrator, error) {
+			return dopri.New(dopri.DefaultConfig())
+		},
+		Quantiles: []float64{0.25},
+	})
+
+	ps := [][]float64{{1}, {2}, {3}, {4}, {5}}
+
+	result, _, err := integrator.ComputeSamples(dydx, [][]float64{{0}}, ps, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(result.Xs, []float64{0, 1}, t)
+	assert.Close(result.Mean, []float64{0, 3}, 1e-12, t)
+	assert.Close(result.Variance, []float64{0, 2.5}, 1e-12, t)
+	assert.Close(result.Quantiles[0], []float64{0, 2}, 1e-12, t)
+}
@@ -0,0 +1,40 @@
+package multirate
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The step of integration of the slow components.
+	Step float64 `json:"step"`
+	// The number of steps of the fast components per step of the slow ones.
+	Substeps uint `json:"substeps"`
+	// The indices of the fast components. The other components are slow.
+	Fast []uint `json:"fast"`
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Substeps == 0 {
+		return errors.New("the number of substeps should be positive")
+	}
+
+	seen := make(map[uint]bool, len(c.Fast))
+	for _, i := range c.Fast {
+		if seen[i] {
+			return errors.New("the fast components should be distinct")
+		}
+		seen[i] = true
+	}
+
+	return nil
+}
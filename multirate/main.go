@@ -0,0 +1,255 @@
+// Package multirate provides an integrator of systems of ordinary differential
+// equations whose components evolve on two different time scales,
+//
+//	u′ = g(x, u, v),
+//	v′ = h(x, u, v),
+//
+// where u are the slow components and v are the fast ones. The slow components
+// are advanced using the fourth-order Runge–Kutta method with a large step H,
+// and the fast ones using the same method with m substeps of size H/m, so
+// that the slow part of the right-hand side is evaluated only at the slow
+// time scale.
+//
+// Each step follows the fastest-first strategy. The fast components are
+// advanced first while the slow ones are extrapolated using the cubic Hermite
+// polynomial of the previous step. The slow components are then advanced
+// while the fast ones are interpolated using the cubic Hermite polynomials of
+// the substeps. The first step, which has no history, is taken using m
+// substeps of the whole system. The resulting method is of order four.
+//
+// https://en.wikipedia.org/wiki/Runge%E2%80%93Kutta_methods
+package multirate
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system u′ = g(x, u, v) and v′ = h(x, u, v).
+//
+// The input functions slow(x, y, f) and fast(x, y, f) evaluate the derivatives
+// of the slow and fast components, respectively, for a given x and state y,
+// which are their first and second arguments, and store them in their third
+// arguments. The state contains all the components in their original order,
+// and the derivatives are stored in the order of the components, that is, in
+// the order of Config.Fast for the fast components. The initial condition is
+// y0, which corresponds to x0 = xs[0].
+//
+// The solution is returned at a number of equidistant points starting from and
+// including x0. The final point is the closest point to the last element of xs
+// with respect to the step of the slow components. If the interval is shorter
+// than half the step, a single step that spans it is taken instead. The points
+// of xs should be strictly increasing. The points are returned as the second
+// result.
+func (self *Integrator) Compute(slow, fast func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	nd := len(y0)
+
+	kind := make([]bool, nd)
+	for _, i := range self.config.Fast {
+		if int(i) >= nd {
+			return nil, nil, errors.New("the fast components should be within the state")
+		}
+		kind[i] = true
+	}
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, err
+	}
+
+	iF := make([]int, 0, nd)
+	iS := make([]int, 0, nd)
+	for _, i := range self.config.Fast {
+		iF = append(iF, int(i))
+	}
+	for i := 0; i < nd; i++ {
+		if !kind[i] {
+			iS = append(iS, i)
+		}
+	}
+
+	nf, nsl := len(iF), len(iS)
+
+	H := self.config.Step
+	m := int(self.config.Substeps)
+
+	x0, xend := xs[0], xs[len(xs)-1]
+	ns := int((xend-x0)/H+0.5) + 1
+	if ns == 1 {
+		ns, H = 2, xend-x0
+	}
+
+	h := H / float64(m)
+
+	ys := make([]float64, ns*nd)
+	xs = make([]float64, ns)
+	copy(ys, y0)
+	xs[0] = x0
+
+	z := make([]float64, nd)
+	f := make([]float64, nd)
+	buffer := make([]float64, 4*nd)
+
+	// The slow components and their derivatives at the beginning and end of
+	// the previous step.
+	history := make([]float64, 5*nsl)
+	u0, g0 := history[0*nsl:1*nsl], history[1*nsl:2*nsl]
+	u1, g1 := history[2*nsl:3*nsl], history[3*nsl:4*nsl]
+	u := history[4*nsl : 5*nsl]
+
+	// The fast components and their derivatives at the ends of the substeps.
+	V := make([]float64, (m+1)*nf)
+	W := make([]float64, (m+1)*nf)
+
+	gf, gs := make([]float64, nf), make([]float64, nsl)
+
+	gather := func(y []float64, indices []int, u []float64) {
+		for l, i := range indices {
+			u[l] = y[i]
+		}
+	}
+	scatter := func(u []float64, indices []int, y []float64) {
+		for l, i := range indices {
+			y[i] = u[l]
+		}
+	}
+
+	// whole evaluates the whole right-hand side.
+	whole := func(x float64, y, f []float64) {
+		slow(x, y, gs)
+		fast(x, y, gf)
+		scatter(gs, iS, f)
+		scatter(gf, iF, f)
+	}
+
+	var x float64
+
+	// fastAt evaluates the fast part with the slow components extrapolated.
+	fastAt := func(t float64, v, f []float64) {
+		interpolate(x-H, u0, g0, x, u1, g1, t, gs)
+		scatter(gs, iS, z)
+		scatter(v, iF, z)
+		fast(t, z, f)
+	}
+
+	// slowAt evaluates the slow part with the fast components interpolated.
+	slowAt := func(t float64, u, f []float64) {
+		s := int((t - x) / h)
+		if s >= m {
+			s = m - 1
+		}
+		xl := x + float64(s)*h
+		interpolate(xl, V[s*nf:(s+1)*nf], W[s*nf:(s+1)*nf],
+			xl+h, V[(s+1)*nf:(s+2)*nf], W[(s+1)*nf:(s+2)*nf], t, gf)
+		scatter(u, iS, z)
+		scatter(gf, iF, z)
+		slow(t, z, f)
+	}
+
+	for j := 1; j < ns; j++ {
+		x = x0 + float64(j-1)*H
+
+		y, ynew := ys[(j-1)*nd:j*nd], ys[j*nd:(j+1)*nd]
+
+		if j == 1 {
+			// Take substeps of the whole system.
+			copy(ynew, y)
+			for s := 0; s < m; s++ {
+				t := x + float64(s)*h
+				whole(t, ynew, f)
+				step(whole, t, h, ynew, f, ynew, buffer)
+			}
+			gather(y, iS, u1)
+			slow(x, y, g1)
+		} else {
+			// Advance the fast components.
+			gather(y, iF, V[:nf])
+			for s := 0; s < m; s++ {
+				t := x + float64(s)*h
+				v, w := V[s*nf:(s+1)*nf], W[s*nf:(s+1)*nf]
+				fastAt(t, v, w)
+				step(fastAt, t, h, v, w, V[(s+1)*nf:(s+2)*nf], buffer)
+			}
+			fastAt(x+H, V[m*nf:], W[m*nf:])
+
+			// Advance the slow components.
+			step(slowAt, x, H, u1, g1, u, buffer)
+
+			scatter(u, iS, ynew)
+			scatter(V[m*nf:], iF, ynew)
+		}
+
+		copy(u0, u1)
+		copy(g0, g1)
+		gather(ynew, iS, u1)
+		slow(x+H, ynew, g1)
+
+		xs[j] = x0 + float64(j)*H
+	}
+
+	return ys, xs, nil
+}
+
+// step takes a step of the fourth-order Runge–Kutta method given the
+// derivative k1 at the current point. The buffer should be at least four times
+// as long as y. The result can be stored in y.
+func step(f func(float64, []float64, []float64), x, h float64, y, k1 []float64,
+	ynew []float64, buffer []float64) {
+
+	nd := len(y)
+
+	k2, k3 := buffer[0*nd:1*nd], buffer[1*nd:2*nd]
+	k4, z := buffer[2*nd:3*nd], buffer[3*nd:4*nd]
+
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h/2*k1[i]
+	}
+	f(x+h/2, z, k2)
+
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h/2*k2[i]
+	}
+	f(x+h/2, z, k3)
+
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h*k3[i]
+	}
+	f(x+h, z, k4)
+
+	for i := 0; i < nd; i++ {
+		ynew[i] = y[i] + h/6*(k1[i]+2*k2[i]+2*k3[i]+k4[i])
+	}
+}
+
+// interpolate evaluates the cubic Hermite polynomial given by the values y0
+// and y1 and derivatives f0 and f1 at x0 and x1, respectively. The point xnext
+// can be outside [x0, x1], in which case the polynomial is extrapolated.
+func interpolate(x0 float64, y0, f0 []float64, x1 float64, y1, f1 []float64,
+	xnext float64, ynext []float64) {
+
+	h := x1 - x0
+	s := (xnext - x0) / h
+
+	h00 := (1 + 2*s) * (1 - s) * (1 - s)
+	h10 := s * (1 - s) * (1 - s)
+	h01 := s * s * (3 - 2*s)
+	h11 := s * s * (s - 1)
+
+	for i := range ynext {
+		ynext[i] = h00*y0[i] + h*h10*f0[i] + h01*y1[i] + h*h11*f1[i]
+	}
+}
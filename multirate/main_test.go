@@ -0,0 +1,127 @@
+package multirate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/dopri"
+)
+
+// A slow decay driven by a fast oscillator, which is given by the second and
+// third components.
+func oscillator() (slow, fast, whole func(float64, []float64, []float64)) {
+	const ω = 50
+
+	slow = func(x float64, y, f []float64) {
+		f[0] = -y[0] + y[1] + math.Cos(x)
+	}
+	fast = func(_ float64, y, f []float64) {
+		f[0] = ω*y[2] + 0.1*y[0]
+		f[1] = -ω * y[1]
+	}
+	whole = func(x float64, y, f []float64) {
+		slow(x, y, f[:1])
+		fast(x, y, f[1:])
+	}
+	return
+}
+
+func TestComputeOrder(t *testing.T) {
+	slow, fast, whole := oscillator()
+
+	y0 := []float64{1, 0, 1}
+
+	reference, _ := dopri.New(&dopri.Config{AbsError: 1e-14, RelError: 1e-13})
+	zs, zxs, _ := reference.Compute(whole, y0, []float64{0, 1})
+	z := zs[(len(zxs)-1)*3:]
+
+	errors := make([]float64, 2)
+	for k, H := range []float64{0.0025, 0.00125} {
+		integrator, _ := New(&Config{Step: H, Substeps: 10, Fast: []uint{1, 2}})
+
+		ys, xs, err := integrator.Compute(slow, fast, y0, []float64{0, 1})
+		assert.Equal(err, nil, t)
+
+		n := len(xs)
+		assert.Close(xs[n-1], 1.0, 1e-12, t)
+		y := ys[(n-1)*3:]
+		errors[k] = math.Max(math.Abs(y[0]-z[0]), math.Hypot(y[1]-z[1], y[2]-z[2]))
+	}
+
+	assert.Close(math.Log2(errors[0]/errors[1]), 4.0, 0.3, t)
+}
+
+func TestComputeEvaluations(t *testing.T) {
+	slow, fast, _ := oscillator()
+
+	var ns, nf uint
+	countSlow := func(x float64, y, f []float64) {
+		ns++
+		slow(x, y, f)
+	}
+	countFast := func(x float64, y, f []float64) {
+		nf++
+		fast(x, y, f)
+	}
+
+	integrator, _ := New(&Config{Step: 0.1, Substeps: 20, Fast: []uint{1, 2}})
+
+	_, xs, _ := integrator.Compute(countSlow, countFast, []float64{1, 0, 1}, []float64{0, 1})
+	assert.Equal(len(xs), 11, t)
+
+	// The first step evaluates the whole system at each substep.
+	assert.Equal(ns, uint(4*20+2+4*9), t)
+	assert.Equal(nf, uint(4*20+(4*20+1)*9), t)
+}
+
+func TestComputeReorder(t *testing.T) {
+	slow, fast, _ := oscillator()
+
+	// The same system with the fast components placed first.
+	reorder := func(g func(float64, []float64, []float64)) func(float64, []float64, []float64) {
+		return func(x float64, y, f []float64) {
+			g(x, []float64{y[2], y[0], y[1]}, f)
+		}
+	}
+
+	config := &Config{Step: 0.05, Substeps: 10, Fast: []uint{1, 2}}
+	integrator, _ := New(config)
+	ys, _, _ := integrator.Compute(slow, fast, []float64{1, 0, 1}, []float64{0, 1})
+
+	config.Fast = []uint{0, 1}
+	integrator, _ = New(config)
+	zs, _, _ := integrator.Compute(reorder(slow), reorder(fast), []float64{0, 1, 1}, []float64{0, 1})
+
+	for i := 0; i < len(ys)/3; i++ {
+		assert.Equal([]float64{zs[3*i+2], zs[3*i], zs[3*i+1]}, ys[3*i:3*i+3], t)
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&Config{Step: 0.1, Substeps: 10, Fast: []uint{0, 0}})
+	assert.Equal(err != nil, true, t)
+
+	_, err = New(&Config{Step: 0.1, Fast: []uint{0}})
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeInterval(t *testing.T) {
+	slow, fast, whole := oscillator()
+
+	y0, xs := []float64{1, 0, 1}, []float64{0, 0.01}
+
+	reference, _ := dopri.New(&dopri.Config{AbsError: 1e-14, RelError: 1e-13})
+	zs, zxs, _ := reference.Compute(whole, y0, xs)
+	z := zs[(len(zxs)-1)*3:]
+
+	integrator, _ := New(&Config{Step: 0.1, Substeps: 10, Fast: []uint{1, 2}})
+
+	ys, xs, err := integrator.Compute(slow, fast, y0, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.01}, t)
+	assert.Close(ys[3:], z, 1e-6, t)
+
+	_, _, err = integrator.Compute(slow, fast, y0, []float64{1, 0})
+	assert.Equal(err != nil, true, t)
+}
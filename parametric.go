@@ -0,0 +1,49 @@
+package ode
+
+// Parametric is the right-hand side of a system of differential equations
+// dy/dx = f(x, y, p) that depends on a vector of parameters p.
+//
+// The function evaluates f(x, y, p) for a given x, y, and p in its first three
+// arguments and stores the result in its last argument.
+type Parametric func(x float64, y, p, f []float64)
+
+// Bind fixes the parameters of a parametric right-hand side, which yields a
+// right-hand side that can be given to Compute. The parameters are copied.
+func Bind(dydx Parametric, p []float64) func(float64, []float64, []float64) {
+	p = append([]float64(nil), p...)
+	return func(x float64, y, f []float64) {
+		dydx(x, y, p, f)
+	}
+}
+
+// ComputeParametric integrates the system of differential equations
+// dy/dx = f(x, y, p) for a given vector of parameters using an integrator. See
+// Integrator.Compute.
+func ComputeParametric(integrator Integrator, dydx Parametric, y0, p []float64,
+	xs []float64) ([]float64, []float64, error) {
+
+	return integrator.Compute(Bind(dydx, p), y0, xs)
+}
+
+// SweepResult is the solution of a system for one vector of parameters.
+type SweepResult struct {
+	P   []float64 // The parameters.
+	Ys  []float64 // The solution.
+	Xs  []float64 // The points of the solution.
+	Err error     // The error of the integration if any.
+}
+
+// Sweep integrates the system of differential equations dy/dx = f(x, y, p)
+// for each of a set of vectors of parameters using an integrator. The
+// integrations are independent, and a failure of one does not affect the
+// others. The function returns the results in the order of the vectors.
+func Sweep(integrator Integrator, dydx Parametric, y0 []float64, ps [][]float64,
+	xs []float64) []SweepResult {
+
+	results := make([]SweepResult, len(ps))
+	for k, p := range ps {
+		ys, xs, err := ComputeParametric(integrator, dydx, y0, p, xs)
+		results[k] = SweepResult{P: p, Ys: ys, Xs: xs, Err: err}
+	}
+	return results
+}
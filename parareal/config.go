@@ -0,0 +1,47 @@
+package parareal
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The integrator that is cheap and used sequentially.
+	Coarse ode.Integrator `json:"-"`
+	// The integrator that is accurate and used in parallel. It should be safe
+	// to be used by several goroutines at the same time, which is the case for
+	// all the integrators of this package.
+	Fine ode.Integrator `json:"-"`
+	// The number of time slices, which is used when the points of the
+	// solution are not specified.
+	Slices uint `json:"slices"`
+	// The maximal number of iterations. If it is zero, the number of slices is
+	// used, in which case the solution of the fine integrator is recovered
+	// exactly.
+	MaxIterations uint `json:"maxIterations"`
+	// The tolerance on the maximal change of the solution at the boundaries of
+	// the slices between two iterations.
+	Tolerance float64 `json:"tolerance"`
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Coarse == nil || c.Fine == nil {
+		return errors.New("the coarse and fine integrators should be given")
+	}
+	if c.Slices == 0 {
+		return errors.New("the number of slices should be positive")
+	}
+	if c.Tolerance < 0 {
+		return errors.New("the tolerance should be nonnegative")
+	}
+
+	return nil
+}
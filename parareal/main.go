@@ -0,0 +1,163 @@
+// Package parareal provides a parallel-in-time integrator of systems of
+// ordinary differential equations based on the parareal algorithm.
+//
+// The interval of integration is split into time slices. A coarse integrator
+// propagates the solution through the slices sequentially, and a fine
+// integrator, which is the expensive one, propagates the current estimates at
+// the beginnings of the slices over all the slices at once using goroutines.
+// The estimates are then corrected as
+//
+//	Uₙᵏ⁺¹ = G(Uₙ₋₁ᵏ⁺¹) + F(Uₙ₋₁ᵏ) - G(Uₙ₋₁ᵏ),
+//
+// where G and F are the coarse and fine propagators, respectively. After k
+// iterations, the first k slices coincide with the ones of the fine
+// integrator, and the iterations usually converge much faster than that, in
+// which case the wall-clock time is reduced on multicore machines.
+//
+// https://en.wikipedia.org/wiki/Parareal
+package parareal
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/ready-steady/ode"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package. The input function should be safe
+// to be called by several goroutines at the same time.
+//
+// The solution is returned at the boundaries of the time slices. If xs
+// specifies intermediate points, they are used as the boundaries. Otherwise,
+// the interval is split into Config.Slices slices of equal length.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	nx := len(xs)
+	if nx < 2 {
+		return nil, nil, nil, errors.New("the interval should have two endpoints")
+	}
+
+	config := &self.config
+
+	if nx == 2 {
+		x0, xend := xs[0], xs[1]
+		nx = int(config.Slices) + 1
+		xs = make([]float64, nx)
+		for i := range xs {
+			xs[i] = x0 + (xend-x0)*float64(i)/float64(nx-1)
+		}
+		xs[nx-1] = xend
+	} else {
+		xs = append([]float64(nil), xs...)
+	}
+
+	nd, nn := len(y0), nx-1
+
+	maxIterations := int(config.MaxIterations)
+	if maxIterations == 0 || maxIterations > nn {
+		maxIterations = nn
+	}
+
+	stats := &Stats{}
+
+	// propagate integrates over the nth slice starting from y and stores the
+	// result in ynew.
+	propagate := func(integrator ode.Integrator, n int, y, ynew []float64) error {
+		zs, _, err := integrator.Compute(dydx, y, []float64{xs[n], xs[n+1]})
+		if err != nil {
+			return err
+		}
+		copy(ynew, zs[len(zs)-nd:])
+		return nil
+	}
+
+	// The estimates at the boundaries and the results of the two propagators
+	// over the slices.
+	ys := make([]float64, nx*nd)
+	G := make([]float64, nn*nd)
+	F := make([]float64, nn*nd)
+	g := make([]float64, nd)
+
+	copy(ys, y0)
+	for n := 0; n < nn; n++ {
+		if err := propagate(config.Coarse, n, ys[n*nd:(n+1)*nd], G[n*nd:(n+1)*nd]); err != nil {
+			return nil, nil, stats, err
+		}
+		copy(ys[(n+1)*nd:(n+2)*nd], G[n*nd:(n+1)*nd])
+	}
+
+	for k := 0; k < maxIterations; k++ {
+		stats.Iterations++
+
+		// The first k slices have already converged.
+		errs := make([]error, nn)
+		var group sync.WaitGroup
+		for n := k; n < nn; n++ {
+			group.Add(1)
+			go func(n int) {
+				defer group.Done()
+				errs[n] = propagate(config.Fine, n, ys[n*nd:(n+1)*nd], F[n*nd:(n+1)*nd])
+			}(n)
+		}
+		group.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return nil, nil, stats, err
+			}
+		}
+
+		// The beginning of the kth slice is exact; hence, so is its end.
+		change := 0.0
+		for n := k; n < nn; n++ {
+			y, ynew := ys[n*nd:(n+1)*nd], ys[(n+1)*nd:(n+2)*nd]
+			if n == k {
+				for i := 0; i < nd; i++ {
+					change = math.Max(change, math.Abs(F[n*nd+i]-ynew[i]))
+				}
+				copy(ynew, F[n*nd:(n+1)*nd])
+				continue
+			}
+			if err := propagate(config.Coarse, n, y, g); err != nil {
+				return nil, nil, stats, err
+			}
+			for i := 0; i < nd; i++ {
+				value := g[i] + F[n*nd+i] - G[n*nd+i]
+				change = math.Max(change, math.Abs(value-ynew[i]))
+				ynew[i] = value
+			}
+			copy(G[n*nd:(n+1)*nd], g)
+		}
+
+		if change <= config.Tolerance {
+			break
+		}
+	}
+
+	return ys, xs, stats, nil
+}
@@ -0,0 +1,61 @@
+package parareal
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/dopri"
+	"github.com/ready-steady/ode/rk4"
+)
+
+func TestComputeDecay(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	coarse, _ := rk4.New(&rk4.Config{Step: 0.5})
+	fine, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-10})
+
+	integrator, _ := New(&Config{Coarse: coarse, Fine: fine, Slices: 20, Tolerance: 1e-10})
+
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{0.5}, []float64{0, 10})
+	assert.Equal(err, nil, t)
+	assert.Equal(len(xs), 21, t)
+	assert.Equal(stats.Iterations < 10, true, t)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 1e-9, t)
+	}
+}
+
+func TestComputeExact(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	coarse, _ := rk4.New(&rk4.Config{Step: 0.25})
+	fine, _ := rk4.New(&rk4.Config{Step: 0.01})
+
+	integrator, _ := New(&Config{Coarse: coarse, Fine: fine, Slices: 1})
+
+	xs := []float64{0, 1, 2, 3, 4}
+
+	ys, _, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Iterations <= 4, true, t)
+
+	y := []float64{1, 0}
+	for n := 1; n < len(xs); n++ {
+		zs, _, _ := fine.Compute(dydx, y, []float64{xs[n-1], xs[n]})
+		y = zs[len(zs)-2:]
+		assert.Close(ys[2*n:2*n+2], y, 1e-14, t)
+	}
+}
+
+func TestNew(t *testing.T) {
+	fine, _ := rk4.New(&rk4.Config{Step: 0.01})
+
+	_, err := New(&Config{Fine: fine, Slices: 1})
+	assert.Equal(err != nil, true, t)
+}
@@ -0,0 +1,6 @@
+package parareal
+
+// Stats contains information about the work done by an integrator.
+type Stats struct {
+	Iterations uint // The number of iterations the algorithm has taken.
+}
@@ -0,0 +1,381 @@
+package problems
+
+import (
+	"math"
+)
+
+// DETEST returns the nonstiff problems of DETEST, which are the 25 problems
+// A1–A5, B1–B5, C1–C5, D1–D5, and E1–E5 of Hull et al. on [0, 20]. The second
+// order problems of the classes D and E are given as first-order systems
+// whose states consist of the positions followed by the velocities.
+//
+// https://doi.org/10.1137/0709052
+func DETEST() []*Problem {
+	problems := []*Problem{
+		{
+			Name: "A1",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = -y[0]
+			},
+			Y0:        []float64{1},
+			Reference: []float64{math.Exp(-20)},
+		},
+		{
+			Name: "A2",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = -y[0] * y[0] * y[0] / 2
+			},
+			Y0:        []float64{1},
+			Reference: []float64{1 / math.Sqrt(21)},
+		},
+		{
+			Name: "A3",
+			Dydx: func(x float64, y, f []float64) {
+				f[0] = y[0] * math.Cos(x)
+			},
+			Y0:        []float64{1},
+			Reference: []float64{math.Exp(math.Sin(20))},
+		},
+		{
+			Name: "A4",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = y[0] / 4 * (1 - y[0]/20)
+			},
+			Y0:        []float64{1},
+			Reference: []float64{20 / (1 + 19*math.Exp(-5))},
+		},
+		{
+			Name: "A5",
+			Dydx: func(x float64, y, f []float64) {
+				f[0] = (y[0] - x) / (y[0] + x)
+			},
+			Y0: []float64{4},
+		},
+		{
+			Name: "B1",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = 2 * (y[0] - y[0]*y[1])
+				f[1] = -(y[1] - y[0]*y[1])
+			},
+			Y0: []float64{1, 3},
+		},
+		{
+			Name: "B2",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = -y[0] + y[1]
+				f[1] = y[0] - 2*y[1] + y[2]
+				f[2] = y[1] - y[2]
+			},
+			Y0: []float64{2, 0, 1},
+		},
+		{
+			Name: "B3",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = -y[0]
+				f[1] = y[0] - y[1]*y[1]
+				f[2] = y[1] * y[1]
+			},
+			Y0: []float64{1, 0, 0},
+		},
+		{
+			Name: "B4",
+			Dydx: func(_ float64, y, f []float64) {
+				r := math.Sqrt(y[0]*y[0] + y[1]*y[1])
+				f[0] = -y[1] - y[0]*y[2]/r
+				f[1] = y[0] - y[1]*y[2]/r
+				f[2] = y[0] / r
+			},
+			Y0: []float64{3, 0, 0},
+		},
+		{
+			Name: "B5",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = y[1] * y[2]
+				f[1] = -y[0] * y[2]
+				f[2] = -0.51 * y[0] * y[1]
+			},
+			Y0: []float64{0, 1, 1},
+		},
+		{
+			Name: "C1",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = -y[0]
+				for i := 1; i < 9; i++ {
+					f[i] = y[i-1] - y[i]
+				}
+				f[9] = y[8]
+			},
+			Y0:        unit(10),
+			Reference: poisson(20),
+		},
+		{
+			Name: "C2",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = -y[0]
+				for i := 1; i < 9; i++ {
+					f[i] = float64(i)*y[i-1] - float64(i+1)*y[i]
+				}
+				f[9] = 9 * y[8]
+			},
+			Y0: unit(10),
+		},
+		{
+			Name: "C3",
+			Dydx: tridiagonal,
+			Y0:   unit(10),
+		},
+		{
+			Name: "C4",
+			Dydx: tridiagonal,
+			Y0:   unit(51),
+		},
+		{
+			Name: "C5",
+			Dydx: planets,
+			Y0: []float64{
+				3.42947415189, 3.35386959711, 1.35494901715,
+				6.64145542550, 5.97156957878, 2.18231499728,
+				11.2630437207, 14.6952576794, 6.27960525067,
+				-30.1552268759, 1.65699966404, 1.43785752721,
+				-21.1238353380, 28.4465098142, 15.3882659679,
+				-0.557160570446, 0.505696783289, 0.230578543901,
+				-0.415570776342, 0.365682722812, 0.169143213293,
+				-0.325325669158, 0.189706021964, 0.0877265322780,
+				-0.0240476254170, -0.287659532608, -0.117219543175,
+				-0.176860753121, -0.216393453025, -0.0148647893090,
+			},
+		},
+	}
+
+	for i, e := range []float64{0.1, 0.3, 0.5, 0.7, 0.9} {
+		e := e
+		problems = append(problems, &Problem{
+			Name: "D" + string(rune('1'+i)),
+			Dydx: func(_ float64, y, f []float64) {
+				r := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+				f[0], f[1] = y[2], y[3]
+				f[2], f[3] = -y[0]/r, -y[1]/r
+			},
+			Y0:        []float64{1 - e, 0, 0, math.Sqrt((1 + e) / (1 - e))},
+			Reference: kepler(e, 20),
+		})
+	}
+
+	problems = append(problems, []*Problem{
+		{
+			Name: "E1",
+			Dydx: func(x float64, y, f []float64) {
+				f[0] = y[1]
+				f[1] = -(y[1]/(x+1) + (1-0.25/((x+1)*(x+1)))*y[0])
+			},
+			Y0: []float64{
+				math.Sqrt(2/math.Pi) * math.Sin(1),
+				math.Sqrt(2/math.Pi) * (math.Cos(1) - math.Sin(1)/2),
+			},
+			Reference: []float64{
+				math.Sqrt(2/(21*math.Pi)) * math.Sin(21),
+				math.Sqrt(2/(21*math.Pi)) * (math.Cos(21) - math.Sin(21)/42),
+			},
+		},
+		{
+			Name: "E2",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = y[1]
+				f[1] = (1-y[0]*y[0])*y[1] - y[0]
+			},
+			Y0: []float64{2, 0},
+		},
+		{
+			Name: "E3",
+			Dydx: func(x float64, y, f []float64) {
+				f[0] = y[1]
+				f[1] = y[0]*y[0]*y[0]/6 - y[0] + 2*math.Sin(2.78535*x)
+			},
+			Y0: []float64{0, 0},
+		},
+		{
+			Name: "E4",
+			Dydx: func(_ float64, y, f []float64) {
+				f[0] = y[1]
+				f[1] = 0.032 - 0.4*y[1]*y[1]
+			},
+			Y0: []float64{30, 0},
+			Reference: []float64{
+				30 + 2.5*math.Log(math.Cosh(20*math.Sqrt(0.0128))),
+				math.Sqrt(0.08) * math.Tanh(20*math.Sqrt(0.0128)),
+			},
+		},
+		{
+			Name: "E5",
+			Dydx: func(x float64, y, f []float64) {
+				f[0] = y[1]
+				f[1] = math.Sqrt(1+y[1]*y[1]) / (25 - x)
+			},
+			Y0:        []float64{0, 0},
+			Reference: []float64{(25*math.Log(5) - 12) / 2, 2.4},
+		},
+	}...)
+
+	for _, problem := range problems {
+		problem.Xs = []float64{0, 20}
+		problem.AbsError = 1e-6
+		problem.RelError = 1e-6
+		if reference, ok := references[problem.Name]; ok {
+			problem.Reference = reference
+		}
+	}
+
+	return problems
+}
+
+// The solutions at x = 20 of the problems of DETEST without closed forms.
+var references = map[string][]float64{
+	"A5": {-0.7887826688963},
+	"B1": {0.6761876008577, 0.186081609964},
+	"B2": {1.000000001031, 1, 0.9999999989694},
+	"B3": {2.061153658159e-09, 0.05257228022049, 0.9474277177184},
+	"B4": {0.9826950928004, 2.198447081695, 0.9129452507277},
+	"B5": {-0.9396570798729, -0.3421177754001, 0.74141265962},
+	"C2": {
+		2.061153637106e-09, 2.061153632858e-09, 2.061153628609e-09,
+		2.061153624361e-09, 2.061153620112e-09, 2.061153615864e-09,
+		2.061153611616e-09, 2.061153607367e-09, 2.061153603118e-09,
+		0.9999999814496,
+	},
+	"C3": {
+		0.002948119211023, 0.005635380154845, 0.007829072515927,
+		0.009348257908596, 0.01007943610302, 0.009982674171429,
+		0.009088693332765, 0.007489115195185, 0.005322964130953,
+		0.00276243437903,
+	},
+	"C4": {
+		0.003124111453722, 0.006015416842152, 0.008470021834844,
+		0.01033682931733, 0.01153249572874, 0.01204549525738,
+		0.01192957068015, 0.01128883207111, 0.01025804501391,
+		0.008982017581934, 0.007597500902493, 0.006219920556825,
+		0.004935916341009, 0.003801432544256, 0.002844213677588,
+		0.002069123394223, 0.001464687282844, 0.001009545263941,
+		0.0006779354330226, 0.0004437815269118, 0.0002833264542939,
+		0.0001765005798797, 0.0001073342592698, 6.374497601779e-05,
+		3.698645309705e-05, 2.097466832644e-05, 1.162956710412e-05,
+		6.306710405779e-06, 3.346286430865e-06, 1.737760074182e-06,
+		8.835366904261e-07, 4.399520411122e-07, 2.146181897152e-07,
+		1.025981211657e-07, 4.807864068812e-08, 2.209175152499e-08,
+		9.956251263304e-09, 4.402193653848e-09, 1.910149382254e-09,
+		8.135892921669e-10, 3.402477118583e-10, 1.39748561751e-10,
+		5.638575302494e-11, 2.235459707442e-11, 8.710498032446e-12,
+		3.336554272628e-12, 1.256679566054e-12, 4.654359042769e-13,
+		1.693559139753e-13, 5.996593786201e-14, 1.891330689797e-14,
+	},
+	"C5": {
+		-4.792730224324, -2.420550725449, -0.9212509306015,
+		-4.217310404035, 7.356202947499, 3.223785985421,
+		4.035559443262, 17.19865528671, 7.478910794234,
+		-29.98759326325, -4.107310937551, -0.9277008321754,
+		-24.42125302518, 23.81459045747, 14.92096306951,
+		0.3499208963064, -0.5748487687913, -0.2551694020879,
+		-0.5237040978903, -0.249300046358, -0.08045341642044,
+		-0.3875289237334, 0.05648603288768, 0.03023606472143,
+		0.04133856546712, -0.2862393029841, -0.1183032405136,
+		-0.1511986457359, -0.2460068894319, -0.03189687411324,
+	},
+	"E2": {2.008149762175, -0.04250887527318},
+	"E3": {-0.1004178858647, 0.2411400132096},
+}
+
+// unit returns the first unit vector of dimension n.
+func unit(n int) []float64 {
+	y := make([]float64, n)
+	y[0] = 1
+	return y
+}
+
+// poisson returns the solution of C1 at x, whose first nine components are the
+// probabilities of the Poisson distribution with mean x and the last one is
+// the remainder.
+func poisson(x float64) []float64 {
+	y := make([]float64, 10)
+	term, sum := math.Exp(-x), 0.0
+	for i := 0; i < 9; i++ {
+		y[i] = term
+		sum += term
+		term *= x / float64(i+1)
+	}
+	y[9] = 1 - sum
+	return y
+}
+
+// tridiagonal is the right-hand side of C3 and C4, which is the discretization
+// of the heat equation with homogeneous boundary conditions.
+func tridiagonal(_ float64, y, f []float64) {
+	n := len(y)
+	for i := range y {
+		f[i] = -2 * y[i]
+		if i > 0 {
+			f[i] += y[i-1]
+		}
+		if i < n-1 {
+			f[i] += y[i+1]
+		}
+	}
+}
+
+// planets is the right-hand side of C5, which is the motion of the five outer
+// planets around the Sun in heliocentric coordinates. The state consists of
+// the positions of the planets in three dimensions followed by the velocities.
+func planets(_ float64, y, f []float64) {
+	const (
+		k2 = 2.95912208286
+		m0 = 1.00000597682
+		n  = 5
+	)
+
+	m := [n]float64{
+		0.000954786104043,
+		0.000285583733151,
+		0.0000437273164546,
+		0.0000517759138449,
+		0.00000277777777778,
+	}
+
+	var r [n]float64
+	for i := 0; i < n; i++ {
+		p := y[3*i : 3*i+3]
+		r[i] = math.Pow(p[0]*p[0]+p[1]*p[1]+p[2]*p[2], 1.5)
+	}
+
+	copy(f[:3*n], y[3*n:])
+	for i := 0; i < n; i++ {
+		p := y[3*i : 3*i+3]
+		for k := 0; k < 3; k++ {
+			a := -(m0 + m[i]) * p[k] / r[i]
+			for j := 0; j < n; j++ {
+				if i == j {
+					continue
+				}
+				q := y[3*j : 3*j+3]
+				d := math.Pow((q[0]-p[0])*(q[0]-p[0])+(q[1]-p[1])*(q[1]-p[1])+
+					(q[2]-p[2])*(q[2]-p[2]), 1.5)
+				a += m[j] * ((q[k]-p[k])/d - q[k]/r[j])
+			}
+			f[3*n+3*i+k] = k2 * a
+		}
+	}
+}
+
+// kepler returns the solution of the two-body problem with eccentricity e at
+// x, which is found by solving Kepler's equation.
+func kepler(e, x float64) []float64 {
+	x = math.Mod(x, 2*math.Pi)
+	E := math.Pi
+	for i := 0; i < 100; i++ {
+		δ := (E - e*math.Sin(E) - x) / (1 - e*math.Cos(E))
+		E -= δ
+		if math.Abs(δ) < 1e-15 {
+			break
+		}
+	}
+	s, c := math.Sin(E), math.Cos(E)
+	q, d := math.Sqrt(1-e*e), 1-e*math.Cos(E)
+	return []float64{c - e, q * s, -s / d, q * c / d}
+}
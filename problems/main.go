@@ -0,0 +1,209 @@
+// Package problems provides canonical initial-value problems for testing
+// integrators.
+//
+// Each problem comes with the interval of integration, the initial condition,
+// the solution at the end of the interval, and the tolerances at which it is
+// usually solved. The solutions are exact where a closed form is known;
+// otherwise, they are computed with an accuracy of about 1e-12 relative to
+// the magnitude of the solution and given to 13 significant digits.
+//
+// The global error of a solution of a problem computed by any integrator can
+// be obtained using Problem.Verify, which compares the solution with an
+// accurate one at each point.
+package problems
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode"
+)
+
+// Problem is an initial-value problem dy/dx = f(x, y), y(x0) = y0.
+type Problem struct {
+	// The name of the problem.
+	Name string
+	// The right-hand side of the system; see ode.Integrator.Compute.
+	Dydx func(float64, []float64, []float64)
+	// The Jacobian matrix of the right-hand side, which is nil if the problem
+	// does not provide one.
+	Jacobian ode.Jacobian
+	// The initial condition.
+	Y0 []float64
+	// The interval of integration, which consists of x0 and xend.
+	Xs []float64
+	// The solution at xend, which is nil if it is not known.
+	Reference []float64
+	// The recommended absolute error tolerance.
+	AbsError float64
+	// The recommended relative error tolerance.
+	RelError float64
+	// Is the problem stiff?
+	Stiff bool
+}
+
+// VanDerPol returns the Van der Pol oscillator
+//
+//	y₁′ = y₂,
+//	y₂′ = μ (1 - y₁²) y₂ - y₁
+//
+// with y(0) = (2, 0) on [0, 2μ], which becomes stiffer as μ grows. The
+// reference solution is known only for μ equal to 1, 10, 100, and 1000.
+func VanDerPol(μ float64) *Problem {
+	references := map[float64][]float64{
+		1:    {0.3233166670462, -1.832974567986},
+		10:   {1.939358532783, -0.07008150573581},
+		100:  {1.718587208019, -0.008796821912417},
+		1000: {1.706167732171, -0.0008928097010244},
+	}
+
+	return &Problem{
+		Name: "Van der Pol",
+		Dydx: func(_ float64, y, f []float64) {
+			f[0] = y[1]
+			f[1] = μ*(1-y[0]*y[0])*y[1] - y[0]
+		},
+		Jacobian: func(_ float64, y, J []float64) {
+			J[0], J[1] = 0, 1
+			J[2], J[3] = -2*μ*y[0]*y[1]-1, μ*(1-y[0]*y[0])
+		},
+		Y0:        []float64{2, 0},
+		Xs:        []float64{0, 2 * μ},
+		Reference: references[μ],
+		AbsError:  1e-6,
+		RelError:  1e-6,
+		Stiff:     μ >= 100,
+	}
+}
+
+// Robertson returns the chemical kinetics of Robertson
+//
+//	y₁′ = -0.04 y₁ + 10⁴ y₂ y₃,
+//	y₂′ = 0.04 y₁ - 10⁴ y₂ y₃ - 3·10⁷ y₂²,
+//	y₃′ = 3·10⁷ y₂²
+//
+// with y(0) = (1, 0, 0) on [0, 40], which is stiff.
+func Robertson() *Problem {
+	return &Problem{
+		Name: "Robertson",
+		Dydx: func(_ float64, y, f []float64) {
+			f[0] = -0.04*y[0] + 1e4*y[1]*y[2]
+			f[1] = 0.04*y[0] - 1e4*y[1]*y[2] - 3e7*y[1]*y[1]
+			f[2] = 3e7 * y[1] * y[1]
+		},
+		Jacobian: func(_ float64, y, J []float64) {
+			J[0], J[1], J[2] = -0.04, 1e4*y[2], 1e4*y[1]
+			J[3], J[4], J[5] = 0.04, -1e4*y[2]-6e7*y[1], -1e4*y[1]
+			J[6], J[7], J[8] = 0, 6e7*y[1], 0
+		},
+		Y0:        []float64{1, 0, 0},
+		Xs:        []float64{0, 40},
+		Reference: []float64{0.7158270687195, 9.18553476455e-06, 0.2841637457458},
+		AbsError:  1e-10,
+		RelError:  1e-6,
+		Stiff:     true,
+	}
+}
+
+// Brusselator returns the Brusselator
+//
+//	y₁′ = 1 + y₁² y₂ - 4 y₁,
+//	y₂′ = 3 y₁ - y₁² y₂
+//
+// with y(0) = (1.5, 3) on [0, 20], whose solution approaches a limit cycle.
+func Brusselator() *Problem {
+	return &Problem{
+		Name: "Brusselator",
+		Dydx: func(_ float64, y, f []float64) {
+			f[0] = 1 + y[0]*y[0]*y[1] - 4*y[0]
+			f[1] = 3*y[0] - y[0]*y[0]*y[1]
+		},
+		Jacobian: func(_ float64, y, J []float64) {
+			J[0], J[1] = 2*y[0]*y[1]-4, y[0]*y[0]
+			J[2], J[3] = 3-2*y[0]*y[1], -y[0]*y[0]
+		},
+		Y0:        []float64{1.5, 3},
+		Xs:        []float64{0, 20},
+		Reference: []float64{0.4986370712683, 4.596780349452},
+		AbsError:  1e-6,
+		RelError:  1e-6,
+	}
+}
+
+// Arenstorf returns the restricted three-body problem of Arenstorf, which
+// describes a satellite moving in the gravitational field of the Earth and the
+// Moon. The state consists of the position and the velocity of the satellite.
+// The interval is one period of the orbit; hence, the solution at its end
+// equals the initial condition.
+func Arenstorf() *Problem {
+	const (
+		μ1 = 0.012277471
+		μ2 = 1 - μ1
+	)
+
+	y0 := []float64{0.994, 0, 0, -2.00158510637908252240537862224}
+
+	return &Problem{
+		Name: "Arenstorf",
+		Dydx: func(_ float64, y, f []float64) {
+			d1 := math.Pow((y[0]+μ1)*(y[0]+μ1)+y[1]*y[1], 1.5)
+			d2 := math.Pow((y[0]-μ2)*(y[0]-μ2)+y[1]*y[1], 1.5)
+			f[0] = y[2]
+			f[1] = y[3]
+			f[2] = y[0] + 2*y[3] - μ2*(y[0]+μ1)/d1 - μ1*(y[0]-μ2)/d2
+			f[3] = y[1] - 2*y[2] - μ2*y[1]/d1 - μ1*y[1]/d2
+		},
+		Y0:        y0,
+		Xs:        []float64{0, 17.0652165601579625588917206249},
+		Reference: append([]float64(nil), y0...),
+		AbsError:  1e-10,
+		RelError:  1e-10,
+	}
+}
+
+// Pleiades returns the motion of seven stars in the plane whose masses are 1,
+// 2, …, 7 on [0, 3]. The state consists of the abscissae, the ordinates, and
+// the corresponding components of the velocities of the stars, in this order,
+// so that the dimension of the system is 28.
+func Pleiades() *Problem {
+	const (
+		n = 7
+	)
+
+	return &Problem{
+		Name: "Pleiades",
+		Dydx: func(_ float64, y, f []float64) {
+			copy(f[:2*n], y[2*n:])
+			for i := 0; i < n; i++ {
+				ax, ay := 0.0, 0.0
+				for j := 0; j < n; j++ {
+					if i == j {
+						continue
+					}
+					dx, dy := y[j]-y[i], y[n+j]-y[n+i]
+					r := math.Pow(dx*dx+dy*dy, 1.5)
+					ax += float64(j+1) * dx / r
+					ay += float64(j+1) * dy / r
+				}
+				f[2*n+i], f[3*n+i] = ax, ay
+			}
+		},
+		Y0: []float64{
+			3, 3, -1, -3, 2, -2, 2,
+			3, -3, 2, 0, 0, -4, 4,
+			0, 0, 0, 0, 0, 1.75, -1.5,
+			0, 0, 0, -1.25, 1, 0, 0,
+		},
+		Xs: []float64{0, 3},
+		Reference: []float64{
+			0.3706139143976, 3.237284092057, -3.222559032419, 0.6597091455776,
+			0.3425581707154, 1.562172101401, -0.700309292221, -3.943437585517,
+			-3.271380973972, 5.225081843458, -2.590612434977, 1.198213693392,
+			-0.2429682344936, 1.091449240428, 3.417003806316, 1.354584501625,
+			-2.590065597811, 2.025053734714, -1.155815100162, -0.8072988170224,
+			0.5952396354222, -3.741244961233, 0.3773459685752, 0.9386858869562,
+			0.3667922227201, -0.3474046353815, 2.344915448181, -1.947020434264,
+		},
+		AbsError: 1e-8,
+		RelError: 1e-8,
+	}
+}
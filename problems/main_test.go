@@ -0,0 +1,109 @@
+package problems
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/dop853"
+	"github.com/ready-steady/ode/radau"
+)
+
+func TestReference(t *testing.T) {
+	problems := []*Problem{
+		VanDerPol(1),
+		VanDerPol(1000),
+		Robertson(),
+		Brusselator(),
+		Arenstorf(),
+		Pleiades(),
+	}
+	problems = append(problems, DETEST()...)
+
+	assert.Equal(len(problems), 31, t)
+
+	for _, problem := range problems {
+		var ys []float64
+		var err error
+		if problem.Stiff {
+			integrator, _ := radau.New(&radau.Config{AbsError: 1e-12, RelError: 1e-10,
+				Jacobian: problem.Jacobian})
+			ys, _, err = integrator.Compute(problem.Dydx, problem.Y0, problem.Xs)
+		} else {
+			integrator, _ := dop853.New(&dop853.Config{AbsError: 1e-13, RelError: 1e-12})
+			ys, _, err = integrator.Compute(problem.Dydx, problem.Y0, problem.Xs)
+		}
+		assert.Equal(err, nil, t)
+
+		nd := len(problem.Y0)
+		assert.Equal(len(problem.Reference), nd, t)
+
+		scale := 0.0
+		for _, value := range problem.Reference {
+			scale = math.Max(scale, math.Abs(value))
+		}
+		assert.Close(ys[len(ys)-nd:], problem.Reference, 1e-8*(1+scale), t)
+	}
+
+	assert.Equal(VanDerPol(42).Reference == nil, true, t)
+}
+
+func TestJacobian(t *testing.T) {
+	for _, problem := range []*Problem{VanDerPol(10), Robertson(), Brusselator()} {
+		nd := len(problem.Y0)
+
+		y := make([]float64, nd)
+		for i := range y {
+			y[i] = problem.Y0[i] + 0.1*float64(i+1)
+		}
+
+		J := make([]float64, nd*nd)
+		problem.Jacobian(0, y, J)
+
+		f, g := make([]float64, nd), make([]float64, nd)
+		for j := 0; j < nd; j++ {
+			δ := 1e-6 * math.Max(1, math.Abs(y[j]))
+			y[j] += δ
+			problem.Dydx(0, y, f)
+			y[j] -= 2 * δ
+			problem.Dydx(0, y, g)
+			y[j] += δ
+			for i := 0; i < nd; i++ {
+				assert.Close(J[i*nd+j], (f[i]-g[i])/(2*δ), 1e-3*math.Max(1, math.Abs(J[i*nd+j])), t)
+			}
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	problem := Brusselator()
+	xs := []float64{0, 5, 10, 15, 20}
+
+	maxima := make([]float64, 0, 2)
+	for _, ε := range []float64{1e-4, 1e-8} {
+		integrator, _ := dop853.New(&dop853.Config{AbsError: ε, RelError: ε})
+		ys, xs, _ := integrator.Compute(problem.Dydx, problem.Y0, xs)
+
+		report, err := problem.Verify(ys, xs)
+		assert.Equal(err, nil, t)
+		assert.Equal(len(report.Errors), 2, t)
+		assert.Equal(report.Max, math.Max(report.Errors[0], report.Errors[1]), t)
+		assert.Close(report.Final, []float64{
+			math.Abs(ys[8] - problem.Reference[0]),
+			math.Abs(ys[9] - problem.Reference[1]),
+		}, 1e-15, t)
+		assert.Equal(report.Max < 1e3*ε, true, t)
+
+		maxima = append(maxima, report.Max)
+	}
+	assert.Equal(maxima[1] < maxima[0], true, t)
+
+	integrator, _ := dop853.New(&dop853.Config{AbsError: 1e-6, RelError: 1e-6})
+	ys, xs, _ := integrator.Compute(problem.Dydx, problem.Y0, problem.Xs)
+	report, err := problem.Verify(ys, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(report.Max > 0, true, t)
+
+	_, err = problem.Verify(ys[1:], xs)
+	assert.Equal(err != nil, true, t)
+}
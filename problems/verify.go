@@ -0,0 +1,97 @@
+package problems
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/dop853"
+	"github.com/ready-steady/ode/radau"
+)
+
+// Report is the global error of a solution of a problem.
+type Report struct {
+	// The maximal absolute error of each component over all the points.
+	Errors []float64
+	// The absolute error of each component at the last point.
+	Final []float64
+	// The maximal absolute error over all the components and points.
+	Max float64
+}
+
+// Verify computes the global error of a solution of the problem, such as the
+// one returned by an integrator given Dydx, Y0, and Xs. The solution ys is
+// given at the points xs as by ode.Integrator.Compute. The exact solution at
+// the points is approximated by an integrator of high accuracy, which is the
+// Dormand–Prince method of order eight for nonstiff problems and the Radau
+// IIA method for stiff ones, with tolerances well below the recommended ones.
+// If the last point is the end of the interval, the reference solution of the
+// problem is used there instead.
+func (self *Problem) Verify(ys, xs []float64) (*Report, error) {
+	nd, np := len(self.Y0), len(xs)
+	if np < 2 || len(ys) != np*nd {
+		return nil, errors.New("the solution should have one entry per component and point")
+	}
+	if xs[0] != self.Xs[0] {
+		return nil, errors.New("the solution should start at the beginning of the interval")
+	}
+
+	exact, err := self.approximate(xs)
+	if err != nil {
+		return nil, err
+	}
+	if xs[np-1] == self.Xs[len(self.Xs)-1] && len(self.Reference) == nd {
+		copy(exact[(np-1)*nd:], self.Reference)
+	}
+
+	report := &Report{
+		Errors: make([]float64, nd),
+		Final:  make([]float64, nd),
+	}
+	for i := 0; i < np; i++ {
+		for j := 0; j < nd; j++ {
+			ε := math.Abs(ys[i*nd+j] - exact[i*nd+j])
+			report.Errors[j] = math.Max(report.Errors[j], ε)
+			if i == np-1 {
+				report.Final[j] = ε
+			}
+		}
+	}
+	for _, ε := range report.Errors {
+		report.Max = math.Max(report.Max, ε)
+	}
+
+	return report, nil
+}
+
+// approximate computes the solution of the problem at a number of points with
+// a high accuracy.
+func (self *Problem) approximate(xs []float64) ([]float64, error) {
+	abserr := math.Min(1e-13, self.AbsError*1e-4)
+	relerr := math.Min(1e-12, self.RelError*1e-4)
+
+	var integrator ode.Integrator
+	var err error
+	if self.Stiff {
+		integrator, err = radau.New(&radau.Config{AbsError: abserr, RelError: relerr,
+			Jacobian: self.Jacobian})
+	} else {
+		integrator, err = dop853.New(&dop853.Config{AbsError: abserr, RelError: relerr})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ys, _, err := integrator.Compute(self.Dydx, self.Y0, xs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without intermediate points, the integrator reports the points that it
+	// traverses, of which only the endpoints are needed.
+	if nd := len(self.Y0); len(xs) == 2 {
+		ys = append(ys[:nd:nd], ys[len(ys)-nd:]...)
+	}
+
+	return ys, nil
+}
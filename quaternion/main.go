@@ -0,0 +1,186 @@
+// Package quaternion provides an integrator of the attitude kinematics of a
+// rigid body given by a unit quaternion,
+//
+//	q′ = q ⊗ (0, ω) / 2,
+//
+// where ω is the angular velocity in the body frame, possibly coupled with
+// other dynamics z′ = g(x, q, z), such as the Euler equations of the angular
+// velocity.
+//
+// The state is composed of the quaternion q = (w, x, y, z), with the scalar
+// part first, followed by z. The integrator is the fourth-order
+// Runge–Kutta–Munthe-Kaas method for the product of the unit quaternions and
+// the Euclidean space. The rotations are accumulated via the exponential map
+// as q ⊗ exp(u/2) where u is a rotation vector, and z is updated additively.
+// Consequently, the quaternion stays of unit norm up to rounding errors
+// without renormalization, which would otherwise interfere with the error
+// estimates of general-purpose integrators. Given
+//
+//	k₁ = h F(x₀, Y₀)
+//
+// where F comprises ω and g, the method is given by
+//
+//	k₂ = h F(x₀ + h/2, Y₀ · exp(k₁/2)),
+//	k₃ = h F(x₀ + h/2, Y₀ · exp(k₂/2 + [k₁, k₂]/8)),
+//	k₄ = h F(x₀ + h, Y₀ · exp(k₃)),
+//	Y₁ = Y₀ · exp((k₁ + 2k₂ + 2k₃ + k₄)/6 + [k₁, k₄]/12),
+//
+// where the bracket is the cross product of the rotation vectors, and it
+// vanishes for the other components.
+//
+// https://en.wikipedia.org/wiki/Quaternions_and_spatial_rotation
+package quaternion
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system q′ = q ⊗ (0, ω(x, q, z)) / 2 and
+// z′ = g(x, q, z).
+//
+// The input function F(x, y, ω, g) evaluates the angular velocity and the
+// derivative of the other components for a given x and state y, which are its
+// first and second arguments, and stores them in its third and fourth
+// arguments, respectively. The initial condition y0 contains a unit
+// quaternion followed by the other components, if any.
+//
+// The solution is returned at a number of equidistant points starting from and
+// including x0 = xs[0]. The final point is the closest point to the last
+// element of xs with respect to the integration step. If the interval is
+// shorter than half the step, a single step that spans it is taken instead.
+// The points of xs should be strictly increasing. The points are returned as
+// the second result.
+func (self *Integrator) Compute(F func(float64, []float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	if len(y0) < 4 {
+		return nil, nil, errors.New("the initial condition should start with a quaternion")
+	}
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, err
+	}
+
+	ny := len(y0)
+	nd := ny - 1
+
+	h := self.config.Step
+
+	x0, xend := xs[0], xs[len(xs)-1]
+	ns := int((xend-x0)/h+0.5) + 1
+	if ns == 1 {
+		ns, h = 2, xend-x0
+	}
+
+	ys := make([]float64, ns*ny)
+	xs = make([]float64, ns)
+	copy(ys, y0)
+	xs[0] = x0
+
+	// The elements of the algebra are composed of a rotation vector followed
+	// by the other components.
+	buffer := make([]float64, 6*nd)
+	k1, k2 := buffer[0*nd:1*nd], buffer[1*nd:2*nd]
+	k3, k4 := buffer[2*nd:3*nd], buffer[3*nd:4*nd]
+	u, w := buffer[4*nd:5*nd], buffer[5*nd:6*nd]
+
+	z := make([]float64, ny)
+
+	// evaluate computes h F(x, Y · exp(u)) and stores it in k.
+	evaluate := func(x float64, y []float64, k []float64) {
+		advance(y, u, z)
+		F(x, z, k[:3], k[3:])
+		for i := range k {
+			k[i] *= h
+		}
+	}
+
+	for j := 1; j < ns; j++ {
+		x := x0 + float64(j-1)*h
+
+		y, ynew := ys[(j-1)*ny:j*ny], ys[j*ny:(j+1)*ny]
+
+		F(x, y, k1[:3], k1[3:])
+		for i := range k1 {
+			k1[i] *= h
+		}
+
+		for i := range u {
+			u[i] = k1[i] / 2
+		}
+		evaluate(x+h/2, y, k2)
+
+		cross(k1, k2, w)
+		for i := range u {
+			u[i] = k2[i] / 2
+		}
+		for i := 0; i < 3; i++ {
+			u[i] += w[i] / 8
+		}
+		evaluate(x+h/2, y, k3)
+
+		copy(u, k3)
+		evaluate(x+h, y, k4)
+
+		cross(k1, k4, w)
+		for i := range u {
+			u[i] = (k1[i] + 2*k2[i] + 2*k3[i] + k4[i]) / 6
+		}
+		for i := 0; i < 3; i++ {
+			u[i] += w[i] / 12
+		}
+		advance(y, u, ynew)
+
+		xs[j] = x0 + float64(j)*h
+	}
+
+	return ys, xs, nil
+}
+
+// advance computes y · exp(u), that is, the quaternion q ⊗ exp(u/2) followed
+// by the other components incremented by the corresponding ones of u.
+func advance(y, u []float64, ynew []float64) {
+	θ := math.Sqrt(u[0]*u[0] + u[1]*u[1] + u[2]*u[2])
+
+	// The quaternion exp(u/2) = (cos(θ/2), sin(θ/2) u/θ).
+	var s float64
+	if θ < 1e-4 {
+		s = 0.5 - θ*θ/48
+	} else {
+		s = math.Sin(θ/2) / θ
+	}
+	p := [4]float64{math.Cos(θ / 2), s * u[0], s * u[1], s * u[2]}
+
+	multiply(y[:4], p[:], ynew[:4])
+
+	for i := 4; i < len(y); i++ {
+		ynew[i] = y[i] + u[i-1]
+	}
+}
+
+// multiply computes the Hamilton product q ⊗ p.
+func multiply(q, p []float64, r []float64) {
+	r[0] = q[0]*p[0] - q[1]*p[1] - q[2]*p[2] - q[3]*p[3]
+	r[1] = q[0]*p[1] + q[1]*p[0] + q[2]*p[3] - q[3]*p[2]
+	r[2] = q[0]*p[2] - q[1]*p[3] + q[2]*p[0] + q[3]*p[1]
+	r[3] = q[0]*p[3] + q[1]*p[2] - q[2]*p[1] + q[3]*p[0]
+}
+
+func cross(u, v []float64, r []float64) {
+	r[0], r[1], r[2] = u[1]*v[2]-u[2]*v[1], u[2]*v[0]-u[0]*v[2], u[0]*v[1]-u[1]*v[0]
+}
@@ -0,0 +1,81 @@
+package quaternion
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/dopri"
+)
+
+// The free rigid body with the principal moments of inertia I.
+var I = [3]float64{1, 2, 3}
+
+func body(_ float64, y, ω, g []float64) {
+	copy(ω, y[4:])
+	g[0] = (I[1] - I[2]) * ω[1] * ω[2] / I[0]
+	g[1] = (I[2] - I[0]) * ω[2] * ω[0] / I[1]
+	g[2] = (I[0] - I[1]) * ω[0] * ω[1] / I[2]
+}
+
+var y0 = []float64{1, 0, 0, 0, 1, 0.1, 0.5}
+
+func TestComputeNorm(t *testing.T) {
+	integrator, _ := New(&Config{Step: 0.1})
+	ys, xs, _ := integrator.Compute(body, y0, []float64{0, 1000})
+
+	energy := func(ω []float64) float64 {
+		return I[0]*ω[0]*ω[0] + I[1]*ω[1]*ω[1] + I[2]*ω[2]*ω[2]
+	}
+
+	for k := range xs {
+		q := ys[7*k : 7*k+4]
+		assert.Close(q[0]*q[0]+q[1]*q[1]+q[2]*q[2]+q[3]*q[3], 1.0, 1e-13, t)
+	}
+
+	n := len(xs)
+	assert.Close(energy(ys[7*(n-1)+4:]), energy(y0[4:]), 1e-4, t)
+}
+
+func TestComputeOrder(t *testing.T) {
+	kinematics := func(x float64, y, f []float64) {
+		var ω [3]float64
+		body(x, y, ω[:], f[4:])
+		q, p := y[:4], [4]float64{0, ω[0] / 2, ω[1] / 2, ω[2] / 2}
+		multiply(q, p[:], f[:4])
+	}
+
+	reference, _ := dopri.New(&dopri.Config{AbsError: 1e-14, RelError: 1e-14})
+	ys, xs, _ := reference.Compute(kinematics, y0, []float64{0, 2})
+	expected := ys[7*(len(xs)-1):]
+
+	solve := func(h float64) float64 {
+		integrator, _ := New(&Config{Step: h})
+		ys, xs, _ := integrator.Compute(body, y0, []float64{0, 2})
+		y := ys[7*(len(xs)-1):]
+		d := 0.0
+		for i := range y {
+			d = math.Max(d, math.Abs(y[i]-expected[i]))
+		}
+		return d
+	}
+
+	ratio := solve(0.1) / solve(0.05)
+	assert.Close(math.Log2(ratio), 4.0, 0.2, t)
+}
+
+func TestComputeInterval(t *testing.T) {
+	spin := func(_ float64, _, ω, _ []float64) {
+		ω[0], ω[1], ω[2] = 0, 0, 1
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	ys, xs, err := integrator.Compute(spin, []float64{1, 0, 0, 0}, []float64{0, 0.01})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.01}, t)
+	assert.Close(ys[4:], []float64{math.Cos(0.005), 0, 0, math.Sin(0.005)}, 1e-13, t)
+
+	_, _, err = integrator.Compute(spin, []float64{1, 0, 0, 0}, []float64{1, 0})
+	assert.Equal(err != nil, true, t)
+}
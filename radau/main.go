@@ -0,0 +1,565 @@
+// Package radau provides an integrator of stiff systems of ordinary
+// differential equations based on the implicit Runge–Kutta method of order
+// five from the Radau IIA family.
+//
+// The collocation system is solved using a simplified Newton method, which,
+// after a change of variables, requires one real and one complex LU
+// decomposition per step size. The error is estimated using an embedded
+// formula of order three, and the step size is chosen using a predictive
+// controller.
+//
+// The integrator also handles systems of the form M y′ = f(x, y) with a
+// constant mass matrix M, which can be singular; see Config.Mass.
+//
+// https://en.wikipedia.org/wiki/List_of_Runge%E2%80%93Kutta_methods#Radau_IIA_methods
+package radau
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/dense"
+	"github.com/ready-steady/ode/internal/interval"
+	"github.com/ready-steady/ode/internal/jacobian"
+)
+
+const (
+	newtonMaxIter = 6
+	minFactor     = 0.2
+	maxFactor     = 10
+)
+
+var (
+	s6 = math.Sqrt(6)
+
+	c = [3]float64{(4 - s6) / 10, (4 + s6) / 10, 1}
+	e = [3]float64{(-13 - 7*s6) / 3, (-13 + 7*s6) / 3, -1.0 / 3}
+
+	μr = 3 + math.Cbrt(9) - math.Cbrt(3)
+	μc = complex(3+0.5*(math.Cbrt(3)-math.Cbrt(9)),
+		-0.5*(math.Pow(3, 5.0/6)+math.Pow(3, 7.0/6)))
+
+	T = [3][3]float64{
+		{0.09443876248897524, -0.14125529502095421, 0.03002919410514742},
+		{0.25021312296533332, 0.20412935229379994, -0.38294211275726192},
+		{1, 1, 0},
+	}
+	TI = [3][3]float64{
+		{4.17871859155190428, 0.32768282076106237, 0.52337644549944951},
+		{-4.17871859155190428, -0.32768282076106237, 0.47662355450055044},
+		{0.50287263494578682, -2.57192694985560522, 0.59603920482822492},
+	}
+
+	P = [3][3]float64{
+		{13.0/3 + 7*s6/3, -23.0/3 - 22*s6/3, 10.0/3 + 5*s6},
+		{13.0/3 - 7*s6/3, -23.0/3 + 22*s6/3, 10.0/3 - 5*s6},
+		{1.0 / 3, -8.0 / 3, 10.0 / 3},
+	}
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	config := &self.config
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	M := config.Mass
+	if M != nil && len(M) != nd*nd {
+		return nil, nil, stats, errors.New("the mass matrix should match the dimension of the system")
+	}
+
+	f := make([]float64, nd)
+	y := make([]float64, nd)
+	ynew := make([]float64, nd)
+	yold := make([]float64, nd)
+	scale := make([]float64, nd)
+	ε := make([]float64, nd)
+	z := make([]float64, nd)
+	fz := make([]float64, nd)
+
+	Z := make([]float64, 3*nd)
+	W := make([]float64, 3*nd)
+	F := make([]float64, 3*nd)
+	Q := make([]float64, 3*nd)
+	MW := make([]float64, 3*nd)
+
+	J := make([]float64, nd*nd)
+	approximator := jacobian.New(uint(nd), nil)
+	Ar := make([]float64, nd*nd)
+	Ac := make([]complex128, nd*nd)
+	br := make([]float64, nd)
+	bc := make([]complex128, nd)
+	lur := dense.NewLU(uint(nd))
+	luc := dense.NewComplexLU(uint(nd))
+
+	evaluate := func(x float64, y, f []float64) {
+		dydx(x, y, f)
+		stats.Evaluations++
+	}
+
+	jacobian := func(x float64, y, f []float64) {
+		if config.Jacobian != nil {
+			config.Jacobian(x, y, J)
+		} else {
+			stats.Evaluations += approximator.Compute(dydx, x, y, f, J)
+		}
+		stats.Jacobians++
+	}
+
+	factorize := func(h float64) error {
+		for i := range J {
+			Ar[i] = -J[i]
+			Ac[i] = complex(-J[i], 0)
+		}
+		if M == nil {
+			for i := 0; i < nd; i++ {
+				Ar[i*nd+i] += μr / h
+				Ac[i*nd+i] += μc / complex(h, 0)
+			}
+		} else {
+			for i := range M {
+				Ar[i] += μr / h * M[i]
+				Ac[i] += μc / complex(h, 0) * complex(M[i], 0)
+			}
+		}
+		stats.Decompositions += 2
+		if err := lur.Factorize(Ar); err != nil {
+			return err
+		}
+		return luc.Factorize(Ac)
+	}
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	abserr, relerr := config.AbsError, config.RelError
+
+	copy(y, y0)
+	evaluate(x, y, f)
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = xend - x
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = initialize(evaluate, x, y, f, z, fz, abserr, relerr)
+	}
+
+	tolerance := math.Max(10*epsilon(1)/relerr, math.Min(0.03, math.Sqrt(relerr)))
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	jacobian(x, y, f)
+	current, factorized := true, false
+
+	var hold, εold float64
+	var xold, hdense float64
+	predicted := false
+
+	for x < xend {
+		var xnew, norm, rate, safety float64
+		var iterations int
+
+		stats.Steps++
+
+		hmin := 10 * epsilon(x)
+
+		if h > hmax {
+			h, hold, εold = hmax, 0, 0
+		} else if h < hmin {
+			h, hold, εold = hmin, 0, 0
+		}
+
+		rejected := false
+
+		for {
+			if h < hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			xnew = x + h
+			if xnew > xend {
+				xnew = xend
+			}
+			if h != xnew-x {
+				h = xnew - x
+				factorized = false
+			}
+
+			// Predict the solution using the previous collocation polynomial.
+			if predicted {
+				for k := 0; k < 3; k++ {
+					evaluatePolynomial(Q, xold, hdense, yold, x+c[k]*h, z)
+					for i := 0; i < nd; i++ {
+						Z[k*nd+i] = z[i] - y[i]
+					}
+				}
+			} else {
+				for i := range Z {
+					Z[i] = 0
+				}
+			}
+
+			for i := 0; i < nd; i++ {
+				scale[i] = abserr + relerr*math.Abs(y[i])
+			}
+
+			converged := false
+			for {
+				if !factorized {
+					if err := factorize(h); err != nil {
+						return nil, nil, stats, err
+					}
+					factorized = true
+				}
+
+				converged, iterations, rate = solve(evaluate, x, y, h, M, Z, W, F, MW,
+					scale, tolerance, lur, luc, br, bc, z)
+				if converged || current {
+					break
+				}
+
+				jacobian(x, y, f)
+				current, factorized = true, false
+			}
+
+			if !converged {
+				stats.Rejections++
+				h *= 0.5
+				factorized = false
+				continue
+			}
+
+			for i := 0; i < nd; i++ {
+				ynew[i] = y[i] + Z[2*nd+i]
+			}
+
+			// Estimate the error.
+			for i := 0; i < nd; i++ {
+				z[i] = (e[0]*Z[i] + e[1]*Z[nd+i] + e[2]*Z[2*nd+i]) / h
+			}
+			multiply(M, z, br)
+			for i := 0; i < nd; i++ {
+				ε[i] = f[i] + br[i]
+				scale[i] = abserr + relerr*math.Max(math.Abs(y[i]), math.Abs(ynew[i]))
+			}
+			lur.Solve(ε)
+			norm = rms(ε, scale)
+
+			safety = 0.9 * (2*newtonMaxIter + 1) / float64(2*newtonMaxIter+iterations)
+
+			if rejected && norm > 1 {
+				for i := 0; i < nd; i++ {
+					z[i] = y[i] + ε[i]
+				}
+				evaluate(x, z, ε)
+				for i := 0; i < nd; i++ {
+					ε[i] += br[i]
+				}
+				lur.Solve(ε)
+				norm = rms(ε, scale)
+			}
+
+			if norm <= 1 {
+				break
+			}
+
+			stats.Rejections++
+
+			h *= math.Max(minFactor, safety*predict(h, hold, norm, εold))
+			factorized = false
+			rejected = true
+		}
+
+		recompute := iterations > 2 && rate > 1e-3
+
+		factor := math.Min(maxFactor, safety*predict(h, hold, norm, εold))
+		if !recompute && factor < 1.2 {
+			factor = 1
+		} else {
+			factorized = false
+		}
+
+		// Compute the coefficients of the collocation polynomial.
+		for k := 0; k < 3; k++ {
+			for i := 0; i < nd; i++ {
+				Q[k*nd+i] = Z[i]*P[0][k] + Z[nd+i]*P[1][k] + Z[2*nd+i]*P[2][k]
+			}
+		}
+		xold, hdense, predicted = x, h, true
+
+		if fixed {
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					copy(ys[nc*nd:(nc+1)*nd], ynew)
+				} else {
+					evaluatePolynomial(Q, x, h, y, xs[nc], ys[nc*nd:(nc+1)*nd])
+				}
+
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		evaluate(xnew, ynew, f)
+
+		if recompute {
+			jacobian(xnew, ynew, f)
+			current = true
+		} else {
+			current = false
+		}
+
+		hold, εold = h, norm
+
+		x = xnew
+		copy(yold, y)
+		copy(y, ynew)
+
+		h *= factor
+	}
+
+	return ys, xs, stats, nil
+}
+
+func solve(evaluate func(float64, []float64, []float64), x float64, y []float64,
+	h float64, M, Z, W, F, MW, scale []float64, tolerance float64, lur *dense.LU,
+	luc *dense.ComplexLU, br []float64, bc []complex128, z []float64) (bool, int, float64) {
+
+	nd := len(y)
+
+	mr, mc := μr/h, μc/complex(h, 0)
+
+	for i := 0; i < nd; i++ {
+		for k := 0; k < 3; k++ {
+			W[k*nd+i] = TI[k][0]*Z[i] + TI[k][1]*Z[nd+i] + TI[k][2]*Z[2*nd+i]
+		}
+	}
+
+	var norm, rate float64
+	old := -1.0
+
+	k := 0
+	for ; k < newtonMaxIter; k++ {
+		for j := 0; j < 3; j++ {
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + Z[j*nd+i]
+			}
+			evaluate(x+c[j]*h, z, F[j*nd:(j+1)*nd])
+		}
+		for i := range F {
+			if math.IsNaN(F[i]) || math.IsInf(F[i], 0) {
+				return false, k + 1, rate
+			}
+		}
+
+		for j := 0; j < 3; j++ {
+			multiply(M, W[j*nd:(j+1)*nd], MW[j*nd:(j+1)*nd])
+		}
+		for i := 0; i < nd; i++ {
+			f0, f1, f2 := F[i], F[nd+i], F[2*nd+i]
+			br[i] = TI[0][0]*f0 + TI[0][1]*f1 + TI[0][2]*f2 - mr*MW[i]
+			bc[i] = complex(TI[1][0]*f0+TI[1][1]*f1+TI[1][2]*f2,
+				TI[2][0]*f0+TI[2][1]*f1+TI[2][2]*f2) -
+				mc*complex(MW[nd+i], MW[2*nd+i])
+		}
+		lur.Solve(br)
+		luc.Solve(bc)
+
+		norm = 0
+		for i := 0; i < nd; i++ {
+			s := scale[i]
+			norm += (br[i]/s)*(br[i]/s) + (real(bc[i])/s)*(real(bc[i])/s) +
+				(imag(bc[i])/s)*(imag(bc[i])/s)
+		}
+		norm = math.Sqrt(norm / float64(3*nd))
+
+		if old >= 0 {
+			rate = norm / old
+			if rate >= 1 || math.Pow(rate, float64(newtonMaxIter-k))/(1-rate)*norm > tolerance {
+				return false, k + 1, rate
+			}
+		}
+
+		for i := 0; i < nd; i++ {
+			W[i] += br[i]
+			W[nd+i] += real(bc[i])
+			W[2*nd+i] += imag(bc[i])
+		}
+		for j := 0; j < 3; j++ {
+			for i := 0; i < nd; i++ {
+				Z[j*nd+i] = T[j][0]*W[i] + T[j][1]*W[nd+i] + T[j][2]*W[2*nd+i]
+			}
+		}
+
+		if norm == 0 || (old >= 0 && rate/(1-rate)*norm < tolerance) {
+			return true, k + 1, rate
+		}
+
+		old = norm
+	}
+
+	return false, k, rate
+}
+
+// multiply computes M v and stores the result in u. If M is nil, it is the
+// identity matrix.
+func multiply(M, v, u []float64) {
+	if M == nil {
+		copy(u, v)
+		return
+	}
+	nd := len(v)
+	for i := 0; i < nd; i++ {
+		sum := 0.0
+		for j := 0; j < nd; j++ {
+			sum += M[i*nd+j] * v[j]
+		}
+		u[i] = sum
+	}
+}
+
+func predict(h, hold, norm, εold float64) float64 {
+	multiplier := 1.0
+	if hold > 0 && εold > 0 && norm > 0 {
+		multiplier = h / hold * math.Pow(εold/norm, 0.25)
+	}
+	return math.Min(1, multiplier) * math.Pow(norm, -0.25)
+}
+
+func evaluatePolynomial(Q []float64, x, h float64, y []float64, xnext float64,
+	ynext []float64) {
+
+	nd := len(y)
+
+	s := (xnext - x) / h
+	s1, s2, s3 := s, s*s, s*s*s
+
+	for i := 0; i < nd; i++ {
+		ynext[i] = y[i] + Q[i]*s1 + Q[nd+i]*s2 + Q[2*nd+i]*s3
+	}
+}
+
+func initialize(evaluate func(float64, []float64, []float64), x float64, y, f, ynew,
+	fnew []float64, abserr, relerr float64) float64 {
+
+	const (
+		order = 3
+	)
+
+	nd := len(y)
+
+	scale := make([]float64, nd)
+	for i := 0; i < nd; i++ {
+		scale[i] = abserr + relerr*math.Abs(y[i])
+	}
+
+	d0, d1 := rms(y, scale), rms(f, scale)
+
+	var h float64
+	if d0 < 1e-5 || d1 < 1e-5 {
+		h = 1e-6
+	} else {
+		h = 0.01 * d0 / d1
+	}
+
+	for i := 0; i < nd; i++ {
+		ynew[i] = y[i] + h*f[i]
+	}
+	evaluate(x+h, ynew, fnew)
+
+	for i := 0; i < nd; i++ {
+		ynew[i] = fnew[i] - f[i]
+	}
+	d2 := rms(ynew, scale) / h
+
+	var h1 float64
+	if d1 <= 1e-15 && d2 <= 1e-15 {
+		h1 = math.Max(1e-6, h*1e-3)
+	} else {
+		h1 = math.Pow(0.01/math.Max(d1, d2), 1.0/(order+1))
+	}
+
+	return math.Min(100*h, h1)
+}
+
+func rms(v, scale []float64) float64 {
+	s := 0.0
+	for i := range v {
+		e := v[i] / scale[i]
+		s += e * e
+	}
+	return math.Sqrt(s / float64(len(v)))
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
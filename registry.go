@@ -0,0 +1,208 @@
+package ode
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Options are the settings shared by the integrators constructed by name; see
+// New. The zero value of a field leaves the corresponding setting of the
+// method at its default value, and the fields that a method does not have are
+// ignored.
+type Options struct {
+	// The initial step of integration of the methods with adaptive steps.
+	TryStep float64
+	// The maximal step of integration of the methods with adaptive steps.
+	MaxStep float64
+	// The absolute error tolerance of the methods with adaptive steps.
+	AbsError float64
+	// The relative error tolerance of the methods with adaptive steps.
+	RelError float64
+	// The step of integration of the methods with fixed steps, which is
+	// required by them.
+	Step float64
+	// The Jacobian matrix of the right-hand side for the implicit methods.
+	Jacobian Jacobian
+}
+
+// Factory is a function creating an integrator given the options.
+type Factory func(options *Options) (Integrator, error)
+
+// Decoder is a function creating an integrator given its configuration in
+// JSON; see Load.
+type Decoder func(config []byte) (Integrator, error)
+
+// Spec is a specification of an integrator, which consists of the name of a
+// registered method and the configuration of the method in JSON, for
+// instance,
+//
+//	{"method": "dopri", "config": {"absError": 1e-8, "relError": 1e-6}}
+//
+// The names of the fields of a configuration are those of the Config type of
+// the method starting with a lowercase letter, and the fields that are
+// functions cannot be specified.
+type Spec struct {
+	Method string          `json:"method"`
+	Config json.RawMessage `json:"config"`
+}
+
+var registry = struct {
+	sync.RWMutex
+	factories map[string]Factory
+	decoders  map[string]Decoder
+}{
+	factories: make(map[string]Factory),
+	decoders:  make(map[string]Decoder),
+}
+
+// Register makes an integrator available by name to New. The subpackages of
+// this package register their integrators under the names of the packages
+// when they are imported, and other packages can register their own ones in
+// the same way. If Register is called twice with the same name or if the
+// factory is nil, it panics.
+func Register(name string, factory Factory) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if factory == nil {
+		panic("the factory of " + name + " is nil")
+	}
+	if _, ok := registry.factories[name]; ok {
+		panic("the method " + name + " is already registered")
+	}
+	registry.factories[name] = factory
+}
+
+// New creates an integrator of a method registered by name. The method should
+// be registered beforehand, which is the case for a subpackage of this
+// package as soon as it is imported, possibly only for its side effects:
+//
+//	import _ "github.com/ready-steady/ode/dopri"
+//
+// If options is nil, the defaults of the method are used.
+func New(method string, options *Options) (Integrator, error) {
+	registry.RLock()
+	factory, ok := registry.factories[method]
+	registry.RUnlock()
+
+	if !ok {
+		return nil, errors.New("the method " + method + " is not registered")
+	}
+	if options == nil {
+		options = &Options{}
+	}
+
+	return factory(options)
+}
+
+// RegisterDecoder makes an integrator available by name to Load and
+// Spec.New. The subpackages of this package register their decoders along
+// with their factories; see Register. If RegisterDecoder is called twice with
+// the same name or if the decoder is nil, it panics.
+func RegisterDecoder(name string, decoder Decoder) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if decoder == nil {
+		panic("the decoder of " + name + " is nil")
+	}
+	if _, ok := registry.decoders[name]; ok {
+		panic("the decoder of " + name + " is already registered")
+	}
+	registry.decoders[name] = decoder
+}
+
+// Load reads a specification in JSON and creates the integrator that it
+// specifies; see Spec. Specifications in other formats, such as YAML, should
+// be converted to JSON beforehand.
+func Load(reader io.Reader) (Integrator, error) {
+	var spec Spec
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, err
+	}
+	return spec.New()
+}
+
+// New creates the integrator of the specification. The configuration is
+// decoded on top of the default one of the method, and it is validated in the
+// same way as when the integrator is created directly. The fields that the
+// configuration of the method does not have are an error.
+func (self *Spec) New() (Integrator, error) {
+	registry.RLock()
+	decoder, ok := registry.decoders[self.Method]
+	registry.RUnlock()
+
+	if !ok {
+		return nil, errors.New("the method " + self.Method + " is not registered")
+	}
+
+	return decoder(self.Config)
+}
+
+// Decode decodes a configuration in JSON on top of a given one and creates an
+// integrator using a constructor, which is expected to validate the
+// configuration. The fields that the configuration does not have are an
+// error. The function is meant for implementing decoders; see
+// RegisterDecoder.
+func Decode[C any, I Integrator](data []byte, config *C,
+	create func(*C) (I, error)) (Integrator, error) {
+
+	if len(data) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(config); err != nil {
+			return nil, err
+		}
+	}
+
+	integrator, err := create(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return integrator, nil
+}
+
+// Methods returns the names of the registered methods in the alphabetical
+// order.
+func Methods() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	names := make([]string, 0, len(registry.factories))
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Adapt overrides the settings of a method with adaptive steps by the nonzero
+// options.
+func (self *Options) Adapt(tryStep, maxStep, absError, relError *float64) {
+	override(tryStep, self.TryStep)
+	override(maxStep, self.MaxStep)
+	override(absError, self.AbsError)
+	override(relError, self.RelError)
+}
+
+// Fix checks the step of a method with fixed steps and returns it.
+func (self *Options) Fix() (float64, error) {
+	if !(self.Step > 0) {
+		return 0, errors.New("the step should be positive")
+	}
+	return self.Step, nil
+}
+
+func override(setting *float64, value float64) {
+	if value != 0 {
+		*setting = value
+	}
+}
@@ -0,0 +1,37 @@
+package ode
+
+import (
+	"errors"
+)
+
+// Resample evaluates a dense solution of a system of dimension nd at n
+// equidistant points spanning the interval where the solution is defined,
+// which requires no further integration. The first and last points are the
+// endpoints of the interval exactly. The solution is returned in the form of
+// Integrator.Compute, that is, as a flat slice with the components at each
+// point being adjacent, along with the points.
+func Resample(solution Dense, nd, n uint) ([]float64, []float64, error) {
+	if nd == 0 {
+		return nil, nil, errors.New("the dimension should be positive")
+	}
+	if n < 2 {
+		return nil, nil, errors.New("the number of points should be at least two")
+	}
+
+	x0, xend := solution.Span()
+
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = x0 + (xend-x0)*(float64(i)/float64(n-1))
+	}
+	xs[n-1] = xend
+
+	ys := make([]float64, n*nd)
+	for i, x := range xs {
+		if err := solution.At(x, ys[uint(i)*nd:uint(i+1)*nd]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return ys, xs, nil
+}
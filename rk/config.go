@@ -0,0 +1,35 @@
+package rk
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The step of integration.
+	Step float64 `json:"step"`
+	// The flag that enables the compensated summation of the independent
+	// variable and the solution across steps, which reduces the accumulation
+	// of rounding errors in integrations with a large number of steps.
+	Compensated bool `json:"compensated"`
+	// The flag that makes the solution at the intermediate points of xs be
+	// computed using cubic Hermite interpolation between the points of the
+	// grid instead of partial steps of the method. The interpolation costs no
+	// evaluations of the derivative beyond the steps, but it is accurate only
+	// to the third order.
+	Interpolate bool `json:"interpolate"`
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if !(c.Step > 0) {
+		return errors.New("the step should be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,383 @@
+// Package rk provides integrators of systems of ordinary differential
+// equations based on explicit Runge–Kutta methods with a fixed step given by
+// Butcher tableaus.
+//
+// https://en.wikipedia.org/wiki/Runge–Kutta_methods
+package rk
+
+import (
+	"context"
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+	"github.com/ready-steady/ode/internal/pool"
+)
+
+// Integrator is an integrator.
+//
+// An integrator is safe for concurrent use by multiple goroutines. Each
+// integration takes a workspace from a pool of the integrator and returns it
+// afterwards, so consecutive integrations of systems of the same dimension
+// allocate only their results.
+type Integrator struct {
+	tableau    *Tableau
+	config     Config
+	workspaces *pool.Pool[Workspace]
+}
+
+// New creates a new integrator based on a tableau.
+func New(tableau *Tableau, config *Config) (*Integrator, error) {
+	if err := tableau.verify(); err != nil {
+		return nil, err
+	}
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{
+		tableau:    tableau,
+		config:     *config,
+		workspaces: pool.New[Workspace](),
+	}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// If xs does not specify any intermediate points, the solution is returned at a
+// number of equidistant points starting from and including x0 = xs[0] and
+// ending at xend, the last element of xs. The number of steps is the one that
+// brings the grid the closest to xend, and the last step is adjusted to land
+// exactly on xend; hence, its size is between a half and one and a half of the
+// integration step. The points of the grid are returned as the second result.
+// Otherwise, the solution is returned exactly at the points of xs, which should
+// be strictly monotonic. The integration still proceeds on the grid, and the
+// solution at each point of xs is computed by a partial step of the method from
+// the preceding point of the grid, which costs one step per point; see
+// Config.Interpolate for a cheaper alternative. If xend < x0, the integration
+// is carried out backward with the step of the configuration taken with the
+// negative sign.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.compute(context.Background(), dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	return self.compute(context.Background(), dydx, y0, xs)
+}
+
+// ComputeContext integrates the system of differential equations
+// dy/dx = f(x, y) like Compute but checks the context before each step. If the
+// context is done, the integration stops, and the function returns the error
+// of the context along with the solution computed so far and the
+// corresponding points.
+func (self *Integrator) ComputeContext(ctx context.Context, dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.compute(ctx, dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+func (self *Integrator) compute(ctx context.Context, dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	if err := interval.Validate(y0, xs); err != nil {
+		return nil, nil, nil, err
+	}
+
+	nd, nx := len(y0), len(xs)
+
+	workspace := self.acquire(nd)
+	defer self.release(workspace)
+
+	if nx > 2 {
+		ys := make([]float64, nx*nd)
+		n, stats, err := self.sample(ctx, dydx, y0, xs, ys, workspace)
+		return ys[:n*nd], xs[:n], detach(stats), err
+	}
+
+	x0, xend := xs[0], xs[nx-1]
+	h := self.step(x0, xend)
+	np := points(x0, xend, h)
+
+	ys := make([]float64, np*nd)
+	xs = make([]float64, np)
+
+	n, stats, err := self.march(ctx, dydx, y0, x0, xend, h, np, workspace,
+		func(k int, x float64, y []float64) {
+			copy(ys[k*nd:(k+1)*nd], y)
+			xs[k] = x
+		})
+
+	return ys[:n*nd], xs[:n], detach(stats), err
+}
+
+// step returns the signed step of integration from x0 to xend.
+func (self *Integrator) step(x0, xend float64) float64 {
+	if xend < x0 {
+		return -self.config.Step
+	}
+	return self.config.Step
+}
+
+// points returns the number of points of the grid with a step h that brings
+// it the closest to xend.
+func points(x0, xend, h float64) int {
+	np := int((xend-x0)/h+0.5) + 1
+	if np == 1 && xend != x0 {
+		np = 2
+	}
+	return np
+}
+
+// march integrates the system on the grid of np points starting from x0 with
+// the last step adjusted to land exactly on xend and passes each point of the
+// grid, including the first one, to emit. The solution passed to emit is valid
+// only until emit returns. The function returns the number of points passed.
+func (self *Integrator) march(ctx context.Context, dydx func(float64, []float64, []float64),
+	y0 []float64, x0, xend, h float64, np int, workspace *Workspace,
+	emit func(int, float64, []float64)) (int, *Stats, error) {
+
+	ns := uint(len(self.tableau.C))
+
+	stats := &workspace.stats
+	*stats = Stats{End: x0}
+
+	y, ynew := workspace.y, workspace.ynew
+	copy(y, y0)
+
+	// Done with the first point.
+	emit(0, x0, y)
+
+	// The compensations of the rounding errors of x and y, if any.
+	var cx float64
+	var cy []float64
+	if self.config.Compensated {
+		cy = workspace.c
+		for i := range cy {
+			cy[i] = 0
+		}
+	}
+
+	stepper := workspace.stepper
+	for k, x := 1, x0; k < np; k++ {
+		if err := ctx.Err(); err != nil {
+			return k, stats, err
+		}
+
+		// Land exactly on the end unless the grid already does so up to
+		// rounding errors.
+		if k == np-1 && math.Abs(xend-x-h) > 1e-8*math.Abs(h) {
+			h = xend - x
+		}
+
+		dydx(x, y, stepper.f[0])
+		stepper.step(dydx, x, h, y, cy, ynew)
+
+		stats.Evaluations += ns
+		stats.Steps++
+
+		if cy == nil {
+			x += h
+		} else {
+			x, cx = add(x, h, cx)
+		}
+		if k == np-1 {
+			x = xend
+		}
+		y, ynew = ynew, y
+
+		emit(k, x, y)
+		stats.End = x
+	}
+
+	return np, stats, nil
+}
+
+// sample computes the solution at the points of xs by taking partial steps
+// from the points of the grid or, if requested by the configuration, by cubic
+// Hermite interpolation between them and stores it in ys. The function
+// returns the number of points stored.
+func (self *Integrator) sample(ctx context.Context, dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64, ys []float64, workspace *Workspace) (int, *Stats, error) {
+
+	nd, nx := len(y0), len(xs)
+
+	h := self.step(xs[0], xs[nx-1])
+
+	copy(ys, y0)
+
+	y, ynew := workspace.y, workspace.ynew
+	copy(y, y0)
+
+	stepper := workspace.stepper
+
+	ns := uint(len(self.tableau.C))
+
+	// The compensation of the rounding errors of y, if any. The points of the
+	// grid are computed directly, and the partial steps are not compensated.
+	var cy []float64
+	if self.config.Compensated {
+		cy = workspace.c
+		for i := range cy {
+			cy[i] = 0
+		}
+	}
+
+	// The derivative at the end of each step, which is needed by the
+	// interpolation and is reused by the next step.
+	interpolate := self.config.Interpolate
+	fnew := workspace.fnew
+
+	x := xs[0]
+
+	stats := &workspace.stats
+	*stats = Stats{End: x}
+
+	for k, nc := 0, 1; nc < nx; k++ {
+		if err := ctx.Err(); err != nil {
+			return nc, stats, err
+		}
+
+		if k == 0 || !interpolate {
+			dydx(x, y, stepper.f[0])
+			stats.Evaluations++
+		}
+		stepper.step(dydx, x, h, y, cy, ynew)
+
+		stats.Evaluations += ns - 1
+		stats.Steps++
+
+		xnew := xs[0] + float64(k+1)*h
+
+		if interpolate {
+			dydx(xnew, ynew, fnew)
+			stats.Evaluations++
+		}
+
+		// The partial steps and the interpolation reuse the derivative at x
+		// in f[0].
+		for ; nc < nx && h*(xnew-xs[nc]) >= 0; nc++ {
+			if xs[nc] == xnew {
+				copy(ys[nc*nd:(nc+1)*nd], ynew)
+			} else if interpolate {
+				hermite(x, y, stepper.f[0], xnew, ynew, fnew, xs[nc], ys[nc*nd:(nc+1)*nd])
+			} else {
+				stepper.step(dydx, x, xs[nc]-x, y, nil, ys[nc*nd:(nc+1)*nd])
+				stats.Evaluations += ns - 1
+			}
+			stats.End = xs[nc]
+		}
+
+		x = xnew
+		copy(y, ynew)
+		if interpolate {
+			copy(stepper.f[0], fnew)
+		}
+	}
+
+	return nx, stats, nil
+}
+
+type stepper struct {
+	tableau *Tableau
+	d       float64
+	z       []float64
+	f       [][]float64
+}
+
+func (self *Integrator) newStepper(nd int) *stepper {
+	d := self.tableau.Denominator
+	if d == 0 {
+		d = 1
+	}
+
+	f := make([][]float64, len(self.tableau.C))
+	for k := range f {
+		f[k] = make([]float64, nd)
+	}
+
+	return &stepper{tableau: self.tableau, d: d, z: make([]float64, nd), f: f}
+}
+
+// step takes a step given the derivative at the current point in f[0]. If the
+// compensation of the rounding errors of y is given by c, the solution is
+// accumulated using compensated summation, and c is updated in place.
+func (self *stepper) step(dydx func(float64, []float64, []float64), x, h float64,
+	y, c, ynew []float64) {
+
+	A, B, C := self.tableau.A, self.tableau.B, self.tableau.C
+	f, z := self.f, self.z
+
+	for l := 1; l < len(C); l++ {
+		combine(y, h, A[l], 1, f, z)
+		dydx(x+C[l]*h, z, f[l])
+	}
+
+	if c == nil {
+		combine(y, h, B, self.d, f, ynew)
+	} else {
+		accumulate(y, h, B, self.d, f, c, ynew)
+	}
+}
+
+func hermite(x0 float64, y0, f0 []float64, x1 float64, y1, f1 []float64,
+	xnext float64, ynext []float64) {
+
+	h := x1 - x0
+	s := (xnext - x0) / h
+
+	h00 := (1 + 2*s) * (1 - s) * (1 - s)
+	h10 := s * (1 - s) * (1 - s)
+	h01 := s * s * (3 - 2*s)
+	h11 := s * s * (s - 1)
+
+	for i := range ynext {
+		ynext[i] = h00*y0[i] + h*h10*f0[i] + h01*y1[i] + h*h11*f1[i]
+	}
+}
+
+// combine computes ynew = y + h Σ w[k] f[k] / d.
+func combine(y []float64, h float64, w []float64, d float64, f [][]float64, ynew []float64) {
+	for i := range y {
+		sum := 0.0
+		for k := range w {
+			if w[k] != 0 {
+				sum += w[k] * f[k][i]
+			}
+		}
+		ynew[i] = y[i] + h*sum/d
+	}
+}
+
+// accumulate computes ynew = y + h Σ w[k] f[k] / d using the compensated
+// summation of Kahan given the compensation c of y, which is replaced with the
+// compensation of ynew.
+func accumulate(y []float64, h float64, w []float64, d float64, f [][]float64,
+	c, ynew []float64) {
+
+	for i := range y {
+		sum := 0.0
+		for k := range w {
+			if w[k] != 0 {
+				sum += w[k] * f[k][i]
+			}
+		}
+		ynew[i], c[i] = add(y[i], h*sum/d, c[i])
+	}
+}
+
+// add computes x + δ using the compensated summation of Kahan given the
+// compensation c of x and returns the sum and its compensation.
+func add(x, δ, c float64) (float64, float64) {
+	δ -= c
+	xnew := x + δ
+	return xnew, (xnew - x) - δ
+}
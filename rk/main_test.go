@@ -0,0 +1,37 @@
+package rk
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(&Tableau{A: [][]float64{{}}, B: []float64{1}, C: []float64{0}}, &Config{Step: 0.1})
+	assert.Equal(err, nil, t)
+
+	_, err = New(&Tableau{A: [][]float64{{}, {1}}, B: []float64{1}, C: []float64{0, 1}}, &Config{Step: 0.1})
+	assert.Equal(err != nil, true, t)
+
+	_, err = New(&Tableau{A: [][]float64{{}}, B: []float64{1}, C: []float64{0}}, &Config{Step: 0})
+	assert.Equal(err != nil, true, t)
+
+	assert.Equal((&Config{Step: math.NaN()}).Validate() != nil, true, t)
+}
+
+func TestComputeLinear(t *testing.T) {
+	dydx := func(x float64, _, f []float64) {
+		f[0] = 2 * x
+	}
+
+	// The explicit midpoint method is exact for a linear derivative.
+	integrator, _ := New(&Tableau{
+		A: [][]float64{{}, {1.0 / 2}},
+		B: []float64{0, 1},
+		C: []float64{0, 1.0 / 2},
+	}, &Config{Step: 0.25})
+
+	ys, _, _ := integrator.Compute(dydx, []float64{1}, []float64{0, 1})
+	assert.Close(ys, []float64{1, 1.0625, 1.25, 1.5625, 2}, 1e-15, t)
+}
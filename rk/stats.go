@@ -0,0 +1,9 @@
+package rk
+
+// Stats contains information about the work done by an integrator.
+type Stats struct {
+	Evaluations uint    // The number of invocations of the derivative function.
+	Rejections  uint    // The number of rejected steps, which is always zero.
+	Steps       uint    // The number of steps the algorithm has taken.
+	End         float64 // The last point where the solution has been returned.
+}
@@ -0,0 +1,77 @@
+package rk
+
+import (
+	"errors"
+)
+
+// Stepper integrates a system of differential equations one step at a time,
+// which is useful when the integration is driven from outside, for instance,
+// in co-simulation.
+type Stepper struct {
+	integrator *Integrator
+	dydx       func(float64, []float64, []float64)
+	stepper    *stepper
+
+	x, xnew      float64
+	y, ynew      []float64
+	f0, f1       []float64
+	taken, ready bool
+}
+
+// NewStepper creates a stepper for the system of differential equations
+// dy/dx = f(x, y). The stepper should be initialized using Init before
+// taking steps.
+func (self *Integrator) NewStepper(dydx func(float64, []float64, []float64)) *Stepper {
+	return &Stepper{integrator: self, dydx: dydx}
+}
+
+// Init sets the initial condition y0 at x0.
+func (self *Stepper) Init(x0 float64, y0 []float64) {
+	nd := len(y0)
+
+	self.stepper = self.integrator.newStepper(nd)
+	self.x, self.xnew = x0, x0
+	self.y = append([]float64(nil), y0...)
+	self.ynew = append([]float64(nil), y0...)
+	self.f0 = make([]float64, nd)
+	self.f1 = make([]float64, nd)
+	self.taken, self.ready = false, true
+
+	self.dydx(x0, self.y, self.f0)
+}
+
+// Step takes one step and returns the new point, the solution at it, and the
+// size of the step. The solution is owned by the stepper and is valid until
+// the next step.
+func (self *Stepper) Step() (float64, []float64, float64, error) {
+	if !self.ready {
+		return 0, nil, 0, errors.New("the stepper should be initialized")
+	}
+
+	if self.taken {
+		self.x = self.xnew
+		copy(self.y, self.ynew)
+		copy(self.f0, self.f1)
+	}
+
+	h := self.integrator.config.Step
+
+	copy(self.stepper.f[0], self.f0)
+	self.stepper.step(self.dydx, self.x, h, self.y, nil, self.ynew)
+
+	self.xnew = self.x + h
+	self.dydx(self.xnew, self.ynew, self.f1)
+	self.taken = true
+
+	return self.xnew, self.ynew, h, nil
+}
+
+// Interpolate computes the solution at a point within the last step using
+// cubic Hermite interpolation and stores the result in y.
+func (self *Stepper) Interpolate(x float64, y []float64) {
+	if !self.taken || x == self.xnew {
+		copy(y, self.ynew)
+		return
+	}
+	hermite(self.x, self.y, self.f0, self.xnew, self.ynew, self.f1, x, y)
+}
@@ -0,0 +1,42 @@
+package rk
+
+import (
+	"errors"
+)
+
+// Tableau is the Butcher tableau of an explicit Runge–Kutta method.
+type Tableau struct {
+	// The coefficients of the stages, which form a strictly lower triangular
+	// matrix stored by rows. The first row is empty.
+	A [][]float64
+	// The weights of the solution, which are to be divided by Denominator.
+	B []float64
+	// The common denominator of the weights of the solution. If it is not
+	// given, it is assumed to be one.
+	Denominator float64
+	// The nodes of the stages.
+	C []float64
+}
+
+func (t *Tableau) verify() error {
+	ns := len(t.C)
+	if ns == 0 {
+		return errors.New("the tableau should have at least one stage")
+	}
+	if len(t.A) != ns {
+		return errors.New("the tableau should have as many rows of coefficients as stages")
+	}
+	for i := range t.A {
+		if len(t.A[i]) > i {
+			return errors.New("the matrix of coefficients should be strictly lower triangular")
+		}
+	}
+	if len(t.B) != ns {
+		return errors.New("the tableau should have as many weights as stages")
+	}
+	if t.Denominator < 0 {
+		return errors.New("the denominator should be nonnegative")
+	}
+
+	return nil
+}
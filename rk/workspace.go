@@ -0,0 +1,106 @@
+package rk
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Workspace is the storage that an integrator needs in order to integrate a
+// system of a given dimension. A workspace can be reused across integrations
+// in order to avoid allocations; see Integrator.ComputeInto. It belongs to
+// the integrator that has created it and should not be used by several
+// integrations at the same time.
+type Workspace struct {
+	stepper *stepper
+	y, ynew []float64
+	c       []float64
+	fnew    []float64
+
+	stats Stats
+}
+
+// NewWorkspace allocates a workspace for systems of dimension nd.
+func (self *Integrator) NewWorkspace(nd uint) *Workspace {
+	return &Workspace{
+		stepper: self.newStepper(int(nd)),
+		y:       make([]float64, nd),
+		ynew:    make([]float64, nd),
+		c:       make([]float64, nd),
+		fnew:    make([]float64, nd),
+	}
+}
+
+// Stats returns the statistics of the last integration that has used the
+// workspace. They are overwritten by the next integration.
+func (self *Workspace) Stats() *Stats {
+	return &self.stats
+}
+
+// ComputeInto integrates the system of differential equations dy/dx = f(x, y)
+// like Compute but stores the solution at the points of xs in ys, which should
+// have room for len(xs)×nd entries, and uses the workspace instead of
+// allocating storage. If xs has only two points, the integration proceeds on
+// the same grid as in Compute, and only the solution at the endpoints is
+// stored. Given a workspace matching the dimension of the system, the
+// integration does not allocate. The function returns the number of points
+// that have been stored.
+func (self *Integrator) ComputeInto(dydx func(float64, []float64, []float64),
+	y0 []float64, xs, ys []float64, workspace *Workspace) (uint, error) {
+
+	if err := interval.Validate(y0, xs); err != nil {
+		return 0, err
+	}
+
+	nd, nx := len(y0), len(xs)
+	if len(workspace.y) != nd {
+		return 0, errors.New("the workspace should match the dimension of the system")
+	}
+	if len(ys) < nx*nd {
+		return 0, errors.New("the output should have room for the solution at all the points")
+	}
+
+	if nx > 2 {
+		n, _, err := self.sample(context.Background(), dydx, y0, xs, ys, workspace)
+		return uint(n), err
+	}
+
+	x0, xend := xs[0], xs[nx-1]
+	h := self.step(x0, xend)
+	np := points(x0, xend, h)
+
+	n, _, err := self.march(context.Background(), dydx, y0, x0, xend, h, np, workspace,
+		func(k int, _ float64, y []float64) {
+			if k == 0 {
+				copy(ys[:nd], y)
+			} else if k == np-1 {
+				copy(ys[nd:2*nd], y)
+			}
+		})
+	if n < np {
+		return 1, err
+	}
+
+	return 2, err
+}
+
+// acquire takes a workspace for systems of dimension nd from the pool of the
+// integrator or allocates one if the pool has none.
+func (self *Integrator) acquire(nd int) *Workspace {
+	if workspace := self.workspaces.Get(uint(nd)); workspace != nil {
+		return workspace
+	}
+	return self.NewWorkspace(uint(nd))
+}
+
+// release returns a workspace to the pool of the integrator.
+func (self *Integrator) release(workspace *Workspace) {
+	self.workspaces.Put(uint(len(workspace.y)), workspace)
+}
+
+// detach copies statistics that belong to a workspace.
+func detach(stats *Stats) *Stats {
+	clone := *stats
+	return &clone
+}
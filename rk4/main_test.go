@@ -1,6 +1,10 @@
 package rk4
 
 import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
 	"testing"
 
 	"github.com/ready-steady/assert"
@@ -23,3 +27,356 @@ func TestComputeKraichnanOrszag(t *testing.T) {
 
 	assert.Close(ys, fixture.ys, 5e-14, t)
 }
+
+func TestComputeInterpolation(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	xs := []float64{0, 0.123, 0.5, 1.77, 2, 3.14159}
+
+	integrator, _ := New(&Config{Step: 0.01, Interpolate: true})
+	ys, zs, stats, _ := integrator.ComputeWithStats(dydx, []float64{1, 0}, xs)
+
+	assert.Equal(zs, xs, t)
+	assert.Equal(len(ys), 2*len(xs), t)
+	for i, x := range xs {
+		assert.Close(ys[2*i:2*i+2], []float64{math.Cos(x), -math.Sin(x)}, 1e-9, t)
+	}
+
+	// The derivative at the end of each step is reused by the next one.
+	assert.Equal(stats.Evaluations, 4*stats.Steps+1, t)
+
+	// The interpolation agrees with the partial steps, which are used by
+	// default, up to its accuracy.
+	exact, _ := New(&Config{Step: 0.01})
+	zs, _, _ = exact.Compute(dydx, []float64{1, 0}, xs)
+	for i := range xs {
+		assert.Close(zs[2*i:2*i+2], ys[2*i:2*i+2], 1e-9, t)
+	}
+}
+
+func TestStepper(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.01})
+
+	stepper := integrator.NewStepper(dydx)
+	stepper.Init(0, []float64{1, 0})
+
+	z := make([]float64, 2)
+	for k := 1; k <= 100; k++ {
+		x, y, h, err := stepper.Step()
+		assert.Equal(err, nil, t)
+		assert.Equal(h, 0.01, t)
+		assert.Close(x, float64(k)*0.01, 1e-12, t)
+		assert.Close(y, []float64{math.Cos(x), -math.Sin(x)}, 1e-9, t)
+
+		stepper.Interpolate(x-h/3, z)
+		assert.Close(z, []float64{math.Cos(x - h/3), -math.Sin(x - h/3)}, 1e-9, t)
+	}
+}
+
+func TestComputeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	evaluations := 0
+	dydx := func(_ float64, y, f []float64) {
+		if evaluations++; evaluations == 40 {
+			cancel()
+		}
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	ys, xs, err := integrator.ComputeContext(ctx, dydx, []float64{1, 0}, []float64{0, 10})
+	assert.Equal(err, context.Canceled, t)
+	assert.Equal(len(xs), 11, t)
+	assert.Equal(len(ys), 2*11, t)
+	assert.Close(xs[10], 1.0, 1e-12, t)
+
+	cancel()
+
+	xs = []float64{0, 0.5, 1, 1.5}
+	ys, zs, err := integrator.ComputeContext(ctx, dydx, []float64{1, 0}, xs)
+	assert.Equal(err, context.Canceled, t)
+	assert.Equal(zs, xs[:1], t)
+	assert.Equal(ys, []float64{1, 0}, t)
+}
+
+func TestComputeBackward(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.01})
+
+	y0 := []float64{math.Cos(3), -math.Sin(3)}
+
+	ys, _, _ := integrator.Compute(dydx, y0, []float64{3, 0})
+	assert.Equal(len(ys), 2*301, t)
+	assert.Close(ys[600:], []float64{1, 0}, 1e-9, t)
+
+	xs := []float64{3, 2.5, 1.234, 0}
+	ys, _, _ = integrator.Compute(dydx, y0, xs)
+	for i, x := range xs {
+		assert.Close(ys[2*i:2*i+2], []float64{math.Cos(x), -math.Sin(x)}, 1e-9, t)
+	}
+}
+
+func TestComputeSubstep(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	grid, _, _ := integrator.Compute(dydx, []float64{1, 0}, []float64{0, 1})
+
+	xs := []float64{0, 0.3, 0.65, 1}
+	ys, _, _ := integrator.Compute(dydx, []float64{1, 0}, xs)
+
+	// The points of the grid are reproduced exactly.
+	assert.Close(ys[2:4], grid[6:8], 1e-15, t)
+	assert.Close(ys[6:8], grid[20:22], 1e-15, t)
+
+	// The other points are given by a step of the method from the grid.
+	partial, _ := New(&Config{Step: 0.05})
+	zs, _, _ := partial.Compute(dydx, grid[12:14], []float64{0.6, 0.65})
+	assert.Equal(ys[4:6], zs[2:4], t)
+}
+
+func TestComputeWithStats(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.3})
+
+	_, _, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Steps, uint(3), t)
+	assert.Equal(stats.Evaluations, uint(4*3), t)
+	assert.Equal(stats.End, 1.0, t)
+
+	_, _, stats, err = integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 0.3, 0.5, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.Steps, uint(4), t)
+	assert.Equal(stats.Evaluations, uint(4*4+2*3), t)
+	assert.Equal(stats.End, 1.0, t)
+
+	integrator, _ = New(&Config{Step: 0.1})
+
+	_, _, stats, err = integrator.ComputeWithStats(dydx, []float64{1, 0}, []float64{0, 0.5, 0.95})
+	assert.Equal(err, nil, t)
+	assert.Equal(stats.End, 0.95, t)
+}
+
+func TestComputeEnd(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.3})
+
+	for _, xend := range []float64{0.1, 0.9, 1, 1.04} {
+		ys, xs, _ := integrator.Compute(dydx, []float64{1, 0}, []float64{0, xend})
+
+		n := len(xs)
+		assert.Equal(len(ys), 2*n, t)
+		assert.Equal(xs[n-1], xend, t)
+		for k := 1; k < n-1; k++ {
+			assert.Close(xs[k], 0.3*float64(k), 1e-15, t)
+		}
+		assert.Close(ys[2*(n-1):], []float64{math.Cos(xend), -math.Sin(xend)}, 1e-3, t)
+	}
+}
+
+func TestComputeInto(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	workspace := integrator.NewWorkspace(2)
+
+	for _, xs := range [][]float64{{0, 1.03}, {0, 0.25, 0.5, 1}} {
+		ys1, _, _ := integrator.Compute(dydx, []float64{1, 0}, xs)
+
+		ys2 := make([]float64, 2*len(xs))
+		n, err := integrator.ComputeInto(dydx, []float64{1, 0}, xs, ys2, workspace)
+		assert.Equal(err, nil, t)
+		assert.Equal(n, uint(len(xs)), t)
+		assert.Equal(ys2[:2], ys1[:2], t)
+		assert.Equal(ys2[len(ys2)-2:], ys1[len(ys1)-2:], t)
+	}
+
+	xs, y0 := []float64{0, 10}, []float64{1, 0}
+	ys := make([]float64, 4)
+	allocations := testing.AllocsPerRun(10, func() {
+		integrator.ComputeInto(dydx, y0, xs, ys, workspace)
+	})
+	assert.Equal(allocations, 0.0, t)
+	assert.Equal(workspace.Stats().Steps, uint(100), t)
+
+	_, err := integrator.ComputeInto(dydx, []float64{1}, xs, ys, workspace)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeInvalid(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	for _, xs := range [][]float64{nil, {0}, {0, 0}, {0, 0.5, 0.5, 1}, {1, 0.5, 0.7, 0}} {
+		_, _, err := integrator.Compute(dydx, []float64{1}, xs)
+		assert.Equal(err != nil, true, t)
+	}
+
+	_, _, err := integrator.Compute(dydx, nil, []float64{0, 1})
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeCompensated(t *testing.T) {
+	dydx := func(_ float64, _, f []float64) {
+		f[0] = 0.1
+	}
+
+	integrator, _ := New(&Config{Step: 1e-3})
+
+	ys, _, err := integrator.Compute(dydx, []float64{0}, []float64{0, 100})
+	assert.Equal(err, nil, t)
+	plain := math.Abs(ys[len(ys)-1] - 10)
+
+	integrator, _ = New(&Config{Step: 1e-3, Compensated: true})
+
+	ys, xs, err := integrator.Compute(dydx, []float64{0}, []float64{0, 100})
+	assert.Equal(err, nil, t)
+	for k := range xs {
+		assert.Close(xs[k], 1e-3*float64(k), 1e-13, t)
+	}
+	assert.Equal(plain > 1e-12, true, t)
+	assert.Close(ys[len(ys)-1], 10.0, 1e-14, t)
+}
+
+func TestComputeConcurrent(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	xs, y0 := []float64{0, 0.25, 0.5, 1}, []float64{1, 0}
+	ys, _, stats, _ := integrator.ComputeWithStats(dydx, y0, xs)
+
+	// Given a workspace, the integration does not allocate, which is measured
+	// without the pool since the runtime can empty it at any time.
+	workspace := integrator.NewWorkspace(2)
+	zs := make([]float64, len(ys))
+	allocations := testing.AllocsPerRun(10, func() {
+		integrator.ComputeInto(dydx, y0, xs, zs, workspace)
+	})
+	assert.Equal(allocations, 0.0, t)
+	assert.Equal(zs, ys, t)
+
+	// The failures are reported by the test goroutine.
+	done := make(chan error)
+	for k := 0; k < 8; k++ {
+		go func() {
+			for i := 0; i < 10; i++ {
+				ys1, _, stats1, err := integrator.ComputeWithStats(dydx, y0, xs)
+				if err != nil {
+					done <- err
+					return
+				}
+				if !reflect.DeepEqual(ys1, ys) || !reflect.DeepEqual(stats1, stats) {
+					done <- errors.New("the concurrent integrations should agree")
+					return
+				}
+			}
+			done <- nil
+		}()
+	}
+	for k := 0; k < 8; k++ {
+		assert.Equal(<-done, nil, t)
+	}
+}
+
+func TestRecommendStiff(t *testing.T) {
+	const λ = -1000.0
+
+	dydx := func(x float64, y, f []float64) {
+		f[0] = λ*(y[0]-math.Cos(x)) - math.Sin(x)
+	}
+
+	xs := []float64{0, 10}
+
+	recommendation, err := Recommend(dydx, []float64{1}, xs, 1e-3)
+	assert.Equal(err, nil, t)
+	assert.Close(recommendation.Radius, -λ, 1, t)
+	assert.Close(recommendation.Stability, 2.5/-λ, 1e-5, t)
+	assert.Equal(recommendation.Accuracy > recommendation.Stability, true, t)
+	assert.Equal(recommendation.Step <= 0.9*recommendation.Stability, true, t)
+
+	integrator, _ := New(&Config{Step: recommendation.Step})
+	ys, zs, _ := integrator.Compute(dydx, []float64{1}, xs)
+	assert.Equal(zs[len(zs)-1], 10.0, t)
+	assert.Close(ys[len(ys)-1], math.Cos(10), 1e-3, t)
+
+	integrator, _ = New(&Config{Step: 3 / -λ})
+	ys, _, _ = integrator.Compute(dydx, []float64{1}, xs)
+	assert.Equal(math.Abs(ys[len(ys)-1]) < 1e3, false, t)
+}
+
+func TestRecommendAccuracy(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{0, 10}
+
+	for _, tolerance := range []float64{1e-6, 1e-10} {
+		recommendation, err := Recommend(dydx, []float64{0.5}, xs, tolerance)
+		assert.Equal(err, nil, t)
+		assert.Equal(recommendation.Accuracy < recommendation.Stability, true, t)
+		assert.Equal(recommendation.Evaluations < 1000, true, t)
+
+		integrator, _ := New(&Config{Step: recommendation.Step})
+		ys, zs, _ := integrator.Compute(dydx, []float64{0.5}, xs)
+		assert.Equal(zs[len(zs)-1], 10.0, t)
+		assert.Close(ys[len(ys)-1], 0.5*(math.Cos(10)+math.Sin(10)), 10*tolerance, t)
+	}
+}
+
+func TestRecommendBackward(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0], f[1] = y[1], -100*y[0]
+	}
+
+	xs := []float64{0, -5}
+
+	recommendation, err := Recommend(dydx, []float64{1, 0}, xs, 1e-8)
+	assert.Equal(err, nil, t)
+	assert.Close(recommendation.Radius, 10.0, 1e-3, t)
+
+	integrator, _ := New(&Config{Step: recommendation.Step})
+	ys, zs, _ := integrator.Compute(dydx, []float64{1, 0}, xs)
+	assert.Equal(zs[len(zs)-1], -5.0, t)
+	assert.Close(ys[len(ys)-2], math.Cos(50), 1e-5, t)
+}
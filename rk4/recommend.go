@@ -0,0 +1,246 @@
+package rk4
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	probeSteps      = 10
+	probeIterations = 20
+
+	// The stability interval of the method on the negative real axis is about
+	// 2.785, and the boundary of its stability region is farther from the
+	// origin elsewhere in the left half-plane except near the imaginary axis,
+	// where it is at 2√2. The limit leaves a margin below both.
+	stabilityLimit = 2.5
+
+	safety = 0.9
+)
+
+// Recommendation is a step of integration recommended by Recommend along with
+// the estimates it is based on.
+type Recommendation struct {
+	Step        float64 // The recommended step.
+	Stability   float64 // The largest step allowed by the stability of the method.
+	Accuracy    float64 // The largest step meeting the tolerance.
+	Radius      float64 // The largest spectral radius of the Jacobian matrix.
+	Evaluations uint    // The number of invocations of the derivative function.
+}
+
+// Recommend chooses a step of integration for the system of differential
+// equations dy/dx = f(x, y) on the interval from x0 = xs[0] to xend, the last
+// element of xs, given the initial condition y0.
+//
+// The function takes a few probing steps of the method from x0 and, along the
+// way, estimates the spectral radius of the Jacobian matrix by the power
+// iteration, in which the products of the matrix with vectors are
+// approximated by finite differences, and the local error by comparing one
+// step with two half steps. The recommended step is the largest one that keeps
+// the spectral radius times the step inside the stability region of the method
+// and the local error per unit of x below tolerance, which is relative to the
+// magnitude of each component of the solution or absolute if the magnitude is
+// below one, with a safety factor. It is reduced further so that a whole
+// number of steps covers the interval.
+//
+// The estimates are only as good as the probed part of the interval is
+// representative of the rest; if the system becomes stiffer or the solution
+// less smooth later on, the step might still be too large.
+func Recommend(dydx func(float64, []float64, []float64), y0 []float64, xs []float64,
+	tolerance float64) (*Recommendation, error) {
+
+	nd, nx := len(y0), len(xs)
+	if nx < 2 {
+		return nil, errors.New("the interval should have two endpoints")
+	}
+	if !(tolerance > 0) {
+		return nil, errors.New("the tolerance should be positive")
+	}
+
+	x0, xend := xs[0], xs[nx-1]
+	if x0 == xend {
+		return nil, errors.New("the interval should not be empty")
+	}
+
+	recommendation := &Recommendation{}
+
+	// Integrate backward as forward in the reversed variable.
+	evaluate := dydx
+	if xend < x0 {
+		evaluate = func(x float64, y, f []float64) {
+			dydx(-x, y, f)
+			for i := range f {
+				f[i] = -f[i]
+			}
+		}
+		x0, xend = -x0, -xend
+	}
+	count := evaluate
+	evaluate = func(x float64, y, f []float64) {
+		count(x, y, f)
+		recommendation.Evaluations++
+	}
+
+	length := xend - x0
+
+	y := append([]float64(nil), y0...)
+	ynew := make([]float64, nd)
+	yhalf := make([]float64, nd)
+	f := make([]float64, nd)
+	u := make([]float64, nd)
+	w := make([]float64, nd)
+	work := make([]float64, 5*nd)
+
+	scale := func(i int) float64 {
+		return math.Max(math.Abs(y[i]), 1)
+	}
+
+	evaluate(x0, y, f)
+
+	radius := func(x float64) {
+		ρ := spectralRadius(evaluate, x, y, f, u, w)
+		recommendation.Radius = math.Max(recommendation.Radius, ρ)
+	}
+	radius(x0)
+
+	// Choose the probing step based on the scale of the derivative.
+	h := length / probeSteps
+	norm, slope := 0.0, 0.0
+	for i := 0; i < nd; i++ {
+		norm = math.Max(norm, math.Abs(y[i])/scale(i))
+		slope = math.Max(slope, math.Abs(f[i])/scale(i))
+	}
+	if slope > 0 {
+		h = math.Min(h, 0.01*math.Max(norm, 1)/slope)
+	}
+	if ρ := recommendation.Radius; ρ > 0 {
+		h = math.Min(h, stabilityLimit/ρ)
+	}
+
+	// The local error of a step of size h behaves as C h⁵.
+	constant := 0.0
+
+	x := x0
+	for k := 0; k < probeSteps && x < xend; k++ {
+		step := math.Min(h, xend-x)
+
+		advance(evaluate, x, step, y, f, ynew, work)
+		advance(evaluate, x, step/2, y, f, yhalf, work)
+		evaluate(x+step/2, yhalf, w)
+		advance(evaluate, x+step/2, step/2, yhalf, w, yhalf, work)
+
+		ε := 0.0
+		for i := 0; i < nd; i++ {
+			ε = math.Max(ε, 16.0/15*math.Abs(ynew[i]-yhalf[i])/scale(i))
+		}
+		constant = math.Max(constant, ε/math.Pow(step, 5))
+
+		x += step
+		copy(y, yhalf)
+		evaluate(x, y, f)
+		radius(x)
+	}
+
+	recommendation.Stability = math.Inf(1)
+	if ρ := recommendation.Radius; ρ > 0 {
+		recommendation.Stability = stabilityLimit / ρ
+	}
+	recommendation.Accuracy = math.Inf(1)
+	if constant > 0 {
+		recommendation.Accuracy = math.Pow(tolerance/constant, 1.0/4)
+	}
+
+	step := math.Min(length, safety*math.Min(recommendation.Stability, recommendation.Accuracy))
+	recommendation.Step = length / math.Ceil(length/step)
+
+	return recommendation, nil
+}
+
+// advance takes a step of the method given the derivative f at (x, y) and
+// stores the result in ynew, which can coincide with y. The buffer work should
+// have room for five vectors.
+func advance(dydx func(float64, []float64, []float64), x, h float64, y, f, ynew,
+	work []float64) {
+
+	nd := len(y)
+	z, k2, k3, k4, sum := work[:nd], work[nd:2*nd], work[2*nd:3*nd], work[3*nd:4*nd],
+		work[4*nd:5*nd]
+
+	for i := range z {
+		z[i] = y[i] + h/2*f[i]
+	}
+	dydx(x+h/2, z, k2)
+	for i := range z {
+		z[i] = y[i] + h/2*k2[i]
+	}
+	dydx(x+h/2, z, k3)
+	for i := range z {
+		z[i] = y[i] + h*k3[i]
+	}
+	dydx(x+h, z, k4)
+	for i := range sum {
+		sum[i] = y[i] + h/6*(f[i]+2*k2[i]+2*k3[i]+k4[i])
+	}
+	copy(ynew, sum)
+}
+
+// spectralRadius estimates the spectral radius of the Jacobian matrix of dydx
+// at (x, y) by the power iteration starting from u, or from a vector with
+// distinct components if u is zero, and stores the last iterate back in u. The
+// products of the matrix with vectors are approximated by forward differences
+// given the derivative f at (x, y), and w is an auxiliary buffer. The growth rates of the second half of the iterations are
+// averaged geometrically, which also accounts for complex eigenvalues.
+func spectralRadius(dydx func(float64, []float64, []float64), x float64, y, f, u,
+	w []float64) float64 {
+
+	nd := len(y)
+	z := make([]float64, nd)
+
+	norm := 0.0
+	for i := range y {
+		norm += y[i] * y[i]
+	}
+	δ := math.Sqrt(0x1p-52) * math.Max(math.Sqrt(norm), 1)
+
+	if normalize(u) == 0 {
+		for i := range u {
+			u[i] = 1 / float64(i+1)
+		}
+		normalize(u)
+	}
+
+	sum := 0.0
+	for k := 0; k < probeIterations; k++ {
+		for i := range z {
+			z[i] = y[i] + δ*u[i]
+		}
+		dydx(x, z, w)
+		for i := range w {
+			w[i] = (w[i] - f[i]) / δ
+		}
+		growth := normalize(w)
+		if growth == 0 {
+			return 0
+		}
+		if 2*k >= probeIterations {
+			sum += math.Log(growth)
+		}
+		copy(u, w)
+	}
+
+	return math.Exp(sum / (probeIterations - probeIterations/2))
+}
+
+func normalize(v []float64) float64 {
+	norm := 0.0
+	for i := range v {
+		norm += v[i] * v[i]
+	}
+	norm = math.Sqrt(norm)
+	if norm > 0 {
+		for i := range v {
+			v[i] /= norm
+		}
+	}
+	return norm
+}
@@ -0,0 +1,8 @@
+package rk4
+
+import (
+	"github.com/ready-steady/ode/rk"
+)
+
+// Stats contains information about the work done by an integrator.
+type Stats = rk.Stats
@@ -0,0 +1,8 @@
+package rk4
+
+import (
+	"github.com/ready-steady/ode/rk"
+)
+
+// Stepper integrates a system of differential equations one step at a time.
+type Stepper = rk.Stepper
@@ -0,0 +1,8 @@
+package rk4
+
+import (
+	"github.com/ready-steady/ode/rk"
+)
+
+// Workspace is the storage of an integration, which can be reused.
+type Workspace = rk.Workspace
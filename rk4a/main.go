@@ -0,0 +1,307 @@
+// Package rk4a provides an integrator of systems of ordinary differential
+// equations based on the fourth-order Runge–Kutta method with an adaptive
+// step size.
+//
+// The local error is estimated by step doubling: each step of size h is taken
+// both at once and as two steps of size h/2, and the difference between the
+// two results divided by 2⁴ - 1 estimates the error of the latter, which is
+// the one retained. A step costs ten evaluations of the derivative in
+// addition to the one at the new point, which is shared with the next step.
+// The solution at intermediate points is computed using cubic Hermite
+// interpolation.
+//
+// https://en.wikipedia.org/wiki/Adaptive_step_size
+package rk4a
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	power = 1.0 / 5
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	if len(y0) == 0 {
+		return nil, nil, nil, errors.New("the initial condition should not be empty")
+	}
+	if len(xs) < 2 {
+		return nil, nil, nil, errors.New("the interval should have two endpoints")
+	}
+	for i := 1; i < len(xs); i++ {
+		if !(xs[i] > xs[i-1]) {
+			return nil, nil, nil, errors.New("the points of the interval should be strictly increasing")
+		}
+	}
+
+	stats := &Stats{}
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	y := make([]float64, nd)
+	ynew := make([]float64, nd)
+	yfull := make([]float64, nd)
+	yhalf := make([]float64, nd)
+	f1 := make([]float64, nd)
+	fnew := make([]float64, nd)
+	fhalf := make([]float64, nd)
+
+	buffer := make([]float64, 4*nd)
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	dydx(x, y, f1)
+	stats.Evaluations++
+
+	config := &self.config
+
+	abserr, relerr := config.AbsError, config.RelError
+	threshold := abserr / relerr
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = 0.1 * (xend - x)
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = xs[1] - x
+		if h > hmax {
+			h = hmax
+		}
+
+		scale := 0.0
+		for i := 0; i < nd; i++ {
+			s := math.Abs(f1[i]) / math.Max(math.Abs(y[i]), threshold)
+			if s > scale {
+				scale = s
+			}
+		}
+		scale = scale / (0.8 * math.Pow(relerr, power))
+
+		if h*scale > 1 {
+			h = 1 / scale
+		}
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	for done := false; ; {
+		var xnew, ε float64
+
+		stats.Steps++
+
+		hmin := 16 * epsilon(x)
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		// Close to the end?
+		if 1.1*h >= xend-x {
+			h = xend - x
+			done = true
+		}
+
+		rejected := false
+
+		for {
+			xnew = x + h
+
+			step(dydx, x, h, y, f1, yfull, buffer)
+			step(dydx, x, h/2, y, f1, yhalf, buffer)
+			dydx(x+h/2, yhalf, fhalf)
+			step(dydx, x+h/2, h/2, yhalf, fhalf, ynew, buffer)
+			dydx(xnew, ynew, fnew)
+
+			stats.Evaluations += 11
+
+			// Compute the relative error.
+			ε = 0
+			for i := 0; i < nd; i++ {
+				scale := math.Max(math.Max(math.Abs(y[i]), math.Abs(ynew[i])), threshold)
+				if δ := math.Abs(ynew[i]-yfull[i]) / 15 / scale; δ > ε {
+					ε = δ
+				}
+			}
+
+			if ε <= relerr {
+				break
+			}
+
+			stats.Rejections++
+
+			if h <= hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			// Shrink the step size as the current one has been rejected.
+			if rejected {
+				h = 0.5 * h
+			} else if scale := 0.8 * math.Pow(relerr/ε, power); scale > 0.1 {
+				h = scale * h
+			} else {
+				h = 0.1 * h
+			}
+
+			if h < hmin {
+				h = hmin
+			}
+
+			done = false
+			rejected = true
+		}
+
+		if fixed {
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					copy(ys[nc*nd:(nc+1)*nd], ynew)
+				} else {
+					hermite(x, y, f1, xnew, ynew, fnew, xs[nc], ys[nc*nd:(nc+1)*nd])
+				}
+
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		if done {
+			break
+		}
+
+		x = xnew
+		copy(f1, fnew)
+		copy(y, ynew)
+
+		if rejected {
+			continue
+		}
+
+		// Compute a new step size.
+		if scale := 1.25 * math.Pow(ε/relerr, power); scale > 0.2 {
+			h = h / scale
+		} else {
+			h = 5 * h
+		}
+	}
+
+	return ys, xs, stats, nil
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
+
+// step takes a step of the fourth-order Runge–Kutta method given the
+// derivative at the current point.
+func step(dydx func(float64, []float64, []float64), x, h float64,
+	y, k1 []float64, ynew []float64, buffer []float64) {
+
+	nd := len(y)
+
+	z, k2 := buffer[0*nd:1*nd], buffer[1*nd:2*nd]
+	k3, k4 := buffer[2*nd:3*nd], buffer[3*nd:4*nd]
+
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h/2*k1[i]
+	}
+	dydx(x+h/2, z, k2)
+
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h/2*k2[i]
+	}
+	dydx(x+h/2, z, k3)
+
+	for i := 0; i < nd; i++ {
+		z[i] = y[i] + h*k3[i]
+	}
+	dydx(x+h, z, k4)
+
+	for i := 0; i < nd; i++ {
+		ynew[i] = y[i] + h/6*(k1[i]+2*k2[i]+2*k3[i]+k4[i])
+	}
+}
+
+func hermite(x0 float64, y0, f0 []float64, x1 float64, y1, f1 []float64,
+	xnext float64, ynext []float64) {
+
+	h := x1 - x0
+	s := (xnext - x0) / h
+
+	h00 := (1 + 2*s) * (1 - s) * (1 - s)
+	h10 := s * (1 - s) * (1 - s)
+	h01 := s * s * (3 - 2*s)
+	h11 := s * s * (s - 1)
+
+	for i := range ynext {
+		ynext[i] = h00*y0[i] + h*h10*f0[i] + h01*y1[i] + h*h11*f1[i]
+	}
+}
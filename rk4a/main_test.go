@@ -0,0 +1,70 @@
+package rk4a
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeOscillator(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+
+	xs := []float64{0, 0.5, 1, 2.25, 5, 7.5, 10}
+
+	for _, relerr := range []float64{1e-4, 1e-8} {
+		integrator, _ := New(&Config{AbsError: relerr, RelError: relerr})
+
+		ys, _, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0}, xs)
+		assert.Equal(err, nil, t)
+		for k, x := range xs {
+			assert.Close(ys[2*k:2*k+2], []float64{math.Cos(x), -math.Sin(x)}, 100*relerr, t)
+		}
+		assert.Equal(stats.Evaluations, 11*(stats.Steps+stats.Rejections)+1, t)
+	}
+}
+
+func TestComputeDecay(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -10 * y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	ys, xs, err := integrator.Compute(dydx, []float64{1}, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs[len(xs)-1], 1.0, t)
+	for k, x := range xs {
+		assert.Close(ys[k], math.Exp(-10*x), 1e-3, t)
+	}
+	for k := 1; k < len(xs); k++ {
+		assert.Equal(xs[k] > xs[k-1], true, t)
+	}
+}
+
+func TestComputeInvalid(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[0]
+	}
+
+	integrator, _ := New(DefaultConfig())
+
+	for _, xs := range [][]float64{{0}, {0, 0}, {1, 0}, {0, 0.5, 0.5, 1}} {
+		_, _, err := integrator.Compute(dydx, []float64{1}, xs)
+		assert.Equal(err != nil, true, t)
+	}
+
+	_, _, err := integrator.Compute(dydx, nil, []float64{0, 1})
+	assert.Equal(err != nil, true, t)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&Config{})
+	assert.Equal(err != nil, true, t)
+
+	_, err = New(DefaultConfig())
+	assert.Equal(err, nil, t)
+}
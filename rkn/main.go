@@ -0,0 +1,331 @@
+// Package rkn provides an integrator of systems of second-order ordinary
+// differential equations of the form
+//
+//	y″ = f(x, y, y′)
+//
+// based on the Runge–Kutta–Nyström form of the Dormand–Prince method of orders
+// five and four.
+//
+// The state of the system is composed of the positions y followed by the
+// velocities y′. The stages of the method are given only in terms of the
+// accelerations; the positions of the stages are obtained by applying the
+// square of the matrix of coefficients. The step size is controlled using
+// the errors of both the positions and the velocities, and the solution at
+// intermediate points is computed using the interpolant of the method. The
+// special case y″ = f(x, y) is handled by simply ignoring the velocities in f.
+//
+// https://en.wikipedia.org/wiki/Runge%E2%80%93Kutta_methods
+package rkn
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	power = 1.0 / 5
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system y″ = f(x, y, y′).
+//
+// The input function f(x, y, v, a) evaluates the acceleration for a given x,
+// position y, and velocity v and stores the result in its fourth argument. The
+// initial condition y0 contains the positions followed by the velocities. The
+// interval of integration is [x0, xend] where x0 and xend are the first and
+// last entries of xs, respectively.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(f func(float64, []float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(f, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(f func(float64, []float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	if len(y0)%2 != 0 {
+		return nil, nil, nil, errors.New("the initial condition should contain positions and velocities")
+	}
+	if len(xs) < 2 {
+		return nil, nil, nil, errors.New("the interval should have two endpoints")
+	}
+
+	ns := len(c)
+
+	stats := &Stats{}
+
+	nd, nx, nc := len(y0)/2, len(xs), 0
+
+	z := make([]float64, nd)
+	y := make([]float64, 2*nd)
+	ynew := make([]float64, 2*nd)
+
+	// The accelerations and the velocities of the stages including the extra
+	// one at the new point.
+	K := make([][]float64, ns+1)
+	V := make([][]float64, ns+1)
+	for k := range K {
+		K[k] = make([]float64, nd)
+		V[k] = make([]float64, nd)
+	}
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	f(x, y[:nd], y[nd:], K[0])
+	stats.Evaluations++
+
+	config := &self.config
+
+	abserr, relerr := config.AbsError, config.RelError
+	threshold := abserr / relerr
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = 0.1 * (xend - x)
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = xs[1] - x
+		if h > hmax {
+			h = hmax
+		}
+
+		scale := 0.0
+		for i := 0; i < 2*nd; i++ {
+			var d float64
+			if i < nd {
+				d = y[nd+i]
+			} else {
+				d = K[0][i-nd]
+			}
+			s := math.Abs(d) / math.Max(math.Abs(y[i]), threshold)
+			if s > scale {
+				scale = s
+			}
+		}
+		scale = scale / (0.8 * math.Pow(relerr, power))
+
+		if h*scale > 1 {
+			h = 1 / scale
+		}
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*2*nd)
+	} else {
+		ys = make([]float64, 0, 4*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	for done := false; ; {
+		var xnew, ε float64
+
+		stats.Steps++
+
+		hmin := 16 * epsilon(x)
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		// Close to the end?
+		if 1.1*h >= xend-x {
+			h = xend - x
+			done = true
+		}
+
+		rejected := false
+
+		for {
+			p, v := y[:nd], y[nd:]
+			pnew, vnew := ynew[:nd], ynew[nd:]
+
+			copy(V[0], v)
+			for k := 1; k < ns; k++ {
+				for i := 0; i < nd; i++ {
+					z[i] = p[i] + c[k]*h*v[i] + h*h*sum(abar[k], K, i)
+					V[k][i] = v[i] + h*sum(a[k], K, i)
+				}
+				f(x+c[k]*h, z, V[k], K[k])
+			}
+
+			for i := 0; i < nd; i++ {
+				pnew[i] = p[i] + h*v[i] + h*h*sum(bbar, K, i)
+				vnew[i] = v[i] + h*sum(b, K, i)
+			}
+			copy(V[ns], vnew)
+
+			xnew = x + h
+
+			f(xnew, pnew, vnew, K[ns])
+
+			stats.Evaluations += uint(ns)
+
+			// Compute the relative error.
+			ε = 0
+			for i := 0; i < 2*nd; i++ {
+				scale := math.Max(math.Max(math.Abs(y[i]), math.Abs(ynew[i])), threshold)
+
+				var δ float64
+				if i < nd {
+					δ = h * h * sum(ebar, K, i)
+				} else {
+					δ = h * sum(e, K, i-nd)
+				}
+
+				if δ = math.Abs(δ) / scale; δ > ε {
+					ε = δ
+				}
+			}
+
+			if ε <= relerr {
+				break
+			}
+
+			stats.Rejections++
+
+			if h <= hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			// Shrink the step size as the current one has been rejected.
+			if rejected {
+				h = 0.5 * h
+			} else if scale := 0.8 * math.Pow(relerr/ε, power); scale > 0.1 {
+				h = scale * h
+			} else {
+				h = 0.1 * h
+			}
+
+			if h < hmin {
+				h = hmin
+			}
+
+			done = false
+			rejected = true
+		}
+
+		if fixed {
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					copy(ys[nc*2*nd:(nc+1)*2*nd], ynew)
+				} else {
+					interpolate(y, K, V, h, (xs[nc]-x)/h, ys[nc*2*nd:(nc+1)*2*nd])
+				}
+
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		if done {
+			break
+		}
+
+		x = xnew
+		copy(K[0], K[ns])
+		copy(y, ynew)
+
+		if rejected {
+			continue
+		}
+
+		// Compute a new step size.
+		if scale := 1.25 * math.Pow(ε/relerr, power); scale > 0.2 {
+			h = h / scale
+		} else {
+			h = 5 * h
+		}
+	}
+
+	return ys, xs, stats, nil
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
+
+// interpolate evaluates the interpolant of the Dormand–Prince method at the
+// relative position s within a step. The positions are interpolated using the
+// velocities of the stages, and the velocities are interpolated using the
+// accelerations of the stages.
+func interpolate(y []float64, K, V [][]float64, h, s float64, ynext []float64) {
+	nd := len(y) / 2
+
+	w := make([]float64, len(d))
+	for k := range d {
+		power := 1.0
+		for _, coefficient := range d[k] {
+			power *= s
+			w[k] += coefficient * power
+		}
+	}
+
+	for i := 0; i < nd; i++ {
+		ynext[i] = y[i] + h*sum(w, V, i)
+		ynext[nd+i] = y[nd+i] + h*sum(w, K, i)
+	}
+}
+
+// sum computes Σ w[k] f[k][i].
+func sum(w []float64, f [][]float64, i int) float64 {
+	s := 0.0
+	for k := range w {
+		if w[k] != 0 {
+			s += w[k] * f[k][i]
+		}
+	}
+	return s
+}
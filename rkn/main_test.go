@@ -0,0 +1,63 @@
+package rkn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeDamped(t *testing.T) {
+	const (
+		ζ = 0.1
+	)
+
+	oscillator := func(_ float64, y, v, a []float64) {
+		a[0] = -y[0] - 2*ζ*v[0]
+	}
+
+	ω := math.Sqrt(1 - ζ*ζ)
+	solution := func(x float64) (float64, float64) {
+		e := math.Exp(-ζ * x)
+		y := e * (math.Cos(ω*x) + ζ/ω*math.Sin(ω*x))
+		v := -e * math.Sin(ω*x) / ω
+		return y, v
+	}
+
+	xs := []float64{0, 0.5, 1, 1.5, 2, 2.5, 3, 3.5, 4}
+
+	integrator, _ := New(&Config{AbsError: 1e-10, RelError: 1e-10})
+
+	ys, _, stats, err := integrator.ComputeWithStats(oscillator, []float64{1, 0}, xs)
+	assert.Equal(err, nil, t)
+	for k, x := range xs {
+		y, v := solution(x)
+		assert.Close(ys[2*k:2*k+2], []float64{y, v}, 1e-8, t)
+	}
+	assert.Equal(stats.Evaluations, 6*stats.Steps+1, t)
+}
+
+func TestComputeKepler(t *testing.T) {
+	const (
+		e = 0.5
+	)
+
+	gravity := func(_ float64, y, _, a []float64) {
+		r := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+		a[0], a[1] = -y[0]/r, -y[1]/r
+	}
+
+	y0 := []float64{1 - e, 0, 0, math.Sqrt((1 + e) / (1 - e))}
+
+	for _, relerr := range []float64{1e-4, 1e-8} {
+		integrator, _ := New(&Config{AbsError: relerr, RelError: relerr})
+
+		ys, xs, stats, err := integrator.ComputeWithStats(gravity, y0, []float64{0, 2 * math.Pi})
+		assert.Equal(err, nil, t)
+
+		n := len(xs)
+		assert.Equal(xs[n-1], 2*math.Pi, t)
+		assert.Close(ys[4*(n-1):], y0, 100*relerr, t)
+		assert.Equal(stats.Evaluations < 2000, true, t)
+	}
+}
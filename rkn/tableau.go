@@ -0,0 +1,63 @@
+package rkn
+
+// The Dormand–Prince method of orders five and four, including the derivative
+// at the new point as the extra stage and the interpolant of order four.
+var (
+	a = [][]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+	}
+	b = []float64{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84}
+	c = []float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1}
+	e = []float64{71.0 / 57600, 0, -71.0 / 16695, 71.0 / 1920, -17253.0 / 339200, 22.0 / 525, -1.0 / 40}
+	d = [][]float64{
+		{1.0, -183.0 / 64, 37.0 / 12, -145.0 / 128},
+		{},
+		{0, 1500.0 / 371, -1000.0 / 159, 1000.0 / 371},
+		{0, -125.0 / 32, 125.0 / 12, -375.0 / 64},
+		{0, 9477.0 / 3392, -729.0 / 106, 25515.0 / 6784},
+		{0, -11.0 / 7, 11.0 / 3, -55.0 / 28},
+		{0, 3.0 / 2, -4.0, 5.0 / 2},
+	}
+)
+
+// The coefficients of the Nyström form, in which the positions of the stages,
+// the new position, and the error of the position are expressed directly in
+// terms of the accelerations of the stages.
+var abar, bbar, ebar = nystrom()
+
+func nystrom() (abar [][]float64, bbar []float64, ebar []float64) {
+	ns := len(c)
+
+	// The velocities of the stages are given by a, and the one of the extra
+	// stage is given by b.
+	row := func(k int) []float64 {
+		if k < ns {
+			return a[k]
+		}
+		return b
+	}
+
+	product := func(w []float64) []float64 {
+		p := make([]float64, ns)
+		for k := range w {
+			for j, v := range row(k) {
+				p[j] += w[k] * v
+			}
+		}
+		return p
+	}
+
+	abar = make([][]float64, ns)
+	for k := range abar {
+		abar[k] = product(a[k])[:k]
+	}
+	bbar = product(b)
+	ebar = product(e)
+
+	return
+}
@@ -0,0 +1,333 @@
+// Package rosenbrock provides an integrator of stiff systems of ordinary
+// differential equations based on the modified Rosenbrock method of orders two
+// and three due to Shampine and Reichelt, which is the method behind ode23s in
+// MATLAB.
+//
+// Each step requires one evaluation of the Jacobian matrix and one LU
+// decomposition but no Newton iterations.
+//
+// https://en.wikipedia.org/wiki/Rosenbrock_methods
+package rosenbrock
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/internal/dense"
+	"github.com/ready-steady/ode/internal/interval"
+	"github.com/ready-steady/ode/internal/jacobian"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	const (
+		d   = 1 / (2 + math.Sqrt2)
+		e32 = 6 + math.Sqrt2
+
+		power = 1.0 / 3
+	)
+
+	stats := &Stats{}
+
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	config := &self.config
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	z := make([]float64, nd)
+	y := make([]float64, nd)
+	ynew := make([]float64, nd)
+	T := make([]float64, nd)
+
+	f := make([]float64, 6*nd)
+	f0 := f[0*nd : 1*nd]
+	f1 := f[1*nd : 2*nd]
+	f2 := f[2*nd : 3*nd]
+	k1 := f[3*nd : 4*nd]
+	k2 := f[4*nd : 5*nd]
+	k3 := f[5*nd : 6*nd]
+
+	J := make([]float64, nd*nd)
+	approximator := jacobian.New(uint(nd), nil)
+	W := make([]float64, nd*nd)
+	var lu ode.LinearSolver = dense.NewLU(uint(nd))
+	if config.LinearSolver != nil {
+		lu = config.LinearSolver(uint(nd), nil)
+	}
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	dydx(x, y, f0)
+	stats.Evaluations++
+
+	relerr := config.RelError
+	threshold := config.AbsError / relerr
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = 0.1 * (xend - x)
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = xs[1] - x
+		if h > hmax {
+			h = hmax
+		}
+
+		scale := 0.0
+		for i := 0; i < nd; i++ {
+			s := math.Abs(f0[i]) / math.Max(math.Abs(y[i]), threshold)
+			if s > scale {
+				scale = s
+			}
+		}
+		scale = scale / (0.8 * math.Pow(relerr, power))
+
+		if h*scale > 1 {
+			h = 1 / scale
+		}
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	for done := false; ; {
+		var xnew, ε float64
+
+		stats.Steps++
+
+		hmin := 16 * epsilon(x)
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		// Close to the end?
+		if 1.1*h >= xend-x {
+			h = xend - x
+			done = true
+		}
+
+		// Evaluate the partial derivatives with respect to y and x.
+		if config.Jacobian != nil {
+			config.Jacobian(x, y, J)
+		} else {
+			stats.Evaluations += approximator.Compute(dydx, x, y, f0, J)
+		}
+		stats.Jacobians++
+
+		δ := math.Sqrt(epsilon(1)) * math.Max(math.Abs(x), math.Abs(x+h))
+		δ = (x + δ) - x
+		dydx(x+δ, y, f1)
+		stats.Evaluations++
+		for i := 0; i < nd; i++ {
+			T[i] = (f1[i] - f0[i]) / δ
+		}
+
+		rejected := false
+
+		for {
+			for i := range W {
+				W[i] = -h * d * J[i]
+			}
+			for i := 0; i < nd; i++ {
+				W[i*nd+i] += 1
+			}
+			stats.Decompositions++
+			if err := lu.Factorize(W); err != nil {
+				return nil, nil, stats, err
+			}
+
+			// Stage 1
+			for i := 0; i < nd; i++ {
+				k1[i] = f0[i] + h*d*T[i]
+			}
+			lu.Solve(k1)
+
+			// Stage 2
+			for i := 0; i < nd; i++ {
+				z[i] = y[i] + 0.5*h*k1[i]
+			}
+			dydx(x+0.5*h, z, f1)
+			for i := 0; i < nd; i++ {
+				k2[i] = f1[i] - k1[i]
+			}
+			lu.Solve(k2)
+			for i := 0; i < nd; i++ {
+				k2[i] += k1[i]
+			}
+
+			for i := 0; i < nd; i++ {
+				ynew[i] = y[i] + h*k2[i]
+			}
+
+			xnew = x + h
+
+			// Stage 3
+			dydx(xnew, ynew, f2)
+			for i := 0; i < nd; i++ {
+				k3[i] = f2[i] - e32*(k2[i]-f1[i]) - 2*(k1[i]-f0[i]) + h*d*T[i]
+			}
+			lu.Solve(k3)
+
+			stats.Evaluations += 2
+
+			// Compute the relative error.
+			ε = 0
+			for i := 0; i < nd; i++ {
+				scale := math.Max(math.Max(math.Abs(y[i]), math.Abs(ynew[i])), threshold)
+				e := math.Abs(h/6*(k1[i]-2*k2[i]+k3[i])) / scale
+				if e > ε {
+					ε = e
+				}
+			}
+
+			if ε <= relerr {
+				break
+			}
+
+			stats.Rejections++
+
+			if h <= hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			// Shrink the step size as the current one has been rejected.
+			if rejected {
+				h = 0.5 * h
+			} else {
+				h = h * math.Max(0.5, 0.8*math.Pow(relerr/ε, power))
+			}
+
+			if h < hmin {
+				h = hmin
+			}
+
+			done = false
+			rejected = true
+		}
+
+		if fixed {
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					copy(ys[nc*nd:(nc+1)*nd], ynew)
+				} else {
+					interpolate(x, y, k1, k2, h, xs[nc], ys[nc*nd:(nc+1)*nd])
+				}
+
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		if done {
+			break
+		}
+
+		x = xnew
+		copy(f0, f2)
+		copy(y, ynew)
+
+		if rejected {
+			continue
+		}
+
+		// Compute a new step size.
+		if scale := 1.25 * math.Pow(ε/relerr, power); scale > 0.2 {
+			h = h / scale
+		} else {
+			h = 5 * h
+		}
+	}
+
+	return ys, xs, stats, nil
+}
+
+func interpolate(x float64, y, k1, k2 []float64, h, xnext float64, ynext []float64) {
+	const (
+		d = 1 / (2 + math.Sqrt2)
+	)
+
+	s := (xnext - x) / h
+
+	c1 := s * (1 - s) / (1 - 2*d)
+	c2 := s * (s - 2*d) / (1 - 2*d)
+
+	for i := range ynext {
+		ynext[i] = y[i] + h*(c1*k1[i]+c2*k2[i])
+	}
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
@@ -0,0 +1,75 @@
+package rosenbrock
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeDecay(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{0, 0.5, 1, 1.5, 2}
+
+	integrator, _ := New(&Config{AbsError: 1e-8, RelError: 1e-6})
+
+	ys, _, _ := integrator.Compute(dydx, []float64{0.5}, xs)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 5e-5, t)
+	}
+}
+
+// http://mathworks.com/company/newsletters/articles/stiff-differential-equations.html
+func TestComputeFlame(t *testing.T) {
+	const δ = 0.0001
+
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[0]*y[0] - y[0]*y[0]*y[0]
+	}
+
+	config := DefaultConfig()
+	config.RelError = 1e-4
+
+	integrator, _ := New(config)
+
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{δ}, []float64{0, 2 / δ})
+	assert.Equal(err, nil, t)
+
+	n := len(xs)
+	assert.Equal(xs[n-1], 2/δ, t)
+	assert.Close(ys[n-1], 1.0, 1e-4, t)
+	assert.Equal(stats.Evaluations < 1000, true, t)
+}
+
+func TestComputeRobertson(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -0.04*y[0] + 1e4*y[1]*y[2]
+		f[1] = 0.04*y[0] - 1e4*y[1]*y[2] - 3e7*y[1]*y[1]
+		f[2] = 3e7 * y[1] * y[1]
+	}
+
+	jacobian := func(_ float64, y, J []float64) {
+		J[0], J[1], J[2] = -0.04, 1e4*y[2], 1e4*y[1]
+		J[3], J[4], J[5] = 0.04, -1e4*y[2]-6e7*y[1], -1e4*y[1]
+		J[6], J[7], J[8] = 0, 6e7*y[1], 0
+	}
+
+	expected := []float64{7.158270687193e-01, 9.185534764529e-06, 2.841637457460e-01}
+
+	for _, J := range []func(float64, []float64, []float64){nil, jacobian} {
+		integrator, _ := New(&Config{AbsError: 1e-10, RelError: 1e-5, Jacobian: J})
+
+		ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0, 0}, []float64{0, 40})
+		assert.Equal(err, nil, t)
+
+		n := len(xs)
+		y := ys[3*(n-1):]
+		for i := range y {
+			assert.Close(y[i]/expected[i], 1.0, 1e-3, t)
+		}
+		assert.Equal(stats.Steps < 1000, true, t)
+	}
+}
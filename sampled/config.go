@@ -0,0 +1,39 @@
+package sampled
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode/dopri"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The configuration of the underlying integrator, which integrates the
+	// plant between consecutive sampling instants.
+	Integrator dopri.Config `json:"integrator"`
+	// The sampling period.
+	Period float64 `json:"period"`
+}
+
+// DefaultConfig returns the default configuration of an integrator with the
+// given sampling period.
+func DefaultConfig(period float64) *Config {
+	return &Config{
+		Integrator: *dopri.DefaultConfig(),
+		Period:     period,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if !(c.Period > 0) {
+		return errors.New("the sampling period should be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+// Package sampled provides an integrator of sampled-data systems, that is,
+// continuous plants
+//
+//	y′(x) = f(x, y(x), u)
+//
+// whose input u is computed by a controller u = k(x, y(x)) at equally spaced
+// sampling instants xₖ = x₀ + kT and held constant until the next instant.
+//
+// The plant is integrated by the Dormand–Prince method from one instant to the
+// next, so that the integrator lands exactly on every instant and no step
+// straddles a jump of the input. The step size is carried over from one
+// interval to the next.
+//
+// https://en.wikipedia.org/wiki/Zero-order_hold
+package sampled
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/dopri"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates a sampled-data system.
+//
+// The input function plant(x, y, u, f) evaluates f for a given x, y(x), and u
+// and stores the result in its last argument. The function controller(x, y,
+// u) computes the input of the plant, which has nu components, at a sampling
+// instant x given the solution y at the instant and stores it in its last
+// argument, which holds the previous input. The initial condition is y0, and
+// the first sampling instant is x0 = xs[0]. The points xs are treated as by
+// Integrator.Compute in the parent package except that xend should be greater
+// than x0; if they do not specify any intermediate points, the solution is
+// returned at the points that the integrator internally traverses, which
+// include the sampling instants.
+func (self *Integrator) Compute(plant func(float64, []float64, []float64, []float64),
+	controller func(float64, []float64, []float64), nu uint, y0,
+	xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(plant, controller, nu, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process. The statistics are accumulated over all the sampling
+// intervals.
+func (self *Integrator) ComputeWithStats(plant func(float64, []float64, []float64, []float64),
+	controller func(float64, []float64, []float64), nu uint, y0,
+	xs []float64) ([]float64, []float64, *dopri.Stats, error) {
+
+	config := &self.config
+
+	nd, nx := len(y0), len(xs)
+	if nx < 2 {
+		return nil, nil, nil, errors.New("the interval should have two endpoints")
+	}
+
+	x0, xend := xs[0], xs[nx-1]
+	if !(xend > x0) {
+		return nil, nil, nil, errors.New("the interval should be traversed forward")
+	}
+
+	integrator, err := dopri.New(&config.Integrator)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	u := make([]float64, nu)
+	dydx := func(x float64, y, f []float64) {
+		plant(x, y, u, f)
+	}
+
+	fixed := nx > 2
+
+	ys, zs := append([]float64(nil), y0...), []float64{x0}
+	y := append([]float64(nil), y0...)
+
+	stats := &dopri.Stats{}
+
+	var state *dopri.State
+
+	for k, nc := 0, 1; ; k++ {
+		a, b := x0+float64(k)*config.Period, x0+float64(k+1)*config.Period
+		if b > xend-1e-10*config.Period {
+			b = xend
+		}
+
+		controller(a, y, u)
+
+		grid := []float64{a}
+		for ; fixed && nc < nx && xs[nc] < b; nc++ {
+			grid = append(grid, xs[nc])
+		}
+		grid = append(grid, b)
+
+		requested := !fixed || nc < nx && xs[nc] == b
+		if fixed && requested {
+			nc++
+		}
+
+		var local, points []float64
+		var partial *dopri.Stats
+		if state == nil {
+			local, points, state, partial, err = integrator.ComputeWithState(dydx, y, grid)
+		} else {
+			// The derivative at the instant is stale since the input has
+			// changed.
+			state.F = nil
+			local, points, state, partial, err = integrator.Continue(dydx, state, grid)
+		}
+		accumulate(stats, partial)
+		if err != nil {
+			return ys, zs, stats, err
+		}
+
+		if fixed && len(grid) == 2 {
+			// Only the endpoints are of interest.
+			local = append(local[:nd:nd], local[len(local)-nd:]...)
+			points = []float64{a, points[len(points)-1]}
+		}
+
+		// Drop the instant at the beginning, which has already been reported,
+		// and the one at the end unless it is requested.
+		local, points = local[nd:], points[1:]
+		if !requested {
+			local, points = local[:len(local)-nd], points[:len(points)-1]
+		}
+		ys, zs = append(ys, local...), append(zs, points...)
+
+		copy(y, state.Y)
+
+		if b == xend {
+			return ys, zs, stats, nil
+		}
+	}
+}
+
+// accumulate adds the statistics of an interval to the total ones.
+func accumulate(total, stats *dopri.Stats) {
+	if stats == nil {
+		return
+	}
+	total.Evaluations += stats.Evaluations
+	total.Rejections += stats.Rejections
+	total.Steps += stats.Steps
+	total.Interpolations += stats.Interpolations
+	if total.MinStep == 0 || stats.MinStep > 0 && stats.MinStep < total.MinStep {
+		total.MinStep = stats.MinStep
+	}
+	total.MaxStep = math.Max(total.MaxStep, stats.MaxStep)
+	if stats.LastStep > 0 {
+		total.LastStep = stats.LastStep
+	}
+}
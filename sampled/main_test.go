@@ -0,0 +1,69 @@
+package sampled
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestCompute(t *testing.T) {
+	const (
+		T    = 0.3
+		gain = 2.0
+	)
+
+	// y′ = -y + u with u = -gain y held over each sampling period, so that
+	// y(xₖ₊₁) = (e^(-T) - gain (1 - e^(-T))) y(xₖ).
+	plant := func(_ float64, y, u, f []float64) {
+		f[0] = -y[0] + u[0]
+	}
+
+	var instants []float64
+	controller := func(x float64, y, u []float64) {
+		instants = append(instants, x)
+		u[0] = -gain * y[0]
+	}
+
+	config := DefaultConfig(T)
+	config.Integrator.AbsError = 1e-12
+	config.Integrator.RelError = 1e-10
+
+	integrator, _ := New(config)
+
+	exact := func(x float64) float64 {
+		k := math.Floor(x/T + 1e-9)
+		ρ := math.Exp(-T) - gain*(1-math.Exp(-T))
+		y := math.Pow(ρ, k)
+		s := x - k*T
+		return math.Exp(-s)*y - gain*(1-math.Exp(-s))*y
+	}
+
+	xs := []float64{0, 0.1, 0.3, 0.45, 0.9, 1.3, 2}
+	ys, zs, err := integrator.Compute(plant, controller, 1, []float64{1}, xs)
+	assert.Equal(err, nil, t)
+	assert.Equal(zs, xs, t)
+	for i, x := range xs {
+		assert.Close(ys[i], exact(x), 1e-9, t)
+	}
+
+	assert.Equal(len(instants), 7, t)
+	for k, x := range instants {
+		assert.Equal(x, float64(k)*T, t)
+	}
+
+	instants = nil
+	ys, zs, err = integrator.Compute(plant, controller, 1, []float64{1}, []float64{0, 2})
+	assert.Equal(err, nil, t)
+	assert.Equal(zs[len(zs)-1], 2.0, t)
+	for k := range instants {
+		found := false
+		for _, x := range zs {
+			found = found || x == float64(k)*T
+		}
+		assert.Equal(found, true, t)
+	}
+	for i, x := range zs {
+		assert.Close(ys[i], exact(x), 1e-9, t)
+	}
+}
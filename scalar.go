@@ -0,0 +1,22 @@
+package ode
+
+// Scalar turns the right-hand side of a single differential equation
+// dy/dx = f(x, y) into the form accepted by integrators, which is that of a
+// system of dimension one.
+func Scalar(dydx func(float64, float64) float64) func(float64, []float64, []float64) {
+	return func(x float64, y, f []float64) {
+		f[0] = dydx(x, y[0])
+	}
+}
+
+// ComputeScalar integrates a single differential equation dy/dx = f(x, y)
+// using an integrator. See Integrator.Compute.
+//
+// The input function dydx(x, y) returns f(x, y) for a given x and y. The
+// initial condition is y0. The solution is returned as one value per point,
+// that is, ys[i] is the solution at xs[i].
+func ComputeScalar(integrator Integrator, dydx func(float64, float64) float64,
+	y0 float64, xs []float64) ([]float64, []float64, error) {
+
+	return integrator.Compute(Scalar(dydx), []float64{y0}, xs)
+}
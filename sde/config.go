@@ -0,0 +1,58 @@
+package sde
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The step of integration.
+	Step float64 `json:"step"`
+	// The number of independent Wiener processes. It is ignored if Noise is
+	// given.
+	Dimensions uint `json:"dimensions"`
+	// The source of random numbers. If it is nil, a source seeded with zero is
+	// used. The source is shared by successive invocations of Compute, which
+	// hence produce different paths. It is ignored if Noise is given.
+	Source rand.Source `json:"-"`
+	// The generator of the increments of the Wiener processes. If it is nil,
+	// independent increments are drawn using Source; see Wiener.
+	Noise Noise `json:"-"`
+
+	// The directional derivative of the diffusion, which is used by the
+	// methods that need it, such as the Milstein method. The function
+	// evaluates the derivative of g(x, y) with respect to y in the direction v
+	// and stores the result, which is an nd-by-nw matrix, in its last
+	// argument. If it is nil, the derivative is approximated using finite
+	// differences.
+	Derivative func(x float64, y, v, dg []float64) `json:"-"`
+}
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return &Config{
+		Step:       1e-3,
+		Dimensions: 1,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Noise == nil && c.Dimensions == 0 {
+		return errors.New("the number of Wiener processes should be positive")
+	}
+	if c.Noise != nil && c.Noise.Dimensions() == 0 {
+		return errors.New("the noise should drive at least one Wiener process")
+	}
+
+	return nil
+}
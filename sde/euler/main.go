@@ -0,0 +1,48 @@
+// Package euler provides an integrator of systems of stochastic differential
+// equations based on the Euler–Maruyama method, which has strong order 0.5
+// and weak order 1. See the sde package for the form of the systems.
+//
+// https://en.wikipedia.org/wiki/Euler–Maruyama_method
+package euler
+
+import (
+	"github.com/ready-steady/ode/sde"
+)
+
+// Integrator is an integrator.
+type Integrator = sde.Integrator
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return sde.New(method, config)
+}
+
+func method(nd, nw uint, _ *Config) sde.Stepper {
+	return &stepper{
+		nw: int(nw),
+		f:  make([]float64, nd),
+		g:  make([]float64, nd*nw),
+	}
+}
+
+type stepper struct {
+	nw int
+	f  []float64
+	g  []float64
+}
+
+func (self *stepper) Step(drift, diffusion func(float64, []float64, []float64), x, h float64,
+	y, dW, ynew []float64) {
+
+	nw, f, g := self.nw, self.f, self.g
+
+	drift(x, y, f)
+	diffusion(x, y, g)
+	for i := range y {
+		sum := 0.0
+		for j := range dW {
+			sum += g[i*nw+j] * dW[j]
+		}
+		ynew[i] = y[i] + h*f[i] + sum
+	}
+}
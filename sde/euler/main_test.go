@@ -0,0 +1,86 @@
+package euler
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeDeterministic(t *testing.T) {
+	drift := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+	diffusion := func(_ float64, _, g []float64) {
+		g[0] = 0
+	}
+
+	integrator, _ := New(&Config{Step: 1e-4, Dimensions: 1})
+	ys, xs, stats, _ := integrator.ComputeWithStats(drift, diffusion, []float64{0.5},
+		[]float64{0, 2})
+
+	assert.Equal(len(xs), 20001, t)
+	assert.Equal(xs[len(xs)-1], 2.0, t)
+	assert.Equal(stats.Steps, uint(20000), t)
+	assert.Close(ys[len(ys)-1], 0.5*(math.Cos(2)+math.Sin(2)), 1e-4, t)
+}
+
+func TestComputeWiener(t *testing.T) {
+	// Y₁ = W₁ + 2 W₂ and Y₂ = -W₂
+	drift := func(_ float64, _, f []float64) {
+		f[0], f[1] = 0, 0
+	}
+	diffusion := func(_ float64, _, g []float64) {
+		g[0], g[1] = 1, 2
+		g[2], g[3] = 0, -1
+	}
+
+	integrator, _ := New(&Config{Step: 0.1, Dimensions: 2, Source: rand.NewSource(42)})
+	ys, xs, _ := integrator.Compute(drift, diffusion, []float64{1, 2}, []float64{0, 0.5, 1})
+
+	assert.Equal(xs, []float64{0, 0.5, 1}, t)
+
+	generator := rand.New(rand.NewSource(42))
+	W := []float64{0, 0}
+	expected := []float64{1, 2}
+	for k := 0; k < 10; k++ {
+		for j := range W {
+			W[j] += math.Sqrt(0.1) * generator.NormFloat64()
+		}
+		if k == 4 || k == 9 {
+			expected = append(expected, 1+W[0]+2*W[1], 2-W[1])
+		}
+	}
+
+	assert.Close(ys, expected, 1e-12, t)
+}
+
+func TestComputeMoments(t *testing.T) {
+	const (
+		μ    = 0.5
+		σ    = 0.3
+		runs = 2000
+	)
+
+	// Geometric Brownian motion
+	drift := func(_ float64, y, f []float64) {
+		f[0] = μ * y[0]
+	}
+	diffusion := func(_ float64, y, g []float64) {
+		g[0] = σ * y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 1e-2, Dimensions: 1, Source: rand.NewSource(1)})
+
+	mean, square := 0.0, 0.0
+	for k := 0; k < runs; k++ {
+		ys, _, _ := integrator.Compute(drift, diffusion, []float64{1}, []float64{0, 1})
+		y := ys[len(ys)-1]
+		mean += y / runs
+		square += y * y / runs
+	}
+
+	assert.Close(mean, math.Exp(μ), 0.05, t)
+	assert.Close(square-mean*mean, math.Exp(2*μ)*(math.Exp(σ*σ)-1), 0.05, t)
+}
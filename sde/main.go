@@ -0,0 +1,184 @@
+// Package sde provides integrators of systems of stochastic differential
+// equations based on one-step methods with a fixed step.
+//
+// The systems are of the form
+//
+//	dY = f(x, Y) dx + g(x, Y) dW
+//
+// where f is the drift, g is the diffusion, and W is a vector of Wiener
+// processes. If nd is the dimension of the system and nw is the number of the
+// Wiener processes, g is an nd-by-nw matrix stored in row-major order; that is,
+// g[i*nw+j] is the intensity with which the jth process drives the ith
+// component of Y.
+//
+// The methods themselves are provided by the subpackages, and this package
+// provides the machinery shared by them, including the generators of the
+// increments of the Wiener processes.
+//
+// https://en.wikipedia.org/wiki/Stochastic_differential_equation
+package sde
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// Method is a one-step method. The function creates a stepper for systems of
+// nd equations driven by nw Wiener processes given the configuration of an
+// integrator.
+type Method func(nd, nw uint, config *Config) Stepper
+
+// Stepper takes steps of a method.
+type Stepper interface {
+	// Step computes the solution ynew at x+h given the solution y at x and
+	// the increments dW of the Wiener processes over [x, x+h].
+	Step(drift, diffusion func(float64, []float64, []float64), x, h float64,
+		y, dW, ynew []float64)
+}
+
+// Integrator is an integrator. Since the generators of random numbers are not
+// safe for concurrent use, neither is the integrator.
+type Integrator struct {
+	method Method
+	config Config
+	noise  Noise
+}
+
+// New creates a new integrator based on a method.
+func New(method Method, config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	noise := config.Noise
+	if noise == nil {
+		source := config.Source
+		if source == nil {
+			source = rand.NewSource(0)
+		}
+		noise = NewWiener(config.Dimensions, source)
+	}
+	return &Integrator{method: method, config: *config, noise: noise}, nil
+}
+
+// Compute integrates the system of stochastic differential equations
+// dY = f(x, Y) dx + g(x, Y) dW along one path.
+//
+// The drift function drift(x, y, f) evaluates f(x, y) and stores the result in
+// its last argument, and the diffusion function diffusion(x, y, g) evaluates
+// g(x, y) and stores the result in its last argument. The initial condition is
+// y0, and the points xs, which should be strictly increasing, are treated as
+// follows.
+//
+// If xs does not specify any intermediate points, the solution is returned at
+// a number of equidistant points starting from and including x0 = xs[0] and
+// ending at xend, the last element of xs, as done by the fixed-step
+// integrators of the rk subpackage. The points of the grid are returned as the
+// second result. Otherwise, the solution is returned exactly at the points of
+// xs; each interval between two consecutive points is then divided into the
+// number of equal steps that brings the step the closest to the one of the
+// configuration.
+func (self *Integrator) Compute(drift, diffusion func(float64, []float64, []float64),
+	y0, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(drift, diffusion, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(drift, diffusion func(float64, []float64, []float64),
+	y0, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	if err := validate(y0, xs); err != nil {
+		return nil, nil, nil, err
+	}
+
+	nd, nw, nx := len(y0), self.noise.Dimensions(), len(xs)
+
+	h := self.config.Step
+
+	if nx == 2 {
+		x0, xend := xs[0], xs[1]
+		np := int((xend-x0)/h+0.5) + 1
+		if np == 1 {
+			np = 2
+		}
+		xs = make([]float64, np)
+		for k := range xs {
+			xs[k] = x0 + float64(k)*h
+		}
+		xs[np-1] = xend
+		nx = np
+	}
+
+	stats := &Stats{End: xs[0]}
+
+	countedDrift := func(x float64, y, f []float64) {
+		drift(x, y, f)
+		stats.Evaluations++
+	}
+	countedDiffusion := func(x float64, y, g []float64) {
+		diffusion(x, y, g)
+		stats.Diffusions++
+	}
+
+	stepper := self.method(uint(nd), nw, &self.config)
+
+	ys := make([]float64, nx*nd)
+	copy(ys, y0)
+
+	dW := make([]float64, nw)
+
+	y := append([]float64(nil), y0...)
+	ynew := make([]float64, nd)
+
+	for k := 1; k < nx; k++ {
+		x := xs[k-1]
+
+		ns := int((xs[k]-x)/h + 0.5)
+		if ns == 0 {
+			ns = 1
+		}
+		δ := (xs[k] - x) / float64(ns)
+
+		for s := 0; s < ns; s++ {
+			xnew := xs[k-1] + float64(s+1)*δ
+			if s == ns-1 {
+				xnew = xs[k]
+			}
+
+			self.noise.Increment(x, xnew-x, dW)
+			stepper.Step(countedDrift, countedDiffusion, x, xnew-x, y, dW, ynew)
+			y, ynew = ynew, y
+
+			stats.Steps++
+
+			x = xnew
+		}
+
+		copy(ys[k*nd:(k+1)*nd], y)
+
+		stats.End = xs[k]
+	}
+
+	return ys, xs, stats, nil
+}
+
+// validate checks that the initial condition is not empty and that the points
+// of the interval are strictly increasing.
+func validate(y0, xs []float64) error {
+	if len(y0) == 0 {
+		return errors.New("the initial condition should not be empty")
+	}
+	nx := len(xs)
+	if nx < 2 {
+		return errors.New("the interval should have two endpoints")
+	}
+	for i := 1; i < nx; i++ {
+		if !(xs[i] > xs[i-1]) {
+			return errors.New("the points of the interval should be strictly increasing")
+		}
+	}
+	return nil
+}
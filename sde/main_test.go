@@ -0,0 +1,102 @@
+package sde
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestNewCorrelated(t *testing.T) {
+	_, err := NewCorrelated([]float64{1, 0.5, 0.5}, rand.NewSource(0))
+	assert.Equal(err != nil, true, t)
+
+	_, err = NewCorrelated([]float64{1, 2, 2, 1}, rand.NewSource(0))
+	assert.Equal(err != nil, true, t)
+
+	_, err = NewCorrelated([]float64{1, 0.5, 0.4, 1}, rand.NewSource(0))
+	assert.Equal(err != nil, true, t)
+}
+
+func TestCorrelatedIncrement(t *testing.T) {
+	const (
+		h       = 0.25
+		samples = 100000
+	)
+
+	correlation := []float64{
+		1.0, 0.6, -0.3,
+		0.6, 1.0, 0.2,
+		-0.3, 0.2, 1.0,
+	}
+
+	noise, _ := NewCorrelated(correlation, rand.NewSource(0))
+	assert.Equal(noise.Dimensions(), uint(3), t)
+
+	dW := make([]float64, 3)
+	covariance := make([]float64, 9)
+	for k := 0; k < samples; k++ {
+		noise.Increment(0, h, dW)
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				covariance[i*3+j] += dW[i] * dW[j] / (h * samples)
+			}
+		}
+	}
+
+	assert.Close(covariance, correlation, 0.02, t)
+}
+
+func TestPath(t *testing.T) {
+	path := NewPath(NewWiener(2, rand.NewSource(0)))
+
+	// Sample on a coarse grid.
+	coarse := make([]float64, 2*5)
+	dW := make([]float64, 2)
+	for k := 0; k < 4; k++ {
+		path.Increment(float64(k)*0.25, 0.25, dW)
+		coarse[2*(k+1)] = coarse[2*k] + dW[0]
+		coarse[2*(k+1)+1] = coarse[2*k+1] + dW[1]
+	}
+
+	// Repeat on a refined grid.
+	fine := make([]float64, 2*17)
+	for k := 0; k < 16; k++ {
+		path.Increment(float64(k)*0.0625, 0.0625, dW)
+		fine[2*(k+1)] = fine[2*k] + dW[0]
+		fine[2*(k+1)+1] = fine[2*k+1] + dW[1]
+	}
+	for k := 0; k < 5; k++ {
+		assert.Close(fine[8*k:8*k+2], coarse[2*k:2*k+2], 1e-14, t)
+	}
+
+	w := make([]float64, 2)
+	path.Value(1, w)
+	assert.Equal(w, coarse[8:], t)
+	path.Value(0, w)
+	assert.Equal(w, []float64{0, 0}, t)
+}
+
+func TestPathBridge(t *testing.T) {
+	const (
+		samples = 20000
+	)
+
+	// The variance of a Brownian bridge from 0 to 1 at 0.3 is 0.3 × 0.7.
+	mean, square := 0.0, 0.0
+	w := []float64{0}
+	for k := 0; k < samples; k++ {
+		path := NewPath(NewWiener(1, rand.NewSource(int64(k))))
+		path.Value(0, w)
+		path.Value(1, w)
+		end := w[0]
+		path.Value(0.3, w)
+		mean += (w[0] - 0.3*end) / samples
+		square += (w[0] - 0.3*end) * (w[0] - 0.3*end) / samples
+	}
+
+	assert.Close(mean, 0.0, 0.02, t)
+	assert.Close(square-mean*mean, 0.3*0.7, 0.02, t)
+	assert.Equal(math.IsNaN(square), false, t)
+}
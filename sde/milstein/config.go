@@ -0,0 +1,13 @@
+package milstein
+
+import (
+	"github.com/ready-steady/ode/sde"
+)
+
+// Config is the configuration of an integrator.
+type Config = sde.Config
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return sde.DefaultConfig()
+}
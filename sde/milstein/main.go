@@ -0,0 +1,125 @@
+// Package milstein provides an integrator of systems of stochastic
+// differential equations based on the Milstein method. See the sde package for
+// the form of the systems.
+//
+// The method augments the Euler–Maruyama method with the term
+//
+//	½ Σⱼ Σₖ (∂gₖ/∂y gⱼ) (ΔWⱼ ΔWₖ - h δⱼₖ)
+//
+// where gⱼ is the jth column of the diffusion. The term involves the
+// directional derivatives of the diffusion, which are either given by
+// Config.Derivative or approximated using finite differences. The iterated
+// integrals of the Wiener processes are expressed through their increments,
+// which is exact for systems with a single Wiener process, diagonal noise, or,
+// more generally, commutative noise. For such systems, the method has strong
+// order 1; otherwise, the order reduces to 0.5.
+//
+// https://en.wikipedia.org/wiki/Milstein_method
+package milstein
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode/sde"
+)
+
+const (
+	epsilon = 2.220446049250313e-16
+)
+
+// Integrator is an integrator.
+type Integrator = sde.Integrator
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return sde.New(method, config)
+}
+
+func method(nd, nw uint, config *Config) sde.Stepper {
+	return &stepper{
+		nw:         int(nw),
+		derivative: config.Derivative,
+		f:          make([]float64, nd),
+		g:          make([]float64, nd*nw),
+		v:          make([]float64, nd),
+		z:          make([]float64, nd),
+		dg:         make([]float64, nd*nw),
+	}
+}
+
+type stepper struct {
+	nw         int
+	derivative func(float64, []float64, []float64, []float64)
+	f          []float64
+	g          []float64
+	v          []float64
+	z          []float64
+	dg         []float64
+}
+
+func (self *stepper) Step(drift, diffusion func(float64, []float64, []float64), x, h float64,
+	y, dW, ynew []float64) {
+
+	nw, f, g, v, dg := self.nw, self.f, self.g, self.v, self.dg
+
+	drift(x, y, f)
+	diffusion(x, y, g)
+	for i := range y {
+		sum := 0.0
+		for j := range dW {
+			sum += g[i*nw+j] * dW[j]
+		}
+		ynew[i] = y[i] + h*f[i] + sum
+	}
+
+	for j := range dW {
+		for i := range v {
+			v[i] = g[i*nw+j]
+		}
+		self.differentiate(diffusion, x, y, g, v, dg)
+		for i := range y {
+			sum := 0.0
+			for k := range dW {
+				I := dW[j] * dW[k]
+				if j == k {
+					I -= h
+				}
+				sum += dg[i*nw+k] * I
+			}
+			ynew[i] += 0.5 * sum
+		}
+	}
+}
+
+// differentiate computes the derivative of the diffusion in the direction v
+// given the diffusion g at y.
+func (self *stepper) differentiate(diffusion func(float64, []float64, []float64), x float64,
+	y, g, v, dg []float64) {
+
+	if self.derivative != nil {
+		self.derivative(x, y, v, dg)
+		return
+	}
+
+	scale, size := 0.0, 0.0
+	for i := range y {
+		scale = math.Max(scale, math.Abs(y[i]))
+		size = math.Max(size, math.Abs(v[i]))
+	}
+	if size == 0 {
+		for i := range dg {
+			dg[i] = 0
+		}
+		return
+	}
+
+	σ := math.Sqrt(epsilon) * (1 + scale) / size
+	z := self.z
+	for i := range y {
+		z[i] = y[i] + σ*v[i]
+	}
+	diffusion(x, z, dg)
+	for i := range dg {
+		dg[i] = (dg[i] - g[i]) / σ
+	}
+}
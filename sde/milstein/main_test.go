@@ -0,0 +1,78 @@
+package milstein
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/sde"
+)
+
+func TestComputeStrongOrder(t *testing.T) {
+	const (
+		μ    = 1.5
+		σ    = 1.0
+		runs = 100
+	)
+
+	// Geometric Brownian motion
+	drift := func(_ float64, y, f []float64) {
+		f[0] = μ * y[0]
+	}
+	diffusion := func(_ float64, y, g []float64) {
+		g[0] = σ * y[0]
+	}
+
+	steps := []float64{1.0 / 16, 1.0 / 32, 1.0 / 64, 1.0 / 128}
+	errors := make([]float64, len(steps))
+
+	w := []float64{0}
+	for k := 0; k < runs; k++ {
+		path := sde.NewPath(sde.NewWiener(1, rand.NewSource(int64(k))))
+		for l, h := range steps {
+			integrator, _ := New(&Config{Step: h, Noise: path})
+			ys, _, _ := integrator.Compute(drift, diffusion, []float64{1}, []float64{0, 1})
+			path.Value(1, w)
+			exact := math.Exp((μ-σ*σ/2)*1 + σ*w[0])
+			errors[l] += math.Abs(ys[len(ys)-1]-exact) / runs
+		}
+	}
+
+	order := math.Log2(errors[0]/errors[len(errors)-1]) / float64(len(errors)-1)
+	assert.Close(order, 1.0, 0.2, t)
+}
+
+func TestComputeDerivative(t *testing.T) {
+	// Diagonal noise driven by two processes
+	drift := func(_ float64, y, f []float64) {
+		f[0], f[1] = -y[0], y[0]-y[1]
+	}
+	diffusion := func(_ float64, y, g []float64) {
+		g[0], g[1] = 0.3*math.Sin(y[0]), 0
+		g[2], g[3] = 0, 0.2*y[1]*y[1]
+	}
+	derivative := func(_ float64, y, v, dg []float64) {
+		dg[0], dg[1] = 0.3*math.Cos(y[0])*v[0], 0
+		dg[2], dg[3] = 0, 0.4*y[1]*v[1]
+	}
+
+	compute := func(derivative func(float64, []float64, []float64, []float64)) ([]float64, *sde.Stats) {
+		integrator, _ := New(&Config{
+			Step:       1e-2,
+			Dimensions: 2,
+			Source:     rand.NewSource(1),
+			Derivative: derivative,
+		})
+		ys, _, stats, _ := integrator.ComputeWithStats(drift, diffusion, []float64{1, 1},
+			[]float64{0, 0.5, 1})
+		return ys, stats
+	}
+
+	ys1, stats1 := compute(derivative)
+	ys2, stats2 := compute(nil)
+
+	assert.Close(ys1, ys2, 1e-6, t)
+	assert.Equal(stats1.Diffusions, uint(100), t)
+	assert.Equal(stats2.Diffusions, uint(300), t)
+}
@@ -0,0 +1,120 @@
+package sde
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// Noise is a generator of the increments of a number of Wiener processes.
+type Noise interface {
+	// Dimensions returns the number of the processes.
+	Dimensions() uint
+	// Increment draws the increments of the processes over [x, x+h] and
+	// stores them in dW.
+	Increment(x, h float64, dW []float64)
+}
+
+// Wiener is a generator of the increments of independent Wiener processes.
+type Wiener struct {
+	nw        uint
+	generator *rand.Rand
+}
+
+// NewWiener creates a generator of the increments of nw independent Wiener
+// processes that draws random numbers from a source.
+func NewWiener(nw uint, source rand.Source) *Wiener {
+	return &Wiener{nw: nw, generator: rand.New(source)}
+}
+
+// Dimensions returns the number of the processes.
+func (self *Wiener) Dimensions() uint {
+	return self.nw
+}
+
+// Increment draws the increments of the processes over [x, x+h].
+func (self *Wiener) Increment(_, h float64, dW []float64) {
+	σ := math.Sqrt(h)
+	for j := range dW {
+		dW[j] = σ * self.generator.NormFloat64()
+	}
+}
+
+// Correlated is a generator of the increments of correlated Wiener processes.
+type Correlated struct {
+	nw        uint
+	L         []float64
+	z         []float64
+	generator *rand.Rand
+}
+
+// NewCorrelated creates a generator of the increments of Wiener processes with
+// a correlation matrix, which is symmetric, positive definite, and stored in
+// row-major order. The number of the processes is the order of the matrix.
+// The independent increments drawn from a source are correlated using the
+// Cholesky decomposition of the matrix.
+func NewCorrelated(correlation []float64, source rand.Source) (*Correlated, error) {
+	nw := uint(math.Sqrt(float64(len(correlation))) + 0.5)
+	if nw == 0 || nw*nw != uint(len(correlation)) {
+		return nil, errors.New("the correlation matrix should be square")
+	}
+
+	L, err := cholesky(correlation, nw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Correlated{
+		nw:        nw,
+		L:         L,
+		z:         make([]float64, nw),
+		generator: rand.New(source),
+	}, nil
+}
+
+// Dimensions returns the number of the processes.
+func (self *Correlated) Dimensions() uint {
+	return self.nw
+}
+
+// Increment draws the increments of the processes over [x, x+h].
+func (self *Correlated) Increment(_, h float64, dW []float64) {
+	nw, L, z := self.nw, self.L, self.z
+	σ := math.Sqrt(h)
+	for j := range z {
+		z[j] = σ * self.generator.NormFloat64()
+	}
+	for i := uint(0); i < nw; i++ {
+		sum := 0.0
+		for j := uint(0); j <= i; j++ {
+			sum += L[i*nw+j] * z[j]
+		}
+		dW[i] = sum
+	}
+}
+
+// cholesky computes the lower-triangular factor of the Cholesky decomposition
+// of a symmetric matrix of order n.
+func cholesky(A []float64, n uint) ([]float64, error) {
+	L := make([]float64, n*n)
+	for i := uint(0); i < n; i++ {
+		for j := uint(0); j <= i; j++ {
+			if A[i*n+j] != A[j*n+i] {
+				return nil, errors.New("the correlation matrix should be symmetric")
+			}
+			sum := A[i*n+j]
+			for k := uint(0); k < j; k++ {
+				sum -= L[i*n+k] * L[j*n+k]
+			}
+			if i == j {
+				if sum <= 0 {
+					return nil, errors.New("the correlation matrix should be positive definite")
+				}
+				L[i*n+i] = math.Sqrt(sum)
+			} else {
+				L[i*n+j] = sum / L[j*n+j]
+			}
+		}
+	}
+	return L, nil
+}
@@ -0,0 +1,122 @@
+package sde
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	tolerance = 1e-12
+)
+
+// Path is a generator of the increments of Wiener processes that records the
+// values of the processes at the points where they have been sampled so that
+// the same path can be repeated on other grids, which is needed in
+// convergence studies. The values at new points beyond the recorded ones are
+// drawn from an underlying generator, and the values at new points between
+// the recorded ones are drawn from the corresponding Brownian bridges. The
+// first point at which the path is sampled is its origin, where the processes
+// are zero.
+type Path struct {
+	noise Noise
+	nw    int
+	xs    []float64
+	ws    []float64
+	z     []float64
+}
+
+// NewPath creates a path of the Wiener processes of an underlying generator.
+func NewPath(noise Noise) *Path {
+	nw := noise.Dimensions()
+	return &Path{
+		noise: noise,
+		nw:    int(nw),
+		z:     make([]float64, nw),
+	}
+}
+
+// Dimensions returns the number of the processes.
+func (self *Path) Dimensions() uint {
+	return uint(self.nw)
+}
+
+// Increment computes the increments of the processes over [x, x+h].
+func (self *Path) Increment(x, h float64, dW []float64) {
+	// Sampling at one point can shift the index of the other.
+	self.locate(x)
+	j := self.locate(x + h)
+	i := self.locate(x)
+	nw := self.nw
+	for k := range dW {
+		dW[k] = self.ws[j*nw+k] - self.ws[i*nw+k]
+	}
+}
+
+// Value computes the values of the processes at x relative to the origin.
+func (self *Path) Value(x float64, w []float64) {
+	i := self.locate(x)
+	copy(w, self.ws[i*self.nw:(i+1)*self.nw])
+}
+
+// locate returns the index of the point x, sampling the processes at the point
+// if it has not been recorded yet.
+func (self *Path) locate(x float64) int {
+	nw, xs, z := self.nw, self.xs, self.z
+
+	n := len(xs)
+	if n == 0 {
+		self.insert(0, x)
+		return 0
+	}
+
+	i := sort.SearchFloat64s(xs, x)
+	if i < n && math.Abs(xs[i]-x) <= tolerance*math.Max(1, math.Abs(x)) {
+		return i
+	}
+	if i > 0 && math.Abs(xs[i-1]-x) <= tolerance*math.Max(1, math.Abs(x)) {
+		return i - 1
+	}
+
+	switch {
+	case i == n:
+		self.noise.Increment(xs[n-1], x-xs[n-1], z)
+		w := self.insert(i, x)
+		for k := range w {
+			w[k] = self.ws[(i-1)*nw+k] + z[k]
+		}
+	case i == 0:
+		self.noise.Increment(x, xs[0]-x, z)
+		w := self.insert(i, x)
+		for k := range w {
+			w[k] = self.ws[nw+k] - z[k]
+		}
+	default:
+		a, b := xs[i-1], xs[i]
+		θ := (x - a) / (b - a)
+		self.noise.Increment(a, θ*(b-x), z)
+		w := self.insert(i, x)
+		for k := range w {
+			wa, wb := self.ws[(i-1)*nw+k], self.ws[(i+1)*nw+k]
+			w[k] = wa + θ*(wb-wa) + z[k]
+		}
+	}
+
+	return i
+}
+
+// insert inserts the point x at position i and returns the slot for the
+// values of the processes at the point.
+func (self *Path) insert(i int, x float64) []float64 {
+	nw := self.nw
+
+	self.xs = append(self.xs, 0)
+	copy(self.xs[i+1:], self.xs[i:])
+	self.xs[i] = x
+
+	for k := 0; k < nw; k++ {
+		self.ws = append(self.ws, 0)
+	}
+	copy(self.ws[(i+1)*nw:], self.ws[i*nw:])
+
+	return self.ws[i*nw : (i+1)*nw]
+}
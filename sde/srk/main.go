@@ -0,0 +1,82 @@
+// Package srk provides an integrator of systems of stochastic differential
+// equations based on the derivative-free stochastic Runge–Kutta method of
+// Platen. See the sde package for the form of the systems.
+//
+// The method replaces the directional derivatives of the Milstein method with
+// differences of the diffusion evaluated at the supporting values
+//
+//	Ῡⱼ = y + h f(x, y) + √h gⱼ(x, y)
+//
+// where gⱼ is the jth column of the diffusion, which costs one evaluation of
+// the diffusion per Wiener process. Like the Milstein method, the method has
+// strong order 1 for systems with commutative noise.
+//
+// https://en.wikipedia.org/wiki/Runge–Kutta_method_(SDE)
+package srk
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode/sde"
+)
+
+// Integrator is an integrator.
+type Integrator = sde.Integrator
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return sde.New(method, config)
+}
+
+func method(nd, nw uint, _ *Config) sde.Stepper {
+	return &stepper{
+		nw: int(nw),
+		f:  make([]float64, nd),
+		g:  make([]float64, nd*nw),
+		z:  make([]float64, nd),
+		gz: make([]float64, nd*nw),
+	}
+}
+
+type stepper struct {
+	nw int
+	f  []float64
+	g  []float64
+	z  []float64
+	gz []float64
+}
+
+func (self *stepper) Step(drift, diffusion func(float64, []float64, []float64), x, h float64,
+	y, dW, ynew []float64) {
+
+	nw, f, g, z, gz := self.nw, self.f, self.g, self.z, self.gz
+
+	drift(x, y, f)
+	diffusion(x, y, g)
+	for i := range y {
+		sum := 0.0
+		for j := range dW {
+			sum += g[i*nw+j] * dW[j]
+		}
+		ynew[i] = y[i] + h*f[i] + sum
+	}
+
+	σ := math.Sqrt(h)
+	for j := range dW {
+		for i := range y {
+			z[i] = y[i] + h*f[i] + σ*g[i*nw+j]
+		}
+		diffusion(x, z, gz)
+		for i := range y {
+			sum := 0.0
+			for k := range dW {
+				I := dW[j] * dW[k]
+				if j == k {
+					I -= h
+				}
+				sum += (gz[i*nw+k] - g[i*nw+k]) * I
+			}
+			ynew[i] += 0.5 * sum / σ
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package srk
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/sde"
+)
+
+func TestComputeStrongOrder(t *testing.T) {
+	const (
+		μ    = 1.5
+		σ    = 1.0
+		runs = 100
+	)
+
+	// Geometric Brownian motion
+	drift := func(_ float64, y, f []float64) {
+		f[0] = μ * y[0]
+	}
+	diffusion := func(_ float64, y, g []float64) {
+		g[0] = σ * y[0]
+	}
+
+	steps := []float64{1.0 / 16, 1.0 / 32, 1.0 / 64, 1.0 / 128}
+	errors := make([]float64, len(steps))
+
+	w := []float64{0}
+	for k := 0; k < runs; k++ {
+		path := sde.NewPath(sde.NewWiener(1, rand.NewSource(int64(k))))
+		for l, h := range steps {
+			integrator, _ := New(&Config{Step: h, Noise: path})
+			ys, _, _ := integrator.Compute(drift, diffusion, []float64{1}, []float64{0, 1})
+			path.Value(1, w)
+			exact := math.Exp((μ-σ*σ/2)*1 + σ*w[0])
+			errors[l] += math.Abs(ys[len(ys)-1]-exact) / runs
+		}
+	}
+
+	order := math.Log2(errors[0]/errors[len(errors)-1]) / float64(len(errors)-1)
+	assert.Close(order, 1.0, 0.2, t)
+}
+
+func TestComputeAdditive(t *testing.T) {
+	// For additive noise, the correction vanishes, and the method coincides
+	// with the Euler–Maruyama method.
+	drift := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+	diffusion := func(_ float64, _, g []float64) {
+		g[0], g[1] = 0.5, 0.1
+	}
+
+	integrator, _ := New(&Config{Step: 0.1, Dimensions: 2, Source: rand.NewSource(7)})
+	ys, _, stats, _ := integrator.ComputeWithStats(drift, diffusion, []float64{1}, []float64{0, 1})
+
+	generator := rand.New(rand.NewSource(7))
+	y := 1.0
+	for k := 0; k < 10; k++ {
+		dW1 := math.Sqrt(0.1) * generator.NormFloat64()
+		dW2 := math.Sqrt(0.1) * generator.NormFloat64()
+		y += -0.1*y + 0.5*dW1 + 0.1*dW2
+	}
+
+	assert.Close(ys[len(ys)-1], y, 1e-12, t)
+	assert.Equal(stats.Evaluations, uint(10), t)
+	assert.Equal(stats.Diffusions, uint(30), t)
+}
@@ -0,0 +1,9 @@
+package sde
+
+// Stats contains information about the work done by an integrator.
+type Stats struct {
+	Evaluations uint    // The number of invocations of the drift function.
+	Diffusions  uint    // The number of invocations of the diffusion function.
+	Steps       uint    // The number of steps the algorithm has taken.
+	End         float64 // The point where the integration has ended.
+}
@@ -0,0 +1,77 @@
+package sdirk
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The initial step of integration.
+	TryStep float64 `json:"tryStep"`
+	// The maximal step of integration.
+	MaxStep float64 `json:"maxStep"`
+	// The absolute error tolerance.
+	AbsError float64 `json:"absError"`
+	// The relative error tolerance.
+	RelError float64 `json:"relError"`
+	// The order of the method, which is either 2, 3, or 4.
+	Order uint `json:"order"`
+	// The Jacobian matrix of the right-hand side, which is stored in row-major
+	// order; see ode.Jacobian. If it is not given, it is approximated using finite
+	// differences.
+	Jacobian ode.Jacobian `json:"-"`
+	// The sparsity pattern of the Jacobian matrix of the right-hand side. If it is
+	// given, the Jacobian matrix is stored in the order of the pattern, its
+	// finite-difference approximation perturbs groups of columns at once, and the
+	// iteration matrix is decomposed using a sparse LU decomposition without
+	// pivoting.
+	Pattern ode.Pattern `json:"pattern"`
+	// The function creating the solver of the linear systems with the iteration
+	// matrix, which is called once per integration; see ode.LinearSolver. If
+	// it is not given, an LU decomposition is used.
+	LinearSolver func(n uint, pattern ode.Pattern) ode.LinearSolver `json:"-"`
+	// The configuration of the Jacobian-free Newton–Krylov mode, in which the
+	// Jacobian matrix is never formed, and the linear systems are solved
+	// iteratively; see ode.Krylov. If it is given, Jacobian, Pattern, and
+	// LinearSolver are ignored.
+	Krylov *ode.Krylov `json:"krylov"`
+}
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return &Config{
+		TryStep:  0,
+		MaxStep:  0,
+		AbsError: 1e-6,
+		RelError: 1e-3,
+		Order:    4,
+	}
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.TryStep < 0 {
+		return errors.New("the initial step should be nonnegative")
+	}
+	if c.MaxStep < 0 {
+		return errors.New("the maximal step should be nonnegative")
+	}
+	if c.AbsError <= 0 {
+		return errors.New("the absolute error tolerance should be positive")
+	}
+	if c.RelError <= 0 {
+		return errors.New("the relative error tolerance should be positive")
+	}
+	if c.Order < 2 || c.Order > 4 {
+		return errors.New("the order should be 2, 3, or 4")
+	}
+
+	return nil
+}
@@ -0,0 +1,391 @@
+// Package sdirk provides integrators of stiff systems of ordinary
+// differential equations based on singly diagonally implicit Runge–Kutta
+// methods of orders two, three, and four.
+//
+// All the methods are L-stable and stiffly accurate. The stages are computed
+// one after another by Newton iterations sharing the same iteration matrix,
+// and the local error is estimated using an embedded method of lower order.
+//
+// https://en.wikipedia.org/wiki/List_of_Runge%E2%80%93Kutta_methods#Diagonally_Implicit_Runge%E2%80%93Kutta_methods
+package sdirk
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+	"github.com/ready-steady/ode/internal/jacobian"
+	"github.com/ready-steady/ode/internal/newton"
+	"github.com/ready-steady/ode/internal/sparse"
+)
+
+const (
+	newtonMaxIter = 7
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// Apart from the endpoints, the solution is returned at a number of
+// intermediate points. These points can be specified by xs. If xs does not
+// specify any intermediate points, the algorithm reports the points that it
+// internally traverses.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	config := &self.config
+
+	tableau := newTableau(config.Order)
+	ns := len(tableau.c)
+	γ := tableau.γ
+
+	power := 1 / float64(tableau.order)
+
+	nd, nx, nc := len(y0), len(xs), 0
+
+	if config.Pattern != nil {
+		if err := sparse.Verify(config.Pattern, uint(nd)); err != nil {
+			return nil, nil, stats, err
+		}
+	}
+
+	y := make([]float64, nd)
+	ynew := make([]float64, nd)
+	ψ := make([]float64, nd)
+	e := make([]float64, nd)
+	scale := make([]float64, nd)
+
+	f := make([]float64, 3*nd)
+	f0 := f[0*nd : 1*nd]
+	f1 := f[1*nd : 2*nd]
+	fz := f[2*nd : 3*nd]
+
+	k := make([][]float64, ns)
+	for i := range k {
+		k[i] = make([]float64, nd)
+	}
+
+	approximator := jacobian.New(uint(nd), config.Pattern)
+	var J []float64
+	if config.Krylov == nil {
+		J = make([]float64, approximator.Size())
+	}
+
+	x, xend := xs[0], xs[nx-1]
+
+	// Should the solution be returned at fixed points?
+	fixed := nx > 2
+
+	// Prepare the first iteration.
+	copy(y, y0)
+	dydx(x, y, f0)
+	stats.Evaluations++
+
+	relerr := config.RelError
+	threshold := config.AbsError / relerr
+
+	solver := newton.New(uint(nd), config.Pattern, config.LinearSolver, newtonMaxIter,
+		relerr*math.Max(10*epsilon(1)/relerr, math.Min(0.03, math.Sqrt(relerr))))
+	if config.Krylov != nil {
+		solver.UseKrylov(config.Krylov)
+	}
+
+	// Compute the limits on the step size.
+	hmax := config.MaxStep
+	if hmax == 0 {
+		hmax = 0.1 * (xend - x)
+	}
+
+	// Choose the initial step size.
+	h := config.TryStep
+	if h == 0 {
+		h = xs[1] - x
+		if h > hmax {
+			h = hmax
+		}
+
+		scale := 0.0
+		for i := 0; i < nd; i++ {
+			s := math.Abs(f0[i]) / math.Max(math.Abs(y[i]), threshold)
+			if s > scale {
+				scale = s
+			}
+		}
+		scale = scale / (0.8 * math.Pow(relerr, power))
+
+		if h*scale > 1 {
+			h = 1 / scale
+		}
+	}
+
+	var ys []float64
+	if fixed {
+		ys = make([]float64, nx*nd)
+	} else {
+		ys = make([]float64, 0, 2*nd)
+		xs = make([]float64, 0, 2)
+	}
+
+	// Done with the first point.
+	if fixed {
+		copy(ys, y0)
+	} else {
+		ys = append(ys, y0...)
+		xs = append(xs, x)
+	}
+	nc += 1
+
+	// Is the Jacobian matrix evaluated at the current point?
+	current := false
+	// The step size for which the iteration matrix has been factorized.
+	hfactorized := 0.0
+
+	for done := false; ; {
+		var xnew, ε float64
+
+		stats.Steps++
+
+		hmin := 16 * epsilon(x)
+
+		if h < hmin {
+			h = hmin
+		}
+		if h > hmax {
+			h = hmax
+		}
+
+		// Close to the end?
+		if 1.1*h >= xend-x {
+			h = xend - x
+			done = true
+		}
+
+		// In the Jacobian-free mode, the Jacobian matrix is never formed, and
+		// the one implied by the iterations is always current.
+		if stats.Jacobians == 0 {
+			if config.Krylov == nil {
+				if config.Jacobian != nil {
+					config.Jacobian(x, y, J)
+				} else {
+					stats.Evaluations += approximator.Compute(dydx, x, y, f0, J)
+				}
+				stats.Jacobians++
+			}
+			current = true
+		}
+
+		for i := 0; i < nd; i++ {
+			scale[i] = math.Max(math.Abs(y[i]), threshold)
+		}
+
+		rejected := false
+
+		for {
+			if h != hfactorized {
+				stats.Decompositions++
+				if err := solver.Factorize(J, γ*h); err != nil {
+					return nil, nil, stats, err
+				}
+				hfactorized = h
+			}
+
+			xnew = x + h
+
+			converged := true
+			for j := 0; j < ns && converged; j++ {
+				a := tableau.a[j]
+				for i := 0; i < nd; i++ {
+					ψ[i] = y[i]
+					for l := range a {
+						ψ[i] += h * a[l] * k[l][i]
+					}
+				}
+
+				// Extrapolate from the previous stage.
+				previous := f0
+				if j > 0 {
+					previous = k[j-1]
+				}
+				for i := 0; i < nd; i++ {
+					ynew[i] = ψ[i] + γ*h*previous[i]
+				}
+
+				var evaluations uint
+				converged, evaluations = solver.Iterate(dydx, x+tableau.c[j]*h, ψ, scale, ynew, fz)
+				stats.Evaluations += evaluations
+
+				for i := 0; i < nd; i++ {
+					k[j][i] = (ynew[i] - ψ[i]) / (γ * h)
+				}
+			}
+
+			if !converged {
+				// Try a fresh Jacobian matrix before shrinking the step size.
+				if !current {
+					if config.Jacobian != nil {
+						config.Jacobian(x, y, J)
+					} else {
+						stats.Evaluations += approximator.Compute(dydx, x, y, f0, J)
+					}
+					stats.Jacobians++
+					current = true
+					hfactorized = 0
+					continue
+				}
+
+				stats.Rejections++
+
+				if h <= hmin {
+					return nil, nil, stats, errors.New("encountered a step-size underflow")
+				}
+
+				h = 0.5 * h
+				if h < hmin {
+					h = hmin
+				}
+
+				done = false
+				rejected = true
+				continue
+			}
+
+			dydx(xnew, ynew, f1)
+			stats.Evaluations++
+
+			// Estimate the local error.
+			for i := 0; i < nd; i++ {
+				e[i] = 0
+				for j := 0; j < ns; j++ {
+					e[i] += h * tableau.e[j] * k[j][i]
+				}
+			}
+
+			// Compute the relative error.
+			ε = 0
+			for i := 0; i < nd; i++ {
+				scale := math.Max(math.Max(math.Abs(y[i]), math.Abs(ynew[i])), threshold)
+				if e := math.Abs(e[i]) / scale; e > ε {
+					ε = e
+				}
+			}
+
+			if ε <= relerr {
+				break
+			}
+
+			stats.Rejections++
+
+			if h <= hmin {
+				return nil, nil, stats, errors.New("encountered a step-size underflow")
+			}
+
+			// Shrink the step size as the current one has been rejected.
+			if rejected {
+				h = 0.5 * h
+			} else {
+				h = h * math.Max(0.5, 0.8*math.Pow(relerr/ε, power))
+			}
+
+			if h < hmin {
+				h = hmin
+			}
+
+			done = false
+			rejected = true
+		}
+
+		if fixed {
+			for nc < nx {
+				if xnew-xs[nc] < 0 {
+					break
+				}
+
+				if xs[nc] == xnew {
+					copy(ys[nc*nd:(nc+1)*nd], ynew)
+				} else {
+					interpolate(x, y, f0, xnew, ynew, f1, xs[nc], ys[nc*nd:(nc+1)*nd])
+				}
+
+				nc++
+			}
+		} else {
+			ys = append(ys, ynew...)
+			xs = append(xs, xnew)
+			nc++
+		}
+
+		if done {
+			break
+		}
+
+		x = xnew
+		copy(f0, f1)
+		copy(y, ynew)
+		current = false
+
+		if rejected {
+			continue
+		}
+
+		// Compute a new step size.
+		if scale := 1.25 * math.Pow(ε/relerr, power); scale > 0.2 {
+			h = h / scale
+		} else {
+			h = 5 * h
+		}
+	}
+
+	return ys, xs, stats, nil
+}
+
+func interpolate(x0 float64, y0, f0 []float64, x1 float64, y1, f1 []float64,
+	xnext float64, ynext []float64) {
+
+	h := x1 - x0
+	s := (xnext - x0) / h
+
+	h00 := (1 + 2*s) * (1 - s) * (1 - s)
+	h10 := s * (1 - s) * (1 - s)
+	h01 := s * s * (3 - 2*s)
+	h11 := s * s * (s - 1)
+
+	for i := range ynext {
+		ynext[i] = h00*y0[i] + h*h10*f0[i] + h01*y1[i] + h*h11*f1[i]
+	}
+}
+
+func epsilon(x float64) float64 {
+	if x < 0 {
+		x = -x
+	}
+	return math.Nextafter(x, x+1) - x
+}
@@ -0,0 +1,193 @@
+package sdirk
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/internal/dense"
+)
+
+func TestComputeDecay(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{0, 0.5, 1, 1.5, 2}
+
+	for _, order := range []uint{2, 3, 4} {
+		integrator, _ := New(&Config{AbsError: 1e-8, RelError: 1e-6, Order: order})
+
+		ys, _, _ := integrator.Compute(dydx, []float64{0.5}, xs)
+		for i, x := range xs {
+			assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 5e-5, t)
+		}
+	}
+}
+
+// http://mathworks.com/company/newsletters/articles/stiff-differential-equations.html
+func TestComputeFlame(t *testing.T) {
+	const δ = 0.0001
+
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = y[0]*y[0] - y[0]*y[0]*y[0]
+	}
+
+	config := DefaultConfig()
+	config.RelError = 1e-4
+
+	integrator, _ := New(config)
+
+	ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{δ}, []float64{0, 2 / δ})
+	assert.Equal(err, nil, t)
+
+	n := len(xs)
+	assert.Equal(xs[n-1], 2/δ, t)
+	assert.Close(ys[n-1], 1.0, 1e-4, t)
+	assert.Equal(stats.Evaluations < 2000, true, t)
+}
+
+func TestComputeRobertson(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -0.04*y[0] + 1e4*y[1]*y[2]
+		f[1] = 0.04*y[0] - 1e4*y[1]*y[2] - 3e7*y[1]*y[1]
+		f[2] = 3e7 * y[1] * y[1]
+	}
+
+	jacobian := func(_ float64, y, J []float64) {
+		J[0], J[1], J[2] = -0.04, 1e4*y[2], 1e4*y[1]
+		J[3], J[4], J[5] = 0.04, -1e4*y[2]-6e7*y[1], -1e4*y[1]
+		J[6], J[7], J[8] = 0, 6e7*y[1], 0
+	}
+
+	expected := []float64{7.158270687193e-01, 9.185534764529e-06, 2.841637457460e-01}
+
+	for _, J := range []func(float64, []float64, []float64){nil, jacobian} {
+		for _, order := range []uint{2, 3, 4} {
+			integrator, _ := New(&Config{AbsError: 1e-10, RelError: 1e-5, Order: order, Jacobian: J})
+
+			ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1, 0, 0}, []float64{0, 40})
+			assert.Equal(err, nil, t)
+
+			n := len(xs)
+			y := ys[3*(n-1):]
+			for i := range y {
+				assert.Close(y[i]/expected[i], 1.0, 1e-3, t)
+			}
+			assert.Equal(stats.Steps < 2000, true, t)
+		}
+	}
+}
+
+func TestComputeSparse(t *testing.T) {
+	// The heat equation discretized on 50 interior points, whose Jacobian
+	// matrix is tridiagonal.
+	const nd = 50
+
+	dydx := func(_ float64, y, f []float64) {
+		for i := 0; i < nd; i++ {
+			f[i] = -2 * y[i]
+			if i > 0 {
+				f[i] += y[i-1]
+			}
+			if i < nd-1 {
+				f[i] += y[i+1]
+			}
+			f[i] *= (nd + 1) * (nd + 1)
+		}
+	}
+
+	pattern := make([][]uint, nd)
+	for i := uint(0); i < nd; i++ {
+		if i > 0 {
+			pattern[i] = append(pattern[i], i-1)
+		}
+		pattern[i] = append(pattern[i], i)
+		if i < nd-1 {
+			pattern[i] = append(pattern[i], i+1)
+		}
+	}
+
+	y0 := make([]float64, nd)
+	for i := range y0 {
+		y0[i] = math.Sin(math.Pi * float64(i+1) / (nd + 1))
+	}
+
+	xs := []float64{0, 0.05, 0.1}
+
+	config := &Config{AbsError: 1e-8, RelError: 1e-6, Order: 4}
+	integrator, _ := New(config)
+	ys1, _, stats1, err := integrator.ComputeWithStats(dydx, y0, xs)
+	assert.Equal(err, nil, t)
+
+	config.Pattern = pattern
+	integrator, _ = New(config)
+	ys2, _, stats2, err := integrator.ComputeWithStats(dydx, y0, xs)
+	assert.Equal(err, nil, t)
+
+	assert.Close(ys2, ys1, 1e-10, t)
+	assert.Equal(stats2.Evaluations < stats1.Evaluations, true, t)
+
+	config.Pattern = nil
+	config.Krylov = &ode.Krylov{}
+	integrator, _ = New(config)
+	ys3, _, stats3, err := integrator.ComputeWithStats(dydx, y0, xs)
+	assert.Equal(err, nil, t)
+
+	assert.Close(ys3, ys1, 1e-6, t)
+	assert.Equal(stats3.Jacobians, uint(0), t)
+
+	config.Krylov = nil
+	config.Pattern = pattern[1:]
+	integrator, _ = New(config)
+	_, _, _, err = integrator.ComputeWithStats(dydx, y0, xs)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeLinearSolver(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0] + y[1]
+		f[1] = -1000 * y[1]
+	}
+
+	xs := []float64{0, 0.5, 1}
+	y0 := []float64{1, 1}
+
+	integrator, _ := New(DefaultConfig())
+	ys1, _, _, err := integrator.ComputeWithStats(dydx, y0, xs)
+	assert.Equal(err, nil, t)
+
+	var solver *countingSolver
+
+	config := DefaultConfig()
+	config.LinearSolver = func(n uint, pattern ode.Pattern) ode.LinearSolver {
+		assert.Equal(n, uint(2), t)
+		assert.Equal(pattern == nil, true, t)
+		solver = &countingSolver{lu: dense.NewLU(n)}
+		return solver
+	}
+	integrator, _ = New(config)
+	ys2, _, stats, err := integrator.ComputeWithStats(dydx, y0, xs)
+	assert.Equal(err, nil, t)
+
+	assert.Equal(ys2, ys1, t)
+	assert.Equal(solver.factorizations, stats.Decompositions, t)
+	assert.Equal(solver.solutions > 0, true, t)
+}
+
+type countingSolver struct {
+	lu             *dense.LU
+	factorizations uint
+	solutions      uint
+}
+
+func (self *countingSolver) Factorize(A []float64) error {
+	self.factorizations++
+	return self.lu.Factorize(A)
+}
+
+func (self *countingSolver) Solve(b []float64) {
+	self.solutions++
+	self.lu.Solve(b)
+}
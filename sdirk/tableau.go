@@ -0,0 +1,97 @@
+package sdirk
+
+import (
+	"math"
+)
+
+// tableau is a stiffly accurate singly diagonally implicit Runge–Kutta
+// method with an embedded method of lower order.
+type tableau struct {
+	order uint
+	γ     float64
+	a     [][]float64
+	c     []float64
+	e     []float64 // The difference between the weights of the two methods.
+}
+
+func newTableau(order uint) *tableau {
+	switch order {
+	case 2:
+		return alexander2
+	case 3:
+		return alexander3
+	default:
+		return hairerWanner4
+	}
+}
+
+// The method of order two due to Alexander with an embedded method of order
+// one.
+var alexander2 = func() *tableau {
+	const (
+		γ = 1 - math.Sqrt2/2
+	)
+
+	return &tableau{
+		order: 2,
+		γ:     γ,
+		a: [][]float64{
+			{},
+			{1 - γ},
+		},
+		c: []float64{γ, 1},
+		e: []float64{-γ, γ},
+	}
+}()
+
+// The method of order three due to Alexander with an embedded method of order
+// two. The diagonal element is the root of x³ - 3x² + 3x/2 - 1/6 lying in
+// (1/6, 1/2).
+var alexander3 = func() *tableau {
+	const (
+		γ = 0.43586652150845899942
+
+		b1 = -(6*γ*γ - 16*γ + 1) / 4
+		b2 = (6*γ*γ - 20*γ + 5) / 4
+
+		c1 = γ
+		c2 = (1 + γ) / 2
+
+		d2 = (0.5 - c1) / (c2 - c1)
+		d1 = 1 - d2
+	)
+
+	return &tableau{
+		order: 3,
+		γ:     γ,
+		a: [][]float64{
+			{},
+			{(1 - γ) / 2},
+			{b1, b2},
+		},
+		c: []float64{c1, c2, 1},
+		e: []float64{b1 - d1, b2 - d2, γ},
+	}
+}()
+
+// The method of order four due to Hairer and Wanner with an embedded method
+// of order three.
+var hairerWanner4 = &tableau{
+	order: 4,
+	γ:     1.0 / 4,
+	a: [][]float64{
+		{},
+		{1.0 / 2},
+		{17.0 / 50, -1.0 / 25},
+		{371.0 / 1360, -137.0 / 2720, 15.0 / 544},
+		{25.0 / 24, -49.0 / 48, 125.0 / 16, -85.0 / 12},
+	},
+	c: []float64{1.0 / 4, 3.0 / 4, 11.0 / 20, 1.0 / 2, 1},
+	e: []float64{
+		25.0/24 - 59.0/48,
+		-49.0/48 + 17.0/96,
+		125.0/16 - 225.0/32,
+		0,
+		1.0 / 4,
+	},
+}
@@ -0,0 +1,36 @@
+package sensitivity
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The integrator of the augmented system.
+	Integrator ode.Integrator `json:"-"`
+	// The Jacobian matrix of the right-hand side with respect to the solution,
+	// which is stored in row-major order; see ode.Jacobian. If it is not
+	// given, its products with the sensitivities are approximated using
+	// directional finite differences.
+	JacobianY func(x float64, y, p, J []float64) `json:"-"`
+	// The Jacobian matrix of the right-hand side with respect to the
+	// parameters, which is an nd-by-np matrix stored in row-major order. If it
+	// is not given, it is approximated using finite differences.
+	JacobianP func(x float64, y, p, J []float64) `json:"-"`
+}
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Integrator == nil {
+		return errors.New("the integrator should be given")
+	}
+
+	return nil
+}
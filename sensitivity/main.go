@@ -0,0 +1,179 @@
+// Package sensitivity provides an integrator of systems of ordinary
+// differential equations dy/dx = f(x, y, p) together with the sensitivities of
+// their solutions with respect to the parameters p.
+//
+// The sensitivities S = ∂y/∂p satisfy the forward sensitivity equations
+//
+//	S′ = ∂f/∂y S + ∂f/∂p,
+//
+// which are appended to the original system, and the augmented system is
+// integrated by a given integrator. Consequently, the solution and the
+// sensitivities share the step-size sequence and the error control. The
+// partial derivatives of f are either given or approximated using finite
+// differences; in the latter case, the jth column of S′ is computed using one
+// evaluation of f along the direction of the jth column of S and the jth
+// parameter.
+//
+// https://en.wikipedia.org/wiki/Sensitivity_analysis
+package sensitivity
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode"
+)
+
+const (
+	epsilon = 2.220446049250313e-16
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y, p)
+// together with its sensitivities.
+//
+// The input function dydx(x, y, p, f) evaluates f(x, y, p) and stores the
+// result in its last argument; see ode.Parametric. The initial condition is y0, and the initial
+// sensitivities are s0, which is an nd-by-np matrix stored in row-major order
+// and can be nil if y0 does not depend on p. The points xs are treated as by
+// Integrator.Compute in the parent package.
+//
+// The function returns the solution and the sensitivities at the points of the
+// output grid, which is also returned. For each point, the solution occupies
+// nd entries of the first slice, and the sensitivities occupy nd×np entries of
+// the second one, in which the entry i*np+j is ∂yᵢ/∂pⱼ.
+func (self *Integrator) Compute(dydx ode.Parametric, y0, s0, p, xs []float64) ([]float64,
+	[]float64, []float64, error) {
+
+	config := &self.config
+
+	nd, np := len(y0), len(p)
+	if s0 != nil && len(s0) != nd*np {
+		return nil, nil, nil, errors.New("the initial sensitivities should match the dimensions of the system")
+	}
+
+	z0 := make([]float64, nd*(1+np))
+	copy(z0, y0)
+	if s0 != nil {
+		copy(z0[nd:], s0)
+	}
+
+	zs, xs, err := config.Integrator.Compute(self.augment(dydx, nd, p), z0, xs)
+
+	nx := len(xs)
+	if len(zs) < nx*nd*(1+np) {
+		nx = len(zs) / (nd * (1 + np))
+	}
+	ys := make([]float64, nx*nd)
+	ss := make([]float64, nx*nd*np)
+	for k := 0; k < nx; k++ {
+		z := zs[k*nd*(1+np) : (k+1)*nd*(1+np)]
+		copy(ys[k*nd:(k+1)*nd], z[:nd])
+		copy(ss[k*nd*np:(k+1)*nd*np], z[nd:])
+	}
+
+	return ys, ss, xs, err
+}
+
+// augment returns the right-hand side of the augmented system.
+func (self *Integrator) augment(dydx ode.Parametric, nd int,
+	p []float64) func(float64, []float64, []float64) {
+
+	config := &self.config
+	np := len(p)
+
+	perturbY := config.JacobianY == nil
+	perturbP := config.JacobianP == nil
+
+	p = append([]float64(nil), p...)
+	q := append([]float64(nil), p...)
+	z := make([]float64, nd)
+	fz := make([]float64, nd)
+
+	var Jy, Jp []float64
+	if !perturbY {
+		Jy = make([]float64, nd*nd)
+	}
+	if !perturbP {
+		Jp = make([]float64, nd*np)
+	}
+
+	return func(x float64, u, du []float64) {
+		y, S := u[:nd], u[nd:]
+		f, dS := du[:nd], du[nd:]
+
+		dydx(x, y, p, f)
+
+		if !perturbY {
+			config.JacobianY(x, y, p, Jy)
+		}
+		if !perturbP {
+			config.JacobianP(x, y, p, Jp)
+		}
+
+		for j := 0; j < np; j++ {
+			// The directional derivative along the jth column of S when ∂f/∂y
+			// is not given and the jth parameter when ∂f/∂p is not given.
+			scale, size := 0.0, 0.0
+			if perturbY {
+				for i := 0; i < nd; i++ {
+					scale = math.Max(scale, math.Abs(y[i]))
+					size = math.Max(size, math.Abs(S[i*np+j]))
+				}
+			}
+			if perturbP {
+				scale = math.Max(scale, math.Abs(p[j]))
+				size = math.Max(size, 1)
+			}
+
+			if size > 0 {
+				σ := math.Sqrt(epsilon) * (1 + scale) / size
+				copy(z, y)
+				if perturbY {
+					for i := 0; i < nd; i++ {
+						z[i] += σ * S[i*np+j]
+					}
+				}
+				if perturbP {
+					q[j] = p[j] + σ
+				}
+				dydx(x, z, q, fz)
+				q[j] = p[j]
+				for i := 0; i < nd; i++ {
+					dS[i*np+j] = (fz[i] - f[i]) / σ
+				}
+			} else {
+				for i := 0; i < nd; i++ {
+					dS[i*np+j] = 0
+				}
+			}
+
+			if !perturbY {
+				for i := 0; i < nd; i++ {
+					sum := 0.0
+					for k := 0; k < nd; k++ {
+						sum += Jy[i*nd+k] * S[k*np+j]
+					}
+					dS[i*np+j] += sum
+				}
+			}
+			if !perturbP {
+				for i := 0; i < nd; i++ {
+					dS[i*np+j] += Jp[i*np+j]
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package sensitivity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/dopri"
+)
+
+func TestComputeDecay(t *testing.T) {
+	// y′ = -a y with y(0) = b.
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = -p[0] * y[0]
+	}
+
+	jacobianY := func(_ float64, _, p, J []float64) {
+		J[0] = -p[0]
+	}
+	jacobianP := func(_ float64, y, _, J []float64) {
+		J[0], J[1] = -y[0], 0
+	}
+
+	a, b := 2.0, 3.0
+
+	xs := []float64{0, 0.5, 1, 1.5, 2}
+
+	for _, analytic := range []bool{false, true} {
+		inner, _ := dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-10})
+		config := &Config{Integrator: inner}
+		if analytic {
+			config.JacobianY, config.JacobianP = jacobianY, jacobianP
+		}
+		integrator, _ := New(config)
+
+		ys, ss, _, err := integrator.Compute(dydx, []float64{b}, []float64{0, 1},
+			[]float64{a, b}, xs)
+		assert.Equal(err, nil, t)
+
+		for i, x := range xs {
+			e := math.Exp(-a * x)
+			assert.Close(ys[i], b*e, 1e-9, t)
+			assert.Close(ss[2*i:2*i+2], []float64{-x * b * e, e}, 1e-7, t)
+		}
+	}
+}
+
+func TestComputeOscillator(t *testing.T) {
+	// y″ = -ω² y with y(0) = 1 and y′(0) = 0, whose solution is cos(ω x).
+	dydx := func(_ float64, y, p, f []float64) {
+		f[0] = y[1]
+		f[1] = -p[0] * p[0] * y[0]
+	}
+
+	ω := 1.5
+
+	inner, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-10})
+	integrator, _ := New(&Config{Integrator: inner})
+
+	xs := []float64{0, 1, 2, 3}
+	ys, ss, _, err := integrator.Compute(dydx, []float64{1, 0}, nil, []float64{ω}, xs)
+	assert.Equal(err, nil, t)
+
+	for i, x := range xs {
+		assert.Close(ys[2*i], math.Cos(ω*x), 1e-8, t)
+		assert.Close(ss[2*i], -x*math.Sin(ω*x), 1e-6, t)
+	}
+}
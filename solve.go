@@ -0,0 +1,166 @@
+package ode
+
+import (
+	"errors"
+)
+
+// SolveOptions are the settings of Solve.
+type SolveOptions struct {
+	// The name of a registered method, which is "dopri" if empty. The method
+	// should be registered beforehand; see New.
+	Method string
+	// The settings of the integrator; see New.
+	Options
+	// The points strictly between x0 and xend where the solution is
+	// requested. If there are none, the solution is reported at the points
+	// that the integrator internally traverses.
+	Points []float64
+	// The events whose occurrences are located during the integration, which
+	// requires an EventIntegrator.
+	Events []Event
+	// The names of the components of the solution, which are optional. If
+	// given, there should be one unique name per component; see Result.Names.
+	Names []string
+}
+
+// Result is a solution computed by Solve.
+type Result struct {
+	// The points where the solution is reported.
+	Xs []float64 `json:"xs"`
+	// The solution at the points of Xs, which is indexed by the point and
+	// then by the component; that is, Ys[i][j] is the jth component at Xs[i].
+	Ys [][]float64 `json:"ys"`
+	// The names of the components of the solution, which are nil unless
+	// given by SolveOptions.Names.
+	Names []string `json:"names,omitempty"`
+	// The work done, which is nil if the integrator does not report it or if
+	// events are located.
+	Stats *Stats `json:"stats,omitempty"`
+	// The occurrences of the events in the chronological order.
+	Events []Crossing `json:"events,omitempty"`
+	// The reason why the integration has stopped.
+	Status string `json:"status"`
+}
+
+// The statuses of a result.
+const (
+	StatusDone     = "the integration has reached the end of the interval"
+	StatusTerminal = "the integration has been stopped by a terminal event"
+)
+
+// Solve integrates the system of differential equations dy/dx = f(x, y) over
+// the interval span = [x0, xend] given the initial condition y0 using a method
+// chosen by options, which can be nil. Unlike Integrator.Compute, which
+// returns the solution as a flat slice, Solve returns it as a Result, which
+// keeps the points and the components apart.
+func Solve(dydx func(float64, []float64, []float64), y0 []float64,
+	span [2]float64, options *SolveOptions) (*Result, error) {
+
+	if options == nil {
+		options = &SolveOptions{}
+	}
+	method := options.Method
+	if method == "" {
+		method = "dopri"
+	}
+
+	if err := verifyNames(options.Names, len(y0)); err != nil {
+		return nil, err
+	}
+
+	integrator, err := New(method, &options.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	xs := make([]float64, 0, len(options.Points)+2)
+	xs = append(xs, span[0])
+	xs = append(xs, options.Points...)
+	xs = append(xs, span[1])
+
+	result := &Result{Names: options.Names, Status: StatusDone}
+
+	var ys []float64
+	if len(options.Events) > 0 {
+		integrator, ok := integrator.(EventIntegrator)
+		if !ok {
+			return nil, errors.New("the method " + method + " does not locate events")
+		}
+		ys, xs, result.Events, err = integrator.ComputeWithCrossings(dydx, y0, xs, options.Events)
+		for _, crossing := range result.Events {
+			if options.Events[crossing.Index].Terminal {
+				result.Status = StatusTerminal
+			}
+		}
+	} else if integrator, ok := integrator.(StatsIntegrator); ok {
+		ys, xs, result.Stats, err = integrator.ComputeWithSummary(dydx, y0, xs)
+	} else {
+		ys, xs, err = integrator.Compute(dydx, y0, xs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nd := len(y0)
+	result.Xs = xs
+	result.Ys = make([][]float64, len(ys)/nd)
+	for i := range result.Ys {
+		result.Ys[i] = ys[i*nd : (i+1)*nd : (i+1)*nd]
+	}
+
+	return result, nil
+}
+
+// Len returns the number of points where the solution is reported.
+func (self *Result) Len() int {
+	return len(self.Xs)
+}
+
+// At returns the ith point and the solution at it.
+func (self *Result) At(i int) (float64, []float64) {
+	return self.Xs[i], self.Ys[i]
+}
+
+// Last returns the last point and the solution at it.
+func (self *Result) Last() (float64, []float64) {
+	return self.At(len(self.Xs) - 1)
+}
+
+// Component returns the jth component of the solution at all the points.
+func (self *Result) Component(j int) []float64 {
+	values := make([]float64, len(self.Ys))
+	for i, y := range self.Ys {
+		values[i] = y[j]
+	}
+	return values
+}
+
+// Named returns the component of the solution with a given name at all the
+// points like Component. The function returns nil if there is no such name.
+func (self *Result) Named(name string) []float64 {
+	for j, other := range self.Names {
+		if other == name {
+			return self.Component(j)
+		}
+	}
+	return nil
+}
+
+// verifyNames checks that the names of the components, if any, are nonempty
+// and unique and that there is one per component.
+func verifyNames(names []string, nd int) error {
+	if names == nil {
+		return nil
+	}
+	if len(names) != nd {
+		return errors.New("the names should correspond to the components of the solution")
+	}
+	seen := make(map[string]bool, nd)
+	for _, name := range names {
+		if name == "" || seen[name] {
+			return errors.New("the names of the components should be nonempty and unique")
+		}
+		seen[name] = true
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package ode
+
+// LinearSolver solves the systems of linear equations arising in the Newton
+// methods of implicit integrators.
+//
+// The matrices are n-by-n where n is the dimension of the system. They are
+// either dense and stored in row-major order or, when the solver is created
+// for a sparsity pattern, sparse and stored in the order of the pattern; see
+// Pattern. In the latter case, the pattern always includes the diagonal.
+//
+// Implicit integrators that accept a linear solver create one per call to
+// Compute using a function of the form
+//
+//	func(n uint, pattern Pattern) LinearSolver
+//
+// where pattern is nil for dense matrices. When such a function is not given,
+// an LU decomposition with partial pivoting is used for dense matrices and
+// one without pivoting for sparse matrices.
+type LinearSolver interface {
+	// Factorize prepares the solver for a matrix A. The matrix should not be
+	// modified. An error should be returned if the matrix is singular, in
+	// which case the integrator gives up.
+	Factorize(A []float64) error
+
+	// Solve solves the system A x = b for the matrix given to the last call to
+	// Factorize. The solution overwrites b.
+	Solve(b []float64)
+}
@@ -0,0 +1,43 @@
+package splitting
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The macro step of integration.
+	Step float64 `json:"step"`
+	// The splitting scheme.
+	Scheme Scheme `json:"scheme"`
+}
+
+// Scheme is a choice of a splitting scheme.
+type Scheme uint
+
+const (
+	// The second-order symmetric splitting due to Strang, which advances all
+	// the parts but the last by half a step, the last one by a full step, and
+	// then the former by half a step again in the reverse order.
+	Strang Scheme = iota
+	// The first-order splitting due to Lie and Trotter, which advances the
+	// parts one after another by a full step.
+	Lie
+)
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Scheme > Lie {
+		return errors.New("the scheme is unknown")
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+// Package splitting provides an integrator of systems of ordinary differential
+// equations whose right-hand side is a sum of several parts,
+//
+//	y′ = f₁(x, y) + f₂(x, y) + … + fₙ(x, y),
+//
+// based on operator splitting. Each part is integrated separately by an
+// integrator of its own, and the resulting flows are composed over each macro
+// step according to the chosen scheme; see Scheme. This allows for using, for
+// instance, an implicit integrator for a stiff diffusion term and an explicit
+// one for a nonstiff reaction term.
+//
+// The error of the composition is of order one for the Lie scheme and of order
+// two for the Strang scheme, provided that the sub-integrators are at least as
+// accurate, and it vanishes when the parts commute.
+//
+// https://en.wikipedia.org/wiki/Strang_splitting
+package splitting
+
+import (
+	"errors"
+
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system y′ = f₁(x, y) + … + fₙ(x, y).
+//
+// The input functions parts[k](x, y, f) evaluate the parts of the right-hand
+// side for a given x and y in their first and second arguments and store the
+// result in their third arguments. The kth part is integrated by
+// integrators[k], which is asked for the solution at the end of each
+// substep; integrators with fixed steps should therefore have steps dividing
+// the substeps of the scheme. The initial condition is y0, which corresponds
+// to x0 = xs[0].
+//
+// The solution is returned at a number of equidistant points starting from and
+// including x0. The final point is the closest point to the last element of xs
+// with respect to the macro step. If the interval is shorter than half the
+// step, a single step that spans it is taken instead. The points of xs should
+// be strictly increasing. The points are returned as the second result.
+func (self *Integrator) Compute(integrators []ode.Integrator,
+	parts []func(float64, []float64, []float64), y0 []float64,
+	xs []float64) ([]float64, []float64, error) {
+
+	if len(parts) == 0 {
+		return nil, nil, errors.New("there should be at least one part")
+	}
+	if len(integrators) != len(parts) {
+		return nil, nil, errors.New("each part should have an integrator")
+	}
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, err
+	}
+
+	nd := len(y0)
+	np := len(parts)
+
+	h := self.config.Step
+
+	x0, xend := xs[0], xs[len(xs)-1]
+	ns := int((xend-x0)/h+0.5) + 1
+	if ns == 1 {
+		ns, h = 2, xend-x0
+	}
+
+	ys := make([]float64, ns*nd)
+	xs = make([]float64, ns)
+	copy(ys, y0)
+	xs[0] = x0
+
+	// advance integrates the kth part over [x, x + τ] starting from y and
+	// stores the result in y.
+	advance := func(k int, x, τ float64, y []float64) error {
+		zs, _, err := integrators[k].Compute(parts[k], y, []float64{x, x + τ})
+		if err != nil {
+			return err
+		}
+		copy(y, zs[len(zs)-nd:])
+		return nil
+	}
+
+	for j := 1; j < ns; j++ {
+		x := x0 + float64(j-1)*h
+
+		y := ys[j*nd : (j+1)*nd]
+		copy(y, ys[(j-1)*nd:j*nd])
+
+		switch self.config.Scheme {
+		case Lie:
+			for k := 0; k < np; k++ {
+				if err := advance(k, x, h, y); err != nil {
+					return nil, nil, err
+				}
+			}
+		default:
+			for k := 0; k < np-1; k++ {
+				if err := advance(k, x, h/2, y); err != nil {
+					return nil, nil, err
+				}
+			}
+			if err := advance(np-1, x, h, y); err != nil {
+				return nil, nil, err
+			}
+			for k := np - 2; k >= 0; k-- {
+				if err := advance(k, x+h/2, h/2, y); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+
+		xs[j] = x0 + float64(j)*h
+	}
+
+	return ys, xs, nil
+}
@@ -0,0 +1,165 @@
+package splitting
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode"
+	"github.com/ready-steady/ode/dopri"
+	"github.com/ready-steady/ode/linear"
+	"github.com/ready-steady/ode/rk4"
+	"github.com/ready-steady/ode/sdirk"
+)
+
+func TestComputeCommuting(t *testing.T) {
+	parts := []func(float64, []float64, []float64){
+		func(_ float64, y, f []float64) {
+			f[0] = -y[0]
+		},
+		func(_ float64, y, f []float64) {
+			f[0] = 2 * y[0]
+		},
+	}
+
+	integrator, _ := New(&Config{Step: 0.5, Scheme: Lie})
+
+	ys, xs, err := integrator.Compute(integrators(2), parts, []float64{1}, []float64{0, 2})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.5, 1, 1.5, 2}, t)
+	for i, x := range xs {
+		assert.Close(ys[i], math.Exp(x), 1e-7, t)
+	}
+}
+
+func TestComputeInterval(t *testing.T) {
+	parts := []func(float64, []float64, []float64){
+		func(_ float64, y, f []float64) {
+			f[0] = -y[0]
+		},
+		func(_ float64, y, f []float64) {
+			f[0] = 2 * y[0]
+		},
+	}
+
+	integrator, _ := New(&Config{Step: 0.5, Scheme: Lie})
+
+	ys, xs, err := integrator.Compute(integrators(2), parts, []float64{1}, []float64{0, 0.1})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.1}, t)
+	assert.Close(ys[1], math.Exp(0.1), 1e-7, t)
+
+	_, _, err = integrator.Compute(integrators(2), parts, []float64{1}, []float64{1, 0})
+	assert.Equal(err != nil, true, t)
+}
+
+func TestComputeOrder(t *testing.T) {
+	A := []float64{0, 1, -1, 0}
+	B := []float64{-1, 0, 0, 0}
+
+	parts := []func(float64, []float64, []float64){
+		func(_ float64, y, f []float64) {
+			f[0], f[1] = A[0]*y[0]+A[1]*y[1], A[2]*y[0]+A[3]*y[1]
+		},
+		func(_ float64, y, f []float64) {
+			f[0], f[1] = B[0]*y[0]+B[1]*y[1], B[2]*y[0]+B[3]*y[1]
+		},
+	}
+
+	C := make([]float64, 4)
+	for i := range C {
+		C[i] = A[i] + B[i]
+	}
+	reference, _, _ := linear.New().Compute(C, nil, []float64{1, 0}, []float64{0, 1})
+
+	for _, scheme := range []Scheme{Lie, Strang} {
+		errors := make([]float64, 2)
+		for k, h := range []float64{0.02, 0.01} {
+			integrator, _ := New(&Config{Step: h, Scheme: scheme})
+			ys, _, _ := integrator.Compute(integrators(2), parts, []float64{1, 0}, []float64{0, 1})
+			n := len(ys)
+			errors[k] = math.Hypot(ys[n-2]-reference[2], ys[n-1]-reference[3])
+		}
+
+		order := 2.0
+		if scheme == Lie {
+			order = 1
+		}
+		assert.Close(math.Log2(errors[0]/errors[1]), order, 0.1, t)
+	}
+}
+
+func TestComputeReactionDiffusion(t *testing.T) {
+	const (
+		nd = 20
+		dx = 1.0 / (nd + 1)
+	)
+
+	diffusion := func(_ float64, y, f []float64) {
+		for i := 0; i < nd; i++ {
+			f[i] = -2 * y[i]
+			if i > 0 {
+				f[i] += y[i-1]
+			}
+			if i < nd-1 {
+				f[i] += y[i+1]
+			}
+			f[i] /= dx * dx
+		}
+	}
+	reaction := func(_ float64, y, f []float64) {
+		for i := 0; i < nd; i++ {
+			f[i] = y[i] * (1 - y[i])
+		}
+	}
+
+	y0 := make([]float64, nd)
+	for i := range y0 {
+		y0[i] = math.Sin(math.Pi * float64(i+1) * dx)
+	}
+
+	implicit, _ := sdirk.New(&sdirk.Config{AbsError: 1e-10, RelError: 1e-8, Order: 4})
+	explicit, _ := dopri.New(&dopri.Config{AbsError: 1e-10, RelError: 1e-8})
+
+	integrator, _ := New(&Config{Step: 0.01})
+
+	ys, xs, err := integrator.Compute([]ode.Integrator{explicit, implicit},
+		[]func(float64, []float64, []float64){reaction, diffusion}, y0, []float64{0, 0.1})
+	assert.Equal(err, nil, t)
+
+	dydx := func(x float64, y, f []float64) {
+		g := make([]float64, nd)
+		diffusion(x, y, f)
+		reaction(x, y, g)
+		for i := range f {
+			f[i] += g[i]
+		}
+	}
+	zs, zxs, _ := implicit.Compute(dydx, y0, []float64{0, 0.1})
+
+	n, m := len(xs), len(zxs)
+	for i := 0; i < nd; i++ {
+		assert.Close(ys[(n-1)*nd+i], zs[(m-1)*nd+i], 1e-4, t)
+	}
+}
+
+func TestComputeMismatch(t *testing.T) {
+	integrator, _ := New(&Config{Step: 0.1})
+
+	parts := []func(float64, []float64, []float64){
+		func(_ float64, _, f []float64) {
+			f[0] = 1
+		},
+	}
+
+	_, _, err := integrator.Compute(integrators(2), parts, []float64{0}, []float64{0, 1})
+	assert.Equal(err != nil, true, t)
+}
+
+func integrators(n int) []ode.Integrator {
+	integrators := make([]ode.Integrator, n)
+	for i := range integrators {
+		integrators[i], _ = rk4.New(&rk4.Config{Step: 1e-3})
+	}
+	return integrators
+}
@@ -0,0 +1,46 @@
+package ssp
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The step of integration.
+	Step float64 `json:"step"`
+	// The method of integration.
+	Scheme Scheme `json:"scheme"`
+}
+
+// Scheme is a choice of a strong-stability-preserving method. Each method is
+// characterized by its SSP coefficient C: if the forward Euler method is
+// strongly stable, for instance, total-variation diminishing, with the step
+// size h, the method is strongly stable with the step size C h.
+type Scheme uint
+
+const (
+	// The three-stage method of order three due to Shu and Osher with C = 1.
+	SSPRK33 Scheme = iota
+	// The two-stage method of order two with C = 1, which is Heun's method.
+	SSPRK22
+	// The ten-stage method of order four due to Ketcheson with C = 6, which
+	// requires only two registers besides the derivative.
+	SSPRK104
+)
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Scheme > SSPRK104 {
+		return errors.New("the scheme is unknown")
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+// Package ssp provides integrators of systems of ordinary differential
+// equations based on strong-stability-preserving explicit Runge–Kutta methods
+// with a fixed step.
+//
+// The methods are implemented in the Shu–Osher form, in which each stage is a
+// convex combination of forward Euler steps. Consequently, any property that
+// the forward Euler method preserves for the system at hand, such as the
+// total variation of a discretized conservation law being nonincreasing or
+// the positivity of the solution, is preserved by the methods under a
+// proportionally relaxed restriction on the step size; see Scheme.
+//
+// https://en.wikipedia.org/wiki/Total_variation_diminishing
+package ssp
+
+import (
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system of differential equations dy/dx = f(x, y). See
+// Integrator.Compute in the parent package.
+//
+// If xs does not specify any intermediate points, the solution is returned at a
+// number of equidistant points starting from and including x0 = xs[0] and
+// ending at xend, the last element of xs. The last step is shortened to land
+// exactly on xend so that no step exceeds the one of the configuration. The
+// points of the grid are returned as the second result. Otherwise, the solution
+// is returned exactly at the points of xs, and the solution at each point is
+// computed by a partial step of the method from the preceding point of the
+// grid. The points of xs should be strictly increasing.
+func (self *Integrator) Compute(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	ys, xs, _, err := self.ComputeWithStats(dydx, y0, xs)
+
+	return ys, xs, err
+}
+
+// ComputeWithStats augments Compute by providing additional information about
+// the solution process.
+func (self *Integrator) ComputeWithStats(dydx func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, *Stats, error) {
+
+	stats := &Stats{}
+
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, stats, err
+	}
+
+	nd, nx := len(y0), len(xs)
+
+	f := make([]float64, nd)
+	z := make([]float64, nd)
+
+	x0, xend := xs[0], xs[nx-1]
+
+	// Count the invocations of the derivative function.
+	evaluate := dydx
+	dydx = func(x float64, y, f []float64) {
+		evaluate(x, y, f)
+		stats.Evaluations++
+	}
+
+	var step func(x, h float64, y []float64)
+	switch self.config.Scheme {
+	case SSPRK22:
+		step = func(x, h float64, y []float64) {
+			copy(z, y)
+			euler(dydx, x, h, z, f)
+			euler(dydx, x+h, h, z, f)
+			combine(0.5, y, 0.5, z)
+		}
+	case SSPRK33:
+		step = func(x, h float64, y []float64) {
+			copy(z, y)
+			euler(dydx, x, h, z, f)
+			euler(dydx, x+h, h, z, f)
+			combine(0.25, z, 0.75, y)
+			euler(dydx, x+h/2, h, z, f)
+			combine(1.0/3, y, 2.0/3, z)
+		}
+	case SSPRK104:
+		step = func(x, h float64, y []float64) {
+			copy(z, y)
+			for i := 0; i < 5; i++ {
+				euler(dydx, x+float64(i)*h/6, h/6, y, f)
+			}
+			combine(1.0/25, z, 9.0/25, y)
+			combine(-5, y, 15, z)
+			for i := 0; i < 4; i++ {
+				euler(dydx, x+h/3+float64(i)*h/6, h/6, y, f)
+			}
+			dydx(x+h, y, f)
+			for i := range y {
+				y[i] = z[i] + 3.0/5*y[i] + h/10*f[i]
+			}
+		}
+	}
+
+	h := self.config.Step
+
+	if nx > 2 {
+		ys := make([]float64, nx*nd)
+		copy(ys, y0)
+
+		y := append([]float64(nil), y0...)
+
+		for k, nc := 0, 1; nc < nx; k++ {
+			x, xnew := x0+float64(k)*h, x0+float64(k+1)*h
+
+			// The partial steps start from the point of the grid preceding
+			// the points of xs.
+			for ; nc < nx && xs[nc] < xnew; nc++ {
+				ynext := ys[nc*nd : (nc+1)*nd]
+				copy(ynext, y)
+				step(x, xs[nc]-x, ynext)
+			}
+			if nc == nx {
+				break
+			}
+
+			step(x, h, y)
+			stats.Steps++
+
+			if nc < nx && xs[nc] == xnew {
+				copy(ys[nc*nd:(nc+1)*nd], y)
+				nc++
+			}
+		}
+
+		return ys, xs, stats, nil
+	}
+
+	// The last step is shortened rather than lengthened, which would void the
+	// restriction on the step size.
+	np := int(math.Ceil((xend-x0)/h-1e-8)) + 1
+
+	ys := make([]float64, np*nd)
+	xs = make([]float64, np)
+
+	// Done with the first point.
+	copy(ys, y0)
+	xs[0] = x0
+
+	for k := 1; k < np; k++ {
+		x := xs[k-1]
+
+		// Land exactly on the end.
+		if k == np-1 {
+			h = xend - x
+		}
+
+		ynew := ys[k*nd : (k+1)*nd]
+		copy(ynew, ys[(k-1)*nd:k*nd])
+		step(x, h, ynew)
+		stats.Steps++
+
+		xs[k] = x0 + float64(k)*self.config.Step
+	}
+	xs[np-1] = xend
+
+	return ys, xs, stats, nil
+}
+
+// euler performs a forward Euler step in place.
+func euler(dydx func(float64, []float64, []float64), x, h float64, y, f []float64) {
+	dydx(x, y, f)
+	for i := range y {
+		y[i] += h * f[i]
+	}
+}
+
+// combine computes y = α y + β z.
+func combine(α float64, y []float64, β float64, z []float64) {
+	for i := range y {
+		y[i] = α*y[i] + β*z[i]
+	}
+}
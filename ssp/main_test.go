@@ -0,0 +1,119 @@
+package ssp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeOrder(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0]*y[0] + math.Cos(x)
+	}
+
+	reference := func() float64 {
+		integrator, _ := New(&Config{Step: 1e-4, Scheme: SSPRK104})
+		ys, _, _ := integrator.Compute(dydx, []float64{1}, []float64{0, 2})
+		return ys[len(ys)-1]
+	}()
+
+	orders := map[Scheme]float64{SSPRK22: 2, SSPRK33: 3, SSPRK104: 4}
+
+	for scheme, order := range orders {
+		solve := func(h float64) float64 {
+			integrator, _ := New(&Config{Step: h, Scheme: scheme})
+			ys, _, _ := integrator.Compute(dydx, []float64{1}, []float64{0, 2})
+			return math.Abs(ys[len(ys)-1] - reference)
+		}
+
+		ratio := solve(0.1) / solve(0.05)
+		assert.Close(math.Log2(ratio), order, 0.15, t)
+	}
+}
+
+func TestComputeAdvection(t *testing.T) {
+	const (
+		nd = 100
+		Δ  = 1.0 / nd
+	)
+
+	// The upwind discretization of u_x + u_t = 0 on a periodic domain, for
+	// which the forward Euler method is total-variation diminishing as long as
+	// the step does not exceed Δ.
+	upwind := func(_ float64, u, f []float64) {
+		for i := range u {
+			f[i] = -(u[i] - u[(i+nd-1)%nd]) / Δ
+		}
+	}
+
+	u0 := make([]float64, nd)
+	for i := 20; i < 40; i++ {
+		u0[i] = 1
+	}
+
+	variation := func(u []float64) float64 {
+		v := 0.0
+		for i := range u {
+			v += math.Abs(u[i] - u[(i+nd-1)%nd])
+		}
+		return v
+	}
+
+	coefficients := map[Scheme]float64{SSPRK22: 1, SSPRK33: 1, SSPRK104: 6}
+
+	for scheme, C := range coefficients {
+		integrator, _ := New(&Config{Step: C * Δ, Scheme: scheme})
+		ys, _, _ := integrator.Compute(upwind, u0, []float64{0, 0.5})
+
+		for k := 0; k < len(ys)/nd; k++ {
+			u := ys[k*nd : (k+1)*nd]
+			assert.Equal(variation(u) <= 2+1e-12, true, t)
+		}
+	}
+}
+
+func TestComputeGrid(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.1, Scheme: SSPRK104})
+
+	ys, xs, err := integrator.Compute(dydx, []float64{1}, []float64{0, 1})
+	assert.Equal(err, nil, t)
+	assert.Equal(len(xs), 11, t)
+	assert.Equal(len(ys), 11, t)
+	assert.Close(xs[3], 0.3, 1e-15, t)
+	assert.Equal(xs[10], 1.0, t)
+	assert.Close(ys[10], math.Exp(-1), 1e-7, t)
+
+	ys, xs, err = integrator.Compute(dydx, []float64{1}, []float64{0, 0.01})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.01}, t)
+	assert.Close(ys[1], math.Exp(-0.01), 1e-12, t)
+}
+
+func TestComputePoints(t *testing.T) {
+	dydx := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	for _, scheme := range []Scheme{SSPRK22, SSPRK33, SSPRK104} {
+		integrator, _ := New(&Config{Step: 0.1, Scheme: scheme})
+
+		points := []float64{0, 0.25, 0.3, 1}
+		ys, xs, stats, err := integrator.ComputeWithStats(dydx, []float64{1}, points)
+		assert.Equal(err, nil, t)
+		assert.Equal(xs, points, t)
+		assert.Equal(len(ys), len(points), t)
+		assert.Equal(stats.Steps, uint(10), t)
+		for k := range points {
+			assert.Close(ys[k], math.Exp(-points[k]), 1e-3, t)
+		}
+
+		grid, _, _ := integrator.Compute(dydx, []float64{1}, []float64{0, 1})
+		assert.Close(ys[2], grid[3], 1e-15, t)
+		assert.Close(ys[3], grid[10], 1e-15, t)
+	}
+}
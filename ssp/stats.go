@@ -0,0 +1,7 @@
+package ssp
+
+// Stats contains information about the work done by an integrator.
+type Stats struct {
+	Evaluations uint // The number of invocations of the derivative function.
+	Steps       uint // The number of steps the algorithm has taken.
+}
@@ -0,0 +1,13 @@
+package tsit5
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Config is the configuration of an integrator.
+type Config = erk.Config
+
+// DefaultConfig returns the default configuration of an integrator.
+func DefaultConfig() *Config {
+	return erk.DefaultConfig()
+}
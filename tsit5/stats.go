@@ -0,0 +1,8 @@
+package tsit5
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Stats contains information about the work done by an integrator.
+type Stats = erk.Stats
@@ -0,0 +1,76 @@
+package verlet
+
+import (
+	"errors"
+)
+
+// Config is the configuration of an integrator.
+type Config struct {
+	// The step of integration.
+	Step float64 `json:"step"`
+	// The composition scheme.
+	Scheme Scheme `json:"scheme"`
+	// The holonomic constraints on the positions, which are enforced after
+	// each Verlet step by the iterations of SHAKE for the positions and of
+	// RATTLE for the velocities. The initial condition should satisfy them,
+	// including the ones on the velocities, which are the derivatives of the
+	// constraints on the positions.
+	Constraints []Constraint `json:"-"`
+	// The masses of the coordinates, which weigh the corrections made by the
+	// constraints. If nil, all the masses are one.
+	Masses []float64 `json:"masses"`
+	// The tolerance of the iterations of SHAKE and RATTLE, which is 1e-10 if
+	// zero.
+	Tolerance float64 `json:"tolerance"`
+	// The maximal number of iterations of SHAKE and RATTLE per step, which is
+	// 100 if zero.
+	MaxIterations uint `json:"maxIterations"`
+}
+
+// Scheme is a choice of the coefficients with which the basic Verlet step is
+// composed. A composition with coefficients γ₁, …, γₛ performs s Verlet steps
+// of sizes γ₁h, …, γₛh, which yields a symplectic method of a higher order.
+type Scheme uint
+
+const (
+	// The basic Verlet method of order two.
+	Verlet Scheme = iota
+	// The triple jump of Yoshida, which is of order four.
+	Yoshida4
+	// The five-stage composition of Suzuki, which is of order four and has a
+	// smaller error constant than the triple jump.
+	Suzuki4
+	// The seven-stage composition of Yoshida (solution A), which is of order
+	// six.
+	Yoshida6
+)
+
+// Validate checks that the configuration is valid, which is also done when an
+// integrator is created.
+func (c *Config) Validate() error {
+	return c.verify()
+}
+
+func (c *Config) verify() error {
+	if c.Step <= 0 {
+		return errors.New("the step should be positive")
+	}
+	if c.Scheme > Yoshida6 {
+		return errors.New("the scheme is unknown")
+	}
+	for _, constraint := range c.Constraints {
+		if constraint.Function == nil || constraint.Gradient == nil {
+			return errors.New("the constraints should have functions and gradients")
+		}
+	}
+	for _, mass := range c.Masses {
+		if !(mass > 0) {
+			return errors.New("the masses should be positive")
+		}
+	}
+	if c.Tolerance < 0 {
+		return errors.New("the tolerance should be nonnegative")
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package verlet
+
+import (
+	"errors"
+	"math"
+)
+
+// Constraint is a holonomic constraint g(y) = 0 on the positions, such as a
+// fixed bond length or angle.
+type Constraint struct {
+	// The function g(y).
+	Function func(y []float64) float64
+	// The function computing the gradient of g at y and storing it in its
+	// second argument.
+	Gradient func(y, grad []float64)
+}
+
+// ErrConstraint is the error of a step where the iterations of SHAKE or
+// RATTLE have not converged.
+var ErrConstraint = errors.New("the constraints could not be satisfied")
+
+// constrainer enforces the constraints on the positions and the velocities
+// after each step using the iterations of SHAKE and RATTLE, respectively. The
+// constraints are treated one at a time in the manner of Gauss–Seidel, and
+// the corrections are made along the gradients scaled by the inverse masses.
+type constrainer struct {
+	constraints []Constraint
+	masses      []float64
+
+	tolerance  float64
+	iterations uint
+
+	old [][]float64 // The gradients at the beginning of the step.
+	new []float64   // The gradient at the current positions.
+}
+
+func newConstrainer(config *Config, nd int) *constrainer {
+	constrainer := &constrainer{
+		constraints: config.Constraints,
+		masses:      config.Masses,
+		tolerance:   config.Tolerance,
+		iterations:  config.MaxIterations,
+		old:         make([][]float64, len(config.Constraints)),
+		new:         make([]float64, nd),
+	}
+	if constrainer.tolerance == 0 {
+		constrainer.tolerance = 1e-10
+	}
+	if constrainer.iterations == 0 {
+		constrainer.iterations = 100
+	}
+	for k := range constrainer.old {
+		constrainer.old[k] = make([]float64, nd)
+	}
+	return constrainer
+}
+
+// prepare evaluates the gradients of the constraints at the positions at the
+// beginning of a step.
+func (self *constrainer) prepare(y []float64) {
+	for k, constraint := range self.constraints {
+		constraint.Gradient(y, self.old[k])
+	}
+}
+
+// shake moves the positions y, which have been advanced by a step of size h,
+// and the velocities v at the middle of the step onto the constraints along
+// the gradients at the beginning of the step.
+func (self *constrainer) shake(h float64, y, v []float64) error {
+	for i := uint(0); i < self.iterations; i++ {
+		converged := true
+		for k, constraint := range self.constraints {
+			σ := constraint.Function(y)
+			if math.Abs(σ) <= self.tolerance {
+				continue
+			}
+			converged = false
+
+			constraint.Gradient(y, self.new)
+			λ := σ / self.product(self.new, self.old[k])
+			for j := range y {
+				δ := λ * self.old[k][j] / self.mass(j)
+				y[j] -= δ
+				v[j] -= δ / h
+			}
+		}
+		if converged {
+			return nil
+		}
+	}
+	return ErrConstraint
+}
+
+// rattle projects the velocities v at the end of a step onto the tangent space
+// of the constraints at the positions y.
+func (self *constrainer) rattle(y, v []float64) error {
+	for i := uint(0); i < self.iterations; i++ {
+		converged := true
+		for _, constraint := range self.constraints {
+			constraint.Gradient(y, self.new)
+			σ := 0.0
+			for j := range v {
+				σ += self.new[j] * v[j]
+			}
+			if math.Abs(σ) <= self.tolerance {
+				continue
+			}
+			converged = false
+
+			μ := σ / self.product(self.new, self.new)
+			for j := range v {
+				v[j] -= μ * self.new[j] / self.mass(j)
+			}
+		}
+		if converged {
+			return nil
+		}
+	}
+	return ErrConstraint
+}
+
+// product computes the inner product of a gradient with a vector scaled by the
+// inverse masses, which is the plain one if there are no masses.
+func (self *constrainer) product(grad, z []float64) float64 {
+	sum := 0.0
+	for j := range grad {
+		sum += grad[j] * z[j] / self.mass(j)
+	}
+	return sum
+}
+
+func (self *constrainer) mass(j int) float64 {
+	if self.masses == nil {
+		return 1
+	}
+	return self.masses[j]
+}
@@ -0,0 +1,171 @@
+// Package verlet provides a symplectic integrator of systems of second-order
+// ordinary differential equations of the form
+//
+//	y″ = a(x, y),
+//
+// which arise from separable Hamiltonians, based on the velocity Verlet
+// method, also known as the Störmer–Verlet or leapfrog method.
+//
+// The state of the system is composed of the positions y followed by the
+// velocities v. A step is given by
+//
+//	v½ = v₀ + h/2 a(x₀, y₀),
+//	y₁ = y₀ + h v½,
+//	v₁ = v½ + h/2 a(x₁, y₁).
+//
+// The method is of order two, time-reversible, and symplectic. Consequently,
+// the energy of a Hamiltonian system does not drift but oscillates around its
+// exact value over very long times. Methods of orders four and six are
+// obtained by composing several Verlet steps of suitably chosen sizes; see
+// Scheme.
+//
+// Holonomic constraints g(y) = 0, such as fixed bond lengths in molecular
+// dynamics, are enforced by the RATTLE variant of the method, which keeps it
+// time-reversible and symplectic on the constraint manifold; see Constraint.
+//
+// https://en.wikipedia.org/wiki/Verlet_integration
+package verlet
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ready-steady/ode/internal/interval"
+)
+
+// Integrator is an integrator.
+type Integrator struct {
+	config Config
+}
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	if err := config.verify(); err != nil {
+		return nil, err
+	}
+	return &Integrator{config: *config}, nil
+}
+
+// Compute integrates the system y″ = a(x, y).
+//
+// The input function a(x, y, f) evaluates the acceleration a(x, y) for a given
+// x and y in its first and second arguments and stores the result in its
+// third argument. The initial condition y0 contains the positions followed by
+// the velocities.
+//
+// The solution is returned at a number of equidistant points starting from and
+// including x0 = xs[0]. The final point is the closest point to the last
+// element of xs with respect to the integration step. If the interval is
+// shorter than half the step, a single step that spans it is taken instead.
+// The points of xs should be strictly increasing. The points are returned as
+// the second result. If the constraints cannot be satisfied in a step, the
+// solution up to the previous point is returned along with ErrConstraint.
+func (self *Integrator) Compute(a func(float64, []float64, []float64),
+	y0 []float64, xs []float64) ([]float64, []float64, error) {
+
+	if len(y0)%2 != 0 {
+		return nil, nil, errors.New("the initial condition should contain positions and velocities")
+	}
+	if err := interval.Forward(y0, xs); err != nil {
+		return nil, nil, err
+	}
+
+	nd := len(y0) / 2
+	if self.config.Masses != nil && len(self.config.Masses) != nd {
+		return nil, nil, errors.New("the masses should match the positions")
+	}
+
+	h := self.config.Step
+
+	γ := coefficients(self.config.Scheme)
+
+	x0, xend := xs[0], xs[len(xs)-1]
+	ns := int((xend-x0)/h+0.5) + 1
+	if ns == 1 {
+		ns, h = 2, xend-x0
+	}
+
+	ys := make([]float64, ns*2*nd)
+	xs = make([]float64, ns)
+	copy(ys, y0)
+	xs[0] = x0
+
+	f := make([]float64, nd)
+	a(x0, y0[:nd], f)
+
+	var c *constrainer
+	if len(self.config.Constraints) > 0 {
+		c = newConstrainer(&self.config, nd)
+	}
+
+	for k := 1; k < ns; k++ {
+		x := x0 + float64(k-1)*h
+
+		ynew, vnew := ys[k*2*nd:][:nd], ys[k*2*nd+nd:][:nd]
+		copy(ys[k*2*nd:(k+1)*2*nd], ys[(k-1)*2*nd:k*2*nd])
+
+		for _, γ := range γ {
+			if err := step(a, x, γ*h, ynew, vnew, f, c); err != nil {
+				return ys[:k*2*nd], xs[:k], err
+			}
+			x += γ * h
+		}
+
+		xs[k] = x0 + float64(k)*h
+	}
+
+	return ys, xs, nil
+}
+
+// step performs a step of size h in place. The acceleration at the current
+// point is given by f, and it is replaced by the one at the new point. If c is
+// not nil, the step is the one of RATTLE, which enforces the constraints.
+func step(a func(float64, []float64, []float64), x, h float64, y, v, f []float64,
+	c *constrainer) error {
+
+	nd := len(y)
+
+	if c != nil {
+		c.prepare(y)
+	}
+	for i := 0; i < nd; i++ {
+		v[i] += h / 2 * f[i]
+		y[i] += h * v[i]
+	}
+	if c != nil {
+		if err := c.shake(h, y, v); err != nil {
+			return err
+		}
+	}
+	a(x+h, y, f)
+	for i := 0; i < nd; i++ {
+		v[i] += h / 2 * f[i]
+	}
+	if c != nil {
+		return c.rattle(y, v)
+	}
+
+	return nil
+}
+
+func coefficients(scheme Scheme) []float64 {
+	switch scheme {
+	case Yoshida4:
+		w1 := 1 / (2 - math.Cbrt(2))
+		w0 := 1 - 2*w1
+		return []float64{w1, w0, w1}
+	case Suzuki4:
+		p := 1 / (4 - math.Cbrt(4))
+		return []float64{p, p, 1 - 4*p, p, p}
+	case Yoshida6:
+		const (
+			w1 = -1.17767998417887100695
+			w2 = 0.235573213359358133684
+			w3 = 0.784513610477557263819
+			w0 = 1 - 2*(w1+w2+w3)
+		)
+		return []float64{w3, w2, w1, w0, w1, w2, w3}
+	default:
+		return []float64{1}
+	}
+}
@@ -0,0 +1,149 @@
+package verlet
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+	"github.com/ready-steady/ode/dopri"
+)
+
+func TestComputeSpring(t *testing.T) {
+	spring := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	solve := func(h float64) float64 {
+		integrator, _ := New(&Config{Step: h})
+		ys, xs, _ := integrator.Compute(spring, []float64{1, 0}, []float64{0, 1})
+		n := len(xs)
+		return math.Abs(ys[2*(n-1)] - math.Cos(1))
+	}
+
+	ratio := solve(0.01) / solve(0.005)
+	assert.Close(math.Log2(ratio), 2.0, 0.01, t)
+}
+
+func TestComputeScheme(t *testing.T) {
+	spring := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	orders := map[Scheme]float64{Verlet: 2, Yoshida4: 4, Suzuki4: 4, Yoshida6: 6}
+
+	for scheme, order := range orders {
+		solve := func(h float64) float64 {
+			integrator, _ := New(&Config{Step: h, Scheme: scheme})
+			ys, xs, _ := integrator.Compute(spring, []float64{1, 0}, []float64{0, 1})
+			n := len(xs)
+			return math.Abs(ys[2*(n-1)] - math.Cos(1))
+		}
+
+		ratio := solve(0.1) / solve(0.05)
+		assert.Close(math.Log2(ratio), order, 0.1, t)
+	}
+}
+
+func TestComputeKepler(t *testing.T) {
+	const (
+		e = 0.5
+	)
+
+	gravity := func(_ float64, y, f []float64) {
+		r := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+		f[0], f[1] = -y[0]/r, -y[1]/r
+	}
+
+	energy := func(y []float64) float64 {
+		return (y[2]*y[2]+y[3]*y[3])/2 - 1/math.Sqrt(y[0]*y[0]+y[1]*y[1])
+	}
+
+	y0 := []float64{1 - e, 0, 0, math.Sqrt((1 + e) / (1 - e))}
+
+	// One thousand revolutions
+	integrator, _ := New(&Config{Step: 1e-2})
+	ys, xs, _ := integrator.Compute(gravity, y0, []float64{0, 2000 * math.Pi})
+
+	// The energy error stays bounded instead of growing with time.
+	n := len(xs)
+	for k := 0; k < n; k += 1000 {
+		assert.Close(energy(ys[4*k:4*(k+1)]), energy(y0), 5e-3, t)
+	}
+	assert.Close(energy(ys[4*(n-1):]), energy(y0), 5e-3, t)
+}
+
+func TestComputePendulum(t *testing.T) {
+	const (
+		g = 9.81
+	)
+
+	// A pendulum of unit length in Cartesian coordinates.
+	gravity := func(_ float64, _, f []float64) {
+		f[0], f[1] = 0, -g
+	}
+	length := Constraint{
+		Function: func(y []float64) float64 {
+			return y[0]*y[0] + y[1]*y[1] - 1
+		},
+		Gradient: func(y, grad []float64) {
+			grad[0], grad[1] = 2*y[0], 2*y[1]
+		},
+	}
+	energy := func(y []float64) float64 {
+		return (y[2]*y[2]+y[3]*y[3])/2 + g*y[1]
+	}
+
+	θ0 := math.Pi / 3
+	y0 := []float64{math.Sin(θ0), -math.Cos(θ0), 0, 0}
+
+	for _, scheme := range []Scheme{Verlet, Yoshida4} {
+		integrator, _ := New(&Config{
+			Step:        1e-3,
+			Scheme:      scheme,
+			Constraints: []Constraint{length},
+			Masses:      []float64{2, 2},
+			Tolerance:   1e-12,
+		})
+
+		ys, xs, err := integrator.Compute(gravity, y0, []float64{0, 10})
+		assert.Equal(err, nil, t)
+
+		for k := range xs {
+			y := ys[4*k : 4*(k+1)]
+			assert.Close(length.Function(y), 0.0, 1e-11, t)
+			assert.Close(y[0]*y[2]+y[1]*y[3], 0.0, 1e-11, t)
+			assert.Close(energy(y), energy(y0), 1e-4, t)
+		}
+
+		// The angle follows the equation of the pendulum θ″ = -g sin θ.
+		reference, _ := dopri.New(&dopri.Config{AbsError: 1e-12, RelError: 1e-12})
+		θs, _, _ := reference.Compute(func(_ float64, y, f []float64) {
+			f[0], f[1] = y[1], -g*math.Sin(y[0])
+		}, []float64{θ0, 0}, []float64{0, 5, 10})
+		for _, i := range []int{1, 2} {
+			k := 5000 * i
+			assert.Close(math.Atan2(ys[4*k], -ys[4*k+1]), θs[2*i], 1e-4, t)
+		}
+	}
+
+	integrator, _ := New(&Config{Step: 1e-3, Constraints: []Constraint{length}, MaxIterations: 1})
+	_, xs, err := integrator.Compute(gravity, y0, []float64{0, 1})
+	assert.Equal(err, ErrConstraint, t)
+	assert.Equal(len(xs), 1, t)
+}
+
+func TestComputeInterval(t *testing.T) {
+	spring := func(_ float64, y, f []float64) {
+		f[0] = -y[0]
+	}
+
+	integrator, _ := New(&Config{Step: 0.1})
+
+	ys, xs, err := integrator.Compute(spring, []float64{1, 0}, []float64{0, 0.01})
+	assert.Equal(err, nil, t)
+	assert.Equal(xs, []float64{0, 0.01}, t)
+	assert.Close(ys[2:], []float64{math.Cos(0.01), -math.Sin(0.01)}, 1e-6, t)
+
+	_, _, err = integrator.Compute(spring, []float64{1, 0}, []float64{1, 0})
+	assert.Equal(err != nil, true, t)
+}
@@ -0,0 +1,178 @@
+// Package verner87 provides an integrator of systems of ordinary differential
+// equations based on the Verner method of orders eight and seven.
+//
+// The method is the most efficient 8(7) pair of Verner with 13 stages, which
+// pays off at tolerances of about 1e-10–1e-13 on nonstiff problems where the
+// methods of order five take too many steps. The solution at intermediate
+// points is computed using cubic Hermite interpolation, which is far less
+// accurate than the steps themselves unless the maximal step is small.
+//
+// https://www.sfu.ca/~jverner/
+package verner87
+
+import (
+	"github.com/ready-steady/ode/erk"
+)
+
+// Integrator is an integrator.
+type Integrator = erk.Integrator
+
+// New creates a new integrator.
+func New(config *Config) (*Integrator, error) {
+	return erk.New(tableau, config)
+}
+
+var tableau = &erk.Tableau{
+	A: [][]float64{
+		{},
+		{
+			0.05,
+		},
+		{
+			-0.0069931640625,
+			0.1135556640625,
+		},
+		{
+			0.0399609375,
+			0,
+			0.1198828125,
+		},
+		{
+			0.3613975628004575124052940721184028345129,
+			0,
+			-1.341524066700492771819987788202715834917,
+			1.370126503900035259414693716084313000404,
+		},
+		{
+			0.0490472027972027972027972027972027972028,
+			0,
+			0,
+			0.2350972042214404739862988335493427143122,
+			0.180855592981356728810903963653454488485,
+		},
+		{
+			0.06169289044289044289044289044289044289044,
+			0,
+			0,
+			0.1123656831464027662262557035130015442303,
+			-0.03885046071451366767049048108111244567456,
+			0.01979188712522045855379188712522045855379,
+		},
+		{
+			-1.767630240222326875735597119572145586714,
+			0,
+			0,
+			-62.5,
+			-6.061889377376669100821361459659331999758,
+			5.650823198222763138561298030600840174201,
+			65.62169641937623283799566054863063741227,
+		},
+		{
+			-1.180945066554970799825116282628297957882,
+			0,
+			0,
+			-41.50473441114320841606641502701994225874,
+			-4.434438319103725011225169229846100211776,
+			4.260408188586133024812193710744693240761,
+			43.75364022446171584987676829438379303004,
+			0.007871425489912310687446475044226307553,
+		},
+		{
+			-1.281405999441488405459510291182054246266,
+			0,
+			0,
+			-45.04713996013986630220754257136007322267,
+			-4.731362069449576477311464265491282810943,
+			4.514967016593807841185851584597240996214,
+			47.44909557172985134869022392235929015114,
+			0.01059228297111661135687393955516542875228,
+			-0.005746842263844616254432318478286296232021,
+		},
+		{
+			-1.724470134262485191756709817484481861731,
+			0,
+			0,
+			-60.92349008483054016518434619253765246063,
+			-5.95151837622239245520283276706185486829,
+			5.556523730698456235979791650843592496839,
+			63.98301198033305336837536378635995939281,
+			0.01464202825041496159275921391759452676003,
+			0.06460408772358203603621865144977650714892,
+			-0.07930323169008878984024452548693373291447,
+		},
+		{
+			-3.301622667747079016353994789790983625569,
+			0,
+			0,
+			-118.011272359752508566692330395789886851,
+			-10.14142238845611248642783916034510897595,
+			9.139311332232057923544012273556827000619,
+			123.3759428284042683684847180986501894364,
+			4.623244378874580474839807625067630924792,
+			-3.383277738068201923652550971536811240814,
+			4.527592100324618189451265339351129035325,
+			-5.828495485811622963193088019162985703755,
+		},
+		{
+			-3.039515033766309030040102851821200251056,
+			0,
+			0,
+			-109.2608680894176254686444192322164623352,
+			-9.290642497400293449717665542656897549158,
+			8.430504981764911142134299253836167803454,
+			114.2010010378331313557424041095523427476,
+			-0.9637271342145479358162375658987901652762,
+			-5.034884088802189791198680336183332323118,
+			5.958130824002923177540402165388172072794,
+			0,
+			0,
+		},
+	},
+	B: []float64{
+		0.044279894190079510747167466680985188621108,
+		0,
+		0,
+		0,
+		0,
+		0.35410493917244487448155520287335683541211,
+		0.24796921549564378286676294153706630238841,
+		-15.69420203883808405099207034271191213459,
+		25.084064965558562613439300312371862784979,
+		-31.73836778626027646833156112007297739975,
+		22.938283273988783952314835603447970183014,
+		-0.2361324633071542145259900641263517600739,
+		0,
+	},
+	C: []float64{
+		0,
+		0.05,
+		0.1065625,
+		0.15984375,
+		0.39,
+		0.465,
+		0.155,
+		0.943,
+		0.901802041735856958259707940678372149956,
+		0.909,
+		0.94,
+		1,
+		1,
+	},
+	E: []float64{
+		-0.000032721039010281377696898421105109027818857,
+		0,
+		0,
+		0,
+		0,
+		-0.0005046250618777703047627322161486684733794,
+		0.00012117235897847590476426938662043638675726,
+		-20.142336771313868543717198659871561005896,
+		5.2371785994398289141299763193949834322004,
+		-8.1567444087946580486363815113690277490401,
+		22.938283273988783952314835603447970183014,
+		-0.2361324633071542145259900641263517600739,
+		0.36016794372897751621245367377462024091067,
+		0,
+	},
+	Order: 7,
+}
@@ -0,0 +1,118 @@
+package verner87
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeDecay(t *testing.T) {
+	dydx := func(x float64, y, f []float64) {
+		f[0] = -y[0] + math.Cos(x)
+	}
+
+	xs := []float64{0, 0.25, 0.5, 0.75, 1, 1.25, 1.5, 1.75, 2}
+
+	integrator, _ := New(&Config{AbsError: 1e-12, RelError: 1e-12})
+
+	// The intermediate points are interpolated, and the last one is not.
+	ys, _, _ := integrator.Compute(dydx, []float64{0.5}, xs)
+	for i, x := range xs {
+		assert.Close(ys[i], 0.5*(math.Cos(x)+math.Sin(x)), 1e-5, t)
+	}
+	assert.Close(ys[len(xs)-1], 0.5*(math.Cos(2)+math.Sin(2)), 1e-11, t)
+}
+
+func TestComputeKepler(t *testing.T) {
+	const (
+		e = 0.5
+	)
+
+	dydx := func(_ float64, y, f []float64) {
+		r := math.Pow(y[0]*y[0]+y[1]*y[1], 1.5)
+		f[0], f[1] = y[2], y[3]
+		f[2], f[3] = -y[0]/r, -y[1]/r
+	}
+
+	y0 := []float64{1 - e, 0, 0, math.Sqrt((1 + e) / (1 - e))}
+
+	for _, relerr := range []float64{1e-6, 1e-9, 1e-12} {
+		integrator, _ := New(&Config{AbsError: relerr, RelError: relerr})
+
+		ys, xs, stats, err := integrator.ComputeWithStats(dydx, y0, []float64{0, 2 * math.Pi})
+		assert.Equal(err, nil, t)
+
+		n := len(xs)
+		assert.Close(ys[4*(n-1):], y0, 100*relerr, t)
+		assert.Equal(stats.Evaluations < 3000, true, t)
+	}
+}
+
+// TestOrder checks the order conditions of the method and of the embedded one
+// for all the rooted trees up to orders eight and seven, respectively.
+func TestOrder(t *testing.T) {
+	A, B, C, E := tableau.A, tableau.B, tableau.C, tableau.E
+	ns := len(C)
+
+	type tree struct {
+		order  int
+		γ      float64
+		weight []float64
+	}
+
+	// The trees of each order are grown from the forests of the smaller ones.
+	// The weight of a tree at the ith stage is the product over its subtrees
+	// of the sums of the coefficients of the stage times their weights.
+	var trees []tree
+	for n := 1; n <= 8; n++ {
+		limit := len(trees)
+		var grow func(m, k int, weight []float64, γ float64)
+		grow = func(m, k int, weight []float64, γ float64) {
+			if m == 0 {
+				trees = append(trees, tree{order: n, γ: float64(n) * γ, weight: weight})
+				return
+			}
+			for j := k; j < limit; j++ {
+				if trees[j].order > m {
+					continue
+				}
+				next := make([]float64, ns)
+				for i := range next {
+					sum := 0.0
+					for l, a := range A[i] {
+						sum += a * trees[j].weight[l]
+					}
+					next[i] = weight[i] * sum
+				}
+				grow(m-trees[j].order, j, next, γ*trees[j].γ)
+			}
+		}
+		weight := make([]float64, ns)
+		for i := range weight {
+			weight[i] = 1
+		}
+		grow(n-1, 0, weight, 1)
+	}
+	assert.Equal(len(trees), 200, t)
+
+	for _, tree := range trees {
+		Φ, Φhat := 0.0, 0.0
+		for i := 0; i < ns; i++ {
+			Φ += B[i] * tree.weight[i]
+			Φhat += (B[i] - E[i]) * tree.weight[i]
+		}
+		assert.Close(Φ, 1/tree.γ, 1e-12, t)
+		if tree.order <= 7 {
+			assert.Close(Φhat, 1/tree.γ, 1e-12, t)
+		}
+	}
+
+	for i := range C {
+		sum := 0.0
+		for _, a := range A[i] {
+			sum += a
+		}
+		assert.Close(sum, C[i], 1e-14, t)
+	}
+}